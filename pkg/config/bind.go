@@ -0,0 +1,263 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"GoFast/pkg/errorhandler"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	textType     = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// FieldError describes a value that could not be bound to a struct field
+type FieldError struct {
+	Key string // Dotted configuration key
+	Err error  // Underlying error
+}
+
+// Error returns the error message for the FieldError
+func (e *FieldError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ErrRequired is wrapped by FieldError when a required key is missing
+var ErrRequired = errors.New("required key is missing")
+
+// Bind decodes a nested map (as produced by the parsers) into the struct pointed
+// to by out. Fields are matched by the `config` tag or the lower-cased field name
+// and support the following tags:
+//
+//	config:"name"      key of the field; "-" skips the field, ",squash" inlines an embedded struct
+//	default:"value"    value used when the key is missing; slices use comma-separated values
+//	required:"true"    report an error when the key is missing and has no default
+//
+// Scalars are converted as needed: strings to numbers and bools, "1m30s" to
+// time.Duration, "10MB" to ByteSize, RFC 3339 strings to time.Time, comma-separated
+// strings to slices, and strings to any encoding.TextUnmarshaler.
+//
+// Parameters:
+// - input: the nested map to decode
+// - out: a non-nil pointer to a struct
+//
+// Returns:
+// - error: a *FieldError, or an *errorhandler.AggregateError when several fields fail
+func Bind(input interface{}, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("config: out must be a non-nil pointer")
+	}
+	var errs []error
+	decodeValue(input, rv.Elem(), "", &errs)
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errorhandler.NewAggregateError(errs)
+}
+
+// decodeValue decodes raw into v, appending failures to errs
+func decodeValue(raw interface{}, v reflect.Value, key string, errs *[]error) {
+	if v.Kind() == reflect.Ptr {
+		if raw == nil {
+			return
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		decodeValue(raw, v.Elem(), key, errs)
+		return
+	}
+	if err := decodeScalar(raw, v); err != errNotScalar {
+		if err != nil {
+			*errs = append(*errs, &FieldError{Key: key, Err: err})
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		m, ok := asMap(raw)
+		if !ok {
+			*errs = append(*errs, &FieldError{Key: key, Err: fmt.Errorf("expected a section, got %T", raw)})
+			return
+		}
+		decodeStruct(normalizeValue(m).(map[string]interface{}), v, key, errs)
+	case reflect.Slice:
+		items := toSlice(raw)
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			decodeValue(item, slice.Index(i), fmt.Sprintf("%s[%d]", key, i), errs)
+		}
+		v.Set(slice)
+	case reflect.Map:
+		m, ok := asMap(raw)
+		if !ok {
+			*errs = append(*errs, &FieldError{Key: key, Err: fmt.Errorf("expected a section, got %T", raw)})
+			return
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			*errs = append(*errs, &FieldError{Key: key, Err: fmt.Errorf("unsupported map key type %s", v.Type().Key())})
+			return
+		}
+		result := reflect.MakeMapWithSize(v.Type(), len(m))
+		for k, item := range m {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			decodeValue(item, elem, joinKey(key, k), errs)
+			result.SetMapIndex(reflect.ValueOf(k).Convert(v.Type().Key()), elem)
+		}
+		v.Set(result)
+	case reflect.Interface:
+		if raw != nil {
+			v.Set(reflect.ValueOf(raw))
+		}
+	default:
+		*errs = append(*errs, &FieldError{Key: key, Err: fmt.Errorf("unsupported field type %s", v.Type())})
+	}
+}
+
+// decodeStruct decodes the fields of a struct from a section
+func decodeStruct(section map[string]interface{}, v reflect.Value, prefix string, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("config"), ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "squash" || (field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct) {
+			decodeStruct(section, v.Field(i), prefix, errs)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = normalizeKey(name)
+		key := joinKey(prefix, name)
+
+		raw, ok := section[name]
+		if !ok {
+			raw, ok = lookupTree(section, name)
+		}
+		if !ok {
+			// Environment variables and flags store max_conns as max.conns
+			raw, ok = lookupTree(section, canonicalKey(name))
+		}
+		if !ok {
+			if def, hasDefault := field.Tag.Lookup("default"); hasDefault {
+				raw, ok = def, true
+			}
+		}
+		if !ok {
+			if field.Tag.Get("required") == "true" {
+				*errs = append(*errs, &FieldError{Key: key, Err: ErrRequired})
+			} else if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+				// Nested structs still receive their own defaults and required checks
+				decodeStruct(map[string]interface{}{}, v.Field(i), key, errs)
+			}
+			continue
+		}
+		decodeValue(raw, v.Field(i), key, errs)
+	}
+}
+
+var errNotScalar = errors.New("not a scalar type")
+
+// decodeScalar decodes raw into a scalar field; errNotScalar means v is a composite type
+func decodeScalar(raw interface{}, v reflect.Value) error {
+	switch v.Type() {
+	case durationType:
+		d, err := toDuration(raw)
+		if err == nil {
+			v.SetInt(int64(d))
+		}
+		return err
+	case byteSizeType:
+		s, err := toByteSize(raw)
+		if err == nil {
+			v.SetInt(int64(s))
+		}
+		return err
+	case timeType:
+		t, err := toTime(raw)
+		if err == nil {
+			v.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+	if s, ok := raw.(string); ok && reflect.PointerTo(v.Type()).Implements(textType) && v.CanAddr() {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, err := toString(raw)
+		if err == nil {
+			v.SetString(s)
+		}
+		return err
+	case reflect.Bool:
+		b, err := toBool(raw)
+		if err == nil {
+			v.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(raw)
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := toUint64(raw)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("value %d overflows %s", u, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(raw)
+		if err != nil {
+			return err
+		}
+		if v.OverflowFloat(f) {
+			return fmt.Errorf("value %v overflows %s", f, v.Type())
+		}
+		v.SetFloat(f)
+		return nil
+	}
+	return errNotScalar
+}
+
+// joinKey joins a section prefix and a key
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
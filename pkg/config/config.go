@@ -0,0 +1,489 @@
+// Package config provides layered configuration management.
+// Values are loaded from files (JSON, YAML, TOML, INI, .env), environment variables
+// and command-line flags, merged by source priority and bound to structs via tags.
+package config
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrKeyNotFound is returned when a configuration key does not exist
+var ErrKeyNotFound = errors.New("config key not found")
+
+// Config holds the merged values of all registered sources. The values are kept
+// in an immutable snapshot that is swapped atomically on reload, so readers never
+// observe a partially applied configuration.
+type Config struct {
+	mu          sync.RWMutex
+	sources     []Source
+	validators  []Validator
+	subscribers map[int]func(ChangeEvent)
+	nextID      int
+	reloadMu    sync.Mutex
+	values      atomic.Pointer[map[string]interface{}]
+}
+
+// New creates a new Config with the given sources
+//
+// Parameters:
+// - sources: the configuration sources, merged by priority when Load is called
+//
+// Returns:
+// - *Config: the new Config instance
+func New(sources ...Source) *Config {
+	c := &Config{
+		sources:     sources,
+		subscribers: make(map[int]func(ChangeEvent)),
+	}
+	c.swap(make(map[string]interface{}))
+	return c
+}
+
+// AddSource registers an additional source; call Load to apply it
+//
+// Parameters:
+// - source: the source to add
+func (c *Config) AddSource(source Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = append(c.sources, source)
+}
+
+// Load reads all sources and merges them. Sources with a higher priority override
+// lower ones; sources with equal priority are applied in registration order.
+// Load is equivalent to Reload without the change event.
+//
+// Returns:
+// - error: the first source or validation error encountered, otherwise nil
+func (c *Config) Load() error {
+	_, err := c.Reload()
+	return err
+}
+
+// snapshot returns the current immutable value map
+func (c *Config) snapshot() map[string]interface{} {
+	return *c.values.Load()
+}
+
+// swap atomically replaces the current value map
+func (c *Config) swap(values map[string]interface{}) {
+	c.values.Store(&values)
+}
+
+// loadSources loads and merges the sources into a flat key/value map
+func loadSources(sources []Source) (map[string]interface{}, error) {
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Priority() < sources[j].Priority()
+	})
+
+	values := make(map[string]interface{})
+	for _, source := range sources {
+		data, err := source.Load()
+		if err != nil {
+			return nil, &SourceError{Source: source.Name(), Err: err}
+		}
+		flatten("", data, values)
+	}
+	return values, nil
+}
+
+// Get returns the value stored under the dotted key. When the key addresses a
+// section rather than a leaf, the section is returned as a nested map.
+//
+// Parameters:
+// - key: the dotted key, e.g. "server.port"
+//
+// Returns:
+// - interface{}: the value
+// - bool: true if the key exists, false otherwise
+func (c *Config) Get(key string) (interface{}, bool) {
+	return lookup(c.snapshot(), key)
+}
+
+// IsSet checks whether the key exists in any source
+//
+// Parameters:
+// - key: the dotted key
+//
+// Returns:
+// - bool: true if the key exists, false otherwise
+func (c *Config) IsSet(key string) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// GetString returns the value of the key as a string
+//
+// Parameters:
+// - key: the dotted key
+// - def: the value returned when the key is missing or cannot be converted
+//
+// Returns:
+// - string: the value
+func (c *Config) GetString(key string, def string) string {
+	if v, ok := c.Get(key); ok {
+		if s, err := toString(v); err == nil {
+			return s
+		}
+	}
+	return def
+}
+
+// GetInt returns the value of the key as an int
+//
+// Parameters:
+// - key: the dotted key
+// - def: the value returned when the key is missing or cannot be converted
+//
+// Returns:
+// - int: the value
+func (c *Config) GetInt(key string, def int) int {
+	if v, ok := c.Get(key); ok {
+		if i, err := toInt64(v); err == nil {
+			return int(i)
+		}
+	}
+	return def
+}
+
+// GetFloat returns the value of the key as a float64
+//
+// Parameters:
+// - key: the dotted key
+// - def: the value returned when the key is missing or cannot be converted
+//
+// Returns:
+// - float64: the value
+func (c *Config) GetFloat(key string, def float64) float64 {
+	if v, ok := c.Get(key); ok {
+		if f, err := toFloat64(v); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// GetBool returns the value of the key as a bool
+//
+// Parameters:
+// - key: the dotted key
+// - def: the value returned when the key is missing or cannot be converted
+//
+// Returns:
+// - bool: the value
+func (c *Config) GetBool(key string, def bool) bool {
+	if v, ok := c.Get(key); ok {
+		if b, err := toBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// GetDuration returns the value of the key as a time.Duration, e.g. "1m30s"
+//
+// Parameters:
+// - key: the dotted key
+// - def: the value returned when the key is missing or cannot be converted
+//
+// Returns:
+// - time.Duration: the value
+func (c *Config) GetDuration(key string, def time.Duration) time.Duration {
+	if v, ok := c.Get(key); ok {
+		if d, err := toDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// GetSize returns the value of the key as a ByteSize, e.g. "10MB"
+//
+// Parameters:
+// - key: the dotted key
+// - def: the value returned when the key is missing or cannot be converted
+//
+// Returns:
+// - ByteSize: the value
+func (c *Config) GetSize(key string, def ByteSize) ByteSize {
+	if v, ok := c.Get(key); ok {
+		if s, err := toByteSize(v); err == nil {
+			return s
+		}
+	}
+	return def
+}
+
+// GetStringSlice returns the value of the key as a string slice. Scalar strings
+// are split on commas.
+//
+// Parameters:
+// - key: the dotted key
+//
+// Returns:
+// - []string: the value, or nil if the key is missing
+func (c *Config) GetStringSlice(key string) []string {
+	v, ok := c.Get(key)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, item := range toSlice(v) {
+		if s, err := toString(item); err == nil {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Keys returns all leaf keys in sorted order
+//
+// Returns:
+// - []string: the sorted keys
+func (c *Config) Keys() []string {
+	values := c.snapshot()
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// All returns a copy of all values as a flat map of dotted keys
+//
+// Returns:
+// - map[string]interface{}: the flattened values
+func (c *Config) All() map[string]interface{} {
+	return copyValues(c.snapshot())
+}
+
+// Bind decodes the configuration into the struct pointed to by out.
+// See the package-level Bind for supported tags.
+//
+// Parameters:
+// - out: a non-nil pointer to a struct
+//
+// Returns:
+// - error: an error if decoding fails or required keys are missing
+func (c *Config) Bind(out interface{}) error {
+	return c.BindKey("", out)
+}
+
+// BindKey decodes the section under key into the struct pointed to by out
+//
+// Parameters:
+// - key: the dotted section key, or "" for the root
+// - out: a non-nil pointer to a struct
+//
+// Returns:
+// - error: an error if decoding fails or required keys are missing
+func (c *Config) BindKey(key string, out interface{}) error {
+	tree := unflatten(c.snapshot())
+
+	var section interface{} = tree
+	if key != "" {
+		v, ok := lookupTree(tree, normalizeKey(key))
+		if !ok {
+			v = map[string]interface{}{}
+		}
+		section = v
+	}
+	return Bind(section, out)
+}
+
+// SourceError describes a failure to load a single source
+type SourceError struct {
+	Source string // Name of the failing source
+	Err    error  // Underlying error
+}
+
+// Error returns the error message for the SourceError
+func (e *SourceError) Error() string {
+	return "config source " + e.Source + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// normalizeKey lower-cases a key and trims surrounding dots and spaces
+func normalizeKey(key string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(key)), ".")
+}
+
+// keySeparators maps the characters that environment variables and flags use in
+// place of dots
+var keySeparators = strings.NewReplacer("_", ".", "-", ".")
+
+// canonicalKey treats "_", "-" and "." alike, so that APP_MAX_CONNECTIONS matches
+// the file key max_connections. The result has the same length as key.
+func canonicalKey(key string) string {
+	return keySeparators.Replace(key)
+}
+
+// existingKey returns the key in values that is equivalent to key under
+// canonicalKey, preferring an exact match, or key itself if there is none
+func existingKey(values map[string]interface{}, key string) string {
+	if _, ok := values[key]; ok {
+		return key
+	}
+	canonical, match := canonicalKey(key), ""
+	for k := range values {
+		if canonicalKey(k) == canonical && (match == "" || k < match) {
+			match = k
+		}
+	}
+	if match == "" {
+		return key
+	}
+	return match
+}
+
+// flatten merges a nested map into dst using dotted, lower-cased keys. A leaf
+// overrides an existing key that differs only in separators.
+func flatten(prefix string, src map[string]interface{}, dst map[string]interface{}) {
+	for k, v := range src {
+		key := normalizeKey(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := asMap(v); ok && len(nested) > 0 {
+			// A section replaces a scalar previously stored under the same key
+			delete(dst, key)
+			flatten(key, nested, dst)
+			continue
+		}
+		key = existingKey(dst, key)
+		// A scalar replaces a section previously stored under the same key
+		for existing := range dst {
+			if strings.HasPrefix(existing, key+".") {
+				delete(dst, existing)
+			}
+		}
+		dst[key] = normalizeValue(v)
+	}
+}
+
+// unflatten rebuilds a nested map from dotted keys
+func unflatten(values map[string]interface{}) map[string]interface{} {
+	root := make(map[string]interface{})
+	for key, v := range values {
+		parts := strings.Split(key, ".")
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = v
+	}
+	return root
+}
+
+// lookup finds a leaf or a section in the flat value map. Keys that differ only
+// in separators match, so "max_connections" finds a value set by APP_MAX_CONNECTIONS.
+func lookup(values map[string]interface{}, key string) (interface{}, bool) {
+	key = normalizeKey(key)
+	if v, ok := values[existingKey(values, key)]; ok {
+		return v, true
+	}
+	section := make(map[string]interface{})
+	prefix := canonicalKey(key) + "."
+	for k, v := range values {
+		if strings.HasPrefix(canonicalKey(k), prefix) {
+			section[k[len(prefix):]] = v
+		}
+	}
+	if len(section) == 0 {
+		return nil, false
+	}
+	return unflatten(section), true
+}
+
+// lookupTree walks a nested map along a dotted key
+func lookupTree(tree map[string]interface{}, key string) (interface{}, bool) {
+	var node interface{} = tree
+	for _, part := range strings.Split(key, ".") {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// asMap converts the map types produced by the parsers into map[string]interface{}
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			s, _ := toString(k)
+			result[s] = val
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// copyValues deep-copies a flat value map so that callers cannot modify a snapshot
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		result[k] = copyValue(v)
+	}
+	return result
+}
+
+// copyValue deep-copies the maps and slices produced by normalizeValue; other values are immutable
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return copyValues(val)
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			result[i] = copyValue(item)
+		}
+		return result
+	}
+	return v
+}
+
+// normalizeValue converts nested maps inside slices into map[string]interface{}
+// with lower-cased keys so that values from every parser look alike
+func normalizeValue(v interface{}) interface{} {
+	if m, ok := asMap(v); ok {
+		result := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			result[normalizeKey(k)] = normalizeValue(val)
+		}
+		return result
+	}
+	if s, ok := v.([]interface{}); ok {
+		result := make([]interface{}, len(s))
+		for i, item := range s {
+			result[i] = normalizeValue(item)
+		}
+		return result
+	}
+	return v
+}
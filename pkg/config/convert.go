@@ -0,0 +1,327 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a size in bytes that can be written in configuration files with
+// a unit suffix, e.g. "512KB", "10MB" or "1.5GiB". Units are binary (1KB = 1024B).
+type ByteSize int64
+
+// Common byte sizes
+const (
+	Byte     ByteSize = 1
+	KiloByte          = 1024 * Byte
+	MegaByte          = 1024 * KiloByte
+	GigaByte          = 1024 * MegaByte
+	TeraByte          = 1024 * GigaByte
+	PetaByte          = 1024 * TeraByte
+)
+
+var sizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KiloByte,
+	"kb":  KiloByte,
+	"kib": KiloByte,
+	"m":   MegaByte,
+	"mb":  MegaByte,
+	"mib": MegaByte,
+	"g":   GigaByte,
+	"gb":  GigaByte,
+	"gib": GigaByte,
+	"t":   TeraByte,
+	"tb":  TeraByte,
+	"tib": TeraByte,
+	"p":   PetaByte,
+	"pb":  PetaByte,
+	"pib": PetaByte,
+}
+
+// ParseByteSize parses a size string such as "10MB" or "1.5 GiB"
+//
+// Parameters:
+// - s: the size string
+//
+// Returns:
+// - ByteSize: the size in bytes
+// - error: if the string is not a valid size
+func ParseByteSize(s string) (ByteSize, error) {
+	str := strings.TrimSpace(s)
+	i := 0
+	for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.' || str[i] == '-' || str[i] == '+') {
+		i++
+	}
+	number, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+	multiplier, ok := sizeUnits[unit]
+	if number == "" || !ok {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	// whole numbers use integer math so that sizes near the int64 limit are exact
+	if whole, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if whole < 0 {
+			return 0, fmt.Errorf("invalid byte size %q", s)
+		}
+		if whole > math.MaxInt64/int64(multiplier) {
+			return 0, fmt.Errorf("byte size %q overflows int64", s)
+		}
+		return ByteSize(whole) * multiplier, nil
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, so compare against 2^63 itself
+	bytes := value * float64(multiplier)
+	if bytes >= 1<<63 {
+		return 0, fmt.Errorf("byte size %q overflows int64", s)
+	}
+	return ByteSize(bytes), nil
+}
+
+// String formats the size with the largest unit that represents it exactly
+func (b ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PetaByte, "PB"}, {TeraByte, "TB"}, {GigaByte, "GB"}, {MegaByte, "MB"}, {KiloByte, "KB"},
+	}
+	for _, u := range units {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// toString converts a scalar value to a string
+func toString(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case time.Time:
+		return val.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return val.String(), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val), nil
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("cannot convert %T to string", v)
+}
+
+// toBool converts a value to a bool
+func toBool(v interface{}) (bool, error) {
+	switch val := v.(type) {
+	case bool:
+		return val, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(val)) {
+		case "1", "t", "true", "y", "yes", "on":
+			return true, nil
+		case "0", "f", "false", "n", "no", "off", "":
+			return false, nil
+		}
+		return false, fmt.Errorf("invalid boolean %q", val)
+	}
+	if i, err := toInt64(v); err == nil {
+		return i != 0, nil
+	}
+	return false, fmt.Errorf("cannot convert %T to bool", v)
+}
+
+// toInt64 converts a value to an int64
+func toInt64(v interface{}) (int64, error) {
+	switch val := v.(type) {
+	case int:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case int16:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint:
+		return int64(val), nil
+	case uint8:
+		return int64(val), nil
+	case uint16:
+		return int64(val), nil
+	case uint32:
+		return int64(val), nil
+	case uint64:
+		if val > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", val)
+		}
+		return int64(val), nil
+	case float32:
+		return floatToInt(float64(val))
+	case float64:
+		return floatToInt(val)
+	case string:
+		s := strings.ReplaceAll(strings.TrimSpace(val), "_", "")
+		if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return floatToInt(f)
+		}
+		return 0, fmt.Errorf("invalid integer %q", val)
+	}
+	return 0, fmt.Errorf("cannot convert %T to int", v)
+}
+
+// floatToInt converts a float without a fractional part to an int64
+func floatToInt(f float64) (int64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("value %v is not an integer", f)
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, so compare against 2^63 itself
+	if f >= 1<<63 || f < math.MinInt64 {
+		return 0, fmt.Errorf("value %v overflows int64", f)
+	}
+	return int64(f), nil
+}
+
+// toUint64 converts a value to a uint64
+func toUint64(v interface{}) (uint64, error) {
+	if s, ok := v.(string); ok {
+		u, err := strconv.ParseUint(strings.ReplaceAll(strings.TrimSpace(s), "_", ""), 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		return u, nil
+	}
+	if u, ok := v.(uint64); ok {
+		return u, nil
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("value %d is negative", i)
+	}
+	return uint64(i), nil
+}
+
+// toFloat64 converts a value to a float64
+func toFloat64(v interface{}) (float64, error) {
+	switch val := v.(type) {
+	case float32:
+		return float64(val), nil
+	case float64:
+		return val, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", val)
+		}
+		return f, nil
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	return float64(i), nil
+}
+
+// toDuration converts a string such as "1m30s" or a number of nanoseconds to a duration
+func toDuration(v interface{}) (time.Duration, error) {
+	switch val := v.(type) {
+	case time.Duration:
+		return val, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(val))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", val)
+		}
+		return d, nil
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(i), nil
+}
+
+// toByteSize converts a size string or a number of bytes to a ByteSize
+func toByteSize(v interface{}) (ByteSize, error) {
+	switch val := v.(type) {
+	case ByteSize:
+		return val, nil
+	case string:
+		return ParseByteSize(val)
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	return ByteSize(i), nil
+}
+
+// toTime converts an RFC 3339 string or date to a time.Time
+func toTime(v interface{}) (time.Time, error) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, nil
+	case string:
+		s := strings.TrimSpace(val)
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05", "2006-01-02", "15:04:05"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid time %q", val)
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to time", v)
+}
+
+// toSlice converts a value to a slice; strings are split on commas
+func toSlice(v interface{}) []interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return val
+	case []string:
+		result := make([]interface{}, len(val))
+		for i, s := range val {
+			result[i] = s
+		}
+		return result
+	case string:
+		if strings.TrimSpace(val) == "" {
+			return []interface{}{}
+		}
+		parts := strings.Split(val, ",")
+		result := make([]interface{}, len(parts))
+		for i, part := range parts {
+			result[i] = strings.TrimSpace(part)
+		}
+		return result
+	}
+	return []interface{}{v}
+}
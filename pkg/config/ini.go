@@ -0,0 +1,132 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// parseINI decodes an INI document. Sections become nested tables ("[db.primary]"
+// nests under "db"), keys outside any section are placed at the root, and all
+// values are kept as strings.
+func parseINI(data []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	section := root
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("ini: line %d: unterminated section header", lineNo)
+			}
+			name := strings.TrimSpace(line[1:end])
+			if name == "" {
+				return nil, fmt.Errorf("ini: line %d: empty section name", lineNo)
+			}
+			section = root
+			for _, part := range strings.Split(name, ".") {
+				child, ok := section[part].(map[string]interface{})
+				if !ok {
+					child = make(map[string]interface{})
+					section[part] = child
+				}
+				section = child
+			}
+			continue
+		}
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, fmt.Errorf("ini: line %d: expected key = value", lineNo)
+		}
+		key := strings.TrimSpace(line[:sep])
+		if key == "" {
+			return nil, fmt.Errorf("ini: line %d: empty key", lineNo)
+		}
+		section[key] = unquoteValue(strings.TrimSpace(line[sep+1:]), false)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// parseDotEnv decodes KEY=VALUE lines as used by .env files. Double-quoted
+// values support escapes, single-quoted values are literal, and ${VAR}
+// references in unquoted or double-quoted values are expanded from previously
+// defined keys and the process environment.
+func parseDotEnv(data []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	expand := func(name string) string {
+		if v, ok := result[name]; ok {
+			return v.(string)
+		}
+		return os.Getenv(name)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("env: line %d: expected KEY=VALUE", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("env: line %d: empty key", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "'") {
+			result[key] = unquoteValue(value, false)
+			continue
+		}
+		result[key] = os.Expand(unquoteValue(value, true), expand)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// unquoteValue strips matching quotes and trailing inline comments. When escapes
+// is true, double-quoted values are unescaped like Go strings.
+func unquoteValue(value string, escapes bool) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		quote := value[0]
+		for i := 1; i < len(value); i++ {
+			if value[i] == '\\' && quote == '"' {
+				i++
+				continue
+			}
+			if value[i] != quote {
+				continue
+			}
+			if quote == '"' && escapes {
+				if s, err := strconv.Unquote(value[:i+1]); err == nil {
+					return s
+				}
+			}
+			return value[1:i]
+		}
+	}
+	for _, marker := range []string{" #", " ;", "\t#", "\t;"} {
+		if i := strings.Index(value, marker); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parser decodes raw file contents into a nested map
+type Parser func(data []byte) (map[string]interface{}, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]Parser{
+		"json": parseJSON,
+		"yaml": parseYAML,
+		"yml":  parseYAML,
+		"toml": parseTOML,
+		"ini":  parseINI,
+		"env":  parseDotEnv,
+	}
+)
+
+// RegisterParser registers a parser for a format name or file extension,
+// replacing any existing parser for it
+//
+// Parameters:
+// - format: the format name or extension without the dot, e.g. "hcl"
+// - parser: the parser
+func RegisterParser(format string, parser Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[strings.ToLower(format)] = parser
+}
+
+// lookupParser returns the parser registered for the format
+func lookupParser(format string) (Parser, bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	p, ok := parsers[strings.ToLower(format)]
+	return p, ok
+}
+
+// parseJSON decodes JSON, keeping integers exact
+func parseJSON(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result map[string]interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return convertJSONNumbers(result).(map[string]interface{}), nil
+}
+
+// convertJSONNumbers replaces json.Number values with int64 or float64
+func convertJSONNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = convertJSONNumbers(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = convertJSONNumbers(item)
+		}
+		return val
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	}
+	return v
+}
+
+// parseYAML decodes YAML
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
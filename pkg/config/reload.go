@@ -0,0 +1,172 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Validator checks a freshly loaded configuration before it replaces the current one.
+// It receives a detached Config holding the candidate values, so it may call Get
+// or Bind on it freely.
+type Validator func(next *Config) error
+
+// ChangeEvent describes the difference between two configuration snapshots
+type ChangeEvent struct {
+	Added    []string               // Keys that exist only in the new snapshot
+	Removed  []string               // Keys that exist only in the old snapshot
+	Modified []string               // Keys whose value changed
+	Old      map[string]interface{} // Previous values, flattened
+	New      map[string]interface{} // Current values, flattened
+}
+
+// Empty reports whether the event contains no changes
+func (e ChangeEvent) Empty() bool {
+	return len(e.Added) == 0 && len(e.Removed) == 0 && len(e.Modified) == 0
+}
+
+// Changed returns all added, removed and modified keys in sorted order
+func (e ChangeEvent) Changed() []string {
+	keys := make([]string, 0, len(e.Added)+len(e.Removed)+len(e.Modified))
+	keys = append(keys, e.Added...)
+	keys = append(keys, e.Removed...)
+	keys = append(keys, e.Modified...)
+	sort.Strings(keys)
+	return keys
+}
+
+// AddValidator registers a validator that must accept every reloaded configuration
+//
+// Parameters:
+// - validator: the validator to add
+func (c *Config) AddValidator(validator Validator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validators = append(c.validators, validator)
+}
+
+// Subscribe registers a callback invoked after every reload that changes at least
+// one key. Callbacks run synchronously on the reloading goroutine after the new
+// values are in place, so they may call Get or even Reload. Each callback receives
+// its own copy of the old and new values.
+//
+// Parameters:
+// - fn: the callback receiving the change event
+//
+// Returns:
+// - func(): a function that removes the subscription
+func (c *Config) Subscribe(fn func(ChangeEvent)) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.nextID
+	c.nextID++
+	c.subscribers[id] = fn
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subscribers, id)
+	}
+}
+
+// Reload re-reads all sources, validates the result and atomically swaps it in.
+// When loading or validation fails the current configuration is kept unchanged.
+//
+// Returns:
+// - ChangeEvent: the keys changed by the reload, with copies of the old and new values
+// - error: the source or validation error, otherwise nil
+func (c *Config) Reload() (ChangeEvent, error) {
+	event, err := c.reload()
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+	// Subscribers run without reloadMu so that they may reload themselves
+	if !event.Empty() {
+		c.notify(event)
+	}
+	return event.clone(), nil
+}
+
+// reload loads, validates and swaps in new values; reloads are serialized so that
+// each event describes the change from the snapshot it replaced
+func (c *Config) reload() (ChangeEvent, error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.mu.RLock()
+	sources := make([]Source, len(c.sources))
+	copy(sources, c.sources)
+	validators := make([]Validator, len(c.validators))
+	copy(validators, c.validators)
+	c.mu.RUnlock()
+
+	values, err := loadSources(sources)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+
+	candidate := New()
+	candidate.swap(values)
+	for _, validate := range validators {
+		if err := validate(candidate); err != nil {
+			return ChangeEvent{}, err
+		}
+	}
+
+	event := Diff(c.snapshot(), values)
+	c.swap(values)
+	return event, nil
+}
+
+// notify delivers the event to all subscribers
+func (c *Config) notify(event ChangeEvent) {
+	c.mu.RLock()
+	ids := make([]int, 0, len(c.subscribers))
+	for id := range c.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subscribers := make([]func(ChangeEvent), 0, len(ids))
+	for _, id := range ids {
+		subscribers = append(subscribers, c.subscribers[id])
+	}
+	c.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(event.clone())
+	}
+}
+
+// clone returns a copy of the event whose value maps share nothing with the snapshots
+func (e ChangeEvent) clone() ChangeEvent {
+	e.Old = copyValues(e.Old)
+	e.New = copyValues(e.New)
+	return e
+}
+
+// Diff compares two flattened value maps
+//
+// Parameters:
+// - prev: the previous values
+// - next: the current values
+//
+// Returns:
+// - ChangeEvent: the sorted lists of added, removed and modified keys
+func Diff(prev, next map[string]interface{}) ChangeEvent {
+	event := ChangeEvent{Old: prev, New: next}
+	for k, v := range next {
+		old, ok := prev[k]
+		if !ok {
+			event.Added = append(event.Added, k)
+		} else if !reflect.DeepEqual(old, v) {
+			event.Modified = append(event.Modified, k)
+		}
+	}
+	for k := range prev {
+		if _, ok := next[k]; !ok {
+			event.Removed = append(event.Removed, k)
+		}
+	}
+	sort.Strings(event.Added)
+	sort.Strings(event.Removed)
+	sort.Strings(event.Modified)
+	return event
+}
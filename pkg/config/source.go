@@ -0,0 +1,298 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Source priorities. Values from a higher priority source override lower ones,
+// giving the precedence: defaults < files < .env files < environment < flags.
+const (
+	PriorityDefaults = 0
+	PriorityFile     = 100
+	PriorityDotEnv   = 200
+	PriorityEnv      = 300
+	PriorityFlag     = 400
+)
+
+// Source is a provider of configuration values
+type Source interface {
+	// Name returns a human-readable name used in error messages
+	Name() string
+	// Priority returns the merge priority of the source
+	Priority() int
+	// Load reads the source and returns its values as a nested map
+	Load() (map[string]interface{}, error)
+}
+
+// MapSource provides values from an in-memory map, typically defaults
+type MapSource struct {
+	Values map[string]interface{}
+}
+
+// NewMapSource creates a source with default priority from a nested or dotted map
+//
+// Parameters:
+// - values: the values, keys may be dotted ("server.port")
+//
+// Returns:
+// - *MapSource: the new MapSource
+func NewMapSource(values map[string]interface{}) *MapSource {
+	return &MapSource{Values: values}
+}
+
+// Name returns the name of the source
+func (s *MapSource) Name() string {
+	return "map"
+}
+
+// Priority returns the merge priority of the source
+func (s *MapSource) Priority() int {
+	return PriorityDefaults
+}
+
+// Load returns the in-memory values
+func (s *MapSource) Load() (map[string]interface{}, error) {
+	return s.Values, nil
+}
+
+// FileSource loads values from a configuration file. The format is chosen by
+// file extension unless Format is set explicitly.
+type FileSource struct {
+	Path     string // Path to the file
+	Format   string // Format name (json, yaml, toml, ini, env); empty means detect from extension
+	Optional bool   // If true, a missing file yields no values instead of an error
+}
+
+// NewFileSource creates a source for a required configuration file
+//
+// Parameters:
+// - path: the path to the file
+//
+// Returns:
+// - *FileSource: the new FileSource
+func NewFileSource(path string) *FileSource {
+	return &FileSource{Path: path}
+}
+
+// Name returns the name of the source
+func (s *FileSource) Name() string {
+	return s.Path
+}
+
+// Priority returns the merge priority of the source
+func (s *FileSource) Priority() int {
+	return PriorityFile
+}
+
+// Files returns the file backing the source
+func (s *FileSource) Files() []string {
+	return []string{s.Path}
+}
+
+// Load reads and parses the file
+func (s *FileSource) Load() (map[string]interface{}, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if s.Optional && errors.Is(err, os.ErrNotExist) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, err
+	}
+
+	format := s.Format
+	if format == "" {
+		format = formatFromPath(s.Path)
+	}
+	parser, ok := lookupParser(format)
+	if !ok {
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	values, err := parser(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "env" {
+		return envToTree(values, ""), nil
+	}
+	return values, nil
+}
+
+// DotEnvSource loads KEY=VALUE pairs from a .env file and maps them to keys
+// the same way EnvSource does. It overrides files but not the real environment.
+type DotEnvSource struct {
+	Path     string // Path to the .env file
+	Prefix   string // Only variables starting with Prefix_ are used; empty uses all
+	Optional bool   // If true, a missing file yields no values instead of an error
+}
+
+// NewDotEnvSource creates a source for a .env file
+//
+// Parameters:
+// - path: the path to the .env file
+// - prefix: the variable prefix, e.g. "APP"
+//
+// Returns:
+// - *DotEnvSource: the new DotEnvSource
+func NewDotEnvSource(path, prefix string) *DotEnvSource {
+	return &DotEnvSource{Path: path, Prefix: prefix}
+}
+
+// Name returns the name of the source
+func (s *DotEnvSource) Name() string {
+	return s.Path
+}
+
+// Priority returns the merge priority of the source
+func (s *DotEnvSource) Priority() int {
+	return PriorityDotEnv
+}
+
+// Files returns the file backing the source
+func (s *DotEnvSource) Files() []string {
+	return []string{s.Path}
+}
+
+// Load reads and parses the .env file
+func (s *DotEnvSource) Load() (map[string]interface{}, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if s.Optional && errors.Is(err, os.ErrNotExist) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, err
+	}
+	values, err := parseDotEnv(data)
+	if err != nil {
+		return nil, err
+	}
+	return envToTree(values, s.Prefix), nil
+}
+
+// EnvSource loads values from environment variables. With prefix "APP",
+// APP_SERVER_PORT becomes the key "server.port". Since underscores cannot be told
+// apart from separators, keys are matched with "_", "-" and "." treated alike:
+// APP_MAX_CONNECTIONS overrides the file key "max_connections".
+type EnvSource struct {
+	Prefix  string          // Only variables starting with Prefix_ are used; empty uses all
+	Environ func() []string // Returns KEY=VALUE pairs; defaults to os.Environ
+}
+
+// NewEnvSource creates a source for environment variables with the given prefix
+//
+// Parameters:
+// - prefix: the variable prefix, e.g. "APP"
+//
+// Returns:
+// - *EnvSource: the new EnvSource
+func NewEnvSource(prefix string) *EnvSource {
+	return &EnvSource{Prefix: prefix, Environ: os.Environ}
+}
+
+// Name returns the name of the source
+func (s *EnvSource) Name() string {
+	return "env"
+}
+
+// Priority returns the merge priority of the source
+func (s *EnvSource) Priority() int {
+	return PriorityEnv
+}
+
+// Load reads the environment
+func (s *EnvSource) Load() (map[string]interface{}, error) {
+	environ := s.Environ
+	if environ == nil {
+		environ = os.Environ
+	}
+	values := make(map[string]interface{})
+	for _, kv := range environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			values[k] = v
+		}
+	}
+	return envToTree(values, s.Prefix), nil
+}
+
+// FlagSource loads values from a parsed flag.FlagSet. Only flags that were set
+// explicitly on the command line are used, so flag defaults never override
+// other sources. Dashes in flag names act as key separators: -server-port and
+// -server.port both map to "server.port", and -max-connections overrides the
+// file key "max_connections".
+type FlagSource struct {
+	FlagSet *flag.FlagSet
+}
+
+// NewFlagSource creates a source for a parsed flag set
+//
+// Parameters:
+// - fs: the parsed flag set; nil uses flag.CommandLine
+//
+// Returns:
+// - *FlagSource: the new FlagSource
+func NewFlagSource(fs *flag.FlagSet) *FlagSource {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	return &FlagSource{FlagSet: fs}
+}
+
+// Name returns the name of the source
+func (s *FlagSource) Name() string {
+	return "flags"
+}
+
+// Priority returns the merge priority of the source
+func (s *FlagSource) Priority() int {
+	return PriorityFlag
+}
+
+// Load collects the explicitly set flags
+func (s *FlagSource) Load() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	s.FlagSet.Visit(func(f *flag.Flag) {
+		key := strings.ReplaceAll(f.Name, "-", ".")
+		if getter, ok := f.Value.(flag.Getter); ok {
+			values[key] = getter.Get()
+			return
+		}
+		values[key] = f.Value.String()
+	})
+	return values, nil
+}
+
+// envToTree maps environment-style names to dotted keys, keeping only the
+// variables that carry the prefix
+func envToTree(values map[string]interface{}, prefix string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if prefix != "" {
+		prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_")) + "_"
+	}
+	for name, v := range values {
+		upper := strings.ToUpper(name)
+		if prefix != "" {
+			if !strings.HasPrefix(upper, prefix) {
+				continue
+			}
+			name = name[len(prefix):]
+		}
+		if name == "" {
+			continue
+		}
+		result[strings.ReplaceAll(strings.ToLower(name), "_", ".")] = v
+	}
+	return result
+}
+
+// formatFromPath returns the format name implied by the file extension
+func formatFromPath(path string) string {
+	base := filepath.Base(path)
+	if base == ".env" || strings.HasPrefix(base, ".env.") {
+		return "env"
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
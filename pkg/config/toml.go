@@ -0,0 +1,533 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tomlParser is a parser for the commonly used subset of TOML 1.0: tables,
+// arrays of tables, dotted keys, all string forms, integers (with bases and
+// underscores), floats, booleans, arrays and inline tables. Dates and times
+// are kept as strings and converted when bound.
+type tomlParser struct {
+	src     string
+	pos     int
+	line    int
+	root    map[string]interface{}
+	current map[string]interface{}
+}
+
+var tomlDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// parseTOML decodes a TOML document
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	p := &tomlParser{src: string(data), line: 1, root: make(map[string]interface{})}
+	p.current = p.root
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.root, nil
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("toml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipBlank(true)
+		if p.eof() {
+			return nil
+		}
+		var err error
+		if p.peek() == '[' {
+			err = p.parseTableHeader()
+		} else {
+			err = p.parseKeyValue(p.current)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.expectLineEnd(); err != nil {
+			return err
+		}
+	}
+}
+
+// skipBlank skips spaces, tabs and comments, and newlines if requested
+func (p *tomlParser) skipBlank(newlines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && newlines:
+			p.line++
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) expectLineEnd() error {
+	p.skipBlank(false)
+	if p.eof() {
+		return nil
+	}
+	if p.peek() != '\n' {
+		return p.errorf("unexpected %q after value", p.peek())
+	}
+	return nil
+}
+
+func (p *tomlParser) parseTableHeader() error {
+	p.pos++ // [
+	array := p.peek() == '['
+	if array {
+		p.pos++
+	}
+	p.skipBlank(false)
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipBlank(false)
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.src[p.pos:], closing) {
+		return p.errorf("expected %q to close table header", closing)
+	}
+	p.pos += len(closing)
+
+	parent, err := p.descend(p.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if array {
+		table := make(map[string]interface{})
+		existing, ok := parent[last]
+		if !ok {
+			parent[last] = []interface{}{table}
+		} else if list, ok := existing.([]interface{}); ok {
+			parent[last] = append(list, table)
+		} else {
+			return p.errorf("key %q is not an array of tables", last)
+		}
+		p.current = table
+		return nil
+	}
+	table, err := p.descend(parent, []string{last})
+	if err != nil {
+		return err
+	}
+	p.current = table
+	return nil
+}
+
+// descend walks (and creates) nested tables; an array of tables resolves to its last element
+func (p *tomlParser) descend(node map[string]interface{}, keys []string) (map[string]interface{}, error) {
+	for _, key := range keys {
+		switch child := node[key].(type) {
+		case nil:
+			table := make(map[string]interface{})
+			node[key] = table
+			node = table
+		case map[string]interface{}:
+			node = child
+		case []interface{}:
+			if len(child) == 0 {
+				return nil, p.errorf("key %q is an empty array", key)
+			}
+			table, ok := child[len(child)-1].(map[string]interface{})
+			if !ok {
+				return nil, p.errorf("key %q is not a table", key)
+			}
+			node = table
+		default:
+			return nil, p.errorf("key %q is already defined as a value", key)
+		}
+	}
+	return node, nil
+}
+
+func (p *tomlParser) parseKeyValue(table map[string]interface{}) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipBlank(false)
+	if p.peek() != '=' {
+		return p.errorf("expected '=' after key %q", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipBlank(false)
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := p.descend(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorf("duplicate key %q", last)
+	}
+	parent[last] = value
+	return nil
+}
+
+// parseKey parses a possibly dotted key made of bare or quoted parts
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipBlank(false)
+		var key string
+		switch c := p.peek(); {
+		case c == '"':
+			s, err := p.parseBasicString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		case c == '\'':
+			s, err := p.parseLiteralString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("expected key")
+			}
+			key = p.src[start:p.pos]
+		}
+		keys = append(keys, key)
+		p.skipBlank(false)
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) parseValue() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			return p.parseMultilineBasicString()
+		}
+		return p.parseBasicString()
+	case c == '\'':
+		if strings.HasPrefix(p.src[p.pos:], `'''`) {
+			return p.parseMultilineLiteralString()
+		}
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	case c == 0:
+		return nil, p.errorf("unexpected end of input, expected value")
+	}
+	return p.parseScalar()
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++ // "
+	var sb strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		if c == '"' {
+			p.pos++
+			return sb.String(), nil
+		}
+		if c == '\\' {
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseMultilineBasicString() (string, error) {
+	p.pos += 3
+	p.skipNewline()
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			p.pos += 3
+			// Up to two quotes directly before the delimiter belong to the string
+			for i := 0; i < 2 && p.peek() == '"'; i++ {
+				sb.WriteByte('"')
+				p.pos++
+			}
+			return sb.String(), nil
+		}
+		c := p.peek()
+		if c == '\\' {
+			// A line-ending backslash trims the newline and following whitespace
+			rest := strings.TrimLeft(p.src[p.pos+1:], " \t\r")
+			if strings.HasPrefix(rest, "\n") {
+				p.pos = len(p.src) - len(rest)
+				for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if c == '\n' {
+			p.line++
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++ // '
+	start := p.pos
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated literal string")
+		}
+		if p.peek() == '\'' {
+			s := p.src[start:p.pos]
+			p.pos++
+			return s, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseMultilineLiteralString() (string, error) {
+	p.pos += 3
+	p.skipNewline()
+	end := strings.Index(p.src[p.pos:], `'''`)
+	if end < 0 {
+		return "", p.errorf("unterminated multi-line literal string")
+	}
+	end += p.pos
+	// Up to two quotes directly before the delimiter belong to the string
+	for i := 0; i < 2 && end+3 < len(p.src) && p.src[end+3] == '\''; i++ {
+		end++
+	}
+	s := p.src[p.pos:end]
+	p.line += strings.Count(s, "\n")
+	p.pos = end + 3
+	return s, nil
+}
+
+// skipNewline skips a newline immediately following an opening delimiter
+func (p *tomlParser) skipNewline() {
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+		p.line++
+	} else if p.peek() == '\n' {
+		p.pos++
+		p.line++
+	}
+}
+
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	p.pos++ // backslash
+	if p.eof() {
+		return p.errorf("unterminated escape sequence")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'e':
+		sb.WriteByte(0x1b)
+	case '"':
+		sb.WriteByte('"')
+	case '\\':
+		sb.WriteByte('\\')
+	case 'u', 'U':
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		if p.pos+n > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape %q", p.src[p.pos:p.pos+n])
+		}
+		sb.WriteRune(rune(code))
+		p.pos += n
+	default:
+		return p.errorf("invalid escape sequence \\%c", c)
+	}
+	return nil
+}
+
+func (p *tomlParser) parseArray() ([]interface{}, error) {
+	p.pos++ // [
+	result := make([]interface{}, 0)
+	for {
+		p.skipBlank(true)
+		if p.peek() == ']' {
+			p.pos++
+			return result, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+		p.skipBlank(true)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return result, nil
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (map[string]interface{}, error) {
+	p.pos++ // {
+	table := make(map[string]interface{})
+	p.skipBlank(false)
+	if p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// parseScalar parses booleans, numbers and date/time values
+func (p *tomlParser) parseScalar() (interface{}, error) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.peek())) {
+		p.pos++
+	}
+	token := p.src[start:p.pos]
+	// Date and time may be separated by a space instead of 'T'
+	if tomlDatePattern.MatchString(token) && p.pos+1 < len(p.src) && p.src[p.pos] == ' ' &&
+		p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9' {
+		p.pos++
+		for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.peek())) {
+			p.pos++
+		}
+		token = p.src[start:p.pos]
+	}
+
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+	if len(token) >= 10 && token[4] == '-' || len(token) >= 8 && token[2] == ':' {
+		return token, nil // date, time or date-time
+	}
+
+	clean := strings.ReplaceAll(token, "_", "")
+	if len(clean) > 2 && clean[0] == '0' {
+		base := 0
+		switch clean[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			i, err := strconv.ParseInt(clean[2:], base, 64)
+			if err != nil {
+				return nil, p.errorf("invalid integer %q", token)
+			}
+			return i, nil
+		}
+	}
+	if i, err := strconv.ParseInt(clean, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(clean, 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("invalid value %q", token)
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"sync"
+	"time"
+
+	"GoFast/pkg/errorhandler"
+)
+
+// ErrCodeReloadFailed is the errorhandler code reported when a watched reload fails
+const ErrCodeReloadFailed = 2001
+
+// DefaultWatchInterval is the polling interval used when a non-positive interval is given
+const DefaultWatchInterval = 5 * time.Second
+
+// FileBacked is implemented by sources that read from files and can be watched
+type FileBacked interface {
+	Files() []string
+}
+
+// fileState is the last observed state of a watched file
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// Watcher polls the files behind a Config's sources and reloads the Config when
+// they change. Polling works on every platform and file system, including
+// network mounts and container volumes where change notifications are unreliable.
+type Watcher struct {
+	config   *Config
+	interval time.Duration
+	states   map[string]fileState
+	stop     chan struct{}
+	done     chan struct{}
+	mu       sync.Mutex
+	checkMu  sync.Mutex
+
+	// OnError, if set, is called with the *errorhandler.CustomError of every failed reload
+	OnError func(err error)
+}
+
+// NewWatcher creates a Watcher for the config; call Start to begin polling
+//
+// Parameters:
+// - config: the config to reload
+// - interval: the polling interval; zero or negative means DefaultWatchInterval
+//
+// Returns:
+// - *Watcher: the new Watcher
+func NewWatcher(config *Config, interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	w := &Watcher{
+		config:   config,
+		interval: interval,
+		states:   make(map[string]fileState),
+	}
+	for _, path := range w.files() {
+		w.states[path] = statFile(path, fileState{})
+	}
+	return w
+}
+
+// Watch creates and starts a Watcher for the config
+//
+// Parameters:
+// - interval: the polling interval; zero or negative means DefaultWatchInterval
+//
+// Returns:
+// - *Watcher: the running Watcher
+func (c *Config) Watch(interval time.Duration) *Watcher {
+	w := NewWatcher(c, interval)
+	w.Start()
+	return w
+}
+
+// Start begins polling in a background goroutine; it is a no-op if already running
+func (w *Watcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		return
+	}
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.run(w.stop, w.done)
+}
+
+// Stop stops polling and waits for the background goroutine to exit
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (w *Watcher) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Check polls the watched files once and reloads the config if any of them changed.
+// A failed reload keeps the previous configuration and is reported through
+// errorhandler.LogError, the registered errorhandler handlers and OnError.
+//
+// Returns:
+// - bool: true if a change was detected
+// - error: the reload error, if any
+func (w *Watcher) Check() (bool, error) {
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	changed := false
+	for _, path := range w.files() {
+		prev := w.states[path]
+		next := statFile(path, prev)
+		// Files that were only touched keep their hash and do not trigger a reload
+		if next.exists != prev.exists || next.sum != prev.sum {
+			changed = true
+		}
+		w.states[path] = next
+	}
+	if !changed {
+		return false, nil
+	}
+
+	if _, err := w.config.Reload(); err != nil {
+		customErr := errorhandler.NewError(ErrCodeReloadFailed, "configuration reload failed",
+			errorhandler.Error, w.files(), err)
+		errorhandler.LogError(customErr)
+		errorhandler.TriggerCustomErrorHandlers(customErr)
+		if w.OnError != nil {
+			w.OnError(customErr)
+		}
+		return true, customErr
+	}
+	return true, nil
+}
+
+// files returns the paths of all file-backed sources
+func (w *Watcher) files() []string {
+	w.config.mu.RLock()
+	defer w.config.mu.RUnlock()
+	var paths []string
+	for _, source := range w.config.sources {
+		if fb, ok := source.(FileBacked); ok {
+			paths = append(paths, fb.Files()...)
+		}
+	}
+	return paths
+}
+
+// statFile returns the current state of a file. The content hash is only
+// recomputed when the modification time or size differ from prev.
+func statFile(path string, prev fileState) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	state := fileState{exists: true, modTime: info.ModTime(), size: info.Size(), sum: prev.sum}
+	if prev.exists && state.modTime.Equal(prev.modTime) && state.size == prev.size {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileState{}
+	}
+	state.sum = sha256.Sum256(data)
+	return state
+}
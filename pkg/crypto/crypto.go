@@ -0,0 +1,273 @@
+// Package crypto provides authenticated encryption and key management helpers.
+// Ciphertexts are wrapped in a self-describing envelope that records the format
+// version, the cipher, the nonce and the key derivation parameters, so data can
+// be decrypted with nothing but the key or password that produced it.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Algorithm identifies an AEAD cipher
+type Algorithm uint8
+
+const (
+	// AES128GCM is AES-128 in Galois/Counter Mode
+	AES128GCM Algorithm = iota + 1
+	// AES256GCM is AES-256 in Galois/Counter Mode
+	AES256GCM
+	// ChaCha20Poly1305 is the RFC 8439 ChaCha20-Poly1305 construction
+	ChaCha20Poly1305
+	// XChaCha20Poly1305 is ChaCha20-Poly1305 with a 192-bit nonce, safe for random nonces at any volume
+	XChaCha20Poly1305
+)
+
+var (
+	// ErrUnsupportedAlgorithm is returned for unknown cipher or KDF identifiers
+	ErrUnsupportedAlgorithm = errors.New("crypto: unsupported algorithm")
+	// ErrInvalidKey is returned when a key has the wrong length for its algorithm
+	ErrInvalidKey = errors.New("crypto: invalid key size")
+	// ErrInvalidEnvelope is returned when ciphertext is truncated or malformed
+	ErrInvalidEnvelope = errors.New("crypto: invalid envelope")
+	// ErrAuthFailed is returned when decryption fails authentication, i.e. the key
+	// is wrong or the ciphertext has been modified
+	ErrAuthFailed = errors.New("crypto: message authentication failed")
+)
+
+// String returns the name of the algorithm
+func (a Algorithm) String() string {
+	switch a {
+	case AES128GCM:
+		return "AES-128-GCM"
+	case AES256GCM:
+		return "AES-256-GCM"
+	case ChaCha20Poly1305:
+		return "ChaCha20-Poly1305"
+	case XChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", uint8(a))
+	}
+}
+
+// KeySize returns the key length in bytes required by the algorithm
+//
+// Returns:
+// - int: the key size, or 0 for unknown algorithms
+func (a Algorithm) KeySize() int {
+	switch a {
+	case AES128GCM:
+		return 16
+	case AES256GCM:
+		return 32
+	case ChaCha20Poly1305, XChaCha20Poly1305:
+		return chacha20poly1305.KeySize
+	default:
+		return 0
+	}
+}
+
+// NewAEAD creates the cipher.AEAD for the algorithm
+//
+// Parameters:
+// - alg: the algorithm
+// - key: the key, which must be exactly alg.KeySize() bytes
+//
+// Returns:
+// - cipher.AEAD: the AEAD instance
+// - error: ErrUnsupportedAlgorithm or ErrInvalidKey
+func NewAEAD(alg Algorithm, key []byte) (cipher.AEAD, error) {
+	size := alg.KeySize()
+	if size == 0 {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if len(key) != size {
+		return nil, ErrInvalidKey
+	}
+	switch alg {
+	case AES128GCM, AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return chacha20poly1305.NewX(key)
+	}
+}
+
+// GenerateKey returns a random key of the size required by the algorithm
+//
+// Parameters:
+// - alg: the algorithm
+//
+// Returns:
+// - []byte: the key
+// - error: ErrUnsupportedAlgorithm or an error from the random source
+func GenerateKey(alg Algorithm) ([]byte, error) {
+	size := alg.KeySize()
+	if size == 0 {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	return randomBytes(size)
+}
+
+// Encrypt encrypts plaintext with a raw key and returns a sealed envelope
+//
+// Parameters:
+// - alg: the algorithm
+// - key: the key, which must be exactly alg.KeySize() bytes
+// - plaintext: the data to encrypt
+//
+// Returns:
+// - []byte: the envelope containing header, nonce and ciphertext
+// - error: an error if the key or algorithm is invalid
+func Encrypt(alg Algorithm, key, plaintext []byte) ([]byte, error) {
+	return seal(&Envelope{Version: Version, Algorithm: alg}, key, plaintext)
+}
+
+// Decrypt opens an envelope produced by Encrypt
+//
+// Parameters:
+// - key: the key used for encryption
+// - data: the envelope
+//
+// Returns:
+// - []byte: the plaintext
+// - error: ErrInvalidEnvelope, ErrAuthFailed or a key error
+func Decrypt(key, data []byte) ([]byte, error) {
+	env, err := ParseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	if env.KDF.Algorithm != KDFNone {
+		return nil, fmt.Errorf("%w: envelope requires a password", ErrInvalidEnvelope)
+	}
+	return open(env, key)
+}
+
+// EncryptWithPassword derives a key from the password and encrypts plaintext.
+// A fresh random salt is generated and stored in the envelope together with
+// the KDF parameters.
+//
+// Parameters:
+// - alg: the algorithm
+// - password: the password
+// - params: the key derivation parameters, e.g. DefaultArgon2idParams()
+// - plaintext: the data to encrypt
+//
+// Returns:
+// - []byte: the envelope
+// - error: an error if the parameters are invalid
+func EncryptWithPassword(alg Algorithm, password []byte, params KDFParams, plaintext []byte) ([]byte, error) {
+	env, key, err := newPasswordEnvelope(alg, password, params)
+	if err != nil {
+		return nil, err
+	}
+	return seal(env, key, plaintext)
+}
+
+// DecryptWithPassword opens an envelope produced by EncryptWithPassword. Envelopes whose KDF
+// parameters exceed DefaultKDFPolicy are rejected before any key is derived.
+//
+// Parameters:
+// - password: the password
+// - data: the envelope
+//
+// Returns:
+// - []byte: the plaintext
+// - error: ErrInvalidEnvelope, ErrKDFCost or ErrAuthFailed
+func DecryptWithPassword(password, data []byte) ([]byte, error) {
+	return DecryptWithPasswordPolicy(password, data, DefaultKDFPolicy())
+}
+
+// DecryptWithPasswordPolicy is like DecryptWithPassword with a caller-supplied KDFPolicy
+//
+// Parameters:
+// - password: the password
+// - data: the envelope
+// - policy: the bounds on the KDF parameters in the envelope header
+//
+// Returns:
+// - []byte: the plaintext
+// - error: ErrInvalidEnvelope, ErrKDFCost or ErrAuthFailed
+func DecryptWithPasswordPolicy(password, data []byte, policy KDFPolicy) ([]byte, error) {
+	env, err := ParseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	key, err := env.passwordKey(password, policy)
+	if err != nil {
+		return nil, err
+	}
+	return open(env, key)
+}
+
+// newPasswordEnvelope creates an envelope header with a fresh salt and derives its key
+func newPasswordEnvelope(alg Algorithm, password []byte, params KDFParams) (*Envelope, []byte, error) {
+	if params.Algorithm == KDFNone {
+		return nil, nil, fmt.Errorf("%w: password encryption requires a KDF", ErrUnsupportedAlgorithm)
+	}
+	if alg.KeySize() == 0 {
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+	salt, err := randomBytes(DefaultSaltSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := DeriveKey(password, salt, params, alg.KeySize())
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Envelope{Version: Version, Algorithm: alg, KDF: params, Salt: salt}, key, nil
+}
+
+// seal fills in the nonce and encrypts plaintext, authenticating the envelope header
+func seal(env *Envelope, key, plaintext []byte) ([]byte, error) {
+	aead, err := NewAEAD(env.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	if env.Nonce, err = randomBytes(aead.NonceSize()); err != nil {
+		return nil, err
+	}
+	header := env.header()
+	return aead.Seal(header, env.Nonce, plaintext, header), nil
+}
+
+// open decrypts the ciphertext of a parsed envelope
+func open(env *Envelope, key []byte) ([]byte, error) {
+	if env.ChunkSize != 0 {
+		return nil, fmt.Errorf("%w: streamed envelopes must be read with NewDecryptReader", ErrInvalidEnvelope)
+	}
+	aead, err := NewAEAD(env.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, env.header())
+	if err != nil {
+		return nil, ErrAuthFailed
+	}
+	return plaintext, nil
+}
+
+// randomBytes returns n bytes from crypto/rand
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,161 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Version is the current envelope format version
+const Version = 1
+
+// magic prefixes every envelope so foreign data is rejected early
+var magic = [3]byte{'G', 'F', 'E'}
+
+// flagStream marks envelopes whose ciphertext is a sequence of chunks
+const flagStream = 1 << 0
+
+// Envelope is the self-describing container around a ciphertext.
+// The binary layout is:
+//
+//	magic "GFE" | version | flags | algorithm | kdf | time (4) | memory (4) | threads |
+//	salt length | salt | nonce length | nonce | [chunk size (4)] | ciphertext
+//
+// Everything before the ciphertext is passed to the AEAD as additional data, so
+// tampering with any header field causes decryption to fail.
+type Envelope struct {
+	Version    uint8
+	Algorithm  Algorithm
+	KDF        KDFParams
+	Salt       []byte
+	Nonce      []byte // The nonce, or the nonce prefix of a stream
+	ChunkSize  uint32 // Plaintext bytes per chunk; zero for one-shot envelopes
+	Ciphertext []byte
+}
+
+// ParseEnvelope decodes an envelope without decrypting it
+//
+// Parameters:
+// - data: the encoded envelope
+//
+// Returns:
+// - *Envelope: the decoded envelope; Ciphertext aliases data
+// - error: ErrInvalidEnvelope if data is truncated or has an unknown format
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	env, n, err := parseHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	env.Ciphertext = data[n:]
+	return env, nil
+}
+
+// Marshal encodes the envelope
+//
+// Returns:
+// - []byte: the header followed by the ciphertext
+func (e *Envelope) Marshal() []byte {
+	return append(e.header(), e.Ciphertext...)
+}
+
+// header encodes all fields except the ciphertext
+func (e *Envelope) header() []byte {
+	buf := make([]byte, 0, 24+len(e.Salt)+len(e.Nonce))
+	flags := byte(0)
+	if e.ChunkSize > 0 {
+		flags |= flagStream
+	}
+	buf = append(buf, magic[:]...)
+	buf = append(buf, e.Version, flags, byte(e.Algorithm), byte(e.KDF.Algorithm))
+	buf = binary.BigEndian.AppendUint32(buf, e.KDF.Time)
+	buf = binary.BigEndian.AppendUint32(buf, e.KDF.Memory)
+	buf = append(buf, e.KDF.Threads)
+	buf = append(buf, byte(len(e.Salt)))
+	buf = append(buf, e.Salt...)
+	buf = append(buf, byte(len(e.Nonce)))
+	buf = append(buf, e.Nonce...)
+	if e.ChunkSize > 0 {
+		buf = binary.BigEndian.AppendUint32(buf, e.ChunkSize)
+	}
+	return buf
+}
+
+// parseHeader decodes the header and returns the number of bytes consumed
+func parseHeader(data []byte) (*Envelope, int, error) {
+	r := headerReader{data: data}
+	if m := r.next(3); m == nil || [3]byte(m) != magic {
+		return nil, 0, ErrInvalidEnvelope
+	}
+	fixed := r.next(13)
+	if fixed == nil {
+		return nil, 0, ErrInvalidEnvelope
+	}
+	env := &Envelope{
+		Version:   fixed[0],
+		Algorithm: Algorithm(fixed[2]),
+		KDF: KDFParams{
+			Algorithm: KDF(fixed[3]),
+			Time:      binary.BigEndian.Uint32(fixed[4:8]),
+			Memory:    binary.BigEndian.Uint32(fixed[8:12]),
+			Threads:   fixed[12],
+		},
+	}
+	if env.Version != Version {
+		return nil, 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidEnvelope, env.Version)
+	}
+	env.Salt = r.bytes()
+	env.Nonce = r.bytes()
+	if fixed[1]&flagStream != 0 {
+		if size := r.next(4); size != nil {
+			env.ChunkSize = binary.BigEndian.Uint32(size)
+		}
+		if env.ChunkSize == 0 {
+			return nil, 0, ErrInvalidEnvelope
+		}
+	}
+	if r.err {
+		return nil, 0, ErrInvalidEnvelope
+	}
+	return env, r.pos, nil
+}
+
+// passwordKey derives the envelope key from a password using the stored KDF parameters,
+// which must be within the policy
+func (e *Envelope) passwordKey(password []byte, policy KDFPolicy) ([]byte, error) {
+	if e.KDF.Algorithm == KDFNone {
+		return nil, fmt.Errorf("%w: envelope was not sealed with a password", ErrInvalidEnvelope)
+	}
+	if e.Algorithm.KeySize() == 0 {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if err := policy.Check(e.KDF); err != nil {
+		return nil, err
+	}
+	return DeriveKey(password, e.Salt, e.KDF, e.Algorithm.KeySize())
+}
+
+// headerReader is a bounds-checked cursor over an encoded header
+type headerReader struct {
+	data []byte
+	pos  int
+	err  bool
+}
+
+// next returns the next n bytes, or nil if the data is too short
+func (r *headerReader) next(n int) []byte {
+	if r.err || len(r.data)-r.pos < n {
+		r.err = true
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// bytes reads a length-prefixed byte string
+func (r *headerReader) bytes() []byte {
+	n := r.next(1)
+	if n == nil {
+		return nil
+	}
+	return r.next(int(n[0]))
+}
@@ -0,0 +1,205 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// hybridMagic prefixes envelopes produced by SealTo
+var hybridMagic = [3]byte{'G', 'F', 'H'}
+
+// Key wrapping schemes of hybrid envelopes
+const (
+	wrapRSAOAEP uint8 = iota + 1
+	wrapECDHP256
+	wrapECDHP384
+	wrapECDHP521
+	wrapX25519
+)
+
+// hybridInfo is the HKDF context string for ECDH-derived data keys
+const hybridInfo = "GoFast hybrid v1"
+
+// SealTo encrypts plaintext for the holder of a private key. A random AES-256-GCM
+// data key encrypts the message and is itself wrapped for the recipient:
+//
+//	RSA:        the data key is encrypted with RSA-OAEP (SHA-256)
+//	ECDH/ECDSA: the data key is derived with HKDF-SHA256 from an ephemeral ECDH exchange
+//
+// The result is "GFH" | version | scheme | wrapped key length (2) | wrapped key | envelope,
+// where envelope is the output of Encrypt.
+//
+// Parameters:
+// - pub: an *rsa.PublicKey, *ecdsa.PublicKey or *ecdh.PublicKey (P-256, P-384, P-521 or X25519)
+// - plaintext: the data to encrypt
+//
+// Returns:
+// - []byte: the hybrid envelope
+// - error: ErrUnsupportedKey or an encryption error
+func SealTo(pub any, plaintext []byte) ([]byte, error) {
+	var scheme uint8
+	var wrapped, dataKey []byte
+	var err error
+
+	if k, ok := pub.(*rsa.PublicKey); ok {
+		scheme = wrapRSAOAEP
+		if dataKey, err = GenerateKey(AES256GCM); err != nil {
+			return nil, err
+		}
+		if wrapped, err = EncryptOAEP(k, dataKey, hybridMagic[:]); err != nil {
+			return nil, err
+		}
+	} else {
+		recipient, err := ecdhPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		if scheme, err = ecdhScheme(recipient.Curve()); err != nil {
+			return nil, err
+		}
+		ephemeral, err := recipient.Curve().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := ephemeral.ECDH(recipient)
+		if err != nil {
+			return nil, err
+		}
+		wrapped = ephemeral.PublicKey().Bytes()
+		if dataKey, err = deriveDataKey(secret, scheme, wrapped, recipient.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	sealed, err := Encrypt(AES256GCM, dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 7+len(wrapped)+len(sealed))
+	out = append(out, hybridMagic[:]...)
+	out = append(out, Version, scheme)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(wrapped)))
+	out = append(out, wrapped...)
+	return append(out, sealed...), nil
+}
+
+// OpenWith decrypts a hybrid envelope produced by SealTo
+//
+// Parameters:
+// - priv: an *rsa.PrivateKey, *ecdsa.PrivateKey or *ecdh.PrivateKey matching the public key used by SealTo
+// - data: the hybrid envelope
+//
+// Returns:
+// - []byte: the plaintext
+// - error: ErrInvalidEnvelope, ErrAuthFailed or ErrUnsupportedKey
+func OpenWith(priv any, data []byte) ([]byte, error) {
+	if len(data) < 7 || [3]byte(data[:3]) != hybridMagic || data[3] != Version {
+		return nil, ErrInvalidEnvelope
+	}
+	scheme := data[4]
+	n := int(binary.BigEndian.Uint16(data[5:7]))
+	if len(data) < 7+n {
+		return nil, ErrInvalidEnvelope
+	}
+	wrapped, sealed := data[7:7+n], data[7+n:]
+
+	var dataKey []byte
+	var err error
+	if k, ok := priv.(*rsa.PrivateKey); ok {
+		if scheme != wrapRSAOAEP {
+			return nil, ErrUnsupportedKey
+		}
+		if dataKey, err = DecryptOAEP(k, wrapped, hybridMagic[:]); err != nil {
+			return nil, err
+		}
+	} else {
+		own, err := ecdhPrivateKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		if expected, err := ecdhScheme(own.Curve()); err != nil || expected != scheme {
+			return nil, ErrUnsupportedKey
+		}
+		ephemeral, err := own.Curve().NewPublicKey(wrapped)
+		if err != nil {
+			return nil, ErrInvalidEnvelope
+		}
+		secret, err := own.ECDH(ephemeral)
+		if err != nil {
+			return nil, ErrAuthFailed
+		}
+		if dataKey, err = deriveDataKey(secret, scheme, wrapped, own.PublicKey().Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return Decrypt(dataKey, sealed)
+}
+
+// deriveDataKey expands an ECDH shared secret into an AES-256 key. The ephemeral
+// and recipient public keys are bound into the HKDF info.
+func deriveDataKey(secret []byte, scheme uint8, ephemeral, recipient []byte) ([]byte, error) {
+	info := append([]byte(hybridInfo), scheme)
+	info = append(info, ephemeral...)
+	info = append(info, recipient...)
+
+	key := make([]byte, AES256GCM.KeySize())
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, nil, info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ecdhPublicKey converts supported public keys to *ecdh.PublicKey
+func ecdhPublicKey(pub any) (*ecdh.PublicKey, error) {
+	switch k := pub.(type) {
+	case *ecdh.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		key, err := k.ECDH()
+		if err != nil {
+			return nil, ErrUnsupportedKey
+		}
+		return key, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// ecdhPrivateKey converts supported private keys to *ecdh.PrivateKey
+func ecdhPrivateKey(priv any) (*ecdh.PrivateKey, error) {
+	switch k := priv.(type) {
+	case *ecdh.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		key, err := k.ECDH()
+		if err != nil {
+			return nil, ErrUnsupportedKey
+		}
+		return key, nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// ecdhScheme returns the wrapping scheme identifier of a curve
+func ecdhScheme(curve ecdh.Curve) (uint8, error) {
+	switch curve {
+	case ecdh.P256():
+		return wrapECDHP256, nil
+	case ecdh.P384():
+		return wrapECDHP384, nil
+	case ecdh.P521():
+		return wrapECDHP521, nil
+	case ecdh.X25519():
+		return wrapX25519, nil
+	default:
+		return 0, ErrUnsupportedKey
+	}
+}
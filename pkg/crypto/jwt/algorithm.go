@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+)
+
+// Algorithm is a JWS "alg" value
+type Algorithm string
+
+// Supported algorithms. "none" is deliberately not supported.
+const (
+	HS256 Algorithm = "HS256"
+	HS384 Algorithm = "HS384"
+	HS512 Algorithm = "HS512"
+	RS256 Algorithm = "RS256"
+	ES256 Algorithm = "ES256"
+	EdDSA Algorithm = "EdDSA"
+)
+
+// hash returns the digest function of the algorithm, or 0 for EdDSA
+func (a Algorithm) hash() stdcrypto.Hash {
+	switch a {
+	case HS384:
+		return stdcrypto.SHA384
+	case HS512:
+		return stdcrypto.SHA512
+	case EdDSA:
+		return 0
+	default:
+		return stdcrypto.SHA256
+	}
+}
+
+// valid reports whether the algorithm is supported
+func (a Algorithm) valid() bool {
+	switch a {
+	case HS256, HS384, HS512, RS256, ES256, EdDSA:
+		return true
+	}
+	return false
+}
+
+// digest hashes the signing input
+func (a Algorithm) digest(input []byte) []byte {
+	h := a.hash().New()
+	h.Write(input)
+	return h.Sum(nil)
+}
+
+// sign computes the JWS signature of the signing input. The key type must match the
+// algorithm family: []byte for HS*, *rsa.PrivateKey for RS256, *ecdsa.PrivateKey
+// on P-256 for ES256 and ed25519.PrivateKey for EdDSA.
+func (a Algorithm) sign(input []byte, key interface{}) ([]byte, error) {
+	switch a {
+	case HS256, HS384, HS512:
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return nil, ErrInvalidKey
+		}
+		mac := hmac.New(a.hash().New, secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case RS256:
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		return rsa.SignPKCS1v15(rand.Reader, k, a.hash(), a.digest(input))
+	case ES256:
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok || k.Curve != elliptic.P256() {
+			return nil, ErrInvalidKey
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, k, a.digest(input))
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed-size concatenation r || s rather than ASN.1
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	case EdDSA:
+		k, ok := key.(ed25519.PrivateKey)
+		if !ok || len(k) != ed25519.PrivateKeySize {
+			return nil, ErrInvalidKey
+		}
+		return ed25519.Sign(k, input), nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// verify checks a JWS signature. HMAC secrets are []byte; the asymmetric
+// algorithms accept the public key or, for convenience, the private key.
+func (a Algorithm) verify(input, sig []byte, key interface{}) error {
+	valid := false
+	switch a {
+	case HS256, HS384, HS512:
+		expected, err := a.sign(input, key)
+		if err != nil {
+			return err
+		}
+		valid = hmac.Equal(sig, expected)
+	case RS256:
+		k, ok := publicKey(key).(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidKey
+		}
+		valid = rsa.VerifyPKCS1v15(k, a.hash(), a.digest(input), sig) == nil
+	case ES256:
+		k, ok := publicKey(key).(*ecdsa.PublicKey)
+		if !ok || k.Curve != elliptic.P256() {
+			return ErrInvalidKey
+		}
+		if len(sig) == 64 {
+			r := new(big.Int).SetBytes(sig[:32])
+			s := new(big.Int).SetBytes(sig[32:])
+			valid = ecdsa.Verify(k, a.digest(input), r, s)
+		}
+	case EdDSA:
+		k, ok := publicKey(key).(ed25519.PublicKey)
+		if !ok || len(k) != ed25519.PublicKeySize {
+			return ErrInvalidKey
+		}
+		valid = ed25519.Verify(k, input, sig)
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+	if !valid {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// publicKey returns the public half of private keys and passes other values through
+func publicKey(key interface{}) interface{} {
+	if signer, ok := key.(stdcrypto.Signer); ok {
+		return signer.Public()
+	}
+	return key
+}
@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// NumericDate is a JSON numeric date: seconds since the Unix epoch
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate creates a NumericDate truncated to whole seconds
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - *NumericDate: the numeric date
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON encodes the date as integer seconds
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, d.Unix(), 10), nil
+}
+
+// UnmarshalJSON decodes integer or fractional seconds
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	sec, frac := math.Modf(f)
+	d.Time = time.Unix(int64(sec), int64(frac*1e9))
+	return nil
+}
+
+// Audience is the "aud" claim. It decodes from either a string or an array and
+// encodes a single audience as a plain string.
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string and several as an array
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts a string or an array of strings
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Contains reports whether the audience includes the value
+func (a Audience) Contains(value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisteredClaims are the registered claim names of RFC 7519 section 4.1.
+// Embed it in a struct to add application-specific claims:
+//
+//	type UserClaims struct {
+//		jwt.RegisteredClaims
+//		Role string `json:"role"`
+//	}
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// MapClaims holds arbitrary claims when no struct is defined
+type MapClaims map[string]interface{}
@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"errors"
+
+	"GoFast/pkg/errorhandler"
+)
+
+// errorhandler codes reported by Parse
+const (
+	CodeMalformed        = 4001
+	CodeUnsupportedAlg   = 4002
+	CodeKeyNotFound      = 4003
+	CodeInvalidSignature = 4004
+	CodeExpired          = 4005
+	CodeNotValidYet      = 4006
+	CodeInvalidClaims    = 4007
+)
+
+// Sentinel errors; the *errorhandler.CustomError values returned by Parse wrap
+// one of these, so callers can branch with errors.Is
+var (
+	ErrMalformed            = errors.New("jwt: malformed token")
+	ErrUnsupportedAlgorithm = errors.New("jwt: unsupported or disallowed algorithm")
+	ErrKeyNotFound          = errors.New("jwt: verification key not found")
+	ErrInvalidKey           = errors.New("jwt: key is invalid for the algorithm")
+	ErrSignatureInvalid     = errors.New("jwt: signature is invalid")
+	ErrExpired              = errors.New("jwt: token is expired")
+	ErrNotValidYet          = errors.New("jwt: token is not valid yet")
+	ErrInvalidIssuer        = errors.New("jwt: token has invalid issuer")
+	ErrInvalidAudience      = errors.New("jwt: token has invalid audience")
+	ErrInvalidSubject       = errors.New("jwt: token has invalid subject")
+	ErrMissingClaim         = errors.New("jwt: token is missing a required claim")
+)
+
+func init() {
+	errorhandler.RegisterLocalizedMessages("en", map[int]string{
+		CodeMalformed:        "Malformed token",
+		CodeUnsupportedAlg:   "Unsupported token algorithm",
+		CodeKeyNotFound:      "Token verification key not found",
+		CodeInvalidSignature: "Invalid token signature",
+		CodeExpired:          "Token has expired",
+		CodeNotValidYet:      "Token is not valid yet",
+		CodeInvalidClaims:    "Invalid token claims",
+	})
+	errorhandler.RegisterLocalizedMessages("zh", map[int]string{
+		CodeMalformed:        "令牌格式错误",
+		CodeUnsupportedAlg:   "不支持的令牌算法",
+		CodeKeyNotFound:      "未找到令牌验证密钥",
+		CodeInvalidSignature: "令牌签名无效",
+		CodeExpired:          "令牌已过期",
+		CodeNotValidYet:      "令牌尚未生效",
+		CodeInvalidClaims:    "令牌声明无效",
+	})
+}
+
+// newError wraps a sentinel error into an errorhandler.CustomError
+func newError(code int, err error, context interface{}) *errorhandler.CustomError {
+	return errorhandler.NewError(code, err.Error(), errorhandler.Warning, context, err)
+}
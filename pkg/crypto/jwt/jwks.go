@@ -0,0 +1,237 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK is a JSON Web Key (RFC 7517). Only public key material and symmetric
+// secrets are supported; private key members are ignored.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+	K         string `json:"k,omitempty"`
+}
+
+// KeySet is a JWK Set. It implements KeyProvider by selecting keys on "kid".
+type KeySet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseKeySet decodes a JWKS document, e.g. the body of a /.well-known/jwks.json endpoint.
+// Keys that cannot be used, such as those with an unsupported "kty" or "crv" or with
+// invalid material, are dropped as RFC 7517 section 5 recommends, so that a provider
+// publishing other key types does not break verification with the usable ones.
+//
+// Parameters:
+// - data: the JSON document
+//
+// Returns:
+// - *KeySet: the key set holding the usable keys
+// - error: an error if the document is invalid or none of its keys can be used
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var doc KeySet
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	ks := &KeySet{Keys: make([]JWK, 0, len(doc.Keys))}
+	var firstErr error
+	for _, jwk := range doc.Keys {
+		if _, err := jwk.Key(); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("jwt: key %q: %w", jwk.KeyID, err)
+			}
+			continue
+		}
+		ks.Keys = append(ks.Keys, jwk)
+	}
+	if len(ks.Keys) == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%w: key set is empty", ErrInvalidKey)
+		}
+		return nil, firstErr
+	}
+	return ks, nil
+}
+
+// Lookup returns the key with the given ID
+//
+// Parameters:
+// - kid: the key ID
+//
+// Returns:
+// - *JWK: the key
+// - bool: true if the key was found
+func (ks *KeySet) Lookup(kid string) (*JWK, bool) {
+	for i := range ks.Keys {
+		if ks.Keys[i].KeyID == kid {
+			return &ks.Keys[i], true
+		}
+	}
+	return nil, false
+}
+
+// VerificationKey selects the key named by the token's "kid" header. A token
+// without "kid" is accepted only when the set holds exactly one key. Keys whose
+// "use" is not "sig" or whose "alg" differs from the token are never selected.
+func (ks *KeySet) VerificationKey(header Header) (interface{}, error) {
+	var jwk *JWK
+	if header.KeyID == "" {
+		if len(ks.Keys) != 1 {
+			return nil, errors.New("token has no kid and the key set is ambiguous")
+		}
+		jwk = &ks.Keys[0]
+	} else {
+		var ok bool
+		if jwk, ok = ks.Lookup(header.KeyID); !ok {
+			return nil, fmt.Errorf("no key with kid %q", header.KeyID)
+		}
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return nil, fmt.Errorf("key %q is not a signing key", jwk.KeyID)
+	}
+	if jwk.Algorithm != "" && Algorithm(jwk.Algorithm) != header.Algorithm {
+		return nil, fmt.Errorf("key %q is restricted to %s", jwk.KeyID, jwk.Algorithm)
+	}
+	return jwk.Key()
+}
+
+// Key converts the JWK into a Go key
+//
+// Returns:
+// - interface{}: an *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte
+// - error: an error if the key type is unsupported or the material is invalid
+func (k *JWK) Key() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, ErrInvalidKey
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, size := jwkCurve(k.Curve)
+		if curve == nil {
+			return nil, ErrInvalidKey
+		}
+		x, err := decodeFixed(k.X, size)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeFixed(k.Y, size)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		// ECDH conversion validates that the point lies on the curve
+		if _, err := key.ECDH(); err != nil {
+			return nil, ErrInvalidKey
+		}
+		return key, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, ErrInvalidKey
+		}
+		x, err := decodeFixed(k.X, ed25519.PublicKeySize)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		secret, err := decodeSegment(k.K)
+		if err != nil || len(secret) == 0 {
+			return nil, ErrInvalidKey
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported kty %q", ErrInvalidKey, k.KeyType)
+	}
+}
+
+// NewJWK creates the JWK of a key, for publishing in a key set. Private keys are
+// reduced to their public half; []byte secrets become "oct" keys.
+//
+// Parameters:
+// - kid: the key ID
+// - key: the key
+//
+// Returns:
+// - JWK: the JWK
+// - error: ErrInvalidKey for unsupported key types
+func NewJWK(kid string, key interface{}) (JWK, error) {
+	jwk := JWK{KeyID: kid}
+	switch k := publicKey(key).(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeSegment(k.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = k.Curve.Params().Name
+		jwk.X = encodeSegment(k.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeSegment(k.Y.FillBytes(make([]byte, size)))
+		if curve, _ := jwkCurve(jwk.Curve); curve == nil {
+			return JWK{}, ErrInvalidKey
+		}
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeSegment(k)
+	case []byte:
+		jwk.KeyType = "oct"
+		jwk.K = encodeSegment(k)
+	default:
+		return JWK{}, ErrInvalidKey
+	}
+	return jwk, nil
+}
+
+// jwkCurve maps a JWK "crv" name to its curve and coordinate size
+func jwkCurve(name string) (elliptic.Curve, int) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), 32
+	case "P-384":
+		return elliptic.P384(), 48
+	case "P-521":
+		return elliptic.P521(), 66
+	default:
+		return nil, 0
+	}
+}
+
+// decodeBigInt decodes a base64url unsigned big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := decodeSegment(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidKey
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// decodeFixed decodes a base64url value of an exact length
+func decodeFixed(s string, size int) ([]byte, error) {
+	b, err := decodeSegment(s)
+	if err != nil || len(b) != size {
+		return nil, ErrInvalidKey
+	}
+	return b, nil
+}
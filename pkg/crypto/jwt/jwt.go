@@ -0,0 +1,308 @@
+// Package jwt creates and verifies JSON Web Tokens (RFC 7519) in JWS compact
+// serialization. Verification failures are returned as *errorhandler.CustomError
+// values that wrap the sentinel errors of this package, so callers can tell an
+// expired token from a forged one with errors.Is.
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+// Header is the JOSE header of a token
+type Header struct {
+	Algorithm Algorithm `json:"alg"`
+	Type      string    `json:"typ,omitempty"`
+	KeyID     string    `json:"kid,omitempty"`
+}
+
+// Token is a parsed and verified token
+type Token struct {
+	Raw       string
+	Header    Header
+	Claims    RegisteredClaims
+	Signature []byte
+}
+
+// Signer issues tokens with a fixed algorithm and key
+type Signer struct {
+	Algorithm Algorithm
+	Key       interface{} // []byte, *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey
+	KeyID     string      // Optional "kid" header, used by verifiers to select the key
+}
+
+// NewSigner creates a Signer
+//
+// Parameters:
+// - alg: the signing algorithm
+// - key: the signing key matching the algorithm
+//
+// Returns:
+// - *Signer: the new Signer
+func NewSigner(alg Algorithm, key interface{}) *Signer {
+	return &Signer{Algorithm: alg, Key: key}
+}
+
+// WithKeyID sets the "kid" header and returns the Signer
+func (s *Signer) WithKeyID(kid string) *Signer {
+	s.KeyID = kid
+	return s
+}
+
+// Sign encodes and signs the claims
+//
+// Parameters:
+// - claims: a struct embedding RegisteredClaims, a MapClaims or any JSON-encodable value
+//
+// Returns:
+// - string: the compact token
+// - error: ErrUnsupportedAlgorithm, ErrInvalidKey or a JSON error
+func (s *Signer) Sign(claims interface{}) (string, error) {
+	if !s.Algorithm.valid() {
+		return "", ErrUnsupportedAlgorithm
+	}
+	header, err := json.Marshal(Header{Algorithm: s.Algorithm, Type: "JWT", KeyID: s.KeyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := encodeSegment(header) + "." + encodeSegment(payload)
+	sig, err := s.Algorithm.sign([]byte(input), s.Key)
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encodeSegment(sig), nil
+}
+
+// Sign is a shortcut for NewSigner(alg, key).Sign(claims)
+//
+// Parameters:
+// - alg: the signing algorithm
+// - key: the signing key
+// - claims: the claims
+//
+// Returns:
+// - string: the compact token
+// - error: an error if signing fails
+func Sign(alg Algorithm, key interface{}, claims interface{}) (string, error) {
+	return NewSigner(alg, key).Sign(claims)
+}
+
+// KeyProvider selects the verification key for a token
+type KeyProvider interface {
+	VerificationKey(header Header) (interface{}, error)
+}
+
+// StaticKey is a KeyProvider that always returns the same key
+type StaticKey struct {
+	Key interface{}
+}
+
+// VerificationKey returns the static key
+func (k StaticKey) VerificationKey(Header) (interface{}, error) {
+	return k.Key, nil
+}
+
+// Validator verifies token signatures and registered claims
+type Validator struct {
+	Keys       KeyProvider      // Source of verification keys
+	Algorithms []Algorithm      // Accepted algorithms; required to rule out algorithm substitution
+	Leeway     time.Duration    // Tolerated clock skew for exp, nbf and iat
+	Issuer     string           // Required "iss" value, if set
+	Audience   string           // Required "aud" entry, if set
+	Subject    string           // Required "sub" value, if set
+	RequireExp bool             // Reject tokens without "exp"
+	Now        func() time.Time // Time source; datetime.GetCurrentTime if nil
+}
+
+// NewValidator creates a Validator for a single key
+//
+// Parameters:
+// - key: the HMAC secret or public key
+// - algorithms: the accepted algorithms
+//
+// Returns:
+// - *Validator: the new Validator
+func NewValidator(key interface{}, algorithms ...Algorithm) *Validator {
+	return &Validator{Keys: StaticKey{Key: key}, Algorithms: algorithms}
+}
+
+// Parse verifies a token and decodes its claims
+//
+// Parameters:
+// - token: the compact token
+// - claims: a pointer the payload is decoded into (e.g. *MapClaims); may be nil
+//
+// Returns:
+// - *Token: the verified token
+// - error: an *errorhandler.CustomError wrapping one of the package sentinel errors
+func (v *Validator) Parse(token string, claims interface{}) (*Token, error) {
+	parsed, payload, err := decode(token)
+	if err != nil {
+		return nil, err
+	}
+	if !v.allowed(parsed.Header.Algorithm) {
+		return nil, newError(CodeUnsupportedAlg, ErrUnsupportedAlgorithm, parsed.Header.Algorithm)
+	}
+	if v.Keys == nil {
+		return nil, newError(CodeKeyNotFound, ErrKeyNotFound, parsed.Header.KeyID)
+	}
+	key, err := v.Keys.VerificationKey(parsed.Header)
+	if err != nil {
+		return nil, newError(CodeKeyNotFound, ErrKeyNotFound, err)
+	}
+	input := token[:strings.LastIndexByte(token, '.')]
+	if err := parsed.Header.Algorithm.verify([]byte(input), parsed.Signature, key); err != nil {
+		return nil, newError(CodeInvalidSignature, ErrSignatureInvalid, err)
+	}
+
+	if err := v.validate(&parsed.Claims); err != nil {
+		return nil, err
+	}
+	if claims != nil {
+		if err := unmarshal(payload, claims); err != nil {
+			return nil, newError(CodeMalformed, ErrMalformed, err)
+		}
+	}
+	return parsed, nil
+}
+
+// Parse verifies a token against a single key
+//
+// Parameters:
+// - token: the compact token
+// - key: the HMAC secret or public key
+// - claims: a pointer the payload is decoded into; may be nil
+// - algorithms: the accepted algorithms
+//
+// Returns:
+// - *Token: the verified token
+// - error: an *errorhandler.CustomError, see Validator.Parse
+func Parse(token string, key interface{}, claims interface{}, algorithms ...Algorithm) (*Token, error) {
+	return NewValidator(key, algorithms...).Parse(token, claims)
+}
+
+// ParseUnverified decodes a token without checking its signature or claims. Use it
+// only to inspect tokens, e.g. to read the issuer before choosing a key set.
+//
+// Parameters:
+// - token: the compact token
+// - claims: a pointer the payload is decoded into; may be nil
+//
+// Returns:
+// - *Token: the decoded token
+// - error: an *errorhandler.CustomError wrapping ErrMalformed
+func ParseUnverified(token string, claims interface{}) (*Token, error) {
+	parsed, payload, err := decode(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims != nil {
+		if err := unmarshal(payload, claims); err != nil {
+			return nil, newError(CodeMalformed, ErrMalformed, err)
+		}
+	}
+	return parsed, nil
+}
+
+// allowed checks the header algorithm against the accepted list
+func (v *Validator) allowed(alg Algorithm) bool {
+	if !alg.valid() {
+		return false
+	}
+	for _, a := range v.Algorithms {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the time-based and identity claims
+func (v *Validator) validate(c *RegisteredClaims) error {
+	now := datetime.GetCurrentTime()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	if c.ExpiresAt == nil && v.RequireExp {
+		return newError(CodeInvalidClaims, ErrMissingClaim, "exp")
+	}
+	// A token is expired once now >= exp, tolerating the leeway
+	if c.ExpiresAt != nil && !now.Before(datetime.DateAdd(c.ExpiresAt.Time, v.Leeway)) {
+		return newError(CodeExpired, ErrExpired, c.ExpiresAt.Time)
+	}
+	if c.NotBefore != nil && datetime.DateAdd(now, v.Leeway).Before(c.NotBefore.Time) {
+		return newError(CodeNotValidYet, ErrNotValidYet, c.NotBefore.Time)
+	}
+	if c.IssuedAt != nil && datetime.DateAdd(now, v.Leeway).Before(c.IssuedAt.Time) {
+		return newError(CodeNotValidYet, ErrNotValidYet, c.IssuedAt.Time)
+	}
+
+	if v.Issuer != "" && c.Issuer != v.Issuer {
+		return newError(CodeInvalidClaims, ErrInvalidIssuer, c.Issuer)
+	}
+	if v.Audience != "" && !c.Audience.Contains(v.Audience) {
+		return newError(CodeInvalidClaims, ErrInvalidAudience, c.Audience)
+	}
+	if v.Subject != "" && c.Subject != v.Subject {
+		return newError(CodeInvalidClaims, ErrInvalidSubject, c.Subject)
+	}
+	return nil
+}
+
+// decode splits a token and decodes its header, registered claims and signature
+func decode(token string) (*Token, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, "token must have three segments")
+	}
+	parsed := &Token{Raw: token}
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, err)
+	}
+	if err := json.Unmarshal(header, &parsed.Header); err != nil {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, err)
+	}
+	if parsed.Header.Algorithm == "" {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, "missing alg header")
+	}
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, err)
+	}
+	if err := json.Unmarshal(payload, &parsed.Claims); err != nil {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, err)
+	}
+	if parsed.Signature, err = decodeSegment(parts[2]); err != nil {
+		return nil, nil, newError(CodeMalformed, ErrMalformed, err)
+	}
+	return parsed, payload, nil
+}
+
+// unmarshal decodes the payload keeping numbers exact
+func unmarshal(payload []byte, claims interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	return dec.Decode(claims)
+}
+
+// encodeSegment encodes base64url without padding
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSegment decodes base64url, tolerating padding
+func decodeSegment(seg string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(seg, "="))
+}
@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF identifies a password-based key derivation function
+type KDF uint8
+
+const (
+	// KDFNone means the envelope was sealed with a raw key
+	KDFNone KDF = iota
+	// KDFPBKDF2 is PBKDF2 with HMAC-SHA256
+	KDFPBKDF2
+	// KDFScrypt is scrypt
+	KDFScrypt
+	// KDFArgon2id is Argon2id
+	KDFArgon2id
+)
+
+// ErrKDFCost is returned when the KDF parameters of an envelope exceed the KDFPolicy of the caller
+var ErrKDFCost = errors.New("crypto: KDF parameters exceed policy")
+
+// DefaultSaltSize is the salt length used for password-based envelopes
+const DefaultSaltSize = 16
+
+// KDFParams are the cost parameters of a key derivation function. The meaning of
+// the numeric fields depends on the algorithm:
+//
+//	PBKDF2:   Time = iterations
+//	scrypt:   Time = log2(N), Memory = r, Threads = p
+//	Argon2id: Time = passes, Memory = KiB of memory, Threads = lanes
+type KDFParams struct {
+	Algorithm KDF
+	Time      uint32
+	Memory    uint32
+	Threads   uint8
+}
+
+// DefaultPBKDF2Params returns PBKDF2-HMAC-SHA256 parameters following the OWASP recommendation
+func DefaultPBKDF2Params() KDFParams {
+	return KDFParams{Algorithm: KDFPBKDF2, Time: 600000}
+}
+
+// DefaultScryptParams returns scrypt parameters (N=2^15, r=8, p=1)
+func DefaultScryptParams() KDFParams {
+	return KDFParams{Algorithm: KDFScrypt, Time: 15, Memory: 8, Threads: 1}
+}
+
+// DefaultArgon2idParams returns Argon2id parameters (t=3, m=64MiB, p=4) as recommended by RFC 9106
+func DefaultArgon2idParams() KDFParams {
+	return KDFParams{Algorithm: KDFArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+// String returns the name of the KDF
+func (k KDF) String() string {
+	switch k {
+	case KDFNone:
+		return "none"
+	case KDFPBKDF2:
+		return "PBKDF2-SHA256"
+	case KDFScrypt:
+		return "scrypt"
+	case KDFArgon2id:
+		return "Argon2id"
+	default:
+		return fmt.Sprintf("KDF(%d)", uint8(k))
+	}
+}
+
+// DeriveKey derives a key of the given length from a password and salt
+//
+// Parameters:
+// - password: the password
+// - salt: the salt; use at least DefaultSaltSize random bytes
+// - params: the KDF and its cost parameters
+// - keyLen: the length of the derived key in bytes
+//
+// Returns:
+// - []byte: the derived key
+// - error: an error if the parameters are invalid
+func DeriveKey(password, salt []byte, params KDFParams, keyLen int) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	switch params.Algorithm {
+	case KDFPBKDF2:
+		return pbkdf2.Key(password, salt, int(params.Time), keyLen, sha256.New), nil
+	case KDFScrypt:
+		return scrypt.Key(password, salt, 1<<params.Time, int(params.Memory), int(params.Threads), keyLen)
+	default:
+		return argon2.IDKey(password, salt, params.Time, params.Memory, params.Threads, uint32(keyLen)), nil
+	}
+}
+
+// Validate checks the parameters are usable and not trivially weak
+func (p KDFParams) Validate() error {
+	switch p.Algorithm {
+	case KDFPBKDF2:
+		if p.Time < 1000 {
+			return fmt.Errorf("crypto: PBKDF2 requires at least 1000 iterations")
+		}
+	case KDFScrypt:
+		if p.Time < 10 || p.Time > 30 || p.Memory == 0 || p.Threads == 0 {
+			return fmt.Errorf("crypto: invalid scrypt parameters")
+		}
+	case KDFArgon2id:
+		if p.Time == 0 || p.Threads == 0 || p.Memory < 8*uint32(p.Threads) {
+			return fmt.Errorf("crypto: invalid Argon2id parameters")
+		}
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+	return nil
+}
+
+// KDFPolicy bounds the KDF cost parameters accepted when decrypting. The parameters of a
+// password envelope are stored in its header, so without a bound a crafted envelope could make
+// decryption exhaust memory or CPU.
+type KDFPolicy struct {
+	MaxIterations uint32 // PBKDF2 iterations
+	MaxPasses     uint32 // Argon2id passes over memory
+	MaxMemory     uint32 // KiB of memory for Argon2id and scrypt (128*N*r bytes)
+	MaxThreads    uint8  // Argon2id lanes and scrypt p
+}
+
+// DefaultKDFPolicy returns the policy used by DecryptWithPassword and NewPasswordDecryptReader:
+// up to 10 million PBKDF2 iterations, 10 Argon2id passes, 256 MiB of memory and 16 threads,
+// which admits all the default parameters with room to spare
+func DefaultKDFPolicy() KDFPolicy {
+	return KDFPolicy{MaxIterations: 10000000, MaxPasses: 10, MaxMemory: 256 * 1024, MaxThreads: 16}
+}
+
+// Check reports whether the parameters are valid and within the policy
+//
+// Parameters:
+// - params: the parameters, e.g. from an envelope header
+//
+// Returns:
+// - error: ErrKDFCost if a cost exceeds the policy, or an error from KDFParams.Validate
+func (p KDFPolicy) Check(params KDFParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	switch params.Algorithm {
+	case KDFPBKDF2:
+		if params.Time > p.MaxIterations {
+			return fmt.Errorf("%w: %d PBKDF2 iterations", ErrKDFCost, params.Time)
+		}
+	case KDFScrypt:
+		// 128*N*r bytes, computed in KiB to avoid overflow
+		if memory := uint64(1) << params.Time / 8 * uint64(params.Memory); memory > uint64(p.MaxMemory) || params.Threads > p.MaxThreads {
+			return fmt.Errorf("%w: scrypt N=2^%d r=%d p=%d", ErrKDFCost, params.Time, params.Memory, params.Threads)
+		}
+	case KDFArgon2id:
+		if params.Time > p.MaxPasses || params.Memory > p.MaxMemory || params.Threads > p.MaxThreads {
+			return fmt.Errorf("%w: Argon2id t=%d m=%d p=%d", ErrKDFCost, params.Time, params.Memory, params.Threads)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package crypto
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// KeyFormat selects the DER encoding used for a key
+type KeyFormat int
+
+const (
+	// PKCS8 is the generic private key format, supported for every key type
+	PKCS8 KeyFormat = iota
+	// PKIX is the generic public key format (SubjectPublicKeyInfo), supported for every key type
+	PKIX
+	// PKCS1 is the RSA-only format for private and public keys
+	PKCS1
+	// SEC1 is the EC-only private key format
+	SEC1
+)
+
+// PEM block types
+const (
+	pemPrivateKey    = "PRIVATE KEY"
+	pemPublicKey     = "PUBLIC KEY"
+	pemRSAPrivateKey = "RSA PRIVATE KEY"
+	pemRSAPublicKey  = "RSA PUBLIC KEY"
+	pemECPrivateKey  = "EC PRIVATE KEY"
+	pemCertificate   = "CERTIFICATE"
+)
+
+var (
+	// ErrUnsupportedKey is returned for key types or formats an operation cannot handle
+	ErrUnsupportedKey = errors.New("crypto: unsupported key type")
+	// ErrInvalidPEM is returned when no usable PEM block is found
+	ErrInvalidPEM = errors.New("crypto: invalid PEM data")
+)
+
+// GenerateRSAKey generates an RSA private key
+//
+// Parameters:
+// - bits: the modulus size, at least 2048
+//
+// Returns:
+// - *rsa.PrivateKey: the private key
+// - error: an error if bits is too small
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	if bits < 2048 {
+		return nil, fmt.Errorf("crypto: RSA keys must be at least 2048 bits")
+	}
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// GenerateECDSAKey generates an ECDSA private key
+//
+// Parameters:
+// - curve: the curve, e.g. elliptic.P256()
+//
+// Returns:
+// - *ecdsa.PrivateKey: the private key
+// - error: an error from the random source
+func GenerateECDSAKey(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(curve, rand.Reader)
+}
+
+// GenerateEd25519Key generates an Ed25519 private key
+//
+// Returns:
+// - ed25519.PrivateKey: the private key; its Public method returns the public key
+// - error: an error from the random source
+func GenerateEd25519Key() (ed25519.PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	return priv, err
+}
+
+// GenerateX25519Key generates an X25519 key for use with SealTo and OpenWith
+//
+// Returns:
+// - *ecdh.PrivateKey: the private key
+// - error: an error from the random source
+func GenerateX25519Key() (*ecdh.PrivateKey, error) {
+	return ecdh.X25519().GenerateKey(rand.Reader)
+}
+
+// PublicKey returns the public half of a private key
+//
+// Parameters:
+// - priv: an *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey or *ecdh.PrivateKey
+//
+// Returns:
+// - any: the public key
+// - error: ErrUnsupportedKey for other types
+func PublicKey(priv any) (any, error) {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey, nil
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey, nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
+	case *ecdh.PrivateKey:
+		return k.PublicKey(), nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// MarshalPrivateKeyDER encodes a private key as DER
+//
+// Parameters:
+// - priv: the private key
+// - format: PKCS8, PKCS1 (RSA only) or SEC1 (ECDSA only)
+//
+// Returns:
+// - []byte: the DER bytes
+// - error: ErrUnsupportedKey if the format does not fit the key
+func MarshalPrivateKeyDER(priv any, format KeyFormat) ([]byte, error) {
+	switch format {
+	case PKCS8:
+		return x509.MarshalPKCS8PrivateKey(priv)
+	case PKCS1:
+		if k, ok := priv.(*rsa.PrivateKey); ok {
+			return x509.MarshalPKCS1PrivateKey(k), nil
+		}
+	case SEC1:
+		if k, ok := priv.(*ecdsa.PrivateKey); ok {
+			return x509.MarshalECPrivateKey(k)
+		}
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// MarshalPublicKeyDER encodes a public key as DER
+//
+// Parameters:
+// - pub: the public key
+// - format: PKIX or PKCS1 (RSA only)
+//
+// Returns:
+// - []byte: the DER bytes
+// - error: ErrUnsupportedKey if the format does not fit the key
+func MarshalPublicKeyDER(pub any, format KeyFormat) ([]byte, error) {
+	switch format {
+	case PKIX:
+		return x509.MarshalPKIXPublicKey(pub)
+	case PKCS1:
+		if k, ok := pub.(*rsa.PublicKey); ok {
+			return x509.MarshalPKCS1PublicKey(k), nil
+		}
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// MarshalPrivateKeyPEM encodes a private key as PEM, using the block type that matches the format
+//
+// Parameters:
+// - priv: the private key
+// - format: PKCS8, PKCS1 (RSA only) or SEC1 (ECDSA only)
+//
+// Returns:
+// - []byte: the PEM bytes
+// - error: ErrUnsupportedKey if the format does not fit the key
+func MarshalPrivateKeyPEM(priv any, format KeyFormat) ([]byte, error) {
+	der, err := MarshalPrivateKeyDER(priv, format)
+	if err != nil {
+		return nil, err
+	}
+	blockType := pemPrivateKey
+	switch format {
+	case PKCS1:
+		blockType = pemRSAPrivateKey
+	case SEC1:
+		blockType = pemECPrivateKey
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM encodes a public key as PEM
+//
+// Parameters:
+// - pub: the public key
+// - format: PKIX or PKCS1 (RSA only)
+//
+// Returns:
+// - []byte: the PEM bytes
+// - error: ErrUnsupportedKey if the format does not fit the key
+func MarshalPublicKeyPEM(pub any, format KeyFormat) ([]byte, error) {
+	der, err := MarshalPublicKeyDER(pub, format)
+	if err != nil {
+		return nil, err
+	}
+	blockType := pemPublicKey
+	if format == PKCS1 {
+		blockType = pemRSAPublicKey
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), nil
+}
+
+// ParsePrivateKeyDER decodes a PKCS#8, PKCS#1 or SEC1 private key
+//
+// Parameters:
+// - der: the DER bytes
+//
+// Returns:
+// - any: an *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey or *ecdh.PrivateKey
+// - error: an error if der is none of the supported formats
+func ParsePrivateKeyDER(der []byte) (any, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unrecognized private key encoding", ErrUnsupportedKey)
+}
+
+// ParsePublicKeyDER decodes a PKIX or PKCS#1 public key
+//
+// Parameters:
+// - der: the DER bytes
+//
+// Returns:
+// - any: an *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or *ecdh.PublicKey
+// - error: an error if der is none of the supported formats
+func ParsePublicKeyDER(der []byte) (any, error) {
+	if key, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unrecognized public key encoding", ErrUnsupportedKey)
+}
+
+// ParsePrivateKeyPEM decodes the first private key block in PEM data
+//
+// Parameters:
+// - data: the PEM data
+//
+// Returns:
+// - any: the private key
+// - error: ErrInvalidPEM if no private key block is found
+func ParsePrivateKeyPEM(data []byte) (any, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, ErrInvalidPEM
+		}
+		switch block.Type {
+		case pemPrivateKey, pemRSAPrivateKey, pemECPrivateKey:
+			return ParsePrivateKeyDER(block.Bytes)
+		}
+	}
+}
+
+// ParsePublicKeyPEM decodes the first public key or certificate block in PEM data.
+// For certificates the subject public key is returned.
+//
+// Parameters:
+// - data: the PEM data
+//
+// Returns:
+// - any: the public key
+// - error: ErrInvalidPEM if no public key block is found
+func ParsePublicKeyPEM(data []byte) (any, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, ErrInvalidPEM
+		}
+		switch block.Type {
+		case pemPublicKey, pemRSAPublicKey:
+			return ParsePublicKeyDER(block.Bytes)
+		case pemCertificate:
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return cert.PublicKey, nil
+		}
+	}
+}
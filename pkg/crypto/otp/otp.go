@@ -0,0 +1,318 @@
+// Package otp implements HMAC-based (RFC 4226) and time-based (RFC 6238) one-time
+// passwords as used by authenticator apps, including otpauth:// key URIs.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"strings"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+// Algorithm is the HMAC hash of the OTP
+type Algorithm string
+
+const (
+	// SHA1 is the RFC 4226 default and the only algorithm all authenticator apps support
+	SHA1 Algorithm = "SHA1"
+	// SHA256 is allowed by RFC 6238
+	SHA256 Algorithm = "SHA256"
+	// SHA512 is allowed by RFC 6238
+	SHA512 Algorithm = "SHA512"
+)
+
+const (
+	// DefaultDigits is the default code length
+	DefaultDigits = 6
+	// DefaultPeriod is the default TOTP time step
+	DefaultPeriod = 30 * time.Second
+	// DefaultSecretSize is the secret length generated by GenerateSecret, 160 bits as recommended by RFC 4226
+	DefaultSecretSize = 20
+)
+
+var (
+	// ErrInvalidSecret is returned for empty or badly encoded secrets
+	ErrInvalidSecret = errors.New("otp: invalid secret")
+	// ErrInvalidDigits is returned for code lengths outside 6 to 10
+	ErrInvalidDigits = errors.New("otp: digits must be between 6 and 10")
+	// ErrUnsupportedAlgorithm is returned for unknown hash algorithms
+	ErrUnsupportedAlgorithm = errors.New("otp: unsupported algorithm")
+)
+
+// pow10 holds the modulus for each code length
+var pow10 = [...]uint64{1, 10, 100, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10}
+
+// hash returns the hash constructor of the algorithm
+func (a Algorithm) hash() (func() hash.Hash, error) {
+	switch Algorithm(strings.ToUpper(string(a))) {
+	case SHA1, "":
+		return sha1.New, nil
+	case SHA256:
+		return sha256.New, nil
+	case SHA512:
+		return sha512.New, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+// HOTP generates counter-based one-time passwords
+type HOTP struct {
+	Secret    []byte    // Shared secret
+	Digits    int       // Code length, DefaultDigits if zero
+	Algorithm Algorithm // HMAC hash, SHA1 if empty
+}
+
+// NewHOTP creates an HOTP with the default digits and algorithm
+//
+// Parameters:
+// - secret: the shared secret
+//
+// Returns:
+// - *HOTP: the new HOTP
+func NewHOTP(secret []byte) *HOTP {
+	return &HOTP{Secret: secret}
+}
+
+// Generate computes the code for a counter value
+//
+// Parameters:
+// - counter: the moving factor
+//
+// Returns:
+// - string: the zero-padded code
+// - error: an error if the configuration is invalid
+func (h *HOTP) Generate(counter uint64) (string, error) {
+	return generate(h.Secret, h.Algorithm, h.Digits, counter)
+}
+
+// Verify checks a code against the counter and the lookAhead following values, to
+// resynchronize with tokens that were generated but not used
+//
+// Parameters:
+// - code: the code entered by the user
+// - counter: the next expected counter value
+// - lookAhead: how many further counter values are accepted
+//
+// Returns:
+// - uint64: the counter value to store for the next verification, i.e. the matched value + 1
+// - bool: true if the code matched
+func (h *HOTP) Verify(code string, counter uint64, lookAhead int) (uint64, bool) {
+	for i := 0; i <= lookAhead; i++ {
+		expected, err := h.Generate(counter + uint64(i))
+		if err != nil {
+			return counter, false
+		}
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return counter + uint64(i) + 1, true
+		}
+	}
+	return counter, false
+}
+
+// TOTP generates time-based one-time passwords
+type TOTP struct {
+	Secret    []byte           // Shared secret
+	Digits    int              // Code length, DefaultDigits if zero
+	Algorithm Algorithm        // HMAC hash, SHA1 if empty
+	Period    time.Duration    // Time step, DefaultPeriod if zero
+	Skew      int              // Number of steps accepted before and after the current one
+	Now       func() time.Time // Time source; datetime.GetCurrentTime if nil
+}
+
+// NewTOTP creates a TOTP with the default digits, algorithm and 30 second period,
+// accepting one step of clock skew in either direction
+//
+// Parameters:
+// - secret: the shared secret
+//
+// Returns:
+// - *TOTP: the new TOTP
+func NewTOTP(secret []byte) *TOTP {
+	return &TOTP{Secret: secret, Skew: 1}
+}
+
+// Step returns the time step number of a time
+//
+// Parameters:
+// - tm: the time
+//
+// Returns:
+// - uint64: the number of periods since the Unix epoch
+func (t *TOTP) Step(tm time.Time) uint64 {
+	return uint64(tm.Unix()) / uint64(t.period()/time.Second)
+}
+
+// GenerateAt computes the code valid at a time
+//
+// Parameters:
+// - tm: the time
+//
+// Returns:
+// - string: the zero-padded code
+// - error: an error if the configuration is invalid
+func (t *TOTP) GenerateAt(tm time.Time) (string, error) {
+	return generate(t.Secret, t.Algorithm, t.Digits, t.Step(tm))
+}
+
+// Generate computes the current code
+//
+// Returns:
+// - string: the zero-padded code
+// - error: an error if the configuration is invalid
+func (t *TOTP) Generate() (string, error) {
+	return t.GenerateAt(t.now())
+}
+
+// Remaining returns how long the current code stays valid
+func (t *TOTP) Remaining() time.Duration {
+	now := t.now()
+	period := t.period()
+	next := time.Unix(int64((t.Step(now)+1)*uint64(period/time.Second)), 0)
+	return next.Sub(now)
+}
+
+// VerifyAt checks a code at a time, accepting Skew steps in either direction. The
+// matched step is returned so callers can reject a code that is replayed within
+// its validity window by storing the last accepted step.
+//
+// Parameters:
+// - code: the code entered by the user
+// - tm: the verification time
+//
+// Returns:
+// - uint64: the matched time step
+// - bool: true if the code matched
+func (t *TOTP) VerifyAt(code string, tm time.Time) (uint64, bool) {
+	step := t.Step(tm)
+	for i := -t.Skew; i <= t.Skew; i++ {
+		if i < 0 && uint64(-i) > step {
+			continue
+		}
+		candidate := uint64(int64(step) + int64(i))
+		expected, err := generate(t.Secret, t.Algorithm, t.Digits, candidate)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
+
+// Verify checks a code at the current time
+//
+// Parameters:
+// - code: the code entered by the user
+//
+// Returns:
+// - bool: true if the code matched
+func (t *TOTP) Verify(code string) bool {
+	_, ok := t.VerifyAt(code, t.now())
+	return ok
+}
+
+// period returns the configured or default time step
+func (t *TOTP) period() time.Duration {
+	if t.Period < time.Second {
+		return DefaultPeriod
+	}
+	return t.Period.Truncate(time.Second)
+}
+
+// now returns the current time of the configured time source
+func (t *TOTP) now() time.Time {
+	if t.Now != nil {
+		return t.Now()
+	}
+	return datetime.GetCurrentTime()
+}
+
+// generate implements the RFC 4226 HOTP algorithm with dynamic truncation
+func generate(secret []byte, alg Algorithm, digits int, counter uint64) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrInvalidSecret
+	}
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+	if digits < 6 || digits > 10 {
+		return "", ErrInvalidDigits
+	}
+	newHash, err := alg.hash()
+	if err != nil {
+		return "", err
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(newHash, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := uint64(binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff)
+	code := value % pow10[digits]
+
+	buf := make([]byte, digits)
+	for i := digits - 1; i >= 0; i-- {
+		buf[i] = byte('0' + code%10)
+		code /= 10
+	}
+	return string(buf), nil
+}
+
+// secretEncoding is the unpadded base32 encoding used by authenticator apps
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret creates a random secret of DefaultSecretSize bytes
+//
+// Returns:
+// - []byte: the secret
+// - error: an error if the random source fails
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, DefaultSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// EncodeSecret encodes a secret as unpadded base32, the format shown to users
+//
+// Parameters:
+// - secret: the secret
+//
+// Returns:
+// - string: the encoded secret
+func EncodeSecret(secret []byte) string {
+	return secretEncoding.EncodeToString(secret)
+}
+
+// DecodeSecret decodes a base32 secret, ignoring case, spaces and padding
+//
+// Parameters:
+// - s: the encoded secret
+//
+// Returns:
+// - []byte: the secret
+// - error: ErrInvalidSecret if the encoding is invalid
+func DecodeSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(s))
+	secret, err := secretEncoding.DecodeString(s)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return secret, nil
+}
@@ -0,0 +1,159 @@
+package otp
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidURI is returned by ParseURI for malformed otpauth:// URIs
+var ErrInvalidURI = errors.New("otp: invalid otpauth URI")
+
+// Key is the content of an otpauth:// key URI, the format encoded in the QR codes
+// scanned by authenticator apps
+type Key struct {
+	Type      string        // "totp" or "hotp"
+	Issuer    string        // Service name shown in the app
+	Account   string        // Account name, e.g. the user's email
+	Secret    []byte        // Shared secret
+	Algorithm Algorithm     // HMAC hash
+	Digits    int           // Code length
+	Period    time.Duration // TOTP time step
+	Counter   uint64        // Initial HOTP counter
+}
+
+// Key returns the key URI description of the TOTP
+//
+// Parameters:
+// - issuer: the service name
+// - account: the account name
+//
+// Returns:
+// - *Key: the key
+func (t *TOTP) Key(issuer, account string) *Key {
+	return &Key{
+		Type: "totp", Issuer: issuer, Account: account, Secret: t.Secret,
+		Algorithm: t.Algorithm, Digits: t.Digits, Period: t.period(),
+	}
+}
+
+// Key returns the key URI description of the HOTP
+//
+// Parameters:
+// - issuer: the service name
+// - account: the account name
+// - counter: the initial counter value
+//
+// Returns:
+// - *Key: the key
+func (h *HOTP) Key(issuer, account string, counter uint64) *Key {
+	return &Key{
+		Type: "hotp", Issuer: issuer, Account: account, Secret: h.Secret,
+		Algorithm: h.Algorithm, Digits: h.Digits, Counter: counter,
+	}
+}
+
+// URI formats the key as otpauth://TYPE/ISSUER:ACCOUNT?secret=...&issuer=...
+// Default parameters are omitted, as some apps ignore keys that spell them out.
+//
+// Returns:
+// - string: the URI
+func (k *Key) URI() string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+	q := url.Values{}
+	q.Set("secret", EncodeSecret(k.Secret))
+	if k.Issuer != "" {
+		q.Set("issuer", k.Issuer)
+	}
+	if alg := Algorithm(strings.ToUpper(string(k.Algorithm))); alg != "" && alg != SHA1 {
+		q.Set("algorithm", string(alg))
+	}
+	if k.Digits != 0 && k.Digits != DefaultDigits {
+		q.Set("digits", strconv.Itoa(k.Digits))
+	}
+	if k.Type == "hotp" {
+		q.Set("counter", strconv.FormatUint(k.Counter, 10))
+	} else if k.Period != 0 && k.Period != DefaultPeriod {
+		q.Set("period", strconv.Itoa(int(k.Period/time.Second)))
+	}
+	// Authenticator apps expect %20 rather than + for spaces
+	query := strings.ReplaceAll(q.Encode(), "+", "%20")
+	return "otpauth://" + k.Type + "/" + url.PathEscape(label) + "?" + query
+}
+
+// String returns the URI of the key
+func (k *Key) String() string {
+	return k.URI()
+}
+
+// TOTP creates the TOTP described by a "totp" key, with one step of skew
+func (k *Key) TOTP() *TOTP {
+	return &TOTP{Secret: k.Secret, Digits: k.Digits, Algorithm: k.Algorithm, Period: k.Period, Skew: 1}
+}
+
+// HOTP creates the HOTP described by a "hotp" key
+func (k *Key) HOTP() *HOTP {
+	return &HOTP{Secret: k.Secret, Digits: k.Digits, Algorithm: k.Algorithm}
+}
+
+// ParseURI parses an otpauth:// key URI
+//
+// Parameters:
+// - uri: the URI
+//
+// Returns:
+// - *Key: the key, with defaults filled in for omitted parameters
+// - error: ErrInvalidURI, ErrInvalidSecret, ErrInvalidDigits or ErrUnsupportedAlgorithm
+func ParseURI(uri string) (*Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "otpauth" || (u.Host != "totp" && u.Host != "hotp") {
+		return nil, ErrInvalidURI
+	}
+	k := &Key{Type: u.Host, Algorithm: SHA1, Digits: DefaultDigits}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		k.Issuer, k.Account = issuer, strings.TrimSpace(account)
+	} else {
+		k.Account = label
+	}
+
+	q := u.Query()
+	if k.Secret, err = DecodeSecret(q.Get("secret")); err != nil {
+		return nil, err
+	}
+	if issuer := q.Get("issuer"); issuer != "" {
+		k.Issuer = issuer
+	}
+	if alg := q.Get("algorithm"); alg != "" {
+		k.Algorithm = Algorithm(strings.ToUpper(alg))
+		if _, err := k.Algorithm.hash(); err != nil {
+			return nil, err
+		}
+	}
+	if digits := q.Get("digits"); digits != "" {
+		if k.Digits, err = strconv.Atoi(digits); err != nil || k.Digits < 6 || k.Digits > 10 {
+			return nil, ErrInvalidDigits
+		}
+	}
+	if k.Type == "hotp" {
+		if k.Counter, err = strconv.ParseUint(q.Get("counter"), 10, 64); err != nil {
+			return nil, ErrInvalidURI
+		}
+	} else {
+		k.Period = DefaultPeriod
+		if period := q.Get("period"); period != "" {
+			seconds, err := strconv.Atoi(period)
+			if err != nil || seconds <= 0 {
+				return nil, ErrInvalidURI
+			}
+			k.Period = time.Duration(seconds) * time.Second
+		}
+	}
+	return k, nil
+}
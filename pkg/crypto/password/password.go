@@ -0,0 +1,376 @@
+// Package password hashes passwords for storage with Argon2id, scrypt or bcrypt.
+// Hashes are self-describing strings in PHC format (bcrypt keeps its modular
+// crypt format), so the algorithm and cost can be changed at any time: Verify
+// accepts every supported format and NeedsRehash reports which stored hashes
+// should be upgraded after the next successful login.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"GoFast/pkg/crypto"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithm is a password hashing algorithm
+type Algorithm string
+
+const (
+	// Argon2id is the RFC 9106 memory-hard function, the recommended default
+	Argon2id Algorithm = "argon2id"
+	// Scrypt is the RFC 7914 memory-hard function
+	Scrypt Algorithm = "scrypt"
+	// Bcrypt is bcrypt; passwords longer than 72 bytes are rejected
+	Bcrypt Algorithm = "bcrypt"
+)
+
+const (
+	// SaltSize is the salt length of Argon2id and scrypt hashes
+	SaltSize = 16
+	// KeySize is the digest length of Argon2id and scrypt hashes
+	KeySize = 32
+)
+
+var (
+	// ErrInvalidHash is returned for strings that are not a supported hash format
+	ErrInvalidHash = errors.New("password: invalid hash format")
+	// ErrUnsupportedAlgorithm is returned for unknown algorithms or versions
+	ErrUnsupportedAlgorithm = errors.New("password: unsupported algorithm")
+	// ErrMismatch is returned by Check when the password is wrong
+	ErrMismatch = errors.New("password: password does not match")
+)
+
+// Params are the algorithm and cost of new hashes. As with crypto.KDFParams the
+// numeric fields depend on the algorithm:
+//
+//	Argon2id: Time = passes, Memory = KiB of memory, Threads = lanes
+//	scrypt:   Time = log2(N), Memory = r, Threads = p
+//	bcrypt:   Time = cost
+type Params struct {
+	Algorithm Algorithm
+	Time      uint32
+	Memory    uint32
+	Threads   uint8
+}
+
+// DefaultArgon2idParams returns Argon2id parameters (t=3, m=64MiB, p=4)
+func DefaultArgon2idParams() Params {
+	p := crypto.DefaultArgon2idParams()
+	return Params{Algorithm: Argon2id, Time: p.Time, Memory: p.Memory, Threads: p.Threads}
+}
+
+// DefaultScryptParams returns scrypt parameters (N=2^15, r=8, p=1)
+func DefaultScryptParams() Params {
+	p := crypto.DefaultScryptParams()
+	return Params{Algorithm: Scrypt, Time: p.Time, Memory: p.Memory, Threads: p.Threads}
+}
+
+// DefaultBcryptParams returns bcrypt parameters (cost 12)
+func DefaultBcryptParams() Params {
+	return Params{Algorithm: Bcrypt, Time: 12}
+}
+
+// Hasher hashes passwords with fixed parameters
+type Hasher struct {
+	params Params
+}
+
+// defaultHasher backs the package-level functions
+var defaultHasher = &Hasher{params: DefaultArgon2idParams()}
+
+// NewHasher creates a Hasher
+//
+// Parameters:
+// - params: the algorithm and cost of new hashes
+//
+// Returns:
+// - *Hasher: the new Hasher
+// - error: an error if the parameters are invalid
+func NewHasher(params Params) (*Hasher, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	return &Hasher{params: params}, nil
+}
+
+// Params returns the parameters of new hashes
+func (h *Hasher) Params() Params {
+	return h.params
+}
+
+// Hash hashes a password with a random salt
+//
+// Parameters:
+// - password: the password
+//
+// Returns:
+// - string: the encoded hash
+// - error: an error if hashing fails
+func (h *Hasher) Hash(password string) (string, error) {
+	p := h.params
+	if p.Algorithm == Bcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), int(p.Time))
+		return string(hash), err
+	}
+
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := p.derive([]byte(password), salt, KeySize)
+	if err != nil {
+		return "", err
+	}
+	return encode(p, salt, key), nil
+}
+
+// Verify reports whether the password matches the hash. Any supported format is
+// accepted, regardless of the Hasher's parameters.
+//
+// Parameters:
+// - password: the password
+// - encoded: the stored hash
+//
+// Returns:
+// - bool: true if the password matches
+// - error: ErrInvalidHash or ErrUnsupportedAlgorithm if the hash cannot be checked
+func (h *Hasher) Verify(password, encoded string) (bool, error) {
+	return Verify(password, encoded)
+}
+
+// NeedsRehash reports whether a stored hash uses other parameters than the
+// Hasher. Unparseable hashes also need rehashing.
+//
+// Parameters:
+// - encoded: the stored hash
+//
+// Returns:
+// - bool: true if the hash should be replaced after the next successful Verify
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	d, err := decode(encoded)
+	return err != nil || d.Params != h.params || (d.Algorithm != Bcrypt && len(d.key) != KeySize)
+}
+
+// Hash hashes a password with the default Argon2id parameters
+//
+// Parameters:
+// - password: the password
+//
+// Returns:
+// - string: the encoded hash
+// - error: an error if hashing fails
+func Hash(password string) (string, error) {
+	return defaultHasher.Hash(password)
+}
+
+// Verify reports whether the password matches an Argon2id, scrypt or bcrypt hash
+//
+// Parameters:
+// - password: the password
+// - encoded: the stored hash
+//
+// Returns:
+// - bool: true if the password matches
+// - error: ErrInvalidHash or ErrUnsupportedAlgorithm if the hash cannot be checked
+func Verify(password, encoded string) (bool, error) {
+	if err := Check(password, encoded); err != nil {
+		if errors.Is(err, ErrMismatch) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Check is like Verify but reports a wrong password as ErrMismatch
+//
+// Parameters:
+// - password: the password
+// - encoded: the stored hash
+//
+// Returns:
+// - error: nil if the password matches, ErrMismatch or a format error otherwise
+func Check(password, encoded string) error {
+	d, err := decode(encoded)
+	if err != nil {
+		return err
+	}
+	if d.Algorithm == Bcrypt {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatch
+		}
+		return err
+	}
+	key, err := d.derive([]byte(password), d.salt, len(d.key))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(key, d.key) != 1 {
+		return ErrMismatch
+	}
+	return nil
+}
+
+// NeedsRehash reports whether a stored hash differs from the default Argon2id parameters
+//
+// Parameters:
+// - encoded: the stored hash
+//
+// Returns:
+// - bool: true if the hash should be replaced after the next successful Verify
+func NeedsRehash(encoded string) bool {
+	return defaultHasher.NeedsRehash(encoded)
+}
+
+// Identify returns the parameters a stored hash was created with
+//
+// Parameters:
+// - encoded: the stored hash
+//
+// Returns:
+// - Params: the algorithm and cost
+// - error: ErrInvalidHash or ErrUnsupportedAlgorithm
+func Identify(encoded string) (Params, error) {
+	d, err := decode(encoded)
+	if err != nil {
+		return Params{}, err
+	}
+	return d.Params, nil
+}
+
+// validate checks the parameters are usable and not trivially weak
+func (p Params) validate() error {
+	switch p.Algorithm {
+	case Argon2id, Scrypt:
+		return p.kdf().Validate()
+	case Bcrypt:
+		if cost := int(p.Time); cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return fmt.Errorf("password: bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return nil
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+}
+
+// kdf converts Argon2id and scrypt parameters to their crypto.KDFParams
+func (p Params) kdf() crypto.KDFParams {
+	alg := crypto.KDFArgon2id
+	if p.Algorithm == Scrypt {
+		alg = crypto.KDFScrypt
+	}
+	return crypto.KDFParams{Algorithm: alg, Time: p.Time, Memory: p.Memory, Threads: p.Threads}
+}
+
+// derive computes an Argon2id or scrypt digest
+func (p Params) derive(password, salt []byte, keyLen int) ([]byte, error) {
+	return crypto.DeriveKey(password, salt, p.kdf(), keyLen)
+}
+
+// decoded is a parsed hash string
+type decoded struct {
+	Params
+	salt []byte
+	key  []byte
+}
+
+// b64 is the PHC base64 alphabet: standard, without padding
+var b64 = base64.RawStdEncoding
+
+// encode formats an Argon2id or scrypt hash in PHC format:
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+//	$scrypt$ln=15,r=8,p=1$<salt>$<hash>
+func encode(p Params, salt, key []byte) string {
+	var settings string
+	if p.Algorithm == Argon2id {
+		settings = fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2.Version, p.Memory, p.Time, p.Threads)
+	} else {
+		settings = fmt.Sprintf("ln=%d,r=%d,p=%d", p.Time, p.Memory, p.Threads)
+	}
+	return "$" + string(p.Algorithm) + "$" + settings + "$" + b64.EncodeToString(salt) + "$" + b64.EncodeToString(key)
+}
+
+// decode parses a stored hash
+func decode(encoded string) (*decoded, error) {
+	if strings.HasPrefix(encoded, "$2") {
+		cost, err := bcrypt.Cost([]byte(encoded))
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		return &decoded{Params: Params{Algorithm: Bcrypt, Time: uint32(cost)}}, nil
+	}
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) < 5 || parts[0] != "" {
+		return nil, ErrInvalidHash
+	}
+	d := &decoded{Params: Params{Algorithm: Algorithm(parts[1])}}
+	settings := parts[2]
+	switch d.Algorithm {
+	case Argon2id:
+		if len(parts) != 6 {
+			return nil, ErrInvalidHash
+		}
+		if parts[2] != "v="+strconv.Itoa(argon2.Version) {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		settings = parts[3]
+	case Scrypt:
+		if len(parts) != 5 {
+			return nil, ErrInvalidHash
+		}
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	fields, err := parseSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	timeKey, memoryKey := "t", "m"
+	if d.Algorithm == Scrypt {
+		timeKey, memoryKey = "ln", "r"
+	}
+	d.Time, d.Memory = fields[timeKey], fields[memoryKey]
+	if fields["p"] > 255 || len(fields) != 3 {
+		return nil, ErrInvalidHash
+	}
+	d.Threads = uint8(fields["p"])
+	if err := d.kdf().Validate(); err != nil {
+		return nil, ErrInvalidHash
+	}
+
+	if d.salt, err = b64.DecodeString(parts[len(parts)-2]); err != nil || len(d.salt) < 8 {
+		return nil, ErrInvalidHash
+	}
+	if d.key, err = b64.DecodeString(parts[len(parts)-1]); err != nil || len(d.key) < 16 || len(d.key) > 128 {
+		return nil, ErrInvalidHash
+	}
+	return d, nil
+}
+
+// parseSettings parses a PHC parameter list such as "m=65536,t=3,p=4"
+func parseSettings(s string) (map[string]uint32, error) {
+	fields := make(map[string]uint32)
+	for _, kv := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, ErrInvalidHash
+		}
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidHash
+		}
+		fields[k] = uint32(n)
+	}
+	return fields, nil
+}
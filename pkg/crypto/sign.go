@@ -0,0 +1,116 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+)
+
+// ErrVerification is returned when a signature does not match the message
+var ErrVerification = errors.New("crypto: signature verification failed")
+
+// Sign signs a message with the scheme appropriate for the key:
+//
+//	RSA:     RSASSA-PSS with SHA-256
+//	ECDSA:   ASN.1 DER signature with SHA-256, SHA-384 or SHA-512 for P-256, P-384 and P-521
+//	Ed25519: pure Ed25519
+//
+// Parameters:
+// - priv: an *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey
+// - message: the message; it is hashed internally
+//
+// Returns:
+// - []byte: the signature
+// - error: ErrUnsupportedKey or a signing error
+func Sign(priv any, message []byte) ([]byte, error) {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		return rsa.SignPSS(rand.Reader, k, stdcrypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	case *ecdsa.PrivateKey:
+		return ecdsa.SignASN1(rand.Reader, k, ecdsaDigest(&k.PublicKey, message))
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, message), nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// Verify checks a signature produced by Sign
+//
+// Parameters:
+// - pub: an *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey
+// - message: the signed message
+// - signature: the signature
+//
+// Returns:
+// - error: nil if the signature is valid, ErrVerification or ErrUnsupportedKey otherwise
+func Verify(pub any, message, signature []byte) error {
+	valid := false
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		valid = rsa.VerifyPSS(k, stdcrypto.SHA256, digest[:], signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(k, ecdsaDigest(k, message), signature)
+	case ed25519.PublicKey:
+		valid = len(k) == ed25519.PublicKeySize && ed25519.Verify(k, message, signature)
+	default:
+		return ErrUnsupportedKey
+	}
+	if !valid {
+		return ErrVerification
+	}
+	return nil
+}
+
+// ecdsaDigest hashes the message with the hash whose strength matches the curve
+func ecdsaDigest(pub *ecdsa.PublicKey, message []byte) []byte {
+	hash := stdcrypto.SHA256
+	switch bits := pub.Curve.Params().BitSize; {
+	case bits > 384:
+		hash = stdcrypto.SHA512
+	case bits > 256:
+		hash = stdcrypto.SHA384
+	}
+	h := hash.New()
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+// EncryptOAEP encrypts a short message with RSA-OAEP and SHA-256. Use SealTo for
+// messages of arbitrary length.
+//
+// Parameters:
+// - pub: the recipient's public key
+// - plaintext: the message, at most pub.Size()-66 bytes
+// - label: optional context bound to the ciphertext; must match on decryption
+//
+// Returns:
+// - []byte: the ciphertext
+// - error: an error if the message is too long
+func EncryptOAEP(pub *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, plaintext, label)
+}
+
+// DecryptOAEP decrypts a message encrypted with EncryptOAEP
+//
+// Parameters:
+// - priv: the private key
+// - ciphertext: the ciphertext
+// - label: the label used for encryption
+//
+// Returns:
+// - []byte: the plaintext
+// - error: ErrAuthFailed if the ciphertext or label does not match
+func DecryptOAEP(priv *rsa.PrivateKey, ciphertext, label []byte) ([]byte, error) {
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), nil, priv, ciphertext, label)
+	if err != nil {
+		return nil, ErrAuthFailed
+	}
+	return plaintext, nil
+}
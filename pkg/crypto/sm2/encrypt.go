@@ -0,0 +1,112 @@
+package sm2
+
+import (
+	"crypto/subtle"
+	"io"
+
+	"GoFast/pkg/crypto/sm3"
+)
+
+// CipherMode is the order of the ciphertext components
+type CipherMode int
+
+const (
+	// C1C3C2 is the order used by GB/T 32918.4-2016 and GM/T 0009
+	C1C3C2 CipherMode = iota
+	// C1C2C3 is the order of the 2010 draft, still produced by some older systems
+	C1C2C3
+)
+
+// Encrypt encrypts a message for the holder of pub. C1 is the uncompressed
+// ephemeral point (65 bytes), C2 the masked message and C3 the 32-byte SM3 check value.
+//
+// Parameters:
+// - rand: the random source for the ephemeral key
+// - pub: the recipient's public key
+// - message: the data to encrypt
+// - mode: the component order, C1C3C2 unless interoperating with legacy systems
+//
+// Returns:
+// - []byte: the ciphertext, 97 bytes longer than the message
+// - error: an error from the random source
+func Encrypt(rand io.Reader, pub *PublicKey, message []byte, mode CipherMode) ([]byte, error) {
+	curve := P256()
+	n := curve.Params().N
+	for {
+		k, err := randScalar(rand, n)
+		if err != nil {
+			return nil, err
+		}
+		var kb [32]byte
+		k.FillBytes(kb[:])
+		x1, y1 := scalarBaseMult(&kb)
+		c1 := append(append([]byte{4}, x1[:]...), y1[:]...)
+		x2, y2 := scalarMultPoint(pub.X, pub.Y, &kb)
+		x2b, y2b := x2[:], y2[:]
+
+		t := kdf(append(x2b, y2b...), len(message))
+		if len(message) > 0 && allZero(t) {
+			continue
+		}
+		c2 := make([]byte, len(message))
+		subtle.XORBytes(c2, message, t)
+		c3 := checkValue(x2b, message, y2b)
+
+		out := make([]byte, 0, len(c1)+len(c2)+len(c3))
+		out = append(out, c1...)
+		if mode == C1C2C3 {
+			return append(append(out, c2...), c3...), nil
+		}
+		return append(append(out, c3...), c2...), nil
+	}
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt
+//
+// Parameters:
+// - priv: the recipient's private key
+// - ciphertext: the ciphertext
+// - mode: the component order used for encryption
+//
+// Returns:
+// - []byte: the message
+// - error: ErrDecryption if the ciphertext is malformed or has been modified
+func Decrypt(priv *PrivateKey, ciphertext []byte, mode CipherMode) ([]byte, error) {
+	if len(ciphertext) < 65+sm3.Size {
+		return nil, ErrDecryption
+	}
+	x1, y1, ok := unmarshalPoint(ciphertext[:65])
+	if !ok {
+		return nil, ErrDecryption
+	}
+	var c2, c3 []byte
+	if mode == C1C2C3 {
+		c2, c3 = ciphertext[65:len(ciphertext)-sm3.Size], ciphertext[len(ciphertext)-sm3.Size:]
+	} else {
+		c3, c2 = ciphertext[65:65+sm3.Size], ciphertext[65+sm3.Size:]
+	}
+
+	var d [32]byte
+	priv.D.FillBytes(d[:])
+	x2, y2 := scalarMultPoint(x1, y1, &d)
+	x2b, y2b := x2[:], y2[:]
+	t := kdf(append(x2b, y2b...), len(c2))
+	if len(c2) > 0 && allZero(t) {
+		return nil, ErrDecryption
+	}
+	message := make([]byte, len(c2))
+	subtle.XORBytes(message, c2, t)
+	if subtle.ConstantTimeCompare(checkValue(x2b, message, y2b), c3) != 1 {
+		return nil, ErrDecryption
+	}
+	return message, nil
+}
+
+// checkValue computes C3 = SM3(x2 || M || y2)
+func checkValue(x2, message, y2 []byte) []byte {
+	h := sm3.New()
+	h.Write(x2)
+	h.Write(message)
+	h.Write(y2)
+	return h.Sum(nil)
+}
@@ -0,0 +1,242 @@
+package sm2
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+)
+
+var (
+	oidPublicKeyEC = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSM2         = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+)
+
+// ErrInvalidPEM is returned when no matching PEM block is found
+var ErrInvalidPEM = errors.New("sm2: invalid PEM data")
+
+// pkixPublicKey is SubjectPublicKeyInfo
+type pkixPublicKey struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// pkcs8 is PrivateKeyInfo
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// ecPrivateKey is the SEC1 ECPrivateKey structure
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// algorithmIdentifier returns id-ecPublicKey with the SM2 curve as parameter
+func algorithmIdentifier() pkix.AlgorithmIdentifier {
+	params, _ := asn1.Marshal(oidSM2)
+	return pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyEC, Parameters: asn1.RawValue{FullBytes: params}}
+}
+
+// checkAlgorithm accepts id-ecPublicKey with the SM2 curve parameter
+func checkAlgorithm(algo pkix.AlgorithmIdentifier) bool {
+	var curve asn1.ObjectIdentifier
+	if !algo.Algorithm.Equal(oidPublicKeyEC) && !algo.Algorithm.Equal(oidSM2) {
+		return false
+	}
+	if len(algo.Parameters.FullBytes) == 0 {
+		return algo.Algorithm.Equal(oidSM2)
+	}
+	_, err := asn1.Unmarshal(algo.Parameters.FullBytes, &curve)
+	return err == nil && curve.Equal(oidSM2)
+}
+
+// MarshalPKIXPublicKey encodes a public key as DER SubjectPublicKeyInfo
+//
+// Parameters:
+// - pub: the public key
+//
+// Returns:
+// - []byte: the DER bytes
+// - error: an ASN.1 encoding error
+func MarshalPKIXPublicKey(pub *PublicKey) ([]byte, error) {
+	point := pub.Bytes()
+	return asn1.Marshal(pkixPublicKey{
+		Algorithm: algorithmIdentifier(),
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// ParsePKIXPublicKey decodes a DER SubjectPublicKeyInfo holding an SM2 key
+//
+// Parameters:
+// - der: the DER bytes
+//
+// Returns:
+// - *PublicKey: the public key
+// - error: ErrInvalidKey if der is not an SM2 public key
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	var info pkixPublicKey
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) != 0 || !checkAlgorithm(info.Algorithm) {
+		return nil, ErrInvalidKey
+	}
+	return NewPublicKey(info.PublicKey.RightAlign())
+}
+
+// MarshalECPrivateKey encodes a private key in SEC1 form
+//
+// Parameters:
+// - priv: the private key
+//
+// Returns:
+// - []byte: the DER bytes
+// - error: an ASN.1 encoding error
+func MarshalECPrivateKey(priv *PrivateKey) ([]byte, error) {
+	point := priv.PublicKey.Bytes()
+	return asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    priv.Bytes(),
+		NamedCurveOID: oidSM2,
+		PublicKey:     asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// ParseECPrivateKey decodes a SEC1 private key
+//
+// Parameters:
+// - der: the DER bytes
+//
+// Returns:
+// - *PrivateKey: the private key
+// - error: ErrInvalidKey if der is not an SM2 private key
+func ParseECPrivateKey(der []byte) (*PrivateKey, error) {
+	var key ecPrivateKey
+	if rest, err := asn1.Unmarshal(der, &key); err != nil || len(rest) != 0 || key.Version != 1 {
+		return nil, ErrInvalidKey
+	}
+	if len(key.NamedCurveOID) > 0 && !key.NamedCurveOID.Equal(oidSM2) {
+		return nil, ErrInvalidKey
+	}
+	if len(key.PrivateKey) > 32 {
+		return nil, ErrInvalidKey
+	}
+	return NewPrivateKey(new(big.Int).SetBytes(key.PrivateKey).FillBytes(make([]byte, 32)))
+}
+
+// MarshalPKCS8PrivateKey encodes a private key as DER PKCS#8
+//
+// Parameters:
+// - priv: the private key
+//
+// Returns:
+// - []byte: the DER bytes
+// - error: an ASN.1 encoding error
+func MarshalPKCS8PrivateKey(priv *PrivateKey) ([]byte, error) {
+	inner, err := asn1.Marshal(ecPrivateKey{
+		Version:    1,
+		PrivateKey: priv.Bytes(),
+		PublicKey:  asn1.BitString{Bytes: priv.PublicKey.Bytes(), BitLength: 8 * 65},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8{Algorithm: algorithmIdentifier(), PrivateKey: inner})
+}
+
+// ParsePKCS8PrivateKey decodes a DER PKCS#8 private key holding an SM2 key
+//
+// Parameters:
+// - der: the DER bytes
+//
+// Returns:
+// - *PrivateKey: the private key
+// - error: ErrInvalidKey if der is not an SM2 private key
+func ParsePKCS8PrivateKey(der []byte) (*PrivateKey, error) {
+	var info pkcs8
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) != 0 || !checkAlgorithm(info.Algorithm) {
+		return nil, ErrInvalidKey
+	}
+	return ParseECPrivateKey(info.PrivateKey)
+}
+
+// MarshalPrivateKeyPEM encodes a private key as a PKCS#8 "PRIVATE KEY" PEM block
+//
+// Parameters:
+// - priv: the private key
+//
+// Returns:
+// - []byte: the PEM bytes
+// - error: an ASN.1 encoding error
+func MarshalPrivateKeyPEM(priv *PrivateKey) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM decodes the first "PRIVATE KEY" (PKCS#8) or "EC PRIVATE KEY" (SEC1) block
+//
+// Parameters:
+// - data: the PEM data
+//
+// Returns:
+// - *PrivateKey: the private key
+// - error: ErrInvalidPEM or ErrInvalidKey
+func ParsePrivateKeyPEM(data []byte) (*PrivateKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, ErrInvalidPEM
+		}
+		switch block.Type {
+		case "PRIVATE KEY":
+			return ParsePKCS8PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			return ParseECPrivateKey(block.Bytes)
+		}
+	}
+}
+
+// MarshalPublicKeyPEM encodes a public key as a "PUBLIC KEY" PEM block
+//
+// Parameters:
+// - pub: the public key
+//
+// Returns:
+// - []byte: the PEM bytes
+// - error: an ASN.1 encoding error
+func MarshalPublicKeyPEM(pub *PublicKey) ([]byte, error) {
+	der, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePublicKeyPEM decodes the first "PUBLIC KEY" block
+//
+// Parameters:
+// - data: the PEM data
+//
+// Returns:
+// - *PublicKey: the public key
+// - error: ErrInvalidPEM or ErrInvalidKey
+func ParsePublicKeyPEM(data []byte) (*PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, ErrInvalidPEM
+		}
+		if block.Type == "PUBLIC KEY" {
+			return ParsePKIXPublicKey(block.Bytes)
+		}
+	}
+}
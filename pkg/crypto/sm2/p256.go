@@ -0,0 +1,392 @@
+package sm2
+
+import (
+	"crypto/subtle"
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// This file implements constant-time arithmetic on sm2p256v1 for every operation that
+// involves a secret scalar: field and scalar elements are four 64-bit limbs in Montgomery
+// form, points use the complete projective formulas for a = -3 of Renes, Costello and
+// Batina (https://eprint.iacr.org/2015/1060), and scalar multiplication uses a fixed 4-bit
+// window with a table lookup that reads every entry. No branch or memory access depends on
+// secret data.
+
+// element is an integer modulo a 256-bit modulus in Montgomery form, least significant limb first
+type element [4]uint64
+
+// modulus holds the constants for Montgomery arithmetic modulo m
+type modulus struct {
+	m    element // the modulus
+	mInv uint64  // -m^-1 mod 2^64
+	rr   element // R^2 mod m, R = 2^256
+	one  element // R mod m, i.e. 1 in Montgomery form
+}
+
+// newModulus computes the Montgomery constants; m is public, so math/big is fine here
+func newModulus(m *big.Int) *modulus {
+	mod := &modulus{m: limbs(m)}
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	inv := new(big.Int).ModInverse(m, new(big.Int).Lsh(big.NewInt(1), 64))
+	mod.mInv = -inv.Uint64()
+	mod.rr = limbs(new(big.Int).Mod(new(big.Int).Mul(r, r), m))
+	mod.one = limbs(new(big.Int).Mod(r, m))
+	return mod
+}
+
+// limbs converts a non-negative integer below 2^256 to limbs
+func limbs(x *big.Int) element {
+	var b [32]byte
+	x.FillBytes(b[:])
+	return elementFromBytes(&b)
+}
+
+// elementFromBytes converts 32 big-endian bytes to limbs without reduction
+func elementFromBytes(b *[32]byte) element {
+	var e element
+	for i := range e {
+		for j := 0; j < 8; j++ {
+			e[i] |= uint64(b[31-8*i-j]) << (8 * j)
+		}
+	}
+	return e
+}
+
+// bytes converts limbs to 32 big-endian bytes
+func (e *element) bytes() [32]byte {
+	var b [32]byte
+	for i := range e {
+		for j := 0; j < 8; j++ {
+			b[31-8*i-j] = byte(e[i] >> (8 * j))
+		}
+	}
+	return b
+}
+
+// lessThan returns 1 if x < y and 0 otherwise
+func lessThan(x, y *element) uint64 {
+	var borrow uint64
+	for i := range x {
+		_, borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	return borrow
+}
+
+// isZero returns 1 if x is zero and 0 otherwise
+func isZero(x *element) uint64 {
+	acc := x[0] | x[1] | x[2] | x[3]
+	return 1 ^ (acc|-acc)>>63
+}
+
+// selectElement sets z to x if cond is 1 and leaves it unchanged if cond is 0
+func selectElement(z, x *element, cond uint64) {
+	mask := -cond
+	for i := range z {
+		z[i] ^= mask & (z[i] ^ x[i])
+	}
+}
+
+// madd returns a*b + c + d as a 128-bit value
+func madd(a, b, c, d uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
+}
+
+// reduce sets z to the 257-bit value (top, t) minus m if that is not negative; (top, t) must be below 2m
+func (mod *modulus) reduce(z *element, t *element, top uint64) {
+	var d element
+	var borrow uint64
+	for i := range d {
+		d[i], borrow = bits.Sub64(t[i], mod.m[i], borrow)
+	}
+	_, borrow = bits.Sub64(top, 0, borrow)
+	*z = d
+	selectElement(z, t, borrow)
+}
+
+// mul sets z = x*y/R mod m using coarsely integrated operand scanning
+func (mod *modulus) mul(z, x, y *element) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c, carry uint64
+		for j := 0; j < 4; j++ {
+			c, t[j] = madd(x[j], y[i], t[j], c)
+		}
+		t[4], carry = bits.Add64(t[4], c, 0)
+		t[5] = carry
+
+		q := t[0] * mod.mInv
+		c, _ = madd(q, mod.m[0], t[0], 0)
+		for j := 1; j < 4; j++ {
+			c, t[j-1] = madd(q, mod.m[j], t[j], c)
+		}
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + carry
+	}
+	r := element{t[0], t[1], t[2], t[3]}
+	mod.reduce(z, &r, t[4])
+}
+
+// add sets z = x + y mod m
+func (mod *modulus) add(z, x, y *element) {
+	var t element
+	var carry uint64
+	for i := range t {
+		t[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	mod.reduce(z, &t, carry)
+}
+
+// sub sets z = x - y mod m
+func (mod *modulus) sub(z, x, y *element) {
+	var t element
+	var borrow, carry uint64
+	for i := range t {
+		t[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	mask := -borrow
+	for i := range t {
+		t[i], carry = bits.Add64(t[i], mod.m[i]&mask, carry)
+	}
+	*z = t
+}
+
+// toMontgomery sets z = x*R mod m; x must be below m
+func (mod *modulus) toMontgomery(z, x *element) {
+	mod.mul(z, x, &mod.rr)
+}
+
+// fromMontgomery sets z = x/R mod m
+func (mod *modulus) fromMontgomery(z, x *element) {
+	mod.mul(z, x, &element{1})
+}
+
+// invert sets z = x^(m-2) mod m, the inverse of x for a prime m and 0 for x = 0. The
+// exponent is public, so the sequence of operations is fixed.
+func (mod *modulus) invert(z, x *element) {
+	e := mod.m
+	e[0] -= 2 // m is odd and greater than 2, so there is no borrow
+	acc := mod.one
+	for i := 255; i >= 0; i-- {
+		mod.mul(&acc, &acc, &acc)
+		if e[i/64]>>(i%64)&1 == 1 {
+			mod.mul(&acc, &acc, x)
+		}
+	}
+	*z = acc
+}
+
+// point is a point on the curve in projective coordinates (X:Y:Z) with x = X/Z and y = Y/Z;
+// the point at infinity is (0:1:0)
+type point struct {
+	x, y, z element
+}
+
+var (
+	p256Once sync.Once
+	fieldP   *modulus // arithmetic modulo the field prime p
+	scalarN  *modulus // arithmetic modulo the group order n
+	curveB   element  // the coefficient b in Montgomery form
+	baseG    point    // the generator
+)
+
+// initP256 computes the Montgomery constants of the curve
+func initP256() {
+	p256Once.Do(func() {
+		params := P256().Params()
+		fieldP = newModulus(params.P)
+		scalarN = newModulus(params.N)
+		b := limbs(params.B)
+		fieldP.toMontgomery(&curveB, &b)
+		baseG = *newAffinePoint(limbs(params.Gx), limbs(params.Gy))
+	})
+}
+
+// newAffinePoint converts affine coordinates below p to a projective point
+func newAffinePoint(x, y element) *point {
+	p := &point{z: fieldP.one}
+	fieldP.toMontgomery(&p.x, &x)
+	fieldP.toMontgomery(&p.y, &y)
+	return p
+}
+
+// affine returns the affine coordinates of p; the point at infinity yields (0, 0)
+func (p *point) affine() (x, y [32]byte) {
+	var zInv, ax, ay element
+	fieldP.invert(&zInv, &p.z)
+	fieldP.mul(&ax, &p.x, &zInv)
+	fieldP.mul(&ay, &p.y, &zInv)
+	fieldP.fromMontgomery(&ax, &ax)
+	fieldP.fromMontgomery(&ay, &ay)
+	return ax.bytes(), ay.bytes()
+}
+
+// add sets q = p1 + p2 with the complete addition formula for a = -3 (algorithm 4 of the paper)
+func (q *point) add(p1, p2 *point) *point {
+	f := fieldP
+	var t0, t1, t2, t3, t4, x3, y3, z3 element
+	f.mul(&t0, &p1.x, &p2.x)
+	f.mul(&t1, &p1.y, &p2.y)
+	f.mul(&t2, &p1.z, &p2.z)
+	f.add(&t3, &p1.x, &p1.y)
+	f.add(&t4, &p2.x, &p2.y)
+	f.mul(&t3, &t3, &t4)
+	f.add(&t4, &t0, &t1)
+	f.sub(&t3, &t3, &t4)
+	f.add(&t4, &p1.y, &p1.z)
+	f.add(&x3, &p2.y, &p2.z)
+	f.mul(&t4, &t4, &x3)
+	f.add(&x3, &t1, &t2)
+	f.sub(&t4, &t4, &x3)
+	f.add(&x3, &p1.x, &p1.z)
+	f.add(&y3, &p2.x, &p2.z)
+	f.mul(&x3, &x3, &y3)
+	f.add(&y3, &t0, &t2)
+	f.sub(&y3, &x3, &y3)
+	f.mul(&z3, &curveB, &t2)
+	f.sub(&x3, &y3, &z3)
+	f.add(&z3, &x3, &x3)
+	f.add(&x3, &x3, &z3)
+	f.sub(&z3, &t1, &x3)
+	f.add(&x3, &t1, &x3)
+	f.mul(&y3, &curveB, &y3)
+	f.add(&t1, &t2, &t2)
+	f.add(&t2, &t1, &t2)
+	f.sub(&y3, &y3, &t2)
+	f.sub(&y3, &y3, &t0)
+	f.add(&t1, &y3, &y3)
+	f.add(&y3, &t1, &y3)
+	f.add(&t1, &t0, &t0)
+	f.add(&t0, &t1, &t0)
+	f.sub(&t0, &t0, &t2)
+	f.mul(&t1, &t4, &y3)
+	f.mul(&t2, &t0, &y3)
+	f.mul(&y3, &x3, &z3)
+	f.add(&y3, &y3, &t2)
+	f.mul(&x3, &t3, &x3)
+	f.sub(&x3, &x3, &t1)
+	f.mul(&z3, &t4, &z3)
+	f.mul(&t1, &t3, &t0)
+	f.add(&z3, &z3, &t1)
+	q.x, q.y, q.z = x3, y3, z3
+	return q
+}
+
+// double sets q = 2p with the complete doubling formula for a = -3 (algorithm 6 of the paper)
+func (q *point) double(p *point) *point {
+	f := fieldP
+	var t0, t1, t2, t3, x3, y3, z3 element
+	f.mul(&t0, &p.x, &p.x)
+	f.mul(&t1, &p.y, &p.y)
+	f.mul(&t2, &p.z, &p.z)
+	f.mul(&t3, &p.x, &p.y)
+	f.add(&t3, &t3, &t3)
+	f.mul(&z3, &p.x, &p.z)
+	f.add(&z3, &z3, &z3)
+	f.mul(&y3, &curveB, &t2)
+	f.sub(&y3, &y3, &z3)
+	f.add(&x3, &y3, &y3)
+	f.add(&y3, &x3, &y3)
+	f.sub(&x3, &t1, &y3)
+	f.add(&y3, &t1, &y3)
+	f.mul(&y3, &x3, &y3)
+	f.mul(&x3, &x3, &t3)
+	f.add(&t3, &t2, &t2)
+	f.add(&t2, &t2, &t3)
+	f.mul(&z3, &curveB, &z3)
+	f.sub(&z3, &z3, &t2)
+	f.sub(&z3, &z3, &t0)
+	f.add(&t3, &z3, &z3)
+	f.add(&z3, &z3, &t3)
+	f.add(&t3, &t0, &t0)
+	f.add(&t0, &t3, &t0)
+	f.sub(&t0, &t0, &t2)
+	f.mul(&t0, &t0, &z3)
+	f.add(&y3, &y3, &t0)
+	f.mul(&t0, &p.y, &p.z)
+	f.add(&t0, &t0, &t0)
+	f.mul(&z3, &t0, &z3)
+	f.sub(&x3, &x3, &z3)
+	f.mul(&z3, &t0, &t1)
+	f.add(&z3, &z3, &z3)
+	f.add(&z3, &z3, &z3)
+	q.x, q.y, q.z = x3, y3, z3
+	return q
+}
+
+// scalarMult sets q = k*p in constant time for a 32-byte big-endian scalar k
+func (q *point) scalarMult(p *point, k *[32]byte) *point {
+	// table[i] = i*p; table[0] is the point at infinity
+	var table [16]point
+	table[0] = point{y: fieldP.one}
+	table[1] = *p
+	for i := 2; i < 16; i += 2 {
+		table[i].double(&table[i/2])
+		table[i+1].add(&table[i], p)
+	}
+
+	acc := point{y: fieldP.one}
+	var entry point
+	for i := 0; i < 64; i++ {
+		if i > 0 {
+			acc.double(&acc)
+			acc.double(&acc)
+			acc.double(&acc)
+			acc.double(&acc)
+		}
+		window := k[i/2] >> 4
+		if i%2 == 1 {
+			window = k[i/2] & 0x0f
+		}
+		entry = point{y: fieldP.one}
+		for j := 1; j < 16; j++ {
+			cond := uint64(subtle.ConstantTimeByteEq(window, byte(j)))
+			selectElement(&entry.x, &table[j].x, cond)
+			selectElement(&entry.y, &table[j].y, cond)
+			selectElement(&entry.z, &table[j].z, cond)
+		}
+		acc.add(&acc, &entry)
+	}
+	*q = acc
+	return q
+}
+
+// scalarBaseMult returns the affine coordinates of k*G
+func scalarBaseMult(k *[32]byte) (x, y [32]byte) {
+	initP256()
+	var q point
+	return q.scalarMult(&baseG, k).affine()
+}
+
+// scalarMultPoint returns the affine coordinates of k*(px, py); the point must be on the curve
+func scalarMultPoint(px, py *big.Int, k *[32]byte) (x, y [32]byte) {
+	initP256()
+	var q point
+	return q.scalarMult(newAffinePoint(limbs(px), limbs(py)), k).affine()
+}
+
+// signScalar returns s = (1+d)^-1 * (k - r*d) mod n for scalars below n
+func signScalar(d, k, r *[32]byte) [32]byte {
+	initP256()
+	n := scalarN
+	dm, km, rm := elementFromBytes(d), elementFromBytes(k), elementFromBytes(r)
+	n.toMontgomery(&dm, &dm)
+	n.toMontgomery(&km, &km)
+	n.toMontgomery(&rm, &rm)
+
+	var inv, s element
+	n.add(&inv, &dm, &n.one)
+	n.invert(&inv, &inv)
+	n.mul(&s, &rm, &dm)
+	n.sub(&s, &km, &s)
+	n.mul(&s, &s, &inv)
+	n.fromMontgomery(&s, &s)
+	return s.bytes()
+}
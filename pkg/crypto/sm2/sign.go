@@ -0,0 +1,207 @@
+package sm2
+
+import (
+	stdcrypto "crypto"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+
+	"GoFast/pkg/crypto/sm3"
+)
+
+// SignerOpts configures PrivateKey.Sign
+type SignerOpts struct {
+	UID []byte // Signer identity; DefaultUID if empty
+}
+
+// HashFunc returns zero: SM2 hashes the message itself, so Sign expects the raw message
+func (o *SignerOpts) HashFunc() stdcrypto.Hash {
+	return 0
+}
+
+// ZA computes the signer identity hash Z_A = SM3(ENTL_A || ID_A || a || b || x_G || y_G || x_A || y_A)
+//
+// Parameters:
+// - pub: the signer's public key
+// - uid: the signer identity; DefaultUID if empty
+//
+// Returns:
+// - []byte: the 32-byte Z_A value
+// - error: an error if uid is longer than 8191 bytes
+func ZA(pub *PublicKey, uid []byte) ([]byte, error) {
+	if len(uid) == 0 {
+		uid = DefaultUID
+	}
+	if len(uid) >= 8192 {
+		return nil, errors.New("sm2: uid too long")
+	}
+	params := P256().Params()
+	a := new(big.Int).Sub(params.P, big.NewInt(3))
+
+	h := sm3.New()
+	bitLen := len(uid) * 8
+	h.Write([]byte{byte(bitLen >> 8), byte(bitLen)})
+	h.Write(uid)
+	for _, v := range []*big.Int{a, params.B, params.Gx, params.Gy, pub.X, pub.Y} {
+		h.Write(v.FillBytes(make([]byte, 32)))
+	}
+	return h.Sum(nil), nil
+}
+
+// hashMessage computes e = SM3(Z_A || M) as an integer
+func hashMessage(pub *PublicKey, uid, message []byte) (*big.Int, error) {
+	za, err := ZA(pub, uid)
+	if err != nil {
+		return nil, err
+	}
+	h := sm3.New()
+	h.Write(za)
+	h.Write(message)
+	return new(big.Int).SetBytes(h.Sum(nil)), nil
+}
+
+// ecSignature is the ASN.1 form of a signature
+type ecSignature struct {
+	R, S *big.Int
+}
+
+// Sign signs a message and returns the ASN.1 DER encoded signature SEQUENCE { r, s }
+//
+// Parameters:
+// - rand: the random source for the per-signature nonce
+// - priv: the private key
+// - uid: the signer identity; DefaultUID if empty
+// - message: the message; it is hashed internally together with Z_A
+//
+// Returns:
+// - []byte: the DER signature
+// - error: an error from the random source
+func Sign(rand io.Reader, priv *PrivateKey, uid, message []byte) ([]byte, error) {
+	r, s, err := SignRS(rand, priv, uid, message)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ecSignature{R: r, S: s})
+}
+
+// SignRS signs a message and returns the raw signature integers
+//
+// Parameters:
+// - rand: the random source for the per-signature nonce
+// - priv: the private key
+// - uid: the signer identity; DefaultUID if empty
+// - message: the message
+//
+// Returns:
+// - *big.Int: r
+// - *big.Int: s
+// - error: an error from the random source
+func SignRS(rand io.Reader, priv *PrivateKey, uid, message []byte) (*big.Int, *big.Int, error) {
+	e, err := hashMessage(&priv.PublicKey, uid, message)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := P256().Params().N
+	var d [32]byte
+	priv.D.FillBytes(d[:])
+
+	for {
+		k, err := randScalar(rand, n)
+		if err != nil {
+			return nil, nil, err
+		}
+		var kb [32]byte
+		k.FillBytes(kb[:])
+		x1, _ := scalarBaseMult(&kb)
+
+		// r = (e + x1) mod n; retry if r = 0 or r + k = n. r is public, so math/big is fine.
+		r := new(big.Int).Add(e, new(big.Int).SetBytes(x1[:]))
+		r.Mod(r, n)
+		if r.Sign() == 0 || new(big.Int).Add(r, k).Cmp(n) == 0 {
+			continue
+		}
+
+		// s = (1 + d)^-1 * (k - r*d) mod n, computed in constant time
+		var rb [32]byte
+		r.FillBytes(rb[:])
+		sb := signScalar(&d, &kb, &rb)
+		if s := new(big.Int).SetBytes(sb[:]); s.Sign() != 0 {
+			return r, s, nil
+		}
+	}
+}
+
+// Verify checks a DER signature produced by Sign
+//
+// Parameters:
+// - pub: the signer's public key
+// - uid: the signer identity used for signing; DefaultUID if empty
+// - message: the signed message
+// - signature: the DER signature
+//
+// Returns:
+// - bool: true if the signature is valid
+func Verify(pub *PublicKey, uid, message, signature []byte) bool {
+	var sig ecSignature
+	rest, err := asn1.Unmarshal(signature, &sig)
+	if err != nil || len(rest) != 0 || sig.R == nil || sig.S == nil {
+		return false
+	}
+	return VerifyRS(pub, uid, message, sig.R, sig.S)
+}
+
+// VerifyRS checks a raw signature produced by SignRS
+//
+// Parameters:
+// - pub: the signer's public key
+// - uid: the signer identity used for signing; DefaultUID if empty
+// - message: the signed message
+// - r, s: the signature integers
+//
+// Returns:
+// - bool: true if the signature is valid
+func VerifyRS(pub *PublicKey, uid, message []byte, r, s *big.Int) bool {
+	curve := P256()
+	n := curve.Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return false
+	}
+	if pub.X == nil || pub.Y == nil || !curve.IsOnCurve(pub.X, pub.Y) {
+		return false
+	}
+	e, err := hashMessage(pub, uid, message)
+	if err != nil {
+		return false
+	}
+	t := new(big.Int).Add(r, s)
+	t.Mod(t, n)
+	if t.Sign() == 0 {
+		return false
+	}
+
+	// (x1, y1) = s*G + t*P_A; valid if (e + x1) mod n = r
+	initP256()
+	var sb, tb [32]byte
+	s.FillBytes(sb[:])
+	t.FillBytes(tb[:])
+	var sG, tP point
+	sG.scalarMult(&baseG, &sb)
+	tP.scalarMult(newAffinePoint(limbs(pub.X), limbs(pub.Y)), &tb)
+	x1, _ := sG.add(&sG, &tP).affine()
+	x := new(big.Int).SetBytes(x1[:])
+	x.Add(x, e)
+	x.Mod(x, n)
+	return x.Cmp(r) == 0
+}
+
+// Sign implements crypto.Signer. Unlike ECDSA, SM2 hashes the message itself, so
+// msg is the full message rather than a digest. opts may be a *SignerOpts to set
+// the signer identity.
+func (priv *PrivateKey) Sign(rand io.Reader, msg []byte, opts stdcrypto.SignerOpts) ([]byte, error) {
+	var uid []byte
+	if o, ok := opts.(*SignerOpts); ok {
+		uid = o.UID
+	}
+	return Sign(rand, priv, uid, msg)
+}
@@ -0,0 +1,207 @@
+// Package sm2 implements the SM2 elliptic curve public key algorithms defined in
+// GB/T 32918-2016: digital signatures (part 2) and public key encryption (part 4)
+// over the recommended 256-bit prime curve, with SM3 as the hash function.
+package sm2
+
+import (
+	stdcrypto "crypto"
+	"crypto/elliptic"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+	"sync"
+
+	"GoFast/pkg/crypto/sm3"
+)
+
+// DefaultUID is the signer identity used when none is given, as specified by GM/T 0009
+var DefaultUID = []byte("1234567812345678")
+
+var (
+	// ErrInvalidKey is returned for keys that are out of range or not on the curve
+	ErrInvalidKey = errors.New("sm2: invalid key")
+	// ErrDecryption is returned when a ciphertext is malformed or fails its integrity check
+	ErrDecryption = errors.New("sm2: decryption failed")
+)
+
+var (
+	curveOnce sync.Once
+	sm2P256   *elliptic.CurveParams
+)
+
+// P256 returns the SM2 recommended curve sm2p256v1. Its coefficient a equals p-3,
+// so the generic elliptic.CurveParams arithmetic applies. That arithmetic is not
+// constant time and must not be used with secret scalars; the functions of this
+// package use the constant-time implementation in p256.go instead.
+func P256() elliptic.Curve {
+	curveOnce.Do(func() {
+		sm2P256 = &elliptic.CurveParams{Name: "SM2-P-256", BitSize: 256}
+		sm2P256.P = hexInt("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF00000000FFFFFFFFFFFFFFFF")
+		sm2P256.N = hexInt("FFFFFFFEFFFFFFFFFFFFFFFFFFFFFFFF7203DF6B21C6052B53BBF40939D54123")
+		sm2P256.B = hexInt("28E9FA9E9D9F5E344D5A9E4BCF6509A7F39789F515AB8F92DDBCBD414D940E93")
+		sm2P256.Gx = hexInt("32C4AE2C1F1981195F9904466A39C9948FE30BBFF2660BE1715A4589334C74C7")
+		sm2P256.Gy = hexInt("BC3736A2F4F6779C59BDCEE36B692153D0A9877CC62A474002DF32E52139F0A0")
+	})
+	return sm2P256
+}
+
+func hexInt(s string) *big.Int {
+	n, _ := new(big.Int).SetString(s, 16)
+	return n
+}
+
+// PublicKey is an SM2 public key
+type PublicKey struct {
+	X, Y *big.Int
+}
+
+// PrivateKey is an SM2 private key
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+}
+
+// GenerateKey generates a new key pair
+//
+// Parameters:
+// - rand: the random source, usually crypto/rand.Reader
+//
+// Returns:
+// - *PrivateKey: the private key
+// - error: an error from the random source
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	// d must be in [1, n-2] so that 1+d is invertible for signing
+	nMinus1 := new(big.Int).Sub(P256().Params().N, big.NewInt(1))
+	d, err := randScalar(rand, nMinus1)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKey(d), nil
+}
+
+// NewPrivateKey creates a private key from its 32-byte big-endian scalar
+//
+// Parameters:
+// - d: the private scalar
+//
+// Returns:
+// - *PrivateKey: the private key
+// - error: ErrInvalidKey if d is out of range
+func NewPrivateKey(d []byte) (*PrivateKey, error) {
+	k := new(big.Int).SetBytes(d)
+	nMinus1 := new(big.Int).Sub(P256().Params().N, big.NewInt(1))
+	if len(d) != 32 || k.Sign() == 0 || k.Cmp(nMinus1) >= 0 {
+		return nil, ErrInvalidKey
+	}
+	return newPrivateKey(k), nil
+}
+
+func newPrivateKey(d *big.Int) *PrivateKey {
+	var k [32]byte
+	d.FillBytes(k[:])
+	x, y := scalarBaseMult(&k)
+	return &PrivateKey{PublicKey: PublicKey{X: new(big.Int).SetBytes(x[:]), Y: new(big.Int).SetBytes(y[:])}, D: d}
+}
+
+// NewPublicKey creates a public key from its uncompressed encoding 04 || X || Y
+//
+// Parameters:
+// - data: the 65-byte uncompressed point
+//
+// Returns:
+// - *PublicKey: the public key
+// - error: ErrInvalidKey if the point is malformed or not on the curve
+func NewPublicKey(data []byte) (*PublicKey, error) {
+	x, y, ok := unmarshalPoint(data)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	return &PublicKey{X: x, Y: y}, nil
+}
+
+// Bytes returns the uncompressed encoding 04 || X || Y
+func (pub *PublicKey) Bytes() []byte {
+	return marshalPoint(pub.X, pub.Y)
+}
+
+// Equal reports whether pub and x have the same value
+func (pub *PublicKey) Equal(x stdcrypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && pub.X.Cmp(other.X) == 0 && pub.Y.Cmp(other.Y) == 0
+}
+
+// Bytes returns the 32-byte big-endian private scalar
+func (priv *PrivateKey) Bytes() []byte {
+	return priv.D.FillBytes(make([]byte, 32))
+}
+
+// Public returns the public key; it implements crypto.Signer
+func (priv *PrivateKey) Public() stdcrypto.PublicKey {
+	return &priv.PublicKey
+}
+
+// Equal reports whether priv and x have the same value
+func (priv *PrivateKey) Equal(x stdcrypto.PrivateKey) bool {
+	other, ok := x.(*PrivateKey)
+	return ok && priv.D.Cmp(other.D) == 0 && priv.PublicKey.Equal(&other.PublicKey)
+}
+
+// marshalPoint encodes a point in uncompressed form
+func marshalPoint(x, y *big.Int) []byte {
+	out := make([]byte, 65)
+	out[0] = 4
+	x.FillBytes(out[1:33])
+	y.FillBytes(out[33:])
+	return out
+}
+
+// unmarshalPoint decodes an uncompressed point and checks it lies on the curve
+func unmarshalPoint(data []byte) (*big.Int, *big.Int, bool) {
+	if len(data) != 65 || data[0] != 4 {
+		return nil, nil, false
+	}
+	x := new(big.Int).SetBytes(data[1:33])
+	y := new(big.Int).SetBytes(data[33:])
+	p := P256().Params().P
+	if x.Cmp(p) >= 0 || y.Cmp(p) >= 0 || !P256().IsOnCurve(x, y) {
+		return nil, nil, false
+	}
+	return x, y, true
+}
+
+// randScalar returns a uniformly random integer in [1, max-1] for a max close to 2^256.
+// It draws 32-byte big-endian values until one is in range, so no secret-dependent
+// reduction is needed; for the SM2 order a retry happens with probability about 2^-32.
+func randScalar(rand io.Reader, max *big.Int) (*big.Int, error) {
+	limit := limbs(max)
+	var b [32]byte
+	for {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return nil, err
+		}
+		k := elementFromBytes(&b)
+		if isZero(&k)|(1^lessThan(&k, &limit)) == 0 {
+			return new(big.Int).SetBytes(b[:]), nil
+		}
+	}
+}
+
+// kdf is the SM3-based key derivation function of GB/T 32918.4
+func kdf(z []byte, length int) []byte {
+	out := make([]byte, 0, length+sm3.Size)
+	var counter [4]byte
+	for ct := uint32(1); len(out) < length; ct++ {
+		counter[0], counter[1], counter[2], counter[3] = byte(ct>>24), byte(ct>>16), byte(ct>>8), byte(ct)
+		h := sm3.New()
+		h.Write(z)
+		h.Write(counter[:])
+		out = h.Sum(out)
+	}
+	return out[:length]
+}
+
+// allZero reports whether b consists only of zero bytes
+func allZero(b []byte) bool {
+	return subtle.ConstantTimeCompare(b, make([]byte, len(b))) == 1
+}
@@ -0,0 +1,162 @@
+// Package sm3 implements the SM3 cryptographic hash algorithm as defined in
+// GB/T 32905-2016. SM3 produces a 256-bit digest and implements hash.Hash, so it
+// can be used anywhere a standard library hash is expected, e.g. with crypto/hmac.
+package sm3
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Size is the size of an SM3 checksum in bytes
+const Size = 32
+
+// BlockSize is the block size of SM3 in bytes
+const BlockSize = 64
+
+// iv is the initial hash value
+var iv = [8]uint32{
+	0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600,
+	0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e,
+}
+
+// digest is the running state of an SM3 computation
+type digest struct {
+	h   [8]uint32
+	x   [BlockSize]byte
+	nx  int
+	len uint64
+}
+
+// New returns a new hash.Hash computing the SM3 checksum
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+// Sum returns the SM3 checksum of the data
+//
+// Parameters:
+// - data: the data to hash
+//
+// Returns:
+// - [Size]byte: the checksum
+func Sum(data []byte) [Size]byte {
+	var d digest
+	d.Reset()
+	d.Write(data)
+	var out [Size]byte
+	d.checkSum(out[:0])
+	return out
+}
+
+// Reset resets the hash to its initial state
+func (d *digest) Reset() {
+	d.h = iv
+	d.nx = 0
+	d.len = 0
+}
+
+// Size returns the number of bytes Sum will return
+func (d *digest) Size() int { return Size }
+
+// BlockSize returns the hash's underlying block size
+func (d *digest) BlockSize() int { return BlockSize }
+
+// Write adds more data to the running hash; it never returns an error
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.len += uint64(n)
+	if d.nx > 0 {
+		c := copy(d.x[d.nx:], p)
+		d.nx += c
+		p = p[c:]
+		if d.nx < BlockSize {
+			return n, nil
+		}
+		d.block(d.x[:])
+		d.nx = 0
+	}
+	for len(p) >= BlockSize {
+		d.block(p[:BlockSize])
+		p = p[BlockSize:]
+	}
+	d.nx = copy(d.x[:], p)
+	return n, nil
+}
+
+// Sum appends the current hash to b without changing the underlying state
+func (d *digest) Sum(b []byte) []byte {
+	clone := *d
+	return clone.checkSum(b)
+}
+
+// checkSum pads the message and appends the final hash to b
+func (d *digest) checkSum(b []byte) []byte {
+	length := d.len
+	var pad [BlockSize + 8]byte
+	pad[0] = 0x80
+	padLen := 56 - int(length%BlockSize)
+	if padLen <= 0 {
+		padLen += BlockSize
+	}
+	binary.BigEndian.PutUint64(pad[padLen:], length<<3)
+	d.Write(pad[:padLen+8])
+
+	for _, v := range d.h {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+// p0 and p1 are the permutation functions of the compression function
+func p0(x uint32) uint32 { return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17) }
+func p1(x uint32) uint32 { return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23) }
+
+// block runs the compression function on one 64-byte block
+func (d *digest) block(p []byte) {
+	var w [68]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(p[4*i:])
+	}
+	for j := 16; j < 68; j++ {
+		w[j] = p1(w[j-16]^w[j-9]^bits.RotateLeft32(w[j-3], 15)) ^ bits.RotateLeft32(w[j-13], 7) ^ w[j-6]
+	}
+
+	a, b, c, dd, e, f, g, h := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
+	for j := 0; j < 64; j++ {
+		var t, ff, gg uint32
+		if j < 16 {
+			t = 0x79cc4519
+			ff = a ^ b ^ c
+			gg = e ^ f ^ g
+		} else {
+			t = 0x7a879d8a
+			ff = (a & b) | (a & c) | (b & c)
+			gg = (e & f) | (^e & g)
+		}
+		a12 := bits.RotateLeft32(a, 12)
+		ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(t, j%32), 7)
+		ss2 := ss1 ^ a12
+		tt1 := ff + dd + ss2 + (w[j] ^ w[j+4])
+		tt2 := gg + h + ss1 + w[j]
+		dd = c
+		c = bits.RotateLeft32(b, 9)
+		b = a
+		a = tt1
+		h = g
+		g = bits.RotateLeft32(f, 19)
+		f = e
+		e = p0(tt2)
+	}
+	d.h[0] ^= a
+	d.h[1] ^= b
+	d.h[2] ^= c
+	d.h[3] ^= dd
+	d.h[4] ^= e
+	d.h[5] ^= f
+	d.h[6] ^= g
+	d.h[7] ^= h
+}
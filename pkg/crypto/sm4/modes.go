@@ -0,0 +1,197 @@
+package sm4
+
+import (
+	"crypto/cipher"
+	"errors"
+)
+
+var (
+	// ErrInvalidIV is returned when an IV is not BlockSize bytes long
+	ErrInvalidIV = errors.New("sm4: IV length must equal block size")
+	// ErrNotFullBlocks is returned when ciphertext is not a multiple of the block size
+	ErrNotFullBlocks = errors.New("sm4: ciphertext is not a multiple of the block size")
+	// ErrAuthFailed is returned when GCM authentication fails
+	ErrAuthFailed = errors.New("sm4: message authentication failed")
+)
+
+// EncryptECB encrypts plaintext in ECB mode with PKCS#7 padding. ECB leaks
+// patterns in the plaintext; it is provided only for interoperability.
+//
+// Parameters:
+// - key: the 16-byte key
+// - plaintext: the data to encrypt
+//
+// Returns:
+// - []byte: the ciphertext
+// - error: KeySizeError if the key is invalid
+func EncryptECB(key, plaintext []byte) ([]byte, error) {
+	block, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := Pad(plaintext)
+	for i := 0; i < len(out); i += BlockSize {
+		block.Encrypt(out[i:], out[i:])
+	}
+	return out, nil
+}
+
+// DecryptECB decrypts ciphertext produced by EncryptECB
+//
+// Parameters:
+// - key: the 16-byte key
+// - ciphertext: the data to decrypt
+//
+// Returns:
+// - []byte: the plaintext
+// - error: KeySizeError, ErrNotFullBlocks or ErrInvalidPadding
+func DecryptECB(key, ciphertext []byte) ([]byte, error) {
+	block, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext)%BlockSize != 0 {
+		return nil, ErrNotFullBlocks
+	}
+	out := make([]byte, len(ciphertext))
+	for i := 0; i < len(out); i += BlockSize {
+		block.Decrypt(out[i:], ciphertext[i:])
+	}
+	return Unpad(out)
+}
+
+// EncryptCBC encrypts plaintext in CBC mode with PKCS#7 padding
+//
+// Parameters:
+// - key: the 16-byte key
+// - iv: the 16-byte initialization vector; it must be unpredictable
+// - plaintext: the data to encrypt
+//
+// Returns:
+// - []byte: the ciphertext
+// - error: KeySizeError or ErrInvalidIV
+func EncryptCBC(key, iv, plaintext []byte) ([]byte, error) {
+	block, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != BlockSize {
+		return nil, ErrInvalidIV
+	}
+	out := Pad(plaintext)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, out)
+	return out, nil
+}
+
+// DecryptCBC decrypts ciphertext produced by EncryptCBC
+//
+// Parameters:
+// - key: the 16-byte key
+// - iv: the initialization vector used for encryption
+// - ciphertext: the data to decrypt
+//
+// Returns:
+// - []byte: the plaintext
+// - error: KeySizeError, ErrInvalidIV, ErrNotFullBlocks or ErrInvalidPadding
+func DecryptCBC(key, iv, ciphertext []byte) ([]byte, error) {
+	block, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != BlockSize {
+		return nil, ErrInvalidIV
+	}
+	if len(ciphertext)%BlockSize != 0 {
+		return nil, ErrNotFullBlocks
+	}
+	out := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, ciphertext)
+	return Unpad(out)
+}
+
+// XORKeyStreamCTR encrypts or decrypts data in CTR mode; the operation is its own inverse
+//
+// Parameters:
+// - key: the 16-byte key
+// - iv: the 16-byte initial counter block; never reuse it with the same key
+// - data: the plaintext or ciphertext
+//
+// Returns:
+// - []byte: the transformed data
+// - error: KeySizeError or ErrInvalidIV
+func XORKeyStreamCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != BlockSize {
+		return nil, ErrInvalidIV
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// NewGCM returns SM4 in Galois/Counter Mode with the standard 12-byte nonce
+//
+// Parameters:
+// - key: the 16-byte key
+//
+// Returns:
+// - cipher.AEAD: the AEAD instance
+// - error: KeySizeError if the key is invalid
+func NewGCM(key []byte) (cipher.AEAD, error) {
+	block, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptGCM encrypts and authenticates plaintext in GCM mode
+//
+// Parameters:
+// - key: the 16-byte key
+// - nonce: the 12-byte nonce; never reuse it with the same key
+// - plaintext: the data to encrypt
+// - additionalData: data that is authenticated but not encrypted; may be nil
+//
+// Returns:
+// - []byte: the ciphertext followed by the 16-byte tag
+// - error: KeySizeError or ErrInvalidIV
+func EncryptGCM(key, nonce, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrInvalidIV
+	}
+	return aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+// DecryptGCM decrypts and verifies ciphertext produced by EncryptGCM
+//
+// Parameters:
+// - key: the 16-byte key
+// - nonce: the nonce used for encryption
+// - ciphertext: the ciphertext and tag
+// - additionalData: the additional data used for encryption
+//
+// Returns:
+// - []byte: the plaintext
+// - error: KeySizeError, ErrInvalidIV or ErrAuthFailed
+func DecryptGCM(key, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := NewGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, ErrInvalidIV
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrAuthFailed
+	}
+	return plaintext, nil
+}
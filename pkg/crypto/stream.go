@@ -0,0 +1,385 @@
+package crypto
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultChunkSize is the plaintext size of each chunk in a streamed envelope
+const DefaultChunkSize = 64 * 1024
+
+// maxChunkSize bounds the buffer a reader allocates for an untrusted header
+const maxChunkSize = 16 * 1024 * 1024
+
+// ErrTruncated is returned when a stream ends before its final chunk
+var ErrTruncated = errors.New("crypto: stream truncated")
+
+// Streams are split into chunks that are sealed independently. Each chunk nonce is
+// the random prefix stored in the header followed by a 32-bit big-endian chunk
+// counter and a flag byte that is 1 only for the last chunk, so reordered,
+// duplicated or truncated chunks all fail authentication (the STREAM construction).
+const streamNonceSuffix = 5
+
+// encryptWriter seals data written to it in fixed-size chunks
+type encryptWriter struct {
+	w         io.Writer
+	aead      cipher.AEAD
+	header    []byte
+	nonce     []byte
+	buf       []byte
+	out       []byte
+	chunkSize int
+	counter   uint32
+	closed    bool
+	err       error
+}
+
+// NewEncryptWriter returns a writer that encrypts everything written to it into w.
+// The envelope header is written immediately; Close must be called to seal the
+// final chunk. Close does not close w.
+//
+// Parameters:
+// - w: the destination of the envelope
+// - alg: the algorithm
+// - key: the key, which must be exactly alg.KeySize() bytes
+//
+// Returns:
+// - io.WriteCloser: the encrypting writer
+// - error: an error if the key is invalid or the header cannot be written
+func NewEncryptWriter(w io.Writer, alg Algorithm, key []byte) (io.WriteCloser, error) {
+	return newEncryptWriter(w, &Envelope{Version: Version, Algorithm: alg}, key)
+}
+
+// NewPasswordEncryptWriter is like NewEncryptWriter but derives the key from a password
+//
+// Parameters:
+// - w: the destination of the envelope
+// - alg: the algorithm
+// - password: the password
+// - params: the key derivation parameters
+//
+// Returns:
+// - io.WriteCloser: the encrypting writer
+// - error: an error if the parameters are invalid or the header cannot be written
+func NewPasswordEncryptWriter(w io.Writer, alg Algorithm, password []byte, params KDFParams) (io.WriteCloser, error) {
+	env, key, err := newPasswordEnvelope(alg, password, params)
+	if err != nil {
+		return nil, err
+	}
+	return newEncryptWriter(w, env, key)
+}
+
+func newEncryptWriter(w io.Writer, env *Envelope, key []byte) (*encryptWriter, error) {
+	aead, err := NewAEAD(env.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	if env.Nonce, err = randomBytes(aead.NonceSize() - streamNonceSuffix); err != nil {
+		return nil, err
+	}
+	env.ChunkSize = DefaultChunkSize
+	header := env.header()
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:         w,
+		aead:      aead,
+		header:    header,
+		nonce:     streamNonce(aead, env.Nonce),
+		buf:       make([]byte, 0, DefaultChunkSize),
+		chunkSize: DefaultChunkSize,
+	}, nil
+}
+
+// Write buffers p and writes out every complete chunk except the last one,
+// which is held back until more data arrives or Close is called
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("crypto: write to closed stream")
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+	n := len(p)
+	for len(p) > 0 {
+		if len(e.buf) == e.chunkSize {
+			if e.err = e.flush(false); e.err != nil {
+				return n - len(p), e.err
+			}
+		}
+		take := min(e.chunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:take]...)
+		p = p[take:]
+	}
+	return n, nil
+}
+
+// Close seals and writes the final chunk
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	if e.err != nil {
+		return e.err
+	}
+	return e.flush(true)
+}
+
+// flush seals the buffered plaintext as one chunk
+func (e *encryptWriter) flush(final bool) error {
+	if e.counter == ^uint32(0) && !final {
+		return errors.New("crypto: stream too long")
+	}
+	setChunkNonce(e.nonce, e.counter, final)
+	e.out = e.aead.Seal(e.out[:0], e.nonce, e.buf, e.header)
+	e.buf = e.buf[:0]
+	e.counter++
+	_, err := e.w.Write(e.out)
+	return err
+}
+
+// decryptReader opens a streamed envelope chunk by chunk
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	nonce   []byte
+	in      []byte
+	out     []byte
+	plain   []byte
+	counter uint32
+	done    bool
+	err     error
+}
+
+// NewDecryptReader returns a reader that decrypts an envelope written by NewEncryptWriter.
+// Data is only returned after its chunk has been authenticated; a stream that ends
+// before its final chunk yields ErrTruncated.
+//
+// Parameters:
+// - r: the source of the envelope
+// - key: the key used for encryption
+//
+// Returns:
+// - io.Reader: the decrypting reader
+// - error: an error if the header is invalid or the key does not fit
+func NewDecryptReader(r io.Reader, key []byte) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	env, header, err := readStreamHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if env.KDF.Algorithm != KDFNone {
+		return nil, fmt.Errorf("%w: envelope requires a password", ErrInvalidEnvelope)
+	}
+	return newDecryptReader(br, env, header, key)
+}
+
+// NewPasswordDecryptReader is like NewDecryptReader for envelopes written by NewPasswordEncryptWriter.
+// Envelopes whose KDF parameters exceed DefaultKDFPolicy are rejected before any key is derived.
+//
+// Parameters:
+// - r: the source of the envelope
+// - password: the password
+//
+// Returns:
+// - io.Reader: the decrypting reader
+// - error: an error if the header is invalid, or ErrKDFCost
+func NewPasswordDecryptReader(r io.Reader, password []byte) (io.Reader, error) {
+	return NewPasswordDecryptReaderPolicy(r, password, DefaultKDFPolicy())
+}
+
+// NewPasswordDecryptReaderPolicy is like NewPasswordDecryptReader with a caller-supplied KDFPolicy
+//
+// Parameters:
+// - r: the source of the envelope
+// - password: the password
+// - policy: the bounds on the KDF parameters in the envelope header
+//
+// Returns:
+// - io.Reader: the decrypting reader
+// - error: an error if the header is invalid, or ErrKDFCost
+func NewPasswordDecryptReaderPolicy(r io.Reader, password []byte, policy KDFPolicy) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	env, header, err := readStreamHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	key, err := env.passwordKey(password, policy)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptReader(br, env, header, key)
+}
+
+func newDecryptReader(r *bufio.Reader, env *Envelope, header, key []byte) (*decryptReader, error) {
+	aead, err := NewAEAD(env.Algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize()-streamNonceSuffix {
+		return nil, ErrInvalidEnvelope
+	}
+	return &decryptReader{
+		r:      r,
+		aead:   aead,
+		header: header,
+		nonce:  streamNonce(aead, env.Nonce),
+		in:     make([]byte, int(env.ChunkSize)+aead.Overhead()),
+	}, nil
+}
+
+// Read returns decrypted data, reading and authenticating chunks as needed
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.next()
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// next reads and opens one chunk
+func (d *decryptReader) next() error {
+	n, err := io.ReadFull(d.r, d.in)
+	final := false
+	switch {
+	case err == io.EOF:
+		return ErrTruncated
+	case err == io.ErrUnexpectedEOF:
+		final = true
+	case err != nil:
+		return err
+	default:
+		if _, err := d.r.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if n < d.aead.Overhead() {
+		return ErrTruncated
+	}
+	setChunkNonce(d.nonce, d.counter, final)
+	plain, err := d.aead.Open(d.out[:0], d.nonce, d.in[:n], d.header)
+	if err != nil {
+		// A full chunk at EOF that opens as a non-final chunk means the
+		// stream was cut off exactly at a chunk boundary
+		if final && n == len(d.in) {
+			setChunkNonce(d.nonce, d.counter, false)
+			if _, err := d.aead.Open(nil, d.nonce, d.in[:n], d.header); err == nil {
+				return ErrTruncated
+			}
+		}
+		return ErrAuthFailed
+	}
+	d.counter++
+	d.out = plain
+	d.plain = plain
+	d.done = final
+	return nil
+}
+
+// EncryptStream encrypts src into dst with a raw key
+//
+// Parameters:
+// - dst: the destination of the envelope
+// - src: the plaintext
+// - alg: the algorithm
+// - key: the key
+//
+// Returns:
+// - error: an error from encryption or I/O
+func EncryptStream(dst io.Writer, src io.Reader, alg Algorithm, key []byte) error {
+	w, err := NewEncryptWriter(dst, alg, key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// DecryptStream decrypts an envelope from src into dst. On error, dst may already
+// contain the plaintext of the chunks that were authenticated before the failure.
+//
+// Parameters:
+// - dst: the destination of the plaintext
+// - src: the envelope
+// - key: the key
+//
+// Returns:
+// - error: ErrAuthFailed, ErrTruncated or an I/O error
+func DecryptStream(dst io.Writer, src io.Reader, key []byte) error {
+	r, err := NewDecryptReader(src, key)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, r)
+	return err
+}
+
+// readStreamHeader reads an envelope header field by field from r
+func readStreamHeader(r *bufio.Reader) (*Envelope, []byte, error) {
+	header := make([]byte, 16, 64)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, ErrInvalidEnvelope
+	}
+	// salt and nonce are length-prefixed
+	for i := 0; i < 2; i++ {
+		n, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, ErrInvalidEnvelope
+		}
+		field := make([]byte, int(n)+1)
+		field[0] = n
+		if _, err := io.ReadFull(r, field[1:]); err != nil {
+			return nil, nil, ErrInvalidEnvelope
+		}
+		header = append(header, field...)
+	}
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, nil, ErrInvalidEnvelope
+	}
+	header = append(header, size...)
+
+	env, n, err := parseHeader(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n != len(header) || env.ChunkSize == 0 || env.ChunkSize > maxChunkSize {
+		return nil, nil, ErrInvalidEnvelope
+	}
+	return env, header, nil
+}
+
+// streamNonce returns a full-size nonce buffer starting with the stream prefix
+func streamNonce(aead cipher.AEAD, prefix []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, prefix)
+	return nonce
+}
+
+// setChunkNonce writes the chunk counter and final flag into the nonce suffix
+func setChunkNonce(nonce []byte, counter uint32, final bool) {
+	suffix := nonce[len(nonce)-streamNonceSuffix:]
+	binary.BigEndian.PutUint32(suffix, counter)
+	suffix[4] = 0
+	if final {
+		suffix[4] = 1
+	}
+}
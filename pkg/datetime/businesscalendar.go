@@ -0,0 +1,502 @@
+package datetime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidHolidayData is returned when holiday data cannot be loaded
+	ErrInvalidHolidayData = errors.New("datetime: invalid holiday data")
+	// ErrInvalidWorkingHours is returned for malformed or overlapping working hours
+	ErrInvalidWorkingHours = errors.New("datetime: invalid working hours")
+)
+
+// workPeriod is a working period within a day, in minutes since midnight
+type workPeriod struct {
+	start, end int
+}
+
+// BusinessCalendar determines working days and working hours. Dates are the calendar
+// days of times in their own location, and working hours apply in that location.
+//
+// By default Saturday and Sunday are non-working and the working hours are 09:00-18:00.
+// Holidays and make-up workdays (调休), such as those published by the State Council of
+// China, override the weekend. A BusinessCalendar is safe for concurrent use.
+type BusinessCalendar struct {
+	mu       sync.RWMutex
+	weekend  [7]bool
+	holidays map[int]string
+	workdays map[int]string
+	periods  []workPeriod
+}
+
+// NewBusinessCalendar creates a BusinessCalendar with a Saturday and Sunday weekend,
+// working hours of 09:00-18:00 and no holidays.
+//
+// Returns:
+// - *BusinessCalendar: the new BusinessCalendar
+func NewBusinessCalendar() *BusinessCalendar {
+	c := &BusinessCalendar{
+		holidays: map[int]string{},
+		workdays: map[int]string{},
+		periods:  []workPeriod{{9 * 60, 18 * 60}},
+	}
+	c.weekend[time.Saturday], c.weekend[time.Sunday] = true, true
+	return c
+}
+
+// dateKey returns the calendar day of t as yyyymmdd
+func dateKey(t time.Time) int {
+	year, month, day := t.Date()
+	return year*10000 + int(month)*100 + day
+}
+
+// SetWeekend sets the days of the week that are not working days unless marked as make-up workdays.
+//
+// Parameters:
+// - days: the weekend days, may be empty
+//
+// Returns:
+// - error: if all seven days are weekend days
+func (c *BusinessCalendar) SetWeekend(days ...time.Weekday) error {
+	var weekend [7]bool
+	for _, day := range days {
+		weekend[day%7] = true
+	}
+	if weekend == [7]bool{true, true, true, true, true, true, true} {
+		return errors.New("datetime: at least one day of the week must be a working day")
+	}
+	c.mu.Lock()
+	c.weekend = weekend
+	c.mu.Unlock()
+	return nil
+}
+
+// SetWorkingHours sets the working periods of a working day, e.g. "09:00-12:00" and "13:30-18:00".
+//
+// Parameters:
+// - periods: the periods in HH:mm-HH:mm format, at least one and not overlapping; "24:00" ends at midnight
+//
+// Returns:
+// - error: ErrInvalidWorkingHours if a period is malformed or the periods overlap
+func (c *BusinessCalendar) SetWorkingHours(periods ...string) error {
+	if len(periods) == 0 {
+		return fmt.Errorf("%w: no periods", ErrInvalidWorkingHours)
+	}
+	parsed := make([]workPeriod, 0, len(periods))
+	for _, period := range periods {
+		start, end, ok := strings.Cut(period, "-")
+		startMinute, err1 := parseClock(strings.TrimSpace(start))
+		endMinute, err2 := parseClock(strings.TrimSpace(end))
+		if !ok || err1 != nil || err2 != nil || startMinute >= endMinute {
+			return fmt.Errorf("%w: %q", ErrInvalidWorkingHours, period)
+		}
+		parsed = append(parsed, workPeriod{startMinute, endMinute})
+	}
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i].start < parsed[j].start })
+	for i := 1; i < len(parsed); i++ {
+		if parsed[i].start < parsed[i-1].end {
+			return fmt.Errorf("%w: overlapping periods", ErrInvalidWorkingHours)
+		}
+	}
+	c.mu.Lock()
+	c.periods = parsed
+	c.mu.Unlock()
+	return nil
+}
+
+// parseClock parses HH:mm into minutes since midnight, allowing 24:00
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		if s == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// AddHoliday marks a date as a holiday.
+//
+// Parameters:
+// - date: the holiday
+// - name: the holiday name, e.g. 春节
+func (c *BusinessCalendar) AddHoliday(date time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := dateKey(date)
+	c.holidays[key] = name
+	delete(c.workdays, key)
+}
+
+// AddHolidayRange marks the dates from start to end, inclusive, as holidays.
+//
+// Parameters:
+// - start: the first day of the holiday
+// - end: the last day of the holiday
+// - name: the holiday name
+func (c *BusinessCalendar) AddHolidayRange(start, end time.Time, name string) {
+	for day := startOfDay(start); !day.After(end); day = day.AddDate(0, 0, 1) {
+		c.AddHoliday(day, name)
+	}
+}
+
+// AddWorkday marks a date as a working day even if it falls on the weekend, e.g. a make-up workday (调休).
+//
+// Parameters:
+// - date: the working day
+// - name: the name of the holiday the workday makes up for, may be empty
+func (c *BusinessCalendar) AddWorkday(date time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := dateKey(date)
+	c.workdays[key] = name
+	delete(c.holidays, key)
+}
+
+// holidayFile is a year of holiday data in the format of the holiday-cn project, which
+// transcribes the schedules published by the State Council of China:
+// {"year": 2024, "days": [{"name": "春节", "date": "2024-02-10", "isOffDay": true}, ...]}
+type holidayFile struct {
+	Year int `json:"year"`
+	Days []struct {
+		Name     string `json:"name"`
+		Date     string `json:"date"`
+		IsOffDay *bool  `json:"isOffDay"`
+	} `json:"days"`
+}
+
+// LoadHolidays loads holidays and make-up workdays from JSON in the State Council
+// schedule format used by the holiday-cn project, either one year or an array of years:
+//
+//	{"year": 2024, "days": [
+//	  {"name": "春节", "date": "2024-02-04", "isOffDay": false},
+//	  {"name": "春节", "date": "2024-02-10", "isOffDay": true}
+//	]}
+//
+// Days with isOffDay true are holidays and days with isOffDay false are make-up workdays.
+// Existing entries for other dates are kept; nothing is changed if the data is invalid.
+//
+// Parameters:
+// - r: the reader to load from
+//
+// Returns:
+// - error: ErrInvalidHolidayData if the data is malformed
+func (c *BusinessCalendar) LoadHolidays(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var files []holidayFile
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &files)
+	} else {
+		files = make([]holidayFile, 1)
+		err = json.Unmarshal(trimmed, &files[0])
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidHolidayData, err)
+	}
+
+	type entry struct {
+		date  time.Time
+		name  string
+		isOff bool
+	}
+	var entries []entry
+	for _, file := range files {
+		for _, day := range file.Days {
+			date, err := time.Parse(time.DateOnly, day.Date)
+			if err != nil || day.IsOffDay == nil {
+				return fmt.Errorf("%w: invalid day %q in year %d", ErrInvalidHolidayData, day.Date, file.Year)
+			}
+			entries = append(entries, entry{date, day.Name, *day.IsOffDay})
+		}
+	}
+	for _, e := range entries {
+		if e.isOff {
+			c.AddHoliday(e.date, e.name)
+		} else {
+			c.AddWorkday(e.date, e.name)
+		}
+	}
+	return nil
+}
+
+// LoadHolidaysFromFile loads holidays and make-up workdays from a JSON file, see LoadHolidays.
+//
+// Parameters:
+// - path: the path of the file
+//
+// Returns:
+// - error: if the file cannot be read or its data is malformed
+func (c *BusinessCalendar) LoadHolidaysFromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.LoadHolidays(f)
+}
+
+// HolidayName returns the name of the holiday on a date.
+//
+// Parameters:
+// - date: the date to check
+//
+// Returns:
+// - string: the holiday name
+// - bool: false if the date is not a holiday
+func (c *BusinessCalendar) HolidayName(date time.Time) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	name, ok := c.holidays[dateKey(date)]
+	return name, ok
+}
+
+// IsHoliday reports whether a date is a holiday.
+//
+// Parameters:
+// - date: the date to check
+//
+// Returns:
+// - bool: true if the date is a holiday
+func (c *BusinessCalendar) IsHoliday(date time.Time) bool {
+	_, ok := c.HolidayName(date)
+	return ok
+}
+
+// IsWorkday reports whether a date is a working day: a make-up workday, or a day that
+// is neither a holiday nor on the weekend.
+//
+// Parameters:
+// - date: the date to check
+//
+// Returns:
+// - bool: true if the date is a working day
+func (c *BusinessCalendar) IsWorkday(date time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isWorkday(date)
+}
+
+func (c *BusinessCalendar) isWorkday(date time.Time) bool {
+	key := dateKey(date)
+	if _, ok := c.workdays[key]; ok {
+		return true
+	}
+	if _, ok := c.holidays[key]; ok {
+		return false
+	}
+	return !c.weekend[date.Weekday()]
+}
+
+// NextWorkday returns the first working day after a date, keeping the time of day.
+//
+// Parameters:
+// - date: the date to start from
+//
+// Returns:
+// - time.Time: the next working day
+func (c *BusinessCalendar) NextWorkday(date time.Time) time.Time {
+	return c.AddWorkdays(date, 1)
+}
+
+// PreviousWorkday returns the last working day before a date, keeping the time of day.
+//
+// Parameters:
+// - date: the date to start from
+//
+// Returns:
+// - time.Time: the previous working day
+func (c *BusinessCalendar) PreviousWorkday(date time.Time) time.Time {
+	return c.AddWorkdays(date, -1)
+}
+
+// AddWorkdays returns the n-th working day after a date, or before it if n is negative,
+// keeping the time of day. The date itself is not counted; n = 0 returns the date unchanged.
+//
+// Parameters:
+// - date: the date to start from
+// - n: the number of working days to add
+//
+// Returns:
+// - time.Time: the resulting date
+func (c *BusinessCalendar) AddWorkdays(date time.Time, n int) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if c.isWorkday(date) {
+			n--
+		}
+	}
+	return date
+}
+
+// WorkdaysBetween counts the working days after start up to and including end, so that
+// AddWorkdays(start, n) lands on end when end is a working day. It is negative if end is before start.
+//
+// Parameters:
+// - start: the start date
+// - end: the end date
+//
+// Returns:
+// - int: the number of working days
+func (c *BusinessCalendar) WorkdaysBetween(start, end time.Time) int {
+	sign := 1
+	first, last := startOfDay(start), startOfDay(end)
+	if last.Before(first) {
+		sign, first, last = -1, last.AddDate(0, 0, -1), first.AddDate(0, 0, -1)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	count := 0
+	for day := first.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if c.isWorkday(day) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// startOfDay returns midnight of the calendar day of t in its location
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// workingPeriods returns the working periods of the day of t, or nil if it is not a working day
+func (c *BusinessCalendar) workingPeriods(t time.Time) [][2]time.Time {
+	if !c.isWorkday(t) {
+		return nil
+	}
+	year, month, day := t.Date()
+	periods := make([][2]time.Time, len(c.periods))
+	for i, p := range c.periods {
+		periods[i] = [2]time.Time{
+			time.Date(year, month, day, 0, p.start, 0, 0, t.Location()),
+			time.Date(year, month, day, 0, p.end, 0, 0, t.Location()),
+		}
+	}
+	return periods
+}
+
+// IsWorkingTime reports whether t falls within the working hours of a working day.
+//
+// Parameters:
+// - t: the time to check
+//
+// Returns:
+// - bool: true if t is working time
+func (c *BusinessCalendar) IsWorkingTime(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, p := range c.workingPeriods(t) {
+		if !t.Before(p[0]) && t.Before(p[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddWorkingTime adds working time to t, counting only the working hours of working days,
+// e.g. for an SLA that is due in 16 business hours. A negative d goes backwards.
+// When d is not zero and t is outside working hours, counting starts at the next
+// (or, going backwards, the previous) working period.
+//
+// Parameters:
+// - t: the time to start from
+// - d: the working time to add
+//
+// Returns:
+// - time.Time: the time at which the working time has elapsed
+func (c *BusinessCalendar) AddWorkingTime(t time.Time, d time.Duration) time.Time {
+	if d == 0 {
+		return t
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if d > 0 {
+		for day := t; ; day = startOfDay(day).AddDate(0, 0, 1) {
+			for _, p := range c.workingPeriods(day) {
+				if !t.Before(p[1]) {
+					continue
+				}
+				from := t
+				if from.Before(p[0]) {
+					from = p[0]
+				}
+				available := p[1].Sub(from)
+				if d <= available {
+					return from.Add(d)
+				}
+				d -= available
+			}
+		}
+	}
+	for day := t; ; day = startOfDay(day).AddDate(0, 0, -1) {
+		periods := c.workingPeriods(day)
+		for i := len(periods) - 1; i >= 0; i-- {
+			p := periods[i]
+			if !t.After(p[0]) {
+				continue
+			}
+			to := t
+			if to.After(p[1]) {
+				to = p[1]
+			}
+			available := to.Sub(p[0])
+			if -d <= available {
+				return to.Add(d)
+			}
+			d += available
+		}
+	}
+}
+
+// WorkingTimeBetween returns the working time between two times, negative if end is before start.
+//
+// Parameters:
+// - start: the start time
+// - end: the end time
+//
+// Returns:
+// - time.Duration: the working time between start and end
+func (c *BusinessCalendar) WorkingTimeBetween(start, end time.Time) time.Duration {
+	if end.Before(start) {
+		return -c.WorkingTimeBetween(end, start)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var total time.Duration
+	for day := startOfDay(start); !day.After(end); day = day.AddDate(0, 0, 1) {
+		for _, p := range c.workingPeriods(day) {
+			from, to := p[0], p[1]
+			if from.Before(start) {
+				from = start
+			}
+			if to.After(end) {
+				to = end
+			}
+			if to.After(from) {
+				total += to.Sub(from)
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,306 @@
+package datetime
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time. Code that reads the time or waits through a Clock can be tested
+// with a FakeClock instead of the system clock.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Since returns the time elapsed since t
+	Since(t time.Time) time.Duration
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// NewTimer creates a Timer that sends the current time on its channel after at least d
+	NewTimer(d time.Duration) Timer
+	// NewTicker creates a Ticker that sends the current time on its channel every d; d must be positive
+	NewTicker(d time.Duration) Ticker
+	// Sleep pauses the current goroutine for at least d
+	Sleep(d time.Duration)
+}
+
+// Timer is a single event created by a Clock, see time.Timer
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing and reports whether it was active
+	Stop() bool
+	// Reset changes the Timer to expire after d and reports whether it was active
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals created by a Clock, see time.Ticker
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+	// Stop turns off the Ticker
+	Stop()
+	// Reset stops the Ticker and resets its period to d
+	Reset(d time.Duration)
+}
+
+// NewRealClock returns a Clock backed by the system clock and the time package.
+//
+// Returns:
+// - Clock: the system clock
+func NewRealClock() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+type realTimer struct{ timer *time.Timer }
+
+func (t realTimer) C() <-chan time.Time        { return t.timer.C }
+func (t realTimer) Stop() bool                 { return t.timer.Stop() }
+func (t realTimer) Reset(d time.Duration) bool { return t.timer.Reset(d) }
+
+type realTicker struct{ ticker *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time   { return t.ticker.C }
+func (t realTicker) Stop()                 { t.ticker.Stop() }
+func (t realTicker) Reset(d time.Duration) { t.ticker.Reset(d) }
+
+var (
+	clockMu      sync.RWMutex
+	defaultClock Clock = realClock{}
+)
+
+// SetClock replaces the clock used by Now, GetCurrentTime, GetCurrentUTCTime, NewTimeInterval and
+// DateTime.Humanize, and by packages that default to GetCurrentTime. Tests that set a FakeClock
+// should restore the previous clock when they finish.
+//
+// Parameters:
+// - clock: the new clock; nil restores the system clock
+func SetClock(clock Clock) {
+	if clock == nil {
+		clock = realClock{}
+	}
+	clockMu.Lock()
+	defaultClock = clock
+	clockMu.Unlock()
+}
+
+// GetClock returns the clock set by SetClock, the system clock by default.
+//
+// Returns:
+// - Clock: the current clock
+func GetClock() Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return defaultClock
+}
+
+// FakeClock is a Clock whose time only moves when Advance or Set is called. Timers, tickers and
+// sleepers fire in deadline order, in the goroutine that moves the clock, with the clock set to
+// each deadline as it fires. It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	changed *sync.Cond // broadcast when a waiter is added
+	now     time.Time
+	waiters []*fakeTimer
+	seq     int64
+}
+
+// NewFakeClock creates a FakeClock.
+//
+// Parameters:
+// - now: the initial time
+//
+// Returns:
+// - *FakeClock: the fake clock
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// After returns a channel that receives the fake time once the clock reaches now+d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer creates a Timer that fires once the clock reaches now+d; it fires immediately if d is not positive
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(t, d, 0)
+	return t
+}
+
+// NewTicker creates a Ticker that fires every d of fake time. It panics if d is not positive, as time.NewTicker does.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("datetime: non-positive interval for FakeClock.NewTicker")
+	}
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(t, d, d)
+	return fakeTicker{t}
+}
+
+// Sleep blocks until another goroutine moves the clock to now+d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward, firing due timers, tickers and sleepers in deadline order.
+//
+// Parameters:
+// - d: the duration to move forward; nothing happens if it is not positive
+func (c *FakeClock) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, firing due timers, tickers and sleepers in deadline order. Moving
+// the clock backwards fires nothing.
+//
+// Parameters:
+// - t: the new time
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) > 0 && !c.waiters[0].deadline.After(t) {
+		w := c.waiters[0]
+		if w.deadline.After(c.now) {
+			c.now = w.deadline
+		}
+		c.fire(w)
+	}
+	c.now = t
+}
+
+// BlockUntil blocks until at least n timers, tickers or sleepers are waiting, so that a test can
+// advance the clock after a goroutine under test has started to wait.
+//
+// Parameters:
+// - n: the number of waiters
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.changed.Wait()
+	}
+}
+
+// schedule arms a timer to fire after d, or immediately if d is not positive; c.mu must be held
+func (c *FakeClock) schedule(t *fakeTimer, d, period time.Duration) {
+	c.seq++
+	t.seq, t.period, t.deadline = c.seq, period, c.now.Add(d)
+	if d <= 0 {
+		c.send(t)
+		return
+	}
+	c.waiters = append(c.waiters, t)
+	c.sort()
+	c.changed.Broadcast()
+}
+
+// fire delivers the first waiter and re-arms it if it is a ticker; c.mu must be held
+func (c *FakeClock) fire(t *fakeTimer) {
+	c.send(t)
+	if t.period > 0 {
+		t.deadline = t.deadline.Add(t.period)
+		c.sort()
+		return
+	}
+	c.waiters = c.waiters[1:]
+}
+
+// send delivers the current time without blocking; like time.Ticker, a tick is dropped if the
+// previous one has not been received
+func (c *FakeClock) send(t *fakeTimer) {
+	select {
+	case t.c <- c.now:
+	default:
+	}
+}
+
+// sort orders the waiters by deadline and then by creation; c.mu must be held
+func (c *FakeClock) sort() {
+	sort.Slice(c.waiters, func(i, j int) bool {
+		if c.waiters[i].deadline.Equal(c.waiters[j].deadline) {
+			return c.waiters[i].seq < c.waiters[j].seq
+		}
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+}
+
+// remove disarms a timer and reports whether it was armed; c.mu must be held
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+	period   time.Duration
+	seq      int64
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.remove(t)
+	t.clock.schedule(t, d, 0)
+	return active
+}
+
+type fakeTicker struct{ timer *fakeTimer }
+
+func (t fakeTicker) C() <-chan time.Time { return t.timer.c }
+
+func (t fakeTicker) Stop() { t.timer.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("datetime: non-positive interval for FakeClock Ticker.Reset")
+	}
+	t.timer.clock.mu.Lock()
+	defer t.timer.clock.mu.Unlock()
+	t.timer.clock.remove(t.timer)
+	t.timer.clock.schedule(t.timer, d, d)
+}
@@ -23,3 +23,23 @@ const (
 	CustomDate     = "02-01-2006"                          // Custom date format (DD-MM-YYYY)
 	CustomTime     = "15:04:05"                            // Custom time format (HH:MM:SS)
 )
+
+// Java/Unicode-style patterns for FormatDatePattern, ParseDatePattern and CompilePattern
+const (
+	NormDatePattern           = "yyyy-MM-dd"                      // Date, e.g. 2024-03-07
+	NormTimePattern           = "HH:mm:ss"                        // Time, e.g. 13:45:30
+	NormDatetimeMinutePattern = "yyyy-MM-dd HH:mm"                // Date and time without seconds
+	NormDatetimePattern       = "yyyy-MM-dd HH:mm:ss"             // Date and time
+	NormDatetimeMsPattern     = "yyyy-MM-dd HH:mm:ss.SSS"         // Date and time with milliseconds
+	PureDatePattern           = "yyyyMMdd"                        // Date without separators
+	PureTimePattern           = "HHmmss"                          // Time without separators
+	PureDatetimePattern       = "yyyyMMddHHmmss"                  // Date and time without separators
+	PureDatetimeMsPattern     = "yyyyMMddHHmmssSSS"               // Date and time with milliseconds without separators
+	ChineseDatePattern        = "yyyy年MM月dd日"                     // Chinese date, e.g. 2024年03月07日
+	ChineseDatetimePattern    = "yyyy年MM月dd日HH时mm分ss秒"            // Chinese date and time
+	UTCPattern                = "yyyy-MM-dd'T'HH:mm:ss'Z'"        // UTC date and time with a literal Z
+	UTCMsPattern              = "yyyy-MM-dd'T'HH:mm:ss.SSS'Z'"    // UTC date and time with milliseconds
+	UTCWithOffsetPattern      = "yyyy-MM-dd'T'HH:mm:ssXXX"        // Date and time with offset, e.g. +08:00
+	UTCMsWithOffsetPattern    = "yyyy-MM-dd'T'HH:mm:ss.SSSXXX"    // Date and time with milliseconds and offset
+	HTTPDatetimePattern       = "EEE, dd MMM yyyy HH:mm:ss 'GMT'" // HTTP date, the time must be in UTC
+)
@@ -20,12 +20,12 @@ func NewDateTime(t time.Time) *DateTime {
 	return &DateTime{Time: t}
 }
 
-// Now returns the current DateTime
+// Now returns the current DateTime of the clock set by SetClock
 //
 // Returns:
 // - *DateTime: the current DateTime
 func Now() *DateTime {
-	return NewDateTime(time.Now())
+	return NewDateTime(GetClock().Now())
 }
 
 // Format formats the DateTime according to the given pattern
@@ -39,6 +39,18 @@ func (dt *DateTime) Format(pattern string) string {
 	return dt.Time.Format(pattern)
 }
 
+// FormatPattern formats the DateTime according to a Java/Unicode-style pattern such as "yyyy-MM-dd HH:mm:ss"
+//
+// Parameters:
+// - pattern: the pattern, see Pattern for the supported letters
+//
+// Returns:
+// - string: the formatted DateTime string
+// - error: ErrInvalidPattern if the pattern is malformed
+func (dt *DateTime) FormatPattern(pattern string) (string, error) {
+	return FormatDatePattern(dt.Time, pattern)
+}
+
 // AddDays adds the given number of days to the DateTime
 //
 // Parameters:
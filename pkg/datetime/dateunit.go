@@ -1,7 +1,7 @@
 package datetime
 
 // DateUnit represents a unit of time
-type DateUnit int
+type DateUnit int64
 
 const (
 	Millisecond DateUnit = 1
@@ -10,6 +10,13 @@ const (
 	Hour        DateUnit = 60 * Minute
 	Day         DateUnit = 24 * Hour
 	Week        DateUnit = 7 * Day
+
+	// MonthUnit, Quarter and Year are calendar units whose length varies; their values are the
+	// average lengths in the Gregorian calendar. MonthUnit is named so because Month is the
+	// month of year type.
+	MonthUnit DateUnit = 2629746 * Second
+	Quarter   DateUnit = 3 * MonthUnit
+	Year      DateUnit = 12 * MonthUnit
 )
 
 // GetMillis returns the number of milliseconds in the DateUnit
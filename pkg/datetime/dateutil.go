@@ -225,6 +225,9 @@ func ChineseZodiac(year int) string {
 // Returns:
 // - []time.Time: a slice of dates between the start and end dates
 // - error: if the end date is before the start date
+//
+// Deprecated: DateRange only steps by one day and builds the whole slice. Use TimeRange.Iterate, for dates at midnight
+// TimeRange{Start: start, End: end.AddDate(0, 0, 1)}.Iterate(Day), which steps lazily by any DateUnit.
 func DateRange(start, end time.Time) ([]time.Time, error) {
 	if end.Before(start) {
 		return nil, errors.New("end date must be after start date")
@@ -302,20 +305,20 @@ func AddYears(date time.Time, years int) time.Time {
 	return date.AddDate(years, 0, 0)
 }
 
-// GetCurrentTime returns the current local time.
+// GetCurrentTime returns the current local time of the clock set by SetClock.
 //
 // Returns:
 // - time.Time: the current local time
 func GetCurrentTime() time.Time {
-	return time.Now()
+	return GetClock().Now()
 }
 
-// GetCurrentUTCTime returns the current UTC time.
+// GetCurrentUTCTime returns the current UTC time of the clock set by SetClock.
 //
 // Returns:
 // - time.Time: the current UTC time
 func GetCurrentUTCTime() time.Time {
-	return time.Now().UTC()
+	return GetClock().Now().UTC()
 }
 
 // IsWeekend checks if a given date falls on a weekend.
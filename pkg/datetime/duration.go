@@ -0,0 +1,427 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDuration is returned when a duration string cannot be parsed
+var ErrInvalidDuration = errors.New("datetime: invalid duration")
+
+// durationUnits are the units accepted by ParseDuration
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond, // U+00B5 micro sign
+	"μs": time.Microsecond, // U+03BC Greek letter mu
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+}
+
+// FormatDurationCompact formats a duration as days, hours, minutes and seconds, e.g. "1d 4h 3m".
+// Zero parts are omitted and fractions of a second are truncated; durations shorter than a
+// second use the format of time.Duration, e.g. "350ms". The result is accepted by ParseDuration.
+//
+// Parameters:
+// - d: the duration to format
+//
+// Returns:
+// - string: the formatted duration
+func FormatDurationCompact(d time.Duration) string {
+	if d > -time.Second && d < time.Second {
+		return d.String()
+	}
+	rest := absDuration(d)
+	var parts []string
+	for _, u := range [...]struct {
+		suffix string
+		size   time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}, {"s", time.Second}} {
+		n := rest / u.size
+		rest -= n * u.size
+		if n > 0 {
+			parts = append(parts, strconv.FormatInt(int64(n), 10)+u.suffix)
+		}
+	}
+	s := strings.Join(parts, " ")
+	if d < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// FormatDurationLong formats a duration with the unit words of a locale, e.g. "1 day 4 hours 3 minutes"
+// or "1天4小时3分钟". Zero parts are omitted and fractions of a second are truncated.
+//
+// Parameters:
+// - d: the duration to format
+// - locale: the locale, English if it is not registered
+//
+// Returns:
+// - string: the formatted duration
+func FormatDurationLong(d time.Duration, locale Locale) string {
+	names := localeNames(locale)
+	rest := absDuration(d)
+	var parts []string
+	for _, u := range [...]struct {
+		unit int
+		size time.Duration
+	}{{unitDay, 24 * time.Hour}, {unitHour, time.Hour}, {unitMinute, time.Minute}, {unitSecond, time.Second}} {
+		n := rest / u.size
+		rest -= n * u.size
+		if n > 0 {
+			parts = append(parts, names.unit(u.unit, int64(n)))
+		}
+	}
+	if len(parts) == 0 {
+		return names.unit(unitSecond, 0)
+	}
+	s := strings.Join(parts, names.Separator)
+	if d < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// ParseDuration parses a duration like time.ParseDuration and additionally accepts the units
+// "d" (24 hours) and "w" (7 days), spaces between the parts, e.g. "1d12h", "2w" or "1d 4h 3m",
+// and ISO 8601 durations without years and months, e.g. "P1DT4H" or "PT1.5S".
+//
+// Parameters:
+// - s: the duration string
+//
+// Returns:
+// - time.Duration: the parsed duration
+// - error: ErrInvalidDuration if the string is malformed or overflows
+func ParseDuration(s string) (time.Duration, error) {
+	value := strings.TrimSpace(s)
+	rest, negative := trimSign(value)
+	if strings.HasPrefix(rest, "P") {
+		iso, err := ParseISODuration(value)
+		if err != nil {
+			return 0, err
+		}
+		if iso.Years != 0 || iso.Months != 0 {
+			return 0, fmt.Errorf("%w: %q has years or months, use ParseISODuration", ErrInvalidDuration, s)
+		}
+		days := int64(iso.Weeks)*7 + int64(iso.Days)
+		d, ok := addDuration(time.Duration(days)*24*time.Hour, iso.Time, days <= math.MaxInt64/int64(24*time.Hour))
+		if !ok {
+			return 0, fmt.Errorf("%w: %q overflows", ErrInvalidDuration, s)
+		}
+		if iso.Negative {
+			d = -d
+		}
+		return d, nil
+	}
+	if rest == "0" {
+		return 0, nil
+	}
+	if rest == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+
+	var total time.Duration
+	ok := true
+	for first := true; rest != ""; first = false {
+		if !first {
+			rest = strings.TrimLeft(rest, " ")
+		}
+		whole, frac, unit, next := splitDurationPart(rest)
+		size, known := durationUnits[unit]
+		if (whole == "" && frac == "") || !known {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		part, partOK := scaleDuration(whole, frac, size)
+		total, ok = addDuration(total, part, ok && partOK)
+		rest = next
+	}
+	if !ok {
+		return 0, fmt.Errorf("%w: %q overflows", ErrInvalidDuration, s)
+	}
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// splitDurationPart splits the leading "<whole>.<frac><unit>" part off a duration string
+func splitDurationPart(s string) (whole, frac, unit, rest string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	whole = s[:i]
+	if i < len(s) && s[i] == '.' {
+		j := i + 1
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		frac, i = s[i+1:j], j
+		if frac == "" && whole == "" {
+			return "", "", "", ""
+		}
+	}
+	j := i
+	for j < len(s) && s[j] != '.' && s[j] != ' ' && (s[j] < '0' || s[j] > '9') {
+		j++
+	}
+	return whole, frac, s[i:j], s[j:]
+}
+
+// ISODuration is an ISO 8601 duration such as "P1Y2M3DT4H5M6.5S". Years, months, weeks and
+// days are calendar amounts whose length depends on the date they are added to.
+type ISODuration struct {
+	Negative bool
+	Years    int
+	Months   int
+	Weeks    int
+	Days     int
+	Time     time.Duration // hours, minutes and seconds
+}
+
+// isoTimeUnits are the time part designators of ISO 8601 durations
+var isoTimeUnits = map[string]time.Duration{"H": time.Hour, "M": time.Minute, "S": time.Second}
+
+// ParseISODuration parses an ISO 8601 duration "PnYnMnWnDTnHnMnS". Parts are optional but must
+// appear in this order and at least one is required; only the last part may have a fraction
+// (with '.' or ','), and only if it is hours, minutes or seconds. A leading '-' negates the duration.
+//
+// Parameters:
+// - s: the duration string, e.g. "P1Y2M3DT4H" or "PT1.5S"
+//
+// Returns:
+// - ISODuration: the parsed duration
+// - error: ErrInvalidDuration if the string is malformed or overflows
+func ParseISODuration(s string) (ISODuration, error) {
+	var d ISODuration
+	rest, negative := trimSign(strings.TrimSpace(s))
+	d.Negative = negative
+	if !strings.HasPrefix(rest, "P") || len(rest) == 1 {
+		return ISODuration{}, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+	}
+	rest = rest[1:]
+
+	designators := "YMWD"
+	inTime, ok := false, true
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime || len(rest) == 1 {
+				return ISODuration{}, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+			}
+			inTime, designators, rest = true, "HMS", rest[1:]
+			continue
+		}
+		whole, frac, designator, next := splitISOPart(rest)
+		index := strings.Index(designators, designator)
+		if whole == "" || designator == "" || index < 0 || (frac != "" && (!inTime || next != "")) {
+			return ISODuration{}, fmt.Errorf("%w: %q", ErrInvalidDuration, s)
+		}
+		designators, rest = designators[index+1:], next
+		if inTime {
+			part, partOK := scaleDuration(whole, frac, isoTimeUnits[designator])
+			d.Time, ok = addDuration(d.Time, part, ok && partOK)
+			continue
+		}
+		n, err := strconv.Atoi(whole)
+		if err != nil || n > math.MaxInt32 {
+			ok = false
+		}
+		switch designator {
+		case "Y":
+			d.Years = n
+		case "M":
+			d.Months = n
+		case "W":
+			d.Weeks = n
+		case "D":
+			d.Days = n
+		}
+	}
+	if !ok {
+		return ISODuration{}, fmt.Errorf("%w: %q overflows", ErrInvalidDuration, s)
+	}
+	return d, nil
+}
+
+// splitISOPart splits the leading "<whole>[.,]<frac><designator>" part off an ISO 8601 duration
+func splitISOPart(s string) (whole, frac, designator, rest string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	whole = s[:i]
+	if i < len(s) && (s[i] == '.' || s[i] == ',') {
+		j := i + 1
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		if j == i+1 {
+			return "", "", "", ""
+		}
+		frac, i = s[i+1:j], j
+	}
+	if i == len(s) {
+		return whole, frac, "", ""
+	}
+	return whole, frac, s[i : i+1], s[i+1:]
+}
+
+// AddTo adds the duration to a time. Years, months, weeks and days are added to the calendar
+// date as by time.AddDate, then the time part is added.
+//
+// Parameters:
+// - t: the start time
+//
+// Returns:
+// - time.Time: the end time
+func (d ISODuration) AddTo(t time.Time) time.Time {
+	if d.Negative {
+		return t.AddDate(-d.Years, -d.Months, -(d.Weeks*7 + d.Days)).Add(-d.Time)
+	}
+	return t.AddDate(d.Years, d.Months, d.Weeks*7+d.Days).Add(d.Time)
+}
+
+// DurationFrom returns the exact length of the duration when added to a time.
+//
+// Parameters:
+// - t: the start time
+//
+// Returns:
+// - time.Duration: the elapsed time between t and d.AddTo(t)
+func (d ISODuration) DurationFrom(t time.Time) time.Duration {
+	return d.AddTo(t).Sub(t)
+}
+
+// String formats the duration in ISO 8601, e.g. "P1Y2M3DT4H5M6.5S", "PT0S" for zero and a
+// leading '-' for negative durations.
+//
+// Returns:
+// - string: the ISO 8601 duration
+func (d ISODuration) String() string {
+	var b strings.Builder
+	if d.Negative {
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+	for _, part := range [...]struct {
+		n          int
+		designator byte
+	}{{d.Years, 'Y'}, {d.Months, 'M'}, {d.Weeks, 'W'}, {d.Days, 'D'}} {
+		if part.n != 0 {
+			b.WriteString(strconv.Itoa(part.n))
+			b.WriteByte(part.designator)
+		}
+	}
+	rest := absDuration(d.Time)
+	if rest == 0 {
+		if b.Len() == 1 || (b.Len() == 2 && d.Negative) {
+			b.WriteString("T0S")
+		}
+		return b.String()
+	}
+	b.WriteByte('T')
+	if h := rest / time.Hour; h > 0 {
+		b.WriteString(strconv.FormatInt(int64(h), 10))
+		b.WriteByte('H')
+		rest -= h * time.Hour
+	}
+	if m := rest / time.Minute; m > 0 {
+		b.WriteString(strconv.FormatInt(int64(m), 10))
+		b.WriteByte('M')
+		rest -= m * time.Minute
+	}
+	if rest > 0 {
+		b.WriteString(strconv.FormatInt(int64(rest/time.Second), 10))
+		if ns := rest % time.Second; ns > 0 {
+			b.WriteByte('.')
+			b.WriteString(strings.TrimRight(fmt.Sprintf("%09d", int64(ns)), "0"))
+		}
+		b.WriteByte('S')
+	}
+	return b.String()
+}
+
+// FormatISODuration formats a duration in ISO 8601 using hours, minutes and seconds, e.g. "PT28H3M".
+//
+// Parameters:
+// - d: the duration to format
+//
+// Returns:
+// - string: the ISO 8601 duration
+func FormatISODuration(d time.Duration) string {
+	return ISODuration{Negative: d < 0, Time: absDuration(d)}.String()
+}
+
+// trimSign removes a leading '+' or '-' and reports whether it was '-'
+func trimSign(s string) (string, bool) {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		return s[1:], s[0] == '-'
+	}
+	return s, false
+}
+
+// scaleDuration returns whole.frac units, reporting false on overflow or malformed digits
+func scaleDuration(whole, frac string, unit time.Duration) (time.Duration, bool) {
+	var n int64
+	if whole != "" {
+		var err error
+		if n, err = strconv.ParseInt(whole, 10, 64); err != nil || n > math.MaxInt64/int64(unit) {
+			return 0, false
+		}
+	}
+	d := time.Duration(n) * unit
+	if frac != "" {
+		// the digits are accumulated as an integer and scaled exactly as time.ParseDuration
+		// does, so that both agree to the nanosecond
+		f, scale := leadingFraction(frac)
+		return addDuration(d, time.Duration(float64(f)*(float64(unit)/scale)), true)
+	}
+	return d, true
+}
+
+// leadingFraction returns the digits of a fraction as an integer and the power of ten it is
+// scaled by, ignoring digits that would overflow, like the function of the same name in the
+// time package
+func leadingFraction(frac string) (uint64, float64) {
+	var f uint64
+	scale := 1.0
+	for i := 0; i < len(frac); i++ {
+		if f > (1<<63-1)/10 {
+			break
+		}
+		next := f*10 + uint64(frac[i]-'0')
+		if next > 1<<63 {
+			break
+		}
+		f, scale = next, scale*10
+	}
+	return f, scale
+}
+
+// addDuration adds two non-negative durations if ok, reporting false on overflow
+func addDuration(a, b time.Duration, ok bool) (time.Duration, bool) {
+	if !ok || a > math.MaxInt64-b {
+		return 0, false
+	}
+	return a + b, true
+}
+
+// absDuration returns the absolute value of a duration, saturating at the maximum duration
+func absDuration(d time.Duration) time.Duration {
+	if d >= 0 {
+		return d
+	}
+	if d == math.MinInt64 {
+		return math.MaxInt64
+	}
+	return -d
+}
@@ -0,0 +1,115 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownFestival is returned by FestivalDate for names that are not traditional festivals
+var ErrUnknownFestival = errors.New("datetime: unknown festival")
+
+// festival is a traditional festival on a lunar date, on a solar term, or on the last day
+// of the lunar year when month is 0 and term is negative
+type festival struct {
+	name       string
+	month, day int
+	term       SolarTerm
+}
+
+// traditionalFestivals are the traditional Chinese festivals in the order of the lunar year
+var traditionalFestivals = []festival{
+	{name: "春节", month: 1, day: 1},
+	{name: "元宵节", month: 1, day: 15},
+	{name: "龙抬头", month: 2, day: 2},
+	{name: "上巳节", month: 3, day: 3},
+	{name: "清明节", term: PureBrightness},
+	{name: "端午节", month: 5, day: 5},
+	{name: "七夕节", month: 7, day: 7},
+	{name: "中元节", month: 7, day: 15},
+	{name: "中秋节", month: 8, day: 15},
+	{name: "重阳节", month: 9, day: 9},
+	{name: "寒衣节", month: 10, day: 1},
+	{name: "下元节", month: 10, day: 15},
+	{name: "冬至节", term: WinterSolstice},
+	{name: "腊八节", month: 12, day: 8},
+	{name: "小年", month: 12, day: 23},
+	{name: "除夕", term: -1},
+}
+
+// TraditionalFestivals returns the names of the supported traditional festivals in the order of the lunar year
+//
+// Returns:
+// - []string: the festival names
+func TraditionalFestivals() []string {
+	names := make([]string, len(traditionalFestivals))
+	for i, f := range traditionalFestivals {
+		names[i] = f.name
+	}
+	return names
+}
+
+// GetFestivals returns the traditional festivals that fall on a date. Festivals on lunar
+// dates are not repeated in leap months; 除夕 is the last day of the lunar year.
+//
+// Parameters:
+// - date: the Gregorian date; only its calendar day is used
+//
+// Returns:
+// - []string: the festival names, empty if there are none or the date is out of range
+func GetFestivals(date time.Time) []string {
+	lunar, err := SolarToLunar(date)
+	if err != nil {
+		return nil
+	}
+	term, isTerm := GetSolarTerm(date)
+	var names []string
+	for _, f := range traditionalFestivals {
+		var match bool
+		switch {
+		case f.month > 0:
+			match = !lunar.IsLeapMonth && lunar.Month == f.month && lunar.Day == f.day
+		case f.term >= 0:
+			match = isTerm && term == f.term
+		default:
+			next, err := SolarToLunar(date.AddDate(0, 0, 1))
+			match = err == nil && next.Year == lunar.Year+1
+		}
+		if match {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// FestivalDate returns the Gregorian date of a traditional festival in a lunar year.
+// Festivals late in the lunar year fall in the next Gregorian year, e.g. 除夕 of
+// lunar year 2023 is 2024-02-09.
+//
+// Parameters:
+// - year: the lunar year
+// - name: the festival name, one of TraditionalFestivals
+//
+// Returns:
+// - time.Time: the date at midnight UTC
+// - error: ErrUnknownFestival or ErrLunarOutOfRange
+func FestivalDate(year int, name string) (time.Time, error) {
+	for _, f := range traditionalFestivals {
+		if f.name != name {
+			continue
+		}
+		switch {
+		case f.month > 0:
+			return LunarToSolar(year, f.month, f.day, false)
+		case f.term >= 0:
+			return SolarTermDate(year, f.term)
+		default:
+			if year < MinLunarYear || year > MaxLunarYear {
+				return time.Time{}, ErrLunarOutOfRange
+			}
+			start, _ := LunarToSolar(year, 1, 1, false)
+			return start.AddDate(0, 0, LunarYearDays(year)-1), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrUnknownFestival, name)
+}
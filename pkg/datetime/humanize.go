@@ -0,0 +1,179 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidLocale is returned by RegisterLocale for incomplete locale names
+var ErrInvalidLocale = errors.New("datetime: invalid locale")
+
+// Locale identifies the language of humanized times and durations
+type Locale string
+
+const (
+	// LocaleEnglish renders "3 minutes ago", "in 2 days" and "1 day 4 hours"
+	LocaleEnglish Locale = "en"
+	// LocaleChinese renders "3分钟前", "2天后" and "1天4小时"
+	LocaleChinese Locale = "zh"
+)
+
+// Indexes of LocaleNames.Units
+const (
+	unitSecond = iota
+	unitMinute
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+	unitCount
+)
+
+// UnitNames are the fmt formats of a time unit taking the count as %d, e.g. "%d minute" and "%d minutes"
+type UnitNames struct {
+	One   string
+	Other string
+}
+
+// LocaleNames are the words of a locale used by Humanize and FormatDurationLong
+type LocaleNames struct {
+	JustNow   string               // shown for times less than 10 seconds away
+	Past      string               // fmt format of past times taking the amount as %s, e.g. "%s ago"
+	Future    string               // fmt format of future times taking the amount as %s, e.g. "in %s"
+	Separator string               // placed between the parts of a long duration
+	Units     [unitCount]UnitNames // second, minute, hour, day, week, month and year
+}
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[Locale]LocaleNames{
+		LocaleEnglish: {
+			JustNow:   "just now",
+			Past:      "%s ago",
+			Future:    "in %s",
+			Separator: " ",
+			Units: [unitCount]UnitNames{
+				{"%d second", "%d seconds"},
+				{"%d minute", "%d minutes"},
+				{"%d hour", "%d hours"},
+				{"%d day", "%d days"},
+				{"%d week", "%d weeks"},
+				{"%d month", "%d months"},
+				{"%d year", "%d years"},
+			},
+		},
+		LocaleChinese: {
+			JustNow: "刚刚",
+			Past:    "%s前",
+			Future:  "%s后",
+			Units: [unitCount]UnitNames{
+				{"%d秒", "%d秒"},
+				{"%d分钟", "%d分钟"},
+				{"%d小时", "%d小时"},
+				{"%d天", "%d天"},
+				{"%d周", "%d周"},
+				{"%d个月", "%d个月"},
+				{"%d年", "%d年"},
+			},
+		},
+	}
+)
+
+// RegisterLocale adds or replaces the words of a locale.
+//
+// Parameters:
+// - locale: the locale identifier
+// - names: the words; every field except Separator is required
+//
+// Returns:
+// - error: ErrInvalidLocale if a required field is empty
+func RegisterLocale(locale Locale, names LocaleNames) error {
+	if locale == "" || names.JustNow == "" || !strings.Contains(names.Past, "%s") || !strings.Contains(names.Future, "%s") {
+		return fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+	for _, unit := range names.Units {
+		if !strings.Contains(unit.One, "%d") || !strings.Contains(unit.Other, "%d") {
+			return fmt.Errorf("%w: %q has incomplete units", ErrInvalidLocale, locale)
+		}
+	}
+	localesMu.Lock()
+	locales[locale] = names
+	localesMu.Unlock()
+	return nil
+}
+
+// localeNames returns the words of a locale, falling back to English for unknown locales
+func localeNames(locale Locale) LocaleNames {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	if names, ok := locales[locale]; ok {
+		return names
+	}
+	return locales[LocaleEnglish]
+}
+
+// unit formats a count of a unit
+func (names LocaleNames) unit(unit int, n int64) string {
+	format := names.Units[unit].Other
+	if n == 1 {
+		format = names.Units[unit].One
+	}
+	return fmt.Sprintf(format, n)
+}
+
+// Humanize describes a time relative to now, e.g. "3 minutes ago", "in 2 days", "刚刚" or "3天前".
+// The amount is truncated to the largest fitting unit; months count as 30 days and years as 365 days.
+//
+// Parameters:
+// - t: the time to describe
+// - now: the reference time
+// - locale: the locale, English if it is not registered
+//
+// Returns:
+// - string: the relative time
+func Humanize(t, now time.Time, locale Locale) string {
+	names := localeNames(locale)
+	d := t.Sub(now)
+	format := names.Future
+	if d < 0 {
+		d, format = absDuration(d), names.Past
+	}
+	if d < 10*time.Second {
+		return names.JustNow
+	}
+
+	var amount string
+	days := int64(d / (24 * time.Hour))
+	switch {
+	case d < time.Minute:
+		amount = names.unit(unitSecond, int64(d/time.Second))
+	case d < time.Hour:
+		amount = names.unit(unitMinute, int64(d/time.Minute))
+	case d < 24*time.Hour:
+		amount = names.unit(unitHour, int64(d/time.Hour))
+	case days < 7:
+		amount = names.unit(unitDay, days)
+	case days < 30:
+		amount = names.unit(unitWeek, days/7)
+	case days < 365:
+		amount = names.unit(unitMonth, days/30)
+	default:
+		amount = names.unit(unitYear, days/365)
+	}
+	return fmt.Sprintf(format, amount)
+}
+
+// Humanize describes the DateTime relative to the current time of the clock set by SetClock.
+//
+// Parameters:
+// - locale: the locale, English if it is not registered
+//
+// Returns:
+// - string: the relative time, e.g. "3 minutes ago"
+func (dt *DateTime) Humanize(locale Locale) string {
+	return Humanize(dt.Time, GetClock().Now(), locale)
+}
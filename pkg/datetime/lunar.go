@@ -0,0 +1,338 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Range of lunar years supported by the lunar calendar functions
+const (
+	MinLunarYear = 1900
+	MaxLunarYear = 2100
+)
+
+var (
+	// ErrLunarOutOfRange is returned for dates outside the lunar years MinLunarYear to MaxLunarYear
+	ErrLunarOutOfRange = errors.New("datetime: date out of lunar calendar range")
+	// ErrInvalidLunarDate is returned for lunar dates that do not exist, such as a leap month in a year without one
+	ErrInvalidLunarDate = errors.New("datetime: invalid lunar date")
+)
+
+// lunarYears encodes the lunar years 1900 to 2100:
+// bits 0-3 are the leap month (0 for none), bits 4-15 the lengths of months 12 to 1
+// (1 for 30 days, 0 for 29 days) and bit 16 the length of the leap month.
+// The month starts are the days of the new moons in Beijing time as computed by the
+// Purple Mountain Observatory, so 2033 has the leap eleventh month (闰十一月). The widely
+// copied table this data derives from puts one month start a day off in 1954, 1956, 1978,
+// 2057 and 2060; those years are corrected here. In 2057 the new moon of the ninth month
+// falls less than a minute before midnight on September 28.
+var lunarYears = [...]uint32{
+	0x04bd8, 0x04ae0, 0x0a570, 0x054d5, 0x0d260, 0x0d950, 0x16554, 0x056a0, 0x09ad0, 0x055d2, // 1900-1909
+	0x04ae0, 0x0a5b6, 0x0a4d0, 0x0d250, 0x1d255, 0x0b540, 0x0d6a0, 0x0ada2, 0x095b0, 0x14977, // 1910-1919
+	0x04970, 0x0a4b0, 0x0b4b5, 0x06a50, 0x06d40, 0x1ab54, 0x02b60, 0x09570, 0x052f2, 0x04970, // 1920-1929
+	0x06566, 0x0d4a0, 0x0ea50, 0x16a95, 0x05ad0, 0x02b60, 0x186e3, 0x092e0, 0x1c8d7, 0x0c950, // 1930-1939
+	0x0d4a0, 0x1d8a6, 0x0b550, 0x056a0, 0x1a5b4, 0x025d0, 0x092d0, 0x0d2b2, 0x0a950, 0x0b557, // 1940-1949
+	0x06ca0, 0x0b550, 0x15355, 0x04da0, 0x0a5b0, 0x14573, 0x052b0, 0x0a9a8, 0x0e950, 0x06aa0, // 1950-1959
+	0x0aea6, 0x0ab50, 0x04b60, 0x0aae4, 0x0a570, 0x05260, 0x0f263, 0x0d950, 0x05b57, 0x056a0, // 1960-1969
+	0x096d0, 0x04dd5, 0x04ad0, 0x0a4d0, 0x0d4d4, 0x0d250, 0x0d558, 0x0b540, 0x0b6a0, 0x195a6, // 1970-1979
+	0x095b0, 0x049b0, 0x0a974, 0x0a4b0, 0x0b27a, 0x06a50, 0x06d40, 0x0af46, 0x0ab60, 0x09570, // 1980-1989
+	0x04af5, 0x04970, 0x064b0, 0x074a3, 0x0ea50, 0x06b58, 0x05ac0, 0x0ab60, 0x096d5, 0x092e0, // 1990-1999
+	0x0c960, 0x0d954, 0x0d4a0, 0x0da50, 0x07552, 0x056a0, 0x0abb7, 0x025d0, 0x092d0, 0x0cab5, // 2000-2009
+	0x0a950, 0x0b4a0, 0x0baa4, 0x0ad50, 0x055d9, 0x04ba0, 0x0a5b0, 0x15176, 0x052b0, 0x0a930, // 2010-2019
+	0x07954, 0x06aa0, 0x0ad50, 0x05b52, 0x04b60, 0x0a6e6, 0x0a4e0, 0x0d260, 0x0ea65, 0x0d530, // 2020-2029
+	0x05aa0, 0x076a3, 0x096d0, 0x04afb, 0x04ad0, 0x0a4d0, 0x1d0b6, 0x0d250, 0x0d520, 0x0dd45, // 2030-2039
+	0x0b5a0, 0x056d0, 0x055b2, 0x049b0, 0x0a577, 0x0a4b0, 0x0aa50, 0x1b255, 0x06d20, 0x0ada0, // 2040-2049
+	0x14b63, 0x09370, 0x049f8, 0x04970, 0x064b0, 0x168a6, 0x0ea50, 0x06aa0, 0x1a6c4, 0x0aae0, // 2050-2059
+	0x092e0, 0x0d2e3, 0x0c960, 0x0d557, 0x0d4a0, 0x0da50, 0x05d55, 0x056a0, 0x0a6d0, 0x055d4, // 2060-2069
+	0x052d0, 0x0a9b8, 0x0a950, 0x0b4a0, 0x0b6a6, 0x0ad50, 0x055a0, 0x0aba4, 0x0a5b0, 0x052b0, // 2070-2079
+	0x0b273, 0x06930, 0x07337, 0x06aa0, 0x0ad50, 0x14b55, 0x04b60, 0x0a570, 0x054e4, 0x0d160, // 2080-2089
+	0x0e968, 0x0d520, 0x0daa0, 0x16aa6, 0x056d0, 0x04ae0, 0x0a9d4, 0x0a2d0, 0x0d150, 0x0f252, // 2090-2099
+	0x0d520, // 2100
+}
+
+// lunarEpoch is the Gregorian date of the first day of lunar year 1900
+var lunarEpoch = time.Date(1900, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+var (
+	heavenlyStems   = [10]string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+	earthlyBranches = [12]string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+	zodiacAnimals   = [12]string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+	lunarMonthNames = [12]string{"正", "二", "三", "四", "五", "六", "七", "八", "九", "十", "冬", "腊"}
+	lunarDayTens    = [4]string{"初", "十", "廿", "三"}
+	lunarDayUnits   = [10]string{"十", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+)
+
+// Lunar is a date in the Chinese lunar calendar
+type Lunar struct {
+	Year        int  // lunar year, beginning at the Spring Festival
+	Month       int  // month, 1 to 12
+	Day         int  // day of month, 1 to 30
+	IsLeapMonth bool // whether the month is the leap month following Month
+}
+
+// LeapMonth returns the leap month of a lunar year, or 0 if the year has none.
+//
+// Parameters:
+// - year: the lunar year
+//
+// Returns:
+// - int: the leap month, or 0
+func LeapMonth(year int) int {
+	if year < MinLunarYear || year > MaxLunarYear {
+		return 0
+	}
+	return int(lunarYears[year-MinLunarYear] & 0xf)
+}
+
+// LunarMonthDays returns the number of days in a lunar month.
+//
+// Parameters:
+// - year: the lunar year
+// - month: the month, 1 to 12
+// - leap: whether to use the leap month following month
+//
+// Returns:
+// - int: 29 or 30, or 0 if the month does not exist
+func LunarMonthDays(year, month int, leap bool) int {
+	if year < MinLunarYear || year > MaxLunarYear || month < 1 || month > 12 {
+		return 0
+	}
+	info := lunarYears[year-MinLunarYear]
+	var bit uint32 = 0x10000 >> month
+	if leap {
+		if int(info&0xf) != month {
+			return 0
+		}
+		bit = 0x10000
+	}
+	if info&bit != 0 {
+		return 30
+	}
+	return 29
+}
+
+// LunarYearDays returns the number of days in a lunar year, including its leap month.
+//
+// Parameters:
+// - year: the lunar year
+//
+// Returns:
+// - int: the number of days, or 0 if the year is out of range
+func LunarYearDays(year int) int {
+	if year < MinLunarYear || year > MaxLunarYear {
+		return 0
+	}
+	days := 0
+	for month := 1; month <= 12; month++ {
+		days += LunarMonthDays(year, month, false)
+	}
+	if leap := LeapMonth(year); leap > 0 {
+		days += LunarMonthDays(year, leap, true)
+	}
+	return days
+}
+
+// civilDays returns the number of days from the lunar epoch to the date's calendar day
+func civilDays(date time.Time) int {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(lunarEpoch).Hours() / 24)
+}
+
+// SolarToLunar converts a Gregorian date to the lunar calendar. Only the calendar day of
+// date in its own location is used.
+//
+// Parameters:
+// - date: the Gregorian date
+//
+// Returns:
+// - Lunar: the lunar date
+// - error: ErrLunarOutOfRange if the date is outside the supported lunar years
+func SolarToLunar(date time.Time) (Lunar, error) {
+	offset := civilDays(date)
+	if offset < 0 {
+		return Lunar{}, ErrLunarOutOfRange
+	}
+	year := MinLunarYear
+	for ; year <= MaxLunarYear; year++ {
+		days := LunarYearDays(year)
+		if offset < days {
+			break
+		}
+		offset -= days
+	}
+	if year > MaxLunarYear {
+		return Lunar{}, ErrLunarOutOfRange
+	}
+
+	leap := LeapMonth(year)
+	for month := 1; month <= 12; month++ {
+		days := LunarMonthDays(year, month, false)
+		if offset < days {
+			return Lunar{Year: year, Month: month, Day: offset + 1}, nil
+		}
+		offset -= days
+		if month == leap {
+			days = LunarMonthDays(year, month, true)
+			if offset < days {
+				return Lunar{Year: year, Month: month, Day: offset + 1, IsLeapMonth: true}, nil
+			}
+			offset -= days
+		}
+	}
+	// Unreachable: offset is less than the days of the year
+	return Lunar{}, ErrLunarOutOfRange
+}
+
+// LunarToSolar converts a lunar date to the Gregorian calendar.
+//
+// Parameters:
+// - year: the lunar year
+// - month: the lunar month, 1 to 12
+// - day: the day of month, 1 to 30
+// - leap: whether the date is in the leap month following month
+//
+// Returns:
+// - time.Time: the Gregorian date at midnight UTC
+// - error: ErrLunarOutOfRange or ErrInvalidLunarDate
+func LunarToSolar(year, month, day int, leap bool) (time.Time, error) {
+	if year < MinLunarYear || year > MaxLunarYear {
+		return time.Time{}, ErrLunarOutOfRange
+	}
+	if days := LunarMonthDays(year, month, leap); days == 0 || day < 1 || day > days {
+		return time.Time{}, fmt.Errorf("%w: %d-%d-%d (leap %v)", ErrInvalidLunarDate, year, month, day, leap)
+	}
+
+	offset := 0
+	for y := MinLunarYear; y < year; y++ {
+		offset += LunarYearDays(y)
+	}
+	leapMonth := LeapMonth(year)
+	for m := 1; m < month; m++ {
+		offset += LunarMonthDays(year, m, false)
+		if m == leapMonth {
+			offset += LunarMonthDays(year, m, true)
+		}
+	}
+	if leap {
+		offset += LunarMonthDays(year, month, false)
+	}
+	return lunarEpoch.AddDate(0, 0, offset+day-1), nil
+}
+
+// ToSolar converts the lunar date to the Gregorian calendar, see LunarToSolar.
+//
+// Returns:
+// - time.Time: the Gregorian date at midnight UTC
+// - error: ErrLunarOutOfRange or ErrInvalidLunarDate
+func (l Lunar) ToSolar() (time.Time, error) {
+	return LunarToSolar(l.Year, l.Month, l.Day, l.IsLeapMonth)
+}
+
+// MonthName returns the Chinese name of the month, e.g. 正月, 闰四月 or 腊月
+func (l Lunar) MonthName() string {
+	name := lunarMonthNames[(l.Month-1+12)%12] + "月"
+	if l.IsLeapMonth {
+		return "闰" + name
+	}
+	return name
+}
+
+// DayName returns the Chinese name of the day, e.g. 初一, 十五, 廿三 or 三十
+func (l Lunar) DayName() string {
+	switch l.Day {
+	case 10:
+		return "初十"
+	case 20:
+		return "二十"
+	case 30:
+		return "三十"
+	}
+	return lunarDayTens[l.Day/10%4] + lunarDayUnits[l.Day%10]
+}
+
+// YearGanZhi returns the sexagenary (干支) name of the lunar year, e.g. 甲辰
+func (l Lunar) YearGanZhi() string {
+	return ganZhi(l.Year - 4)
+}
+
+// Animal returns the Chinese zodiac animal of the lunar year, e.g. 龙
+func (l Lunar) Animal() string {
+	return zodiacAnimals[mod(l.Year-4, 12)]
+}
+
+// String returns the traditional representation of the date, e.g. 甲辰年正月初一
+func (l Lunar) String() string {
+	return l.YearGanZhi() + "年" + l.MonthName() + l.DayName()
+}
+
+// GanZhi is the sexagenary (干支) year, month and day of a date as used in the Four Pillars.
+// The year begins at the Start of Spring (立春) and months begin at the twelve
+// sectional solar terms (节), so they can differ from the lunar year and month.
+type GanZhi struct {
+	Year  string // e.g. 甲辰
+	Month string // e.g. 丙寅
+	Day   string // e.g. 甲子
+}
+
+// GetGanZhi returns the sexagenary year, month and day of a Gregorian date.
+//
+// Parameters:
+// - date: the Gregorian date; only its calendar day is used
+//
+// Returns:
+// - GanZhi: the sexagenary year, month and day
+// - error: ErrLunarOutOfRange if the year is outside the solar term table
+func GetGanZhi(date time.Time) (GanZhi, error) {
+	year, month, day := date.Date()
+	if year < MinLunarYear || year > MaxLunarYear {
+		return GanZhi{}, ErrLunarOutOfRange
+	}
+
+	// Months counted from the Tiger (寅) month that begins at the Start of Spring;
+	// Minor Cold (小寒) begins the Ox (丑) month, the last month of the previous year
+	ganZhiYear, branchMonth := year, 0
+	for term := WinterSolstice - 1; term >= MinorCold; term -= 2 {
+		termDay := solarTermDay(year, term)
+		if month > termMonth(term) || month == termMonth(term) && day >= termDay {
+			branchMonth = (int(term) - int(StartOfSpring)) / 2
+			break
+		}
+		if term == MinorCold {
+			// Before Minor Cold: the Rat (子) month of the previous year
+			branchMonth = -2
+		}
+	}
+	if branchMonth < 0 {
+		ganZhiYear--
+		branchMonth += 12
+	}
+
+	yearStem := mod(ganZhiYear-4, 10)
+	monthStem := (yearStem%5*2 + 2 + branchMonth) % 10
+	return GanZhi{
+		Year:  ganZhi(ganZhiYear - 4),
+		Month: heavenlyStems[monthStem] + earthlyBranches[(branchMonth+2)%12],
+		// 1900-01-31 is a 甲辰 day, the 41st of the cycle
+		Day: ganZhi(civilDays(date) + 40),
+	}, nil
+}
+
+// ganZhi returns the name of the n-th combination of the sexagenary cycle, 0 being 甲子
+func ganZhi(n int) string {
+	return heavenlyStems[mod(n, 10)] + earthlyBranches[mod(n, 12)]
+}
+
+// mod returns the non-negative remainder of a divided by b
+func mod(a, b int) int {
+	return (a%b + b) % b
+}
+
+// Lunar returns the lunar date of the DateTime, see SolarToLunar
+//
+// Returns:
+// - Lunar: the lunar date
+// - error: ErrLunarOutOfRange if the date is outside the supported lunar years
+func (dt *DateTime) Lunar() (Lunar, error) {
+	return SolarToLunar(dt.Time)
+}
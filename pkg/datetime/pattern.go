@@ -0,0 +1,784 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidPattern is returned when a Java/Unicode-style pattern cannot be compiled
+	ErrInvalidPattern = errors.New("datetime: invalid pattern")
+	// ErrPatternMismatch is returned when a value does not match a pattern during strict parsing
+	ErrPatternMismatch = errors.New("datetime: value does not match pattern")
+)
+
+// Pattern is a compiled Java/Unicode-style date pattern such as "yyyy-MM-dd HH:mm:ss.SSS".
+//
+// Supported letters:
+// - y: year; "yy" is the two-digit year in 2000-2099, any other count is the full year
+// - M: month; "M"/"MM" numeric, "MMM" short name (Jan), "MMMM" full name (January)
+// - d: day of month; D: day of year
+// - H: hour of day (0-23); h: hour of am/pm (1-12); a: AM/PM marker
+// - m: minute; s: second; S: fraction of second, one digit per letter ("SSS" is milliseconds)
+// - E: day of week; "E" to "EEE" short name (Mon), "EEEE" full name (Monday)
+// - u: ISO 8601 day of week number, 1 (Monday) to 7 (Sunday)
+// - Q: quarter; "Q"/"QQ" numeric, "QQQ" Q1, "QQQQ" 1st quarter
+// - w: ISO 8601 week of year
+// - z: time zone abbreviation (CST)
+// - Z: offset; "Z" to "ZZZ" +0800, "ZZZZ" GMT+08:00, "ZZZZZ" +08:00 or Z
+// - X: offset or Z for UTC; "X" +08, "XX" +0800, "XXX" +08:00
+//
+// Text in single quotes is literal and two single quotes produce one. Other ASCII
+// letters are reserved and rejected; all other characters are literal. When parsing,
+// "a" also accepts the Chinese markers 凌晨, 早上 and 上午 for AM and 下午 and 晚上 for PM.
+// A Pattern is safe for concurrent use.
+type Pattern struct {
+	source string
+	tokens []patternToken
+}
+
+// patternToken is a field or literal of a compiled pattern
+type patternToken struct {
+	field   byte   // pattern letter, 0 for literals
+	count   int    // number of repeated letters
+	literal string // literal text
+	fixed   bool   // numeric field followed by another numeric field, parsed with exactly count digits
+}
+
+// patternCacheLimit bounds the pattern cache so that dynamically built patterns cannot grow it without limit
+const patternCacheLimit = 1024
+
+var (
+	patternCacheMu sync.RWMutex
+	patternCache   = map[string]*Pattern{}
+)
+
+// CompilePattern compiles a Java/Unicode-style date pattern. Compiled patterns are cached.
+//
+// Parameters:
+// - pattern: the pattern, e.g. "yyyy-MM-dd HH:mm:ss"
+//
+// Returns:
+// - *Pattern: the compiled pattern
+// - error: ErrInvalidPattern if the pattern is malformed
+func CompilePattern(pattern string) (*Pattern, error) {
+	patternCacheMu.RLock()
+	p, ok := patternCache[pattern]
+	patternCacheMu.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	p, err := compilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCacheMu.Lock()
+	if len(patternCache) < patternCacheLimit {
+		patternCache[pattern] = p
+	}
+	patternCacheMu.Unlock()
+	return p, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics if the pattern is invalid.
+// It simplifies the initialization of global pattern variables.
+//
+// Parameters:
+// - pattern: the pattern
+//
+// Returns:
+// - *Pattern: the compiled pattern
+func MustCompilePattern(pattern string) *Pattern {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// patternFieldLimits is the maximum number of letters accepted for each field
+var patternFieldLimits = map[byte]int{
+	'y': 9, 'M': 4, 'd': 2, 'H': 2, 'h': 2, 'm': 2, 's': 2, 'S': 9,
+	'E': 4, 'a': 1, 'z': 4, 'Z': 5, 'X': 3, 'Q': 4, 'w': 2, 'D': 3, 'u': 1,
+}
+
+func compilePattern(pattern string) (*Pattern, error) {
+	p := &Pattern{source: pattern}
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			p.tokens = append(p.tokens, patternToken{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		switch {
+		case c == '\'':
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				literal.WriteByte('\'')
+				i += 2
+				continue
+			}
+			end := i + 1
+			for {
+				if end >= len(pattern) {
+					return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalidPattern, pattern)
+				}
+				if pattern[end] == '\'' {
+					if end+1 < len(pattern) && pattern[end+1] == '\'' {
+						literal.WriteByte('\'')
+						end += 2
+						continue
+					}
+					break
+				}
+				literal.WriteByte(pattern[end])
+				end++
+			}
+			i = end + 1
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			limit, ok := patternFieldLimits[c]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown letter %q in %q", ErrInvalidPattern, c, pattern)
+			}
+			count := 1
+			for i+count < len(pattern) && pattern[i+count] == c {
+				count++
+			}
+			if count > limit {
+				return nil, fmt.Errorf("%w: too many %q letters in %q", ErrInvalidPattern, c, pattern)
+			}
+			flush()
+			p.tokens = append(p.tokens, patternToken{field: c, count: count})
+			i += count
+		default:
+			literal.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	// Adjacent numeric fields such as "yyyyMMdd" can only be parsed with fixed widths
+	for i := 0; i+1 < len(p.tokens); i++ {
+		if p.tokens[i].numeric() && p.tokens[i+1].numeric() {
+			p.tokens[i].fixed = true
+		}
+	}
+	return p, nil
+}
+
+// numeric reports whether the token is a numeric field
+func (t patternToken) numeric() bool {
+	switch t.field {
+	case 'y', 'd', 'D', 'H', 'h', 'm', 's', 'S', 'w', 'u':
+		return true
+	case 'M', 'Q':
+		return t.count <= 2
+	}
+	return false
+}
+
+// String returns the source pattern
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Format formats a time with the pattern.
+//
+// Parameters:
+// - t: the time to format
+//
+// Returns:
+// - string: the formatted time
+func (p *Pattern) Format(t time.Time) string {
+	var b strings.Builder
+	b.Grow(len(p.source) + 8)
+	for _, tok := range p.tokens {
+		if tok.field == 0 {
+			b.WriteString(tok.literal)
+			continue
+		}
+		switch tok.field {
+		case 'y':
+			if tok.count == 2 {
+				writePadded(&b, t.Year()%100, 2)
+			} else {
+				writePadded(&b, t.Year(), tok.count)
+			}
+		case 'M':
+			switch tok.count {
+			case 1, 2:
+				writePadded(&b, int(t.Month()), tok.count)
+			case 3:
+				b.WriteString(t.Month().String()[:3])
+			default:
+				b.WriteString(t.Month().String())
+			}
+		case 'd':
+			writePadded(&b, t.Day(), tok.count)
+		case 'D':
+			writePadded(&b, t.YearDay(), tok.count)
+		case 'H':
+			writePadded(&b, t.Hour(), tok.count)
+		case 'h':
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			writePadded(&b, hour, tok.count)
+		case 'm':
+			writePadded(&b, t.Minute(), tok.count)
+		case 's':
+			writePadded(&b, t.Second(), tok.count)
+		case 'S':
+			frac := t.Nanosecond()
+			for i := tok.count; i < 9; i++ {
+				frac /= 10
+			}
+			writePadded(&b, frac, tok.count)
+		case 'E':
+			if tok.count == 4 {
+				b.WriteString(t.Weekday().String())
+			} else {
+				b.WriteString(t.Weekday().String()[:3])
+			}
+		case 'a':
+			if t.Hour() < 12 {
+				b.WriteString("AM")
+			} else {
+				b.WriteString("PM")
+			}
+		case 'Q':
+			quarter := (int(t.Month())-1)/3 + 1
+			switch tok.count {
+			case 1, 2:
+				writePadded(&b, quarter, tok.count)
+			case 3:
+				b.WriteString("Q" + strconv.Itoa(quarter))
+			default:
+				b.WriteString(quarterNames[quarter-1])
+			}
+		case 'w':
+			_, week := t.ISOWeek()
+			writePadded(&b, week, tok.count)
+		case 'u':
+			weekday := int(t.Weekday())
+			if weekday == 0 {
+				weekday = 7
+			}
+			writePadded(&b, weekday, tok.count)
+		case 'z':
+			name, _ := t.Zone()
+			b.WriteString(name)
+		case 'Z', 'X':
+			_, offset := t.Zone()
+			b.WriteString(formatOffset(tok, offset))
+		}
+	}
+	return b.String()
+}
+
+// quarterNames are the full quarter names used by "QQQQ"
+var quarterNames = [4]string{"1st quarter", "2nd quarter", "3rd quarter", "4th quarter"}
+
+// writePadded writes n zero-padded to width digits
+func writePadded(b *strings.Builder, n, width int) {
+	if n < 0 {
+		b.WriteByte('-')
+		n = -n
+	}
+	s := strconv.Itoa(n)
+	for i := len(s); i < width; i++ {
+		b.WriteByte('0')
+	}
+	b.WriteString(s)
+}
+
+// formatOffset formats a UTC offset in seconds for a Z or X token
+func formatOffset(tok patternToken, offset int) string {
+	if offset == 0 && (tok.field == 'X' || tok.count == 5) {
+		return "Z"
+	}
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	hours, minutes := offset/3600, offset%3600/60
+	switch {
+	case tok.field == 'X' && tok.count == 1:
+		if minutes == 0 {
+			return fmt.Sprintf("%c%02d", sign, hours)
+		}
+		return fmt.Sprintf("%c%02d%02d", sign, hours, minutes)
+	case tok.field == 'X' && tok.count == 3, tok.field == 'Z' && tok.count == 5:
+		return fmt.Sprintf("%c%02d:%02d", sign, hours, minutes)
+	case tok.field == 'Z' && tok.count == 4:
+		if offset == 0 {
+			return "GMT"
+		}
+		return fmt.Sprintf("GMT%c%02d:%02d", sign, hours, minutes)
+	default:
+		return fmt.Sprintf("%c%02d%02d", sign, hours, minutes)
+	}
+}
+
+// Parse strictly parses a value with the pattern. Missing date fields default to
+// 1970-01-01 and missing time fields to zero. Without a zone or offset in the value,
+// the result is in UTC.
+//
+// Parameters:
+// - value: the string to parse
+//
+// Returns:
+// - time.Time: the parsed time
+// - error: ErrPatternMismatch if the value does not match the pattern or is not a valid date
+func (p *Pattern) Parse(value string) (time.Time, error) {
+	return p.ParseInLocation(value, time.UTC)
+}
+
+// ParseInLocation is like Parse but interprets a value without a zone or offset in
+// the given location, and resolves zone abbreviations against it.
+//
+// Parameters:
+// - value: the string to parse
+// - loc: the default location
+//
+// Returns:
+// - time.Time: the parsed time
+// - error: ErrPatternMismatch if the value does not match the pattern or is not a valid date
+func (p *Pattern) ParseInLocation(value string, loc *time.Location) (time.Time, error) {
+	st := parseState{year: 1970, month: -1, day: -1, yearDay: -1, hour: -1, hour12: -1, ampm: -1, weekday: -1, quarter: -1, week: -1}
+	rest := value
+	for _, tok := range p.tokens {
+		var err error
+		if tok.field == 0 {
+			if !strings.HasPrefix(rest, tok.literal) {
+				return time.Time{}, p.mismatch(value, "expected %q", tok.literal)
+			}
+			rest = rest[len(tok.literal):]
+			continue
+		}
+		if rest, err = st.parseField(tok, rest); err != nil {
+			return time.Time{}, p.mismatch(value, "%v", err)
+		}
+	}
+	if rest != "" {
+		return time.Time{}, p.mismatch(value, "unexpected trailing text %q", rest)
+	}
+	t, err := st.resolve(loc)
+	if err != nil {
+		return time.Time{}, p.mismatch(value, "%v", err)
+	}
+	return t, nil
+}
+
+func (p *Pattern) mismatch(value, format string, args ...any) error {
+	return fmt.Errorf("%w: parsing %q as %q: %s", ErrPatternMismatch, value, p.source, fmt.Sprintf(format, args...))
+}
+
+// parseState collects parsed fields; -1 marks fields that were not present
+type parseState struct {
+	year, month, day, yearDay    int
+	hour, hour12, ampm           int
+	minute, second, nanos        int
+	weekday, quarter, week       int
+	offset                       int
+	hasOffset                    bool
+	zone                         string
+	twoDigitYear, hasYear, hasMD bool
+}
+
+// parseField parses one field token from the start of s and returns the remainder
+func (st *parseState) parseField(tok patternToken, s string) (string, error) {
+	if tok.numeric() {
+		minWidth, maxWidth := tok.count, tok.count
+		if !tok.fixed && tok.field != 'S' {
+			switch {
+			case tok.field == 'y' && tok.count != 2:
+				maxWidth = 9
+			case tok.field == 'D':
+				maxWidth = 3
+			case tok.field == 'Q', tok.field == 'u':
+				maxWidth = 1
+			default:
+				maxWidth = 2
+			}
+		}
+		n, rest, err := parseDigits(s, minWidth, maxWidth)
+		if err != nil {
+			return s, fmt.Errorf("%c field: %v", tok.field, err)
+		}
+		return rest, st.setNumber(tok, n, len(s)-len(rest))
+	}
+
+	switch tok.field {
+	case 'M':
+		i, rest, ok := matchName(s, monthNames(tok.count == 3))
+		if !ok {
+			return s, errors.New("invalid month name")
+		}
+		st.month, st.hasMD = i+1, true
+		return rest, nil
+	case 'E':
+		i, rest, ok := matchName(s, weekdayNames(tok.count < 4))
+		if !ok {
+			return s, errors.New("invalid day-of-week name")
+		}
+		st.weekday = i
+		return rest, nil
+	case 'a':
+		i, rest, ok := matchName(s, meridiemNames)
+		if !ok {
+			return s, errors.New("invalid AM/PM marker")
+		}
+		st.ampm = meridiemValues[i]
+		return rest, nil
+	case 'Q':
+		names := quarterNames[:]
+		if tok.count == 3 {
+			names = []string{"Q1", "Q2", "Q3", "Q4"}
+		}
+		i, rest, ok := matchName(s, names)
+		if !ok {
+			return s, errors.New("invalid quarter")
+		}
+		st.quarter = i + 1
+		return rest, nil
+	case 'z':
+		end := 0
+		for end < len(s) && (s[end] >= 'A' && s[end] <= 'Z' || s[end] >= 'a' && s[end] <= 'z') {
+			end++
+		}
+		if end == 0 {
+			return s, errors.New("missing time zone")
+		}
+		st.zone = s[:end]
+		return s[end:], nil
+	default: // 'Z', 'X'
+		offset, rest, err := parseOffset(tok, s)
+		if err != nil {
+			return s, err
+		}
+		st.offset, st.hasOffset = offset, true
+		return rest, nil
+	}
+}
+
+// setNumber stores a numeric field after checking its range
+func (st *parseState) setNumber(tok patternToken, n, width int) error {
+	inRange := func(min, max int) error {
+		if n < min || n > max {
+			return fmt.Errorf("%c field: %d out of range [%d, %d]", tok.field, n, min, max)
+		}
+		return nil
+	}
+	switch tok.field {
+	case 'y':
+		st.year, st.hasYear = n, true
+		if tok.count == 2 {
+			st.year = 2000 + n
+		}
+		return nil
+	case 'M':
+		st.month, st.hasMD = n, true
+		return inRange(1, 12)
+	case 'd':
+		st.day, st.hasMD = n, true
+		return inRange(1, 31)
+	case 'D':
+		st.yearDay = n
+		return inRange(1, 366)
+	case 'H':
+		st.hour = n
+		return inRange(0, 23)
+	case 'h':
+		st.hour12 = n
+		return inRange(1, 12)
+	case 'm':
+		st.minute = n
+		return inRange(0, 59)
+	case 's':
+		st.second = n
+		return inRange(0, 59)
+	case 'S':
+		for i := width; i < 9; i++ {
+			n *= 10
+		}
+		st.nanos = n
+		return nil
+	case 'Q':
+		st.quarter = n
+		return inRange(1, 4)
+	case 'u':
+		st.weekday = n % 7
+		return inRange(1, 7)
+	default: // 'w'
+		st.week = n
+		return inRange(1, 53)
+	}
+}
+
+// resolve combines the parsed fields into a time and checks their consistency
+func (st *parseState) resolve(loc *time.Location) (time.Time, error) {
+	hour := 0
+	switch {
+	case st.hour >= 0:
+		hour = st.hour
+		if st.ampm >= 0 && (hour >= 12) != (st.ampm == 1) {
+			return time.Time{}, errors.New("hour conflicts with AM/PM marker")
+		}
+	case st.hour12 >= 0:
+		hour = st.hour12 % 12
+		if st.ampm == 1 {
+			hour += 12
+		}
+	}
+
+	switch {
+	case st.hasOffset:
+		if st.offset == 0 {
+			loc = time.UTC
+		} else {
+			loc = time.FixedZone("", st.offset)
+		}
+	case st.zone != "":
+		zoneLoc, err := resolveZone(st.zone, loc, st.year, st.month, st.day)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = zoneLoc
+	}
+
+	month, day := st.month, st.day
+	if st.week >= 0 && !st.hasMD {
+		// The week-based year and week number select a Monday; E selects the day within the week
+		t := isoWeekStart(st.year, st.week, loc)
+		if st.weekday >= 0 {
+			t = t.AddDate(0, 0, (st.weekday+6)%7)
+		}
+		month, day = int(t.Month()), t.Day()
+		st.year = t.Year()
+	} else if st.yearDay >= 0 && !st.hasMD {
+		t := time.Date(st.year, time.January, st.yearDay, 0, 0, 0, 0, loc)
+		if t.Year() != st.year {
+			return time.Time{}, fmt.Errorf("invalid day %d of year %04d", st.yearDay, st.year)
+		}
+		month, day = int(t.Month()), t.Day()
+	}
+	if month < 0 {
+		month = 1
+		if st.quarter > 0 {
+			month = (st.quarter-1)*3 + 1
+		}
+	}
+	if day < 0 {
+		day = 1
+	}
+
+	t := time.Date(st.year, time.Month(month), day, hour, st.minute, st.second, st.nanos, loc)
+	if t.Day() != day || int(t.Month()) != month {
+		return time.Time{}, fmt.Errorf("invalid date %04d-%02d-%02d", st.year, month, day)
+	}
+	if st.weekday >= 0 && int(t.Weekday()) != st.weekday {
+		return time.Time{}, fmt.Errorf("day of week does not match %s", t.Format("2006-01-02"))
+	}
+	if st.yearDay >= 0 && t.YearDay() != st.yearDay {
+		return time.Time{}, errors.New("day of year does not match date")
+	}
+	if st.quarter > 0 && (month-1)/3+1 != st.quarter {
+		return time.Time{}, errors.New("quarter does not match month")
+	}
+	if _, week := t.ISOWeek(); st.week >= 0 && week != st.week {
+		return time.Time{}, errors.New("week of year does not match date")
+	}
+	return t, nil
+}
+
+// resolveZone maps a zone abbreviation to a location: UTC, GMT and Z are UTC, any
+// other abbreviation must be used by loc at the parsed date
+func resolveZone(zone string, loc *time.Location, year, month, day int) (*time.Location, error) {
+	switch strings.ToUpper(zone) {
+	case "UTC", "GMT", "Z":
+		return time.UTC, nil
+	}
+	month, day = max(month, 1), max(day, 1)
+	for _, probe := range []time.Time{
+		time.Date(year, time.Month(month), day, 12, 0, 0, 0, loc),
+		time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+		time.Date(year, time.July, 1, 0, 0, 0, 0, loc),
+	} {
+		if name, _ := probe.Zone(); name == zone {
+			return loc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown time zone abbreviation %q in %s", zone, loc)
+}
+
+// isoWeekStart returns the Monday of the given ISO 8601 week
+func isoWeekStart(year, week int, loc *time.Location) time.Time {
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	return monday.AddDate(0, 0, (week-1)*7)
+}
+
+// parseDigits parses between minWidth and maxWidth ASCII digits
+func parseDigits(s string, minWidth, maxWidth int) (int, string, error) {
+	n, i := 0, 0
+	for i < len(s) && i < maxWidth && s[i] >= '0' && s[i] <= '9' {
+		n = n*10 + int(s[i]-'0')
+		i++
+	}
+	if i < minWidth {
+		return 0, s, fmt.Errorf("expected %d digits", minWidth)
+	}
+	return n, s[i:], nil
+}
+
+// parseOffset parses a UTC offset in the format of a Z or X token
+func parseOffset(tok patternToken, s string) (int, string, error) {
+	if (tok.field == 'X' || tok.count == 5) && strings.HasPrefix(s, "Z") {
+		return 0, s[1:], nil
+	}
+	if tok.field == 'Z' && tok.count == 4 {
+		if !strings.HasPrefix(s, "GMT") {
+			return 0, s, errors.New("offset must start with GMT")
+		}
+		s = s[3:]
+		if s == "" || s[0] != '+' && s[0] != '-' {
+			return 0, s, nil
+		}
+	}
+	if s == "" || s[0] != '+' && s[0] != '-' {
+		return 0, s, errors.New("offset must start with + or -")
+	}
+	sign := 1
+	if s[0] == '-' {
+		sign = -1
+	}
+	rest := s[1:]
+	hours, rest, err := parseDigits(rest, 2, 2)
+	if err != nil {
+		return 0, s, fmt.Errorf("offset hours: %v", err)
+	}
+	minutes := 0
+	colon := tok.field == 'X' && tok.count == 3 || tok.field == 'Z' && tok.count >= 4
+	switch {
+	case colon:
+		if !strings.HasPrefix(rest, ":") {
+			return 0, s, errors.New("offset must be +HH:mm")
+		}
+		minutes, rest, err = parseDigits(rest[1:], 2, 2)
+	case tok.field == 'X' && tok.count == 1:
+		if len(rest) >= 2 && rest[0] >= '0' && rest[0] <= '9' {
+			minutes, rest, err = parseDigits(rest, 2, 2)
+		}
+	default:
+		minutes, rest, err = parseDigits(rest, 2, 2)
+	}
+	if err != nil {
+		return 0, s, fmt.Errorf("offset minutes: %v", err)
+	}
+	if hours > 18 || minutes > 59 {
+		return 0, s, errors.New("offset out of range")
+	}
+	return sign * (hours*3600 + minutes*60), rest, nil
+}
+
+// matchName matches one of names case-insensitively at the start of s
+func matchName(s string, names []string) (int, string, bool) {
+	for i, name := range names {
+		if len(s) >= len(name) && strings.EqualFold(s[:len(name)], name) {
+			return i, s[len(name):], true
+		}
+	}
+	return -1, s, false
+}
+
+// meridiemNames are the markers accepted for 'a' and meridiemValues their meaning, 0 for AM and 1 for PM
+var (
+	meridiemNames  = []string{"AM", "PM", "凌晨", "早上", "上午", "下午", "晚上"}
+	meridiemValues = []int{0, 1, 0, 0, 0, 1, 1}
+)
+
+// monthNames returns English month names, short or full
+func monthNames(short bool) []string {
+	names := make([]string, 12)
+	for i := range names {
+		names[i] = time.Month(i + 1).String()
+		if short {
+			names[i] = names[i][:3]
+		}
+	}
+	return names
+}
+
+// weekdayNames returns English day-of-week names starting with Sunday, short or full
+func weekdayNames(short bool) []string {
+	names := make([]string, 7)
+	for i := range names {
+		names[i] = time.Weekday(i).String()
+		if short {
+			names[i] = names[i][:3]
+		}
+	}
+	return names
+}
+
+// FormatDatePattern formats a time.Time object with a Java/Unicode-style pattern.
+//
+// Parameters:
+// - date: the time.Time object to format
+// - pattern: the pattern, e.g. "yyyy-MM-dd HH:mm:ss"
+//
+// Returns:
+// - string: the formatted date string
+// - error: ErrInvalidPattern if the pattern is malformed
+func FormatDatePattern(date time.Time, pattern string) (string, error) {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+	return p.Format(date), nil
+}
+
+// ParseDatePattern strictly parses a date string with a Java/Unicode-style pattern.
+// Values without a zone or offset are interpreted as UTC.
+//
+// Parameters:
+// - dateStr: the date string to parse
+// - pattern: the pattern, e.g. "yyyy-MM-dd HH:mm:ss"
+//
+// Returns:
+// - time.Time: the parsed time.Time object
+// - error: ErrInvalidPattern or ErrPatternMismatch
+func ParseDatePattern(dateStr, pattern string) (time.Time, error) {
+	return ParseDatePatternInLocation(dateStr, pattern, time.UTC)
+}
+
+// ParseDatePatternInLocation is like ParseDatePattern but interprets values without a
+// zone or offset in the given location.
+//
+// Parameters:
+// - dateStr: the date string to parse
+// - pattern: the pattern
+// - loc: the default location
+//
+// Returns:
+// - time.Time: the parsed time.Time object
+// - error: ErrInvalidPattern or ErrPatternMismatch
+func ParseDatePatternInLocation(dateStr, pattern string, loc *time.Location) (time.Time, error) {
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return p.ParseInLocation(dateStr, loc)
+}
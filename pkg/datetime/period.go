@@ -0,0 +1,333 @@
+package datetime
+
+import "time"
+
+// PeriodCalendar computes period boundaries with a configurable first day of the week and
+// first month of the fiscal year. The package functions BeginOf, EndOf, Truncate, Ceil and
+// PeriodsBetween use weeks starting on Monday and fiscal years starting in January.
+type PeriodCalendar struct {
+	WeekStart       time.Weekday // first day of the week; the zero value is Sunday
+	FiscalYearStart time.Month   // first month of the fiscal year; 0 is January
+}
+
+// defaultPeriodCalendar is used by the package functions
+var defaultPeriodCalendar = PeriodCalendar{WeekStart: time.Monday, FiscalYearStart: time.January}
+
+// BeginOf returns the first instant of the period of the unit containing t, on the wall clock
+// of t's location, e.g. BeginOf(t, Quarter) is midnight of the first day of the quarter.
+//
+// Parameters:
+// - t: the time
+// - unit: the period unit, see Truncate
+//
+// Returns:
+// - time.Time: the start of the period
+func (c PeriodCalendar) BeginOf(t time.Time, unit DateUnit) time.Time {
+	return c.Truncate(t, unit)
+}
+
+// EndOf returns the last nanosecond of the period of the unit containing t, e.g. EndOf(t, Week)
+// is 23:59:59.999999999 on the last day of the week.
+//
+// Parameters:
+// - t: the time
+// - unit: the period unit, see Truncate
+//
+// Returns:
+// - time.Time: the end of the period
+func (c PeriodCalendar) EndOf(t time.Time, unit DateUnit) time.Time {
+	if unit <= 0 {
+		return t
+	}
+	return c.advance(c.Truncate(t, unit), unit, 1).Add(-time.Nanosecond)
+}
+
+// Truncate rounds t down to a multiple of the unit on the wall clock of t's location. Week,
+// MonthUnit, Quarter and Year truncate to the start of the calendar week, month, quarter and
+// year. Other units are counted from midnight of 1970-01-01 on the wall clock, so units that
+// divide a day, e.g. 15*Minute, truncate within the day; fractions of a millisecond are dropped.
+//
+// Parameters:
+// - t: the time
+// - unit: the unit; t is returned unchanged if it is not positive
+//
+// Returns:
+// - time.Time: the truncated time
+func (c PeriodCalendar) Truncate(t time.Time, unit DateUnit) time.Time {
+	year, month, day := t.Date()
+	loc := t.Location()
+	switch {
+	case unit <= 0:
+		return t
+	case unit == Week:
+		return time.Date(year, month, day-mod(int(t.Weekday())-int(c.WeekStart), 7), 0, 0, 0, 0, loc)
+	case unit == MonthUnit:
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	case unit == Quarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+	case unit == Year:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	}
+	hour, minute, second := t.Clock()
+	wall := time.Date(year, month, day, hour, minute, second, t.Nanosecond(), time.UTC).UnixMilli()
+	return fromWall(time.UnixMilli(wall-mod64(wall, int64(unit))).UTC(), loc)
+}
+
+// Ceil rounds t up to a multiple of the unit, see Truncate. A t on a boundary is returned unchanged.
+//
+// Parameters:
+// - t: the time
+// - unit: the unit; t is returned unchanged if it is not positive
+//
+// Returns:
+// - time.Time: the rounded time
+func (c PeriodCalendar) Ceil(t time.Time, unit DateUnit) time.Time {
+	begin := c.Truncate(t, unit)
+	if begin.Equal(t) {
+		return t
+	}
+	return c.advance(begin, unit, 1)
+}
+
+// advance moves the start of a period by n periods on the wall clock
+func (c PeriodCalendar) advance(begin time.Time, unit DateUnit, n int) time.Time {
+	switch unit {
+	case Week:
+		return begin.AddDate(0, 0, 7*n)
+	case MonthUnit:
+		return begin.AddDate(0, n, 0)
+	case Quarter:
+		return begin.AddDate(0, 3*n, 0)
+	case Year:
+		return begin.AddDate(n, 0, 0)
+	}
+	year, month, day := begin.Date()
+	hour, minute, second := begin.Clock()
+	wall := time.Date(year, month, day, hour, minute, second, begin.Nanosecond(), time.UTC)
+	return fromWall(wall.Add(time.Duration(unit)*time.Duration(n)*time.Millisecond), begin.Location())
+}
+
+// fromWall returns the instant in loc of the wall-clock time given as a UTC time, see wallTime
+func fromWall(wall time.Time, loc *time.Location) time.Time {
+	return wallTime(wall, wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+}
+
+// FiscalYear returns the fiscal year containing t, named after the calendar year in which it
+// ends: with fiscal years starting in October, 2023-10-01 is in fiscal year 2024.
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - int: the fiscal year
+func (c PeriodCalendar) FiscalYear(t time.Time) int {
+	if start := c.fiscalYearStart(); start != time.January && t.Month() >= start {
+		return t.Year() + 1
+	}
+	return t.Year()
+}
+
+// FiscalQuarter returns the quarter of the fiscal year containing t, 1 to 4.
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - int: the fiscal quarter
+func (c PeriodCalendar) FiscalQuarter(t time.Time) int {
+	return mod(int(t.Month())-int(c.fiscalYearStart()), 12)/3 + 1
+}
+
+// BeginOfFiscalYear returns midnight of the first day of the fiscal year containing t.
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - time.Time: the start of the fiscal year
+func (c PeriodCalendar) BeginOfFiscalYear(t time.Time) time.Time {
+	start := c.fiscalYearStart()
+	year := t.Year()
+	if t.Month() < start {
+		year--
+	}
+	return time.Date(year, start, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfFiscalYear returns the last nanosecond of the fiscal year containing t.
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - time.Time: the end of the fiscal year
+func (c PeriodCalendar) EndOfFiscalYear(t time.Time) time.Time {
+	return c.BeginOfFiscalYear(t).AddDate(1, 0, 0).Add(-time.Nanosecond)
+}
+
+func (c PeriodCalendar) fiscalYearStart() time.Month {
+	if c.FiscalYearStart < time.January || c.FiscalYearStart > time.December {
+		return time.January
+	}
+	return c.FiscalYearStart
+}
+
+// PeriodsBetween returns an iterator over the periods of the unit that overlap [start, end),
+// e.g. the months touched by a date range. The first period begins at or before start.
+//
+// Parameters:
+// - start: the start of the range
+// - end: the exclusive end of the range
+// - unit: the period unit, see Truncate
+//
+// Returns:
+// - *PeriodIterator: the iterator
+func (c PeriodCalendar) PeriodsBetween(start, end time.Time, unit DateUnit) *PeriodIterator {
+	it := &PeriodIterator{calendar: c, unit: unit, end: end}
+	if unit > 0 {
+		it.next = c.Truncate(start, unit)
+	}
+	return it
+}
+
+// PeriodIterator iterates over consecutive periods, see PeriodCalendar.PeriodsBetween
+type PeriodIterator struct {
+	calendar PeriodCalendar
+	unit     DateUnit
+	next     time.Time
+	end      time.Time
+}
+
+// Next returns the next period.
+//
+// Returns:
+// - time.Time: the first instant of the period
+// - time.Time: the first instant of the following period
+// - bool: false when there are no more periods
+func (it *PeriodIterator) Next() (time.Time, time.Time, bool) {
+	if it.unit <= 0 || !it.next.Before(it.end) {
+		return time.Time{}, time.Time{}, false
+	}
+	begin := it.next
+	it.next = it.calendar.advance(begin, it.unit, 1)
+	return begin, it.next, true
+}
+
+// BeginOf returns the first instant of the period of the unit containing t, with weeks starting
+// on Monday, see PeriodCalendar.BeginOf.
+//
+// Parameters:
+// - t: the time
+// - unit: the period unit, e.g. Day, Week, MonthUnit, Quarter or Year
+//
+// Returns:
+// - time.Time: the start of the period
+func BeginOf(t time.Time, unit DateUnit) time.Time {
+	return defaultPeriodCalendar.BeginOf(t, unit)
+}
+
+// EndOf returns the last nanosecond of the period of the unit containing t, with weeks starting
+// on Monday, see PeriodCalendar.EndOf.
+//
+// Parameters:
+// - t: the time
+// - unit: the period unit, e.g. Day, Week, MonthUnit, Quarter or Year
+//
+// Returns:
+// - time.Time: the end of the period
+func EndOf(t time.Time, unit DateUnit) time.Time {
+	return defaultPeriodCalendar.EndOf(t, unit)
+}
+
+// Truncate rounds t down to a multiple of the unit, with weeks starting on Monday, see
+// PeriodCalendar.Truncate.
+//
+// Parameters:
+// - t: the time
+// - unit: the unit
+//
+// Returns:
+// - time.Time: the truncated time
+func Truncate(t time.Time, unit DateUnit) time.Time {
+	return defaultPeriodCalendar.Truncate(t, unit)
+}
+
+// Ceil rounds t up to a multiple of the unit, with weeks starting on Monday, see PeriodCalendar.Ceil.
+//
+// Parameters:
+// - t: the time
+// - unit: the unit
+//
+// Returns:
+// - time.Time: the rounded time
+func Ceil(t time.Time, unit DateUnit) time.Time {
+	return defaultPeriodCalendar.Ceil(t, unit)
+}
+
+// PeriodsBetween returns an iterator over the periods of the unit that overlap [start, end),
+// with weeks starting on Monday, see PeriodCalendar.PeriodsBetween.
+//
+// Parameters:
+// - start: the start of the range
+// - end: the exclusive end of the range
+// - unit: the period unit
+//
+// Returns:
+// - *PeriodIterator: the iterator
+func PeriodsBetween(start, end time.Time, unit DateUnit) *PeriodIterator {
+	return defaultPeriodCalendar.PeriodsBetween(start, end, unit)
+}
+
+// QuarterOf returns the calendar quarter of t, 1 to 4.
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - int: the quarter
+func QuarterOf(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
+
+// ISOWeek returns the ISO 8601 week-numbering year and week of t, as time.Time.ISOWeek does.
+//
+// Parameters:
+// - t: the time
+//
+// Returns:
+// - int: the week-numbering year, which differs from the calendar year around January 1
+// - int: the week, 1 to 53
+func ISOWeek(t time.Time) (int, int) {
+	return t.ISOWeek()
+}
+
+// ISOWeekStart returns midnight of the Monday of an ISO 8601 week.
+//
+// Parameters:
+// - year: the week-numbering year
+// - week: the week; weeks outside 1 to ISOWeeksInYear(year) continue into adjacent years
+// - loc: the location of the result
+//
+// Returns:
+// - time.Time: the start of the week
+func ISOWeekStart(year, week int, loc *time.Location) time.Time {
+	return isoWeekStart(year, week, loc)
+}
+
+// ISOWeeksInYear returns the number of ISO 8601 weeks in a week-numbering year, 52 or 53.
+//
+// Parameters:
+// - year: the week-numbering year
+//
+// Returns:
+// - int: the number of weeks
+func ISOWeeksInYear(year int) int {
+	// December 28th is always in the last week
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// mod64 is mod for int64
+func mod64(a, b int64) int64 {
+	return (a%b + b) % b
+}
@@ -0,0 +1,833 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRecurrence is returned when a recurrence rule or recurrence set is malformed or unsupported
+var ErrInvalidRecurrence = errors.New("datetime: invalid recurrence")
+
+// Frequency is the FREQ of a recurrence rule
+type Frequency int
+
+const (
+	Yearly Frequency = iota
+	Monthly
+	Weekly
+	Daily
+	Hourly
+	Minutely
+	Secondly
+)
+
+var frequencyNames = [...]string{"YEARLY", "MONTHLY", "WEEKLY", "DAILY", "HOURLY", "MINUTELY", "SECONDLY"}
+
+// String returns the RFC 5545 name of the frequency, e.g. "WEEKLY"
+func (f Frequency) String() string {
+	if f < Yearly || f > Secondly {
+		return "Frequency(" + strconv.Itoa(int(f)) + ")"
+	}
+	return frequencyNames[f]
+}
+
+// rruleWeekdays are the two-letter weekday names of RFC 5545
+var rruleWeekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is a BYDAY entry: a weekday with an optional ordinal, e.g. -1FR is the last Friday
+// of the month or year. N is 0 for every such weekday.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// String returns the RFC 5545 form of the entry, e.g. "MO" or "-1FR"
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return rruleWeekdays[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + rruleWeekdays[w.Weekday]
+}
+
+// RRule is an RFC 5545 recurrence rule. BYHOUR, BYMINUTE, BYSECOND, BYYEARDAY and BYWEEKNO are
+// not supported; occurrences keep the time of day of the start of the recurrence.
+type RRule struct {
+	Freq       Frequency
+	Interval   int          // 0 is treated as 1
+	Count      int          // 0 for no limit; includes the start of the recurrence
+	Until      time.Time    // inclusive last instant, zero for no limit
+	ByDay      []WeekdayNum // ordinals are only allowed for Monthly and Yearly
+	ByMonthDay []int        // 1 to 31 or -31 to -1, counted from the end of the month
+	ByMonth    []time.Month
+	BySetPos   []int        // positions in the occurrences of each period, negative from the end
+	WeekStart  time.Weekday // WKST; ParseRRule defaults to Monday as in RFC 5545
+}
+
+// ParseRRule parses a recurrence rule such as "FREQ=MONTHLY;BYDAY=-1FR;COUNT=12", with or
+// without the "RRULE:" prefix.
+//
+// Parameters:
+// - s: the recurrence rule
+// - loc: the location of a floating or date-only UNTIL, UTC if nil
+//
+// Returns:
+// - *RRule: the parsed rule
+// - error: ErrInvalidRecurrence if the rule is malformed or uses unsupported parts
+func ParseRRule(s string, loc *time.Location) (*RRule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	value := strings.TrimSpace(s)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+	rule := &RRule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(name)
+		if !ok || val == "" || seen[name] {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRecurrence, s)
+		}
+		seen[name] = true
+		var err error
+		switch name {
+		case "FREQ":
+			err = errors.New("unknown frequency")
+			for f, fn := range frequencyNames {
+				if strings.EqualFold(val, fn) {
+					rule.Freq, err = Frequency(f), nil
+				}
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseRRuleInt(val, 1, math.MaxInt32)
+		case "COUNT":
+			rule.Count, err = parseRRuleInt(val, 1, math.MaxInt32)
+		case "UNTIL":
+			var isDate bool
+			if rule.Until, isDate, err = parseICalTime(val, loc); isDate {
+				// a date-only UNTIL includes the whole day
+				rule.Until = rule.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseRRuleInts(val, 31)
+		case "BYMONTH":
+			var months []int
+			if months, err = parseRRuleInts(val, 12); err == nil {
+				for _, m := range months {
+					if m < 0 {
+						err = errors.New("negative month")
+					}
+					rule.ByMonth = append(rule.ByMonth, time.Month(m))
+				}
+			}
+		case "BYSETPOS":
+			rule.BySetPos, err = parseRRuleInts(val, 366)
+		case "WKST":
+			err = errors.New("unknown weekday")
+			for d, dn := range rruleWeekdays {
+				if strings.EqualFold(val, dn) {
+					rule.WeekStart, err = time.Weekday(d), nil
+				}
+			}
+		default:
+			err = errors.New("unsupported rule part")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s in %q: %v", ErrInvalidRecurrence, name, s, err)
+		}
+	}
+	if !seen["FREQ"] {
+		return nil, fmt.Errorf("%w: %q has no FREQ", ErrInvalidRecurrence, s)
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// parseRRuleInt parses an integer between min and max
+func parseRRuleInt(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%q is not between %d and %d", s, min, max)
+	}
+	return n, nil
+}
+
+// parseRRuleInts parses a comma-separated list of non-zero integers between -max and max
+func parseRRuleInts(s string, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(s, ",") {
+		n, err := parseRRuleInt(strings.TrimPrefix(item, "+"), -max, max)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("%q is not a non-zero integer between %d and %d", item, -max, max)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// parseByDay parses a BYDAY list such as "MO,WE,-1FR"
+func parseByDay(s string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, item := range strings.Split(s, ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		name := strings.ToUpper(item[len(item)-2:])
+		day := -1
+		for d, dn := range rruleWeekdays {
+			if name == dn {
+				day = d
+			}
+		}
+		var n int
+		var err error
+		if ordinal := strings.TrimPrefix(item[:len(item)-2], "+"); ordinal != "" {
+			n, err = parseRRuleInt(ordinal, -53, 53)
+		}
+		if day < 0 || err != nil || (n == 0 && len(item) > 2) {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: time.Weekday(day)})
+	}
+	return days, nil
+}
+
+// Validate checks that the rule only combines parts as allowed by RFC 5545.
+//
+// Returns:
+// - error: ErrInvalidRecurrence describing a problem, or nil
+func (r *RRule) Validate() error {
+	var problem string
+	switch {
+	case r.Freq < Yearly || r.Freq > Secondly:
+		problem = "unknown frequency " + r.Freq.String()
+	case r.Interval < 0 || r.Count < 0:
+		problem = "negative INTERVAL or COUNT"
+	case r.Count > 0 && !r.Until.IsZero():
+		problem = "COUNT and UNTIL are exclusive"
+	case r.Freq == Weekly && len(r.ByMonthDay) > 0:
+		problem = "BYMONTHDAY is not allowed with FREQ=WEEKLY"
+	case r.WeekStart < time.Sunday || r.WeekStart > time.Saturday:
+		problem = "invalid WKST"
+	}
+	for _, d := range r.ByDay {
+		switch {
+		case d.Weekday < time.Sunday || d.Weekday > time.Saturday || d.N < -53 || d.N > 53:
+			problem = "invalid BYDAY " + strconv.Itoa(d.N) + "/" + strconv.Itoa(int(d.Weekday))
+		case d.N != 0 && (r.Freq != Monthly && r.Freq != Yearly || len(r.ByMonthDay) > 0):
+			problem = "BYDAY ordinals are only allowed with FREQ=MONTHLY or FREQ=YEARLY without BYMONTHDAY"
+		}
+	}
+	for _, d := range r.ByMonthDay {
+		if d == 0 || d < -31 || d > 31 {
+			problem = "invalid BYMONTHDAY " + strconv.Itoa(d)
+		}
+	}
+	for _, m := range r.ByMonth {
+		if m < time.January || m > time.December {
+			problem = "invalid BYMONTH " + strconv.Itoa(int(m))
+		}
+	}
+	for _, p := range r.BySetPos {
+		if p == 0 || p < -366 || p > 366 {
+			problem = "invalid BYSETPOS " + strconv.Itoa(p)
+		}
+	}
+	if problem != "" {
+		return fmt.Errorf("%w: %s", ErrInvalidRecurrence, problem)
+	}
+	return nil
+}
+
+// String returns the rule in RFC 5545 form without the "RRULE:" prefix; UNTIL is written in UTC
+func (r *RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	join := func(name string, n int, item func(int) string) {
+		if n == 0 {
+			return
+		}
+		items := make([]string, n)
+		for i := range items {
+			items[i] = item(i)
+		}
+		parts = append(parts, name+"="+strings.Join(items, ","))
+	}
+	join("BYMONTH", len(r.ByMonth), func(i int) string { return strconv.Itoa(int(r.ByMonth[i])) })
+	join("BYMONTHDAY", len(r.ByMonthDay), func(i int) string { return strconv.Itoa(r.ByMonthDay[i]) })
+	join("BYDAY", len(r.ByDay), func(i int) string { return r.ByDay[i].String() })
+	join("BYSETPOS", len(r.BySetPos), func(i int) string { return strconv.Itoa(r.BySetPos[i]) })
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+rruleWeekdays[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// Recurrence is an RFC 5545 recurrence set: a start (DTSTART), an optional rule, extra dates
+// (RDATE) and excluded dates (EXDATE). Occurrences are computed on the wall clock of the start's
+// location, so a 09:00 meeting stays at 09:00 across DST changes. Local times skipped by a DST
+// change are moved forward by the length of the gap; repeated local times use the first instant.
+type Recurrence struct {
+	Start   time.Time
+	Rule    *RRule // nil for a set of RDates only
+	RDates  []time.Time
+	ExDates []time.Time
+}
+
+// ParseRecurrence parses the DTSTART, RRULE, RDATE and EXDATE lines of an iCalendar component, e.g.
+//
+//	DTSTART;TZID=Europe/Berlin:20240325T090000
+//	RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10
+//	EXDATE;TZID=Europe/Berlin:20240327T090000
+//
+// Parameters:
+// - s: the lines; DTSTART is required
+// - loc: the location of floating and date-only values, UTC if nil
+//
+// Returns:
+// - *Recurrence: the parsed recurrence
+// - error: ErrInvalidRecurrence if a line is malformed, or the error loading a TZID
+func ParseRecurrence(s string, loc *time.Location) (*Recurrence, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	rec := &Recurrence{}
+	var rule string
+	var startLoc *time.Location
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		head, value, ok := strings.Cut(line, ":")
+		params := strings.Split(head, ";")
+		name := strings.ToUpper(params[0])
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRecurrence, line)
+		}
+		if name == "RRULE" {
+			if rule != "" {
+				return nil, fmt.Errorf("%w: more than one RRULE", ErrInvalidRecurrence)
+			}
+			rule = value
+			continue
+		}
+		valueLoc := loc
+		for _, param := range params[1:] {
+			key, val, _ := strings.Cut(param, "=")
+			if strings.EqualFold(key, "TZID") {
+				var err error
+				if valueLoc, err = time.LoadLocation(strings.Trim(val, `"`)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		var times []time.Time
+		for _, item := range strings.Split(value, ",") {
+			t, _, err := parseICalTime(item, valueLoc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidRecurrence, line, err)
+			}
+			times = append(times, t)
+		}
+		switch name {
+		case "DTSTART":
+			if !rec.Start.IsZero() || len(times) != 1 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidRecurrence, line)
+			}
+			rec.Start, startLoc = times[0], valueLoc
+		case "RDATE":
+			rec.RDates = append(rec.RDates, times...)
+		case "EXDATE":
+			rec.ExDates = append(rec.ExDates, times...)
+		default:
+			return nil, fmt.Errorf("%w: unsupported property %s", ErrInvalidRecurrence, name)
+		}
+	}
+	if rec.Start.IsZero() {
+		return nil, fmt.Errorf("%w: no DTSTART", ErrInvalidRecurrence)
+	}
+	if rule != "" {
+		var err error
+		if rec.Rule, err = ParseRRule(rule, startLoc); err != nil {
+			return nil, err
+		}
+	}
+	return rec, nil
+}
+
+// parseICalTime parses an iCalendar DATE-TIME ("20240325T090000", UTC with a trailing "Z") or DATE
+// ("20240325", midnight) and reports whether it is a DATE
+func parseICalTime(s string, loc *time.Location) (time.Time, bool, error) {
+	var t time.Time
+	var err error
+	switch {
+	case len(s) == 8:
+		t, err = time.ParseInLocation("20060102", s, loc)
+		return t, true, err
+	case strings.HasSuffix(s, "Z"):
+		t, err = time.Parse("20060102T150405Z", s)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", s, loc)
+	}
+	return t, false, err
+}
+
+// Between returns an iterator over the occurrences in [from, to) in chronological order. A zero
+// to means no upper bound; the iterator then ends only when the rule has a COUNT or UNTIL.
+// Occurrences are computed lazily, so unbounded rules are cheap to iterate.
+//
+// Parameters:
+// - from: the first instant to include
+// - to: the instant to stop before, or zero
+//
+// Returns:
+// - *OccurrenceIterator: the iterator
+// - error: ErrInvalidRecurrence if Start is zero or the rule is invalid
+func (rec *Recurrence) Between(from, to time.Time) (*OccurrenceIterator, error) {
+	if rec.Start.IsZero() {
+		return nil, fmt.Errorf("%w: zero start", ErrInvalidRecurrence)
+	}
+	it := &OccurrenceIterator{from: from, to: to, start: rec.Start, loc: rec.Start.Location()}
+	if rec.Rule != nil {
+		if err := rec.Rule.Validate(); err != nil {
+			return nil, err
+		}
+		rule := *rec.Rule
+		if rule.Interval == 0 {
+			rule.Interval = 1
+		}
+		it.rule = &rule
+		it.skipTo(from)
+	}
+	it.rdates = append([]time.Time(nil), rec.RDates...)
+	sort.Slice(it.rdates, func(i, j int) bool { return it.rdates[i].Before(it.rdates[j]) })
+	it.exdates = make(map[[2]int64]bool, len(rec.ExDates))
+	for _, t := range rec.ExDates {
+		it.exdates[instantKey(t)] = true
+	}
+	return it, nil
+}
+
+// Occurrences returns the occurrences in [from, to), see Between.
+//
+// Parameters:
+// - from: the first instant to include
+// - to: the instant to stop before; it must not be zero unless the rule has a COUNT or UNTIL
+//
+// Returns:
+// - []time.Time: the occurrences
+// - error: ErrInvalidRecurrence if Start is zero or the rule is invalid
+func (rec *Recurrence) Occurrences(from, to time.Time) ([]time.Time, error) {
+	it, err := rec.Between(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var occurrences []time.Time
+	for t, ok := it.Next(); ok; t, ok = it.Next() {
+		occurrences = append(occurrences, t)
+	}
+	return occurrences, nil
+}
+
+// OccurrenceIterator iterates over the occurrences of a Recurrence, see Recurrence.Between.
+// It is not safe for concurrent use.
+type OccurrenceIterator struct {
+	from, to time.Time
+	start    time.Time
+	loc      *time.Location
+	rule     *RRule
+
+	period   int         // index of the next period of the rule to expand
+	pending  []time.Time // expanded occurrences of the rule not yet returned
+	emitted  int         // rule occurrences produced so far, for COUNT
+	started  bool        // whether the start has been produced
+	ruleDone bool
+
+	rdates  []time.Time
+	exdates map[[2]int64]bool
+	last    time.Time
+	hasLast bool
+}
+
+// Next returns the next occurrence.
+//
+// Returns:
+// - time.Time: the occurrence in the location of the start, or of the RDATE it comes from
+// - bool: false when there are no more occurrences
+func (it *OccurrenceIterator) Next() (time.Time, bool) {
+	for {
+		next, fromRule, ok := it.peek()
+		if !ok || (!it.to.IsZero() && !next.Before(it.to)) {
+			it.ruleDone, it.rdates = true, nil
+			return time.Time{}, false
+		}
+		if fromRule {
+			it.pending = it.pending[1:]
+		} else {
+			it.rdates = it.rdates[1:]
+		}
+		if (it.hasLast && next.Equal(it.last)) || next.Before(it.from) || it.exdates[instantKey(next)] {
+			continue
+		}
+		it.last, it.hasLast = next, true
+		return next, true
+	}
+}
+
+// peek returns the earliest pending occurrence of the rule and the RDATEs
+func (it *OccurrenceIterator) peek() (time.Time, bool, bool) {
+	ruleNext, ruleOK := it.peekRule()
+	if len(it.rdates) > 0 && (!ruleOK || it.rdates[0].Before(ruleNext)) {
+		return it.rdates[0], false, true
+	}
+	return ruleNext, true, ruleOK
+}
+
+// peekRule returns the next occurrence of the start and the rule, expanding periods as needed
+func (it *OccurrenceIterator) peekRule() (time.Time, bool) {
+	if !it.started {
+		it.started = true
+		it.pending = []time.Time{it.start}
+		if it.rule != nil {
+			it.emitted = 1
+		}
+		return it.start, true
+	}
+	for len(it.pending) == 0 {
+		if it.rule == nil || it.ruleDone || (it.rule.Count > 0 && it.emitted >= it.rule.Count) {
+			return time.Time{}, false
+		}
+		periodStart, ok := it.periodStart(it.period)
+		if !ok || (!it.to.IsZero() && !periodStart.Before(it.to)) {
+			it.ruleDone = true
+			return time.Time{}, false
+		}
+		occurrences := it.expand(it.period)
+		for _, t := range occurrences {
+			if !t.After(it.start) {
+				continue
+			}
+			if (!it.rule.Until.IsZero() && t.After(it.rule.Until)) || (it.rule.Count > 0 && it.emitted >= it.rule.Count) {
+				it.ruleDone = true
+				break
+			}
+			it.pending = append(it.pending, t)
+			it.emitted++
+		}
+		it.period++
+		if len(occurrences) == 0 && it.rule.Freq > Daily {
+			it.skipDay(periodStart)
+		}
+	}
+	return it.pending[0], true
+}
+
+// stepSize is the length of a period of a sub-daily frequency
+func (it *OccurrenceIterator) stepSize() time.Duration {
+	unit := map[Frequency]time.Duration{Hourly: time.Hour, Minutely: time.Minute, Secondly: time.Second}[it.rule.Freq]
+	return unit * time.Duration(it.rule.Interval)
+}
+
+// skipDay moves a sub-daily rule to the first period of the next day if the day of t does not
+// pass the date limits, so that rules such as FREQ=MINUTELY;BYDAY=MO skip whole days at once
+func (it *OccurrenceIterator) skipDay(t time.Time) {
+	local := t.In(it.loc)
+	date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if it.matchesDate(date) {
+		return
+	}
+	step := it.stepSize()
+	next := wallTime(date.AddDate(0, 0, 1), 0, 0, 0, 0, it.loc).Sub(it.start)
+	if period := int((next + step - 1) / step); period > it.period {
+		it.period = period
+	}
+}
+
+// startDate returns the calendar date of the start at midnight UTC
+func (it *OccurrenceIterator) startDate() time.Time {
+	year, month, day := it.start.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart returns the first instant of a period, reporting false past year 9999
+func (it *OccurrenceIterator) periodStart(period int) (time.Time, bool) {
+	var date time.Time
+	interval := it.rule.Interval
+	switch it.rule.Freq {
+	case Yearly:
+		date = time.Date(it.start.Year()+period*interval, time.January, 1, 0, 0, 0, 0, time.UTC)
+	case Monthly:
+		date = time.Date(it.start.Year(), it.start.Month()+time.Month(period*interval), 1, 0, 0, 0, 0, time.UTC)
+	case Weekly:
+		offset := mod(int(it.start.Weekday())-int(it.rule.WeekStart), 7)
+		date = it.startDate().AddDate(0, 0, period*interval*7-offset)
+	case Daily:
+		date = it.startDate().AddDate(0, 0, period*interval)
+	default:
+		step := it.stepSize()
+		if int64(period) > math.MaxInt64/int64(step) {
+			return time.Time{}, false
+		}
+		t := it.start.Add(time.Duration(period) * step)
+		return t, t.Year() <= 9999
+	}
+	if date.Year() > 9999 {
+		return time.Time{}, false
+	}
+	return wallTime(date, 0, 0, 0, 0, it.loc), true
+}
+
+// skipTo moves to the last period that starts before from when the rule has no COUNT, so that
+// iterating a window far after the start does not expand every period in between
+func (it *OccurrenceIterator) skipTo(from time.Time) {
+	if it.rule.Count > 0 || !from.After(it.start) {
+		return
+	}
+	local := from.In(it.loc)
+	fromDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	days := civilDays(fromDate) - civilDays(it.startDate())
+	var periods int
+	switch it.rule.Freq {
+	case Yearly:
+		periods = local.Year() - it.start.Year()
+	case Monthly:
+		periods = (local.Year()-it.start.Year())*12 + int(local.Month()-it.start.Month())
+	case Weekly:
+		periods = days / 7
+	case Daily:
+		periods = days
+	default:
+		if period := int(from.Sub(it.start)/it.stepSize()) - 1; period > 0 {
+			it.period = period
+		}
+		return
+	}
+	if period := periods/it.rule.Interval - 1; period > 0 {
+		it.period = period
+	}
+}
+
+// expand returns the occurrences of the rule in a period in chronological order
+func (it *OccurrenceIterator) expand(period int) []time.Time {
+	r := it.rule
+	var dates []time.Time
+	switch r.Freq {
+	case Yearly:
+		dates = it.expandYear(it.start.Year() + period*r.Interval)
+	case Monthly:
+		first := time.Date(it.start.Year(), it.start.Month()+time.Month(period*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByMonth) == 0 || containsMonth(r.ByMonth, first.Month()) {
+			dates = it.expandMonth(first.Year(), first.Month())
+		}
+	case Weekly:
+		weekStart, _ := it.periodStart(period)
+		first := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, time.UTC)
+		for i := 0; i < 7; i++ {
+			date := first.AddDate(0, 0, i)
+			if (len(r.ByMonth) == 0 || containsMonth(r.ByMonth, date.Month())) &&
+				(len(r.ByDay) == 0 && date.Weekday() == it.start.Weekday() || containsWeekday(r.ByDay, date.Weekday())) {
+				dates = append(dates, date)
+			}
+		}
+	case Daily:
+		date := it.startDate().AddDate(0, 0, period*r.Interval)
+		if it.matchesDate(date) {
+			dates = append(dates, date)
+		}
+	default:
+		t, _ := it.periodStart(period)
+		local := t.In(it.loc)
+		if !it.matchesDate(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)) {
+			return nil
+		}
+		return applySetPos([]time.Time{t}, r.BySetPos)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	dates = applySetPos(dedupeDates(dates), r.BySetPos)
+	hour, minute, second := it.start.Clock()
+	occurrences := make([]time.Time, len(dates))
+	for i, date := range dates {
+		occurrences[i] = wallTime(date, hour, minute, second, it.start.Nanosecond(), it.loc)
+	}
+	return occurrences
+}
+
+// expandYear returns the dates of a yearly period
+func (it *OccurrenceIterator) expandYear(year int) []time.Time {
+	r := it.rule
+	if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0 {
+		first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return weekdaysIn(first, first.AddDate(1, 0, 0), r.ByDay)
+	}
+	months := r.ByMonth
+	if len(months) == 0 {
+		months = []time.Month{it.start.Month()}
+		if len(r.ByMonthDay) > 0 {
+			months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+		}
+	}
+	var dates []time.Time
+	for _, month := range months {
+		dates = append(dates, it.expandMonth(year, month)...)
+	}
+	return dates
+}
+
+// expandMonth returns the dates of a month selected by BYMONTHDAY and BYDAY, or the day of the
+// month of the start if neither is given
+func (it *OccurrenceIterator) expandMonth(year int, month time.Month) []time.Time {
+	r := it.rule
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := DaysInMonth(year, month)
+	switch {
+	case len(r.ByMonthDay) > 0:
+		var dates []time.Time
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d += days + 1
+			}
+			date := first.AddDate(0, 0, d-1)
+			if d >= 1 && d <= days && (len(r.ByDay) == 0 || containsWeekday(r.ByDay, date.Weekday())) {
+				dates = append(dates, date)
+			}
+		}
+		return dates
+	case len(r.ByDay) > 0:
+		return weekdaysIn(first, first.AddDate(0, 1, 0), r.ByDay)
+	case it.start.Day() <= days:
+		return []time.Time{first.AddDate(0, 0, it.start.Day()-1)}
+	}
+	return nil
+}
+
+// matchesDate reports whether a date passes the BYMONTH, BYMONTHDAY and BYDAY limits of
+// daily and sub-daily rules
+func (it *OccurrenceIterator) matchesDate(date time.Time) bool {
+	r := it.rule
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, date.Month()) {
+		return false
+	}
+	if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, date.Weekday()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := DaysInMonth(date.Year(), date.Month())
+		for _, d := range r.ByMonthDay {
+			if d == date.Day() || d < 0 && d+days+1 == date.Day() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// weekdaysIn returns the dates in [first, end) selected by BYDAY entries, whose ordinals count
+// within that span
+func weekdaysIn(first, end time.Time, byDay []WeekdayNum) []time.Time {
+	var dates []time.Time
+	for _, wd := range byDay {
+		var matches []time.Time
+		for date := first.AddDate(0, 0, mod(int(wd.Weekday)-int(first.Weekday()), 7)); date.Before(end); date = date.AddDate(0, 0, 7) {
+			matches = append(matches, date)
+		}
+		switch {
+		case wd.N == 0:
+			dates = append(dates, matches...)
+		case wd.N > 0 && wd.N <= len(matches):
+			dates = append(dates, matches[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matches):
+			dates = append(dates, matches[len(matches)+wd.N])
+		}
+	}
+	return dates
+}
+
+// applySetPos keeps the positions of BYSETPOS in a sorted set
+func applySetPos(set []time.Time, positions []int) []time.Time {
+	if len(positions) == 0 {
+		return set
+	}
+	keep := make([]bool, len(set))
+	for _, p := range positions {
+		if p < 0 {
+			p += len(set) + 1
+		}
+		if p >= 1 && p <= len(set) {
+			keep[p-1] = true
+		}
+	}
+	var result []time.Time
+	for i, t := range set {
+		if keep[i] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// dedupeDates removes repeated dates from a sorted slice
+func dedupeDates(dates []time.Time) []time.Time {
+	result := dates[:0]
+	for i, date := range dates {
+		if i == 0 || !date.Equal(dates[i-1]) {
+			result = append(result, date)
+		}
+	}
+	return result
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWeekday(days []WeekdayNum, weekday time.Weekday) bool {
+	for _, d := range days {
+		if d.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// wallTime returns the instant of a wall-clock time on a date in loc. A time skipped by a DST
+// change is interpreted with the offset in effect before the change, as RFC 5545 requires.
+func wallTime(date time.Time, hour, minute, second, nsec int, loc *time.Location) time.Time {
+	t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, nsec, loc)
+	if t.Hour() == hour && t.Minute() == minute && t.Day() == date.Day() {
+		return t
+	}
+	naive := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, nsec, time.UTC)
+	t, _ = ResolveLocalTime(naive, loc, DisambiguateCompatible)
+	return t
+}
+
+// instantKey identifies an instant independently of its location
+func instantKey(t time.Time) [2]int64 {
+	return [2]int64{t.Unix(), int64(t.Nanosecond())}
+}
@@ -0,0 +1,561 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrUnrecognizedFormat is returned when Parse cannot infer the format of a value
+var ErrUnrecognizedFormat = errors.New("datetime: unrecognized date format")
+
+// Patterns reported by Parse for Unix timestamps
+const (
+	PatternUnix      = "unix"   // Unix seconds, optionally with a fraction, e.g. 1709620200 or 1709620200.5
+	PatternUnixMilli = "unixms" // Unix milliseconds, e.g. 1709620200000
+	PatternUnixMicro = "unixus" // Unix microseconds
+	PatternUnixNano  = "unixns" // Unix nanoseconds
+)
+
+// ParseOptions controls how Parse interprets ambiguous values
+type ParseOptions struct {
+	// DayFirst reads ambiguous numeric dates such as 03/05/2024 as day/month instead of month/day.
+	// Dates whose first or second number exceeds 12 are unambiguous and ignore this option.
+	DayFirst bool
+	// Location is used for values without a zone or offset and to resolve zone abbreviations; nil means UTC
+	Location *time.Location
+}
+
+// ParseResult is the result of Parse
+type ParseResult struct {
+	Time    time.Time // the parsed time
+	Pattern string    // the Java/Unicode-style pattern that matched, or one of the PatternUnix constants
+}
+
+// rfc2822Zones are the zone abbreviations defined by RFC 2822, used when the location does not know them
+var rfc2822Zones = map[string]int{
+	"UT": 0, "EST": -5, "EDT": -4, "CST": -6, "CDT": -5, "MST": -7, "MDT": -6, "PST": -8, "PDT": -7,
+}
+
+// cjkFieldSuffixes maps Chinese date and time suffixes to pattern letters
+var cjkFieldSuffixes = map[string]byte{
+	"年": 'y', "月": 'M', "日": 'd', "号": 'd', "时": 'H', "点": 'H', "分": 'm', "秒": 's',
+}
+
+// Parse parses a date string whose format is not known in advance. It recognises
+// ISO 8601 and RFC 3339 variants including week dates (2024-W10-2) and ordinal dates
+// (2024-065), RFC 1123/2822/850, ANSI C and Unix date output, Unix timestamps in seconds,
+// milliseconds, microseconds or nanoseconds, numeric dates such as 2024/3/5, 20240305
+// and 03/05/2024, Chinese dates such as 2024年3月5日 14时30分, and English forms such as
+// Mar 5, 2024 2:30 PM. A run of digits is a timestamp only if it has at least 9 digits;
+// shorter runs are dates, so 2024 is a year. Ambiguous numeric dates are read as
+// month/day; values without a zone are in UTC. Use ParseWithOptions to change either.
+//
+// Parameters:
+// - value: the date string to parse
+//
+// Returns:
+// - *ParseResult: the parsed time and the pattern that matched
+// - error: ErrUnrecognizedFormat if the format is not recognised, or ErrPatternMismatch if the value is not a valid date
+func Parse(value string) (*ParseResult, error) {
+	return ParseWithOptions(value, ParseOptions{})
+}
+
+// ParseWithOptions is like Parse but with options for ambiguous dates and the default location.
+//
+// Parameters:
+// - value: the date string to parse
+// - opts: the parse options
+//
+// Returns:
+// - *ParseResult: the parsed time and the pattern that matched
+// - error: ErrUnrecognizedFormat or ErrPatternMismatch
+func ParseWithOptions(value string, opts ParseOptions) (*ParseResult, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty string", ErrUnrecognizedFormat)
+	}
+
+	if result, ok := parseDigitsOnly(value, loc); ok {
+		return result, nil
+	}
+
+	tokens, zone, err := inferPattern(value, opts.DayFirst)
+	if err != nil {
+		return nil, err
+	}
+	pattern := buildInferredPattern(tokens)
+	p, err := CompilePattern(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnrecognizedFormat, value)
+	}
+	t, err := p.ParseInLocation(value, loc)
+	if err != nil && zone != "" {
+		if offset, ok := rfc2822Zones[zone]; ok {
+			t, err = p.ParseInLocation(value, time.FixedZone(zone, offset*3600))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ParseResult{Time: t, Pattern: pattern}, nil
+}
+
+// parseDigitsOnly parses compact numeric dates and Unix timestamps
+func parseDigitsOnly(value string, loc *time.Location) (*ParseResult, bool) {
+	digits := strings.TrimPrefix(value, "-")
+	intPart, fracPart, hasFrac := strings.Cut(digits, ".")
+	if !isAllDigits(intPart) || hasFrac && (!isAllDigits(fracPart) || len(fracPart) > 9) {
+		return nil, false
+	}
+
+	if !hasFrac && digits == value {
+		var pattern string
+		switch len(value) {
+		case 8:
+			pattern = PureDatePattern
+		case 12:
+			pattern = "yyyyMMddHHmm"
+		case 14:
+			pattern = PureDatetimePattern
+		case 17:
+			pattern = PureDatetimeMsPattern
+		}
+		if pattern != "" {
+			if t, err := ParseDatePatternInLocation(value, pattern, loc); err == nil {
+				return &ParseResult{Time: t, Pattern: pattern}, true
+			}
+		}
+	}
+
+	// Shorter digit runs are years, months or ordinal dates such as 2024065, not timestamps
+	if len(intPart) < 9 {
+		return nil, false
+	}
+	n, err := strconv.ParseInt(value[:len(value)-len(digits)+len(intPart)], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	var t time.Time
+	var pattern string
+	switch {
+	case hasFrac:
+		nanos, _ := strconv.Atoi(fracPart + strings.Repeat("0", 9-len(fracPart)))
+		if n < 0 {
+			nanos = -nanos
+		}
+		t, pattern = time.Unix(n, int64(nanos)), PatternUnix
+	case len(intPart) <= 10:
+		t, pattern = time.Unix(n, 0), PatternUnix
+	case len(intPart) <= 13:
+		t, pattern = time.UnixMilli(n), PatternUnixMilli
+	case len(intPart) <= 16:
+		t, pattern = time.UnixMicro(n), PatternUnixMicro
+	default:
+		t, pattern = time.Unix(0, n), PatternUnixNano
+	}
+	return &ParseResult{Time: t.In(loc), Pattern: pattern}, true
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// inferToken is a lexical token of a value; role holds the pattern letters assigned to
+// it, an empty role marks a literal and "-" a token consumed by the previous role
+type inferToken struct {
+	kind byte // 'n' digits, 'w' ASCII letters, 'o' any other character
+	text string
+	role string
+}
+
+// lexValue splits a value into digit runs, letter runs, Chinese AM/PM markers and single other characters
+func lexValue(value string) []inferToken {
+	var tokens []inferToken
+	for i := 0; i < len(value); {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(value) && value[j] >= '0' && value[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, inferToken{kind: 'n', text: value[i:j]})
+			i = j
+		case isASCIILetter(c):
+			j := i
+			for j < len(value) && isASCIILetter(value[j]) {
+				j++
+			}
+			tokens = append(tokens, inferToken{kind: 'w', text: value[i:j]})
+			i = j
+		default:
+			if _, rest, ok := matchName(value[i:], meridiemNames[2:]); ok {
+				// Chinese AM/PM markers are words so that they become the 'a' letter
+				j := len(value) - len(rest)
+				tokens = append(tokens, inferToken{kind: 'w', text: value[i:j]})
+				i = j
+				continue
+			}
+			_, size := utf8.DecodeRuneInString(value[i:])
+			tokens = append(tokens, inferToken{kind: 'o', text: value[i : i+size]})
+			i += size
+		}
+	}
+	return tokens
+}
+
+func isASCIILetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// inferPattern assigns pattern letters to the tokens of a value and returns the zone
+// abbreviation, if any
+func inferPattern(value string, dayFirst bool) ([]inferToken, string, error) {
+	tokens := lexValue(value)
+	unrecognized := fmt.Errorf("%w: %q", ErrUnrecognizedFormat, value)
+	text := func(i int) string {
+		if i < 0 || i >= len(tokens) {
+			return ""
+		}
+		return tokens[i].text
+	}
+	isNum := func(i int) bool { return i >= 0 && i < len(tokens) && tokens[i].kind == 'n' }
+	isTimeField := func(i int) bool {
+		return i >= 0 && i < len(tokens) && tokens[i].role != "" && strings.ContainsAny(tokens[i].role[:1], "HhmsS")
+	}
+
+	meridiem := false
+	for _, tok := range tokens {
+		if tok.kind == 'w' && isMeridiem(tok.text) {
+			meridiem = true
+		}
+	}
+	hourLetter := "H"
+	if meridiem {
+		hourLetter = "h"
+	}
+
+	var dateNums []int
+	var zone string
+	hasMonth, timeSeen := false, false
+	timeField := 0
+	nextTimeRole := func(width int) (string, error) {
+		letters := []string{hourLetter, "m", "s"}
+		if timeField >= len(letters) || width > 2 {
+			return "", unrecognized
+		}
+		timeField++
+		timeSeen = true
+		return strings.Repeat(letters[timeField-1], width), nil
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := &tokens[i]
+		var err error
+		switch tok.kind {
+		case 'n':
+			width := len(tok.text)
+			suffix, hasSuffix := cjkFieldSuffixes[text(i+1)]
+			switch {
+			case (text(i-1) == "." || text(i-1) == ",") && isTimeField(i-2) && tokens[i-2].role[0] == 's':
+				if width > 9 {
+					return nil, "", unrecognized
+				}
+				tok.role = strings.Repeat("S", width)
+			case hasSuffix:
+				switch suffix {
+				case 'y':
+					tok.role = yearRole(width)
+				case 'M', 'd':
+					if width > 2 {
+						return nil, "", unrecognized
+					}
+					tok.role = strings.Repeat(string(suffix), width)
+					hasMonth = hasMonth || suffix == 'M'
+				default:
+					timeField = strings.IndexByte("Hms", suffix)
+					tok.role, err = nextTimeRole(width)
+				}
+			case text(i+1) == ":" && isNum(i+2), text(i-1) == ":" && isTimeField(i-2):
+				tok.role, err = nextTimeRole(width)
+			case timeField == 0 && (isTrailingMeridiem(text(i+1)) || text(i+1) == " " && isTrailingMeridiem(text(i+2))):
+				tok.role, err = nextTimeRole(width)
+			case text(i-1) == "T" && timeField == 0 && (width == 2 || width == 4 || width == 6):
+				tok.role = "HHmmss"[:width]
+				timeField, timeSeen = width/2, true
+			default:
+				dateNums = append(dateNums, i)
+			}
+		case 'w':
+			switch word := tok.text; {
+			case isMonthName(word):
+				tok.role = "MMMM"[:min(len(word), 4)]
+				hasMonth = true
+			case isWeekdayName(word):
+				tok.role = "EEEE"[:min(len(word), 4)]
+			case isMeridiem(word):
+				tok.role = "a"
+			case word == "T" && isNum(i-1) && isNum(i+1):
+			case word == "W" && !timeSeen && isNum(i+1) && len(dateNums) == 1 && len(text(dateNums[0])) == 4 &&
+				(dateNums[0] == i-1 || dateNums[0] == i-2 && text(i-1) == "-"):
+				// ISO 8601 week date: 2024-W10-2 or 2024W102, the year is the week-based year
+				switch week := &tokens[i+1]; {
+				case len(week.text) == 2:
+					week.role = "ww"
+				case len(week.text) == 3 && dateNums[0] == i-1:
+					week.role = "wwu"
+				default:
+					return nil, "", unrecognized
+				}
+				i++
+				if tokens[i].role == "ww" && text(i+1) == "-" && isNum(i+2) && len(text(i+2)) == 1 {
+					tokens[i+2].role = "u"
+					i += 2
+				}
+			case word == "Z" && timeSeen:
+				tok.role = "X"
+			case word == "GMT" && timeSeen && (text(i+1) == "+" || text(i+1) == "-"):
+				if !isNum(i+2) || len(text(i+2)) != 2 || text(i+3) != ":" || !isNum(i+4) {
+					return nil, "", unrecognized
+				}
+				tok.role = "ZZZZ"
+				for j := i + 1; j <= i+4; j++ {
+					tokens[j].role = "-"
+				}
+				i += 4
+			case isOrdinalSuffix(word) && isNum(i-1), word == "at" || word == "of" || word == "on":
+			case timeSeen && len(word) >= 2 && len(word) <= 5 && strings.ToUpper(word) == word:
+				tok.role = "z"
+				zone = word
+			default:
+				return nil, "", unrecognized
+			}
+		default:
+			if (tok.text == "+" || tok.text == "-") && timeSeen && isNum(i+1) && (text(i-1) == " " || isTimeField(i-1)) {
+				consumed := 1
+				switch width := len(text(i + 1)); {
+				case width == 4:
+					tok.role = "XX"
+				case width == 2 && text(i+2) == ":" && isNum(i+3) && len(text(i+3)) == 2:
+					tok.role, consumed = "XXX", 3
+				case width == 2:
+					tok.role = "X"
+				default:
+					return nil, "", unrecognized
+				}
+				for j := i + 1; j <= i+consumed; j++ {
+					tokens[j].role = "-"
+				}
+				i += consumed
+			}
+		}
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if !assignDateRoles(tokens, dateNums, hasMonth, dayFirst) {
+		return nil, "", unrecognized
+	}
+	return tokens, zone, nil
+}
+
+// assignDateRoles assigns year, month and day letters to the numbers outside the time
+// and reports whether they form a date
+func assignDateRoles(tokens []inferToken, nums []int, hasMonth, dayFirst bool) bool {
+	ok := true
+	width := func(k int) int { return len(tokens[nums[k]].text) }
+	value := func(k int) int { n, _ := strconv.Atoi(tokens[nums[k]].text); return n }
+	set := func(k int, letter byte) {
+		switch w := width(k); {
+		case letter == 'y':
+			tokens[nums[k]].role = yearRole(w)
+		case w > 2:
+			ok = false
+		default:
+			tokens[nums[k]].role = strings.Repeat(string(letter), w)
+		}
+	}
+	// monthDay assigns month and day to two ambiguous numbers, swapping them when the order cannot be right
+	monthDay := func(a, b int) {
+		month, day := a, b
+		if dayFirst {
+			month, day = b, a
+		}
+		if value(month) > 12 && value(day) <= 12 {
+			month, day = day, month
+		}
+		set(month, 'M')
+		set(day, 'd')
+	}
+
+	if hasMonth {
+		hasDay, hasYear := false, false
+		for k := range nums {
+			switch {
+			case !hasYear && (width(k) >= 3 || hasDay || value(k) > 31):
+				hasYear = true
+				set(k, 'y')
+			case !hasDay:
+				hasDay = true
+				set(k, 'd')
+			default:
+				return false
+			}
+		}
+		return ok
+	}
+
+	switch len(nums) {
+	case 0:
+	case 1:
+		switch width(0) {
+		case 4:
+			set(0, 'y')
+		case 6:
+			tokens[nums[0]].role = "yyyyMM"
+		case 7:
+			tokens[nums[0]].role = "yyyyDDD"
+		case 8:
+			tokens[nums[0]].role = PureDatePattern
+		default:
+			return false
+		}
+	case 2:
+		switch {
+		case width(0) == 4 && width(1) == 3:
+			// ISO 8601 ordinal date such as 2024-065
+			set(0, 'y')
+			tokens[nums[1]].role = "DDD"
+		case width(0) >= 3:
+			set(0, 'y')
+			set(1, 'M')
+		case width(1) >= 3:
+			set(0, 'M')
+			set(1, 'y')
+		default:
+			monthDay(0, 1)
+		}
+	case 3:
+		if width(0) >= 3 || width(2) <= 2 && value(0) > 31 {
+			set(0, 'y')
+			set(1, 'M')
+			set(2, 'd')
+		} else {
+			monthDay(0, 1)
+			set(2, 'y')
+		}
+	default:
+		return false
+	}
+	return ok
+}
+
+// yearRole returns the year letters for a number of the given width
+func yearRole(width int) string {
+	switch width {
+	case 2:
+		return "yy"
+	case 4:
+		return "yyyy"
+	default:
+		return "y"
+	}
+}
+
+// buildInferredPattern joins the roles and literals of the tokens into a pattern
+func buildInferredPattern(tokens []inferToken) string {
+	var b, literal strings.Builder
+	flush := func() {
+		if literal.Len() == 0 {
+			return
+		}
+		// Letters are quoted so that they are not read as pattern letters
+		text := strings.ReplaceAll(literal.String(), "'", "''")
+		for i := 0; i < len(text); {
+			j := i
+			for j < len(text) && isASCIILetter(text[j]) {
+				j++
+			}
+			if j > i {
+				b.WriteString("'" + text[i:j] + "'")
+				i = j
+				continue
+			}
+			b.WriteByte(text[i])
+			i++
+		}
+		literal.Reset()
+	}
+	for _, tok := range tokens {
+		switch tok.role {
+		case "":
+			literal.WriteString(tok.text)
+		case "-":
+		default:
+			flush()
+			b.WriteString(tok.role)
+		}
+	}
+	flush()
+	return b.String()
+}
+
+// isMeridiem reports whether word is an AM/PM marker, see meridiemNames
+func isMeridiem(word string) bool {
+	for _, name := range meridiemNames {
+		if strings.EqualFold(word, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTrailingMeridiem reports whether word is an AM/PM marker written after the hour, as in
+// 2 PM; Chinese markers come before it, as in 下午2点
+func isTrailingMeridiem(word string) bool {
+	return strings.EqualFold(word, "AM") || strings.EqualFold(word, "PM")
+}
+
+func isOrdinalSuffix(word string) bool {
+	switch strings.ToLower(word) {
+	case "st", "nd", "rd", "th":
+		return true
+	}
+	return false
+}
+
+// isMonthName reports whether word is a full or three-letter English month name
+func isMonthName(word string) bool {
+	return isName(word, monthNames(false))
+}
+
+// isWeekdayName reports whether word is a full or three-letter English day-of-week name
+func isWeekdayName(word string) bool {
+	return isName(word, weekdayNames(false))
+}
+
+func isName(word string, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(word, name) || len(word) == 3 && strings.EqualFold(word, name[:3]) {
+			return true
+		}
+	}
+	return false
+}
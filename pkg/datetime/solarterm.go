@@ -0,0 +1,188 @@
+package datetime
+
+import (
+	"fmt"
+	"time"
+)
+
+// SolarTerm represents one of the 24 solar terms (节气), in their order within a Gregorian year
+type SolarTerm int
+
+const (
+	MinorCold          SolarTerm = iota // 小寒
+	MajorCold                           // 大寒
+	StartOfSpring                       // 立春
+	RainWater                           // 雨水
+	AwakeningOfInsects                  // 惊蛰
+	SpringEquinox                       // 春分
+	PureBrightness                      // 清明
+	GrainRain                           // 谷雨
+	StartOfSummer                       // 立夏
+	GrainBuds                           // 小满
+	GrainInEar                          // 芒种
+	SummerSolstice                      // 夏至
+	MinorHeat                           // 小暑
+	MajorHeat                           // 大暑
+	StartOfAutumn                       // 立秋
+	EndOfHeat                           // 处暑
+	WhiteDew                            // 白露
+	AutumnEquinox                       // 秋分
+	ColdDew                             // 寒露
+	FrostsDescent                       // 霜降
+	StartOfWinter                       // 立冬
+	MinorSnow                           // 小雪
+	MajorSnow                           // 大雪
+	WinterSolstice                      // 冬至
+)
+
+var solarTermNames = [24]string{
+	"小寒", "大寒", "立春", "雨水", "惊蛰", "春分", "清明", "谷雨", "立夏", "小满", "芒种", "夏至",
+	"小暑", "大暑", "立秋", "处暑", "白露", "秋分", "寒露", "霜降", "立冬", "小雪", "大雪", "冬至",
+}
+
+// solarTermBaseDays is the earliest day of month of each solar term from 1900 to 2100
+var solarTermBaseDays = [24]int{4, 19, 3, 18, 4, 19, 4, 19, 4, 20, 4, 20, 6, 22, 6, 22, 6, 22, 7, 22, 6, 21, 6, 21}
+
+// solarTerms encodes the dates of the solar terms from 1900 to 2100 in Beijing time:
+// bits 2n and 2n+1 are the days after solarTermBaseDays[n] on which term n falls
+var solarTerms = [...]uint64{
+	0x5aa665a65a56, 0x6aaaa6aa9a5a, 0xaaaaaabaaa6a, 0xaaabbabbafaa, 0x5aa665a65aab, // 1900-1904
+	0x6aaaa6aa9a5a, 0xaaaaaaaaaa6a, 0xaaabbabbafaa, 0x5aa665a65aab, 0x6aaaa6aa9a5a, // 1905-1909
+	0xaaaaaaaaaa6a, 0xaaabbabbafaa, 0x56a665a65aab, 0x6aa6a6aa9a56, 0xaaaaaaaa9a5a, // 1910-1914
+	0xaaabaabaaeaa, 0x569665a65aaa, 0x6aa6a6a69a56, 0x6aaaaaaa9a5a, 0xaaabaabaaeaa, // 1915-1919
+	0x569665a65aaa, 0x5aa6a6a65a56, 0x6aaaaaaa9a5a, 0xaaabaabaaa6a, 0x569665a65aaa, // 1920-1924
+	0x5aa6a6a65a56, 0x6aaaa6aa9a5a, 0xaaabaabaaa6a, 0x555665a65aaa, 0x5aa665a65a56, // 1925-1929
+	0x6aaaa6aa9a5a, 0xaaaaaabaaa6a, 0x555665665aaa, 0x5aa665a65a56, 0x6aaaa6aa9a5a, // 1930-1934
+	0xaaaaaaaaaa6a, 0x555665665aaa, 0x5aa665a65a56, 0x6aaaa6aa9a5a, 0xaaaaaaaaaa6a, // 1935-1939
+	0x555665665aaa, 0x5aa665a65a56, 0x6aaaa6aa9a5a, 0xaaaaaaaaaa6a, 0x555665655aaa, // 1940-1944
+	0x569665a65a56, 0x6aa6a6aa9a56, 0xaaaaaaaa9a5a, 0x5556556559aa, 0x569665a65a55, // 1945-1949
+	0x6aa6a6a65a56, 0xaaaaaaaa9a5a, 0x5556556559aa, 0x569665a65a55, 0x5aa6a6a65a56, // 1950-1954
+	0x6aaaa6aa9a5a, 0x5556556555aa, 0x569665a65a55, 0x5aa665a65a56, 0x6aaaa6aa9a5a, // 1955-1959
+	0x55555565556a, 0x555665665a55, 0x5aa665a65a56, 0x6aaaa6aa9a5a, 0x55555565556a, // 1960-1964
+	0x555665665a55, 0x5aa665a65a56, 0x6aaaa6aa9a5a, 0x55555555556a, 0x555665665a55, // 1965-1969
+	0x5aa665a65a56, 0x6aaaa6aa9a5a, 0x55555555556a, 0x555665655a55, 0x5aa665a65a56, // 1970-1974
+	0x6aa6a6aa9a5a, 0x55555555456a, 0x555655655a55, 0x5a9665a65a56, 0x6aa6a6a69a56, // 1975-1979
+	0x55555555456a, 0x555655655a55, 0x569665a65a56, 0x6aa6a6a65a56, 0x55555155455a, // 1980-1984
+	0x555655655955, 0x569665a65a55, 0x5aa6a5a65a56, 0x15555155455a, 0x555555655555, // 1985-1989
+	0x569665665a55, 0x5aa665a65a56, 0x15555155455a, 0x555555655515, 0x555665665a55, // 1990-1994
+	0x5aa665a65a56, 0x15555155455a, 0x555555555515, 0x555665665a55, 0x5aa665a65a56, // 1995-1999
+	0x15555155455a, 0x555555555515, 0x555665665a55, 0x5aa665a65a56, 0x15555155455a, // 2000-2004
+	0x555555555515, 0x555655655a55, 0x5aa665a65a56, 0x15515155455a, 0x555555554515, // 2005-2009
+	0x555655655a55, 0x5a9665a65a56, 0x15515151455a, 0x555551554515, 0x555655655a55, // 2010-2014
+	0x569665a65a56, 0x155151510556, 0x555551554505, 0x555655655955, 0x569665665a55, // 2015-2019
+	0x155110510556, 0x155551554505, 0x555555655555, 0x569665665a55, 0x055110510556, // 2020-2024
+	0x155551554505, 0x555555555515, 0x555665665a55, 0x055110510556, 0x155551554505, // 2025-2029
+	0x555555555515, 0x555665665a55, 0x055110510556, 0x155551554505, 0x555555555515, // 2030-2034
+	0x555655655a55, 0x055110510556, 0x155551554505, 0x555555555515, 0x555655655a55, // 2035-2039
+	0x055110510556, 0x155151514505, 0x555555554515, 0x555655655a55, 0x054110510556, // 2040-2044
+	0x155151510505, 0x555551554515, 0x555655655a55, 0x014110110556, 0x155110510501, // 2045-2049
+	0x555551554505, 0x555555655555, 0x014110110555, 0x155110510501, 0x555551554505, // 2050-2054
+	0x555555555555, 0x014110110555, 0x055110510501, 0x155551554505, 0x555555555555, // 2055-2059
+	0x000110110555, 0x055110510501, 0x155551554505, 0x555555555515, 0x000110110555, // 2060-2064
+	0x055110510501, 0x155551554505, 0x555555555515, 0x000100100555, 0x055110510501, // 2065-2069
+	0x155151514505, 0x555555555515, 0x000100100555, 0x054110510501, 0x155151514505, // 2070-2074
+	0x555551554515, 0x000100100555, 0x054110510501, 0x155150510505, 0x555551554515, // 2075-2079
+	0x000100100555, 0x014110110501, 0x155110510505, 0x555551554505, 0x000000100055, // 2080-2084
+	0x014110110500, 0x155110510501, 0x555551554505, 0x000000000055, 0x014110110500, // 2085-2089
+	0x055110510501, 0x155551554505, 0x000000000055, 0x000110110500, 0x055110510501, // 2090-2094
+	0x155551554505, 0x000000000015, 0x000100110500, 0x055110510501, 0x155551554505, // 2095-2099
+	0x555555555515, // 2100-2100
+}
+
+// String returns the Chinese name of the solar term
+func (s SolarTerm) String() string {
+	if s < MinorCold || s > WinterSolstice {
+		return fmt.Sprintf("SolarTerm(%d)", int(s))
+	}
+	return solarTermNames[s]
+}
+
+// termMonth returns the Gregorian month in which a solar term falls
+func termMonth(term SolarTerm) time.Month {
+	return time.Month(term/2 + 1)
+}
+
+// solarTermDay returns the day of month of a solar term; year must be within the table
+func solarTermDay(year int, term SolarTerm) int {
+	return solarTermBaseDays[term] + int(solarTerms[year-MinLunarYear]>>(2*term)&3)
+}
+
+// SolarTermDate returns the date of a solar term in a Gregorian year, in Beijing time.
+//
+// Parameters:
+// - year: the Gregorian year, MinLunarYear to MaxLunarYear
+// - term: the solar term
+//
+// Returns:
+// - time.Time: the date at midnight UTC
+// - error: ErrLunarOutOfRange if the year or term is out of range
+func SolarTermDate(year int, term SolarTerm) (time.Time, error) {
+	if year < MinLunarYear || year > MaxLunarYear || term < MinorCold || term > WinterSolstice {
+		return time.Time{}, ErrLunarOutOfRange
+	}
+	return time.Date(year, termMonth(term), solarTermDay(year, term), 0, 0, 0, 0, time.UTC), nil
+}
+
+// SolarTermsOfYear returns the dates of the 24 solar terms in a Gregorian year, indexed by SolarTerm.
+//
+// Parameters:
+// - year: the Gregorian year, MinLunarYear to MaxLunarYear
+//
+// Returns:
+// - []time.Time: the dates at midnight UTC
+// - error: ErrLunarOutOfRange if the year is out of range
+func SolarTermsOfYear(year int) ([]time.Time, error) {
+	if year < MinLunarYear || year > MaxLunarYear {
+		return nil, ErrLunarOutOfRange
+	}
+	dates := make([]time.Time, len(solarTermNames))
+	for term := range dates {
+		dates[term], _ = SolarTermDate(year, SolarTerm(term))
+	}
+	return dates, nil
+}
+
+// GetSolarTerm returns the solar term that falls on a date.
+//
+// Parameters:
+// - date: the date; only its calendar day is used
+//
+// Returns:
+// - SolarTerm: the solar term
+// - bool: false if no solar term falls on the date
+func GetSolarTerm(date time.Time) (SolarTerm, bool) {
+	year, month, day := date.Date()
+	if year < MinLunarYear || year > MaxLunarYear {
+		return 0, false
+	}
+	for _, term := range []SolarTerm{SolarTerm(month-1) * 2, SolarTerm(month-1)*2 + 1} {
+		if solarTermDay(year, term) == day {
+			return term, true
+		}
+	}
+	return 0, false
+}
+
+// CurrentSolarTerm returns the latest solar term on or before a date, i.e. the period the date belongs to.
+//
+// Parameters:
+// - date: the date; only its calendar day is used
+//
+// Returns:
+// - SolarTerm: the solar term
+// - time.Time: the date on which it began, at midnight UTC
+// - error: ErrLunarOutOfRange if the date is outside the solar term table
+func CurrentSolarTerm(date time.Time) (SolarTerm, time.Time, error) {
+	year, month, day := date.Date()
+	if year < MinLunarYear || year > MaxLunarYear || year == MinLunarYear && month == time.January && day < solarTermDay(year, MinorCold) {
+		return 0, time.Time{}, ErrLunarOutOfRange
+	}
+	for term := SolarTerm(month)*2 - 1; term >= MinorCold; term-- {
+		if termMonth(term) < month || solarTermDay(year, term) <= day {
+			start, _ := SolarTermDate(year, term)
+			return term, start, nil
+		}
+	}
+	start, _ := SolarTermDate(year-1, WinterSolstice)
+	return WinterSolstice, start, nil
+}
@@ -8,15 +8,27 @@ import (
 
 // TimeInterval is a simple timer class used to calculate the duration of code execution
 type TimeInterval struct {
+	clock Clock
 	start time.Time
 }
 
-// NewTimeInterval creates a new TimeInterval instance and starts the timer
+// NewTimeInterval creates a new TimeInterval instance using the clock set by SetClock and starts the timer
 //
 // Returns:
 // - *TimeInterval: a new TimeInterval instance
 func NewTimeInterval() *TimeInterval {
-	return &TimeInterval{start: time.Now()}
+	return NewTimeIntervalWithClock(GetClock())
+}
+
+// NewTimeIntervalWithClock creates a new TimeInterval instance that measures time with the given clock and starts the timer
+//
+// Parameters:
+// - clock: the clock, e.g. a FakeClock in tests
+//
+// Returns:
+// - *TimeInterval: a new TimeInterval instance
+func NewTimeIntervalWithClock(clock Clock) *TimeInterval {
+	return &TimeInterval{clock: clock, start: clock.Now()}
 }
 
 // Elapsed returns the elapsed time in the specified unit
@@ -27,7 +39,11 @@ func NewTimeInterval() *TimeInterval {
 // Returns:
 // - int64: the elapsed time in the specified unit
 func (ti *TimeInterval) Elapsed(unit DateUnit) int64 {
-	duration := time.Since(ti.start)
+	clock := ti.clock
+	if clock == nil {
+		clock = GetClock()
+	}
+	duration := clock.Since(ti.start)
 	return duration.Nanoseconds() / (unit.GetMillis() * 1e6)
 }
 
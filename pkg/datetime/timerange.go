@@ -0,0 +1,261 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrInvalidRange is returned when the end of a time range is before its start
+var ErrInvalidRange = errors.New("datetime: end is before start")
+
+// TimeRange is the half-open interval [Start, End). A range with End not after Start is empty.
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewTimeRange creates a time range.
+//
+// Parameters:
+// - start: the first instant of the range
+// - end: the exclusive end of the range
+//
+// Returns:
+// - TimeRange: the range
+// - error: ErrInvalidRange if end is before start
+func NewTimeRange(start, end time.Time) (TimeRange, error) {
+	if end.Before(start) {
+		return TimeRange{}, fmt.Errorf("%w: %v < %v", ErrInvalidRange, end, start)
+	}
+	return TimeRange{Start: start, End: end}, nil
+}
+
+// IsEmpty reports whether the range contains no instant
+func (r TimeRange) IsEmpty() bool {
+	return !r.Start.Before(r.End)
+}
+
+// Duration returns the length of the range, 0 if it is empty
+func (r TimeRange) Duration() time.Duration {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.End.Sub(r.Start)
+}
+
+// Contains reports whether t is in [Start, End)
+func (r TimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// ContainsRange reports whether every instant of other is in the range; an empty other is
+// contained in any range
+func (r TimeRange) ContainsRange(other TimeRange) bool {
+	return other.IsEmpty() || (!other.Start.Before(r.Start) && !other.End.After(r.End))
+}
+
+// Overlaps reports whether the ranges share at least one instant; adjacent ranges such as
+// [9:00, 10:00) and [10:00, 11:00) do not overlap
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return !r.IsEmpty() && !other.IsEmpty() && r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+// Intersect returns the instants in both ranges.
+//
+// Parameters:
+// - other: the other range
+//
+// Returns:
+// - TimeRange: the intersection
+// - bool: false if the ranges do not overlap
+func (r TimeRange) Intersect(other TimeRange) (TimeRange, bool) {
+	if !r.Overlaps(other) {
+		return TimeRange{}, false
+	}
+	return TimeRange{Start: latest(r.Start, other.Start), End: earliest(r.End, other.End)}, true
+}
+
+// Union returns the instants in either range: one range if they overlap or are adjacent,
+// otherwise both ranges in chronological order. Empty ranges are dropped.
+//
+// Parameters:
+// - other: the other range
+//
+// Returns:
+// - []TimeRange: the union
+func (r TimeRange) Union(other TimeRange) []TimeRange {
+	return MergeRanges([]TimeRange{r, other})
+}
+
+// Subtract returns the instants of the range that are not in other, as zero, one or two ranges.
+//
+// Parameters:
+// - other: the range to remove
+//
+// Returns:
+// - []TimeRange: the remaining ranges in chronological order
+func (r TimeRange) Subtract(other TimeRange) []TimeRange {
+	if r.IsEmpty() {
+		return nil
+	}
+	if !r.Overlaps(other) {
+		return []TimeRange{r}
+	}
+	var result []TimeRange
+	if r.Start.Before(other.Start) {
+		result = append(result, TimeRange{Start: r.Start, End: other.Start})
+	}
+	if other.End.Before(r.End) {
+		result = append(result, TimeRange{Start: other.End, End: r.End})
+	}
+	return result
+}
+
+// Split cuts the range at the period boundaries of the unit, with weeks starting on Monday, e.g.
+// a range from Monday 18:00 to Wednesday 09:00 split by Day gives three ranges. See
+// PeriodCalendar.Truncate for the supported units.
+//
+// Parameters:
+// - unit: the period unit
+//
+// Returns:
+// - []TimeRange: the pieces in chronological order, nil if the range is empty or unit is not positive
+func (r TimeRange) Split(unit DateUnit) []TimeRange {
+	if r.IsEmpty() {
+		return nil
+	}
+	var pieces []TimeRange
+	it := PeriodsBetween(r.Start, r.End, unit)
+	for begin, end, ok := it.Next(); ok; begin, end, ok = it.Next() {
+		pieces = append(pieces, TimeRange{Start: latest(begin, r.Start), End: earliest(end, r.End)})
+	}
+	return pieces
+}
+
+// Iterate returns an iterator over the instants Start, Start+step, ... before End. Multiples of
+// MonthUnit (including Quarter and Year) step by calendar months, clamping the day to the end of
+// shorter months (Jan 31 is followed by Feb 29, Mar 31, Apr 30), and multiples of Day by calendar
+// days, keeping the wall-clock time across DST changes; other steps add a fixed duration.
+//
+// Parameters:
+// - step: the step; the iterator is empty if it is not positive
+//
+// Returns:
+// - *TimeIterator: the iterator
+func (r TimeRange) Iterate(step DateUnit) *TimeIterator {
+	return &TimeIterator{start: r.Start, end: r.End, step: step}
+}
+
+// String formats the range as "[start, end)" with RFC 3339 times
+func (r TimeRange) String() string {
+	return "[" + r.Start.Format(time.RFC3339Nano) + ", " + r.End.Format(time.RFC3339Nano) + ")"
+}
+
+// TimeIterator steps through a time range, see TimeRange.Iterate. It is not safe for concurrent use.
+type TimeIterator struct {
+	start, end time.Time
+	step       DateUnit
+	index      int
+}
+
+// Next returns the next instant.
+//
+// Returns:
+// - time.Time: the instant
+// - bool: false when the end of the range is reached
+func (it *TimeIterator) Next() (time.Time, bool) {
+	if it.step <= 0 {
+		return time.Time{}, false
+	}
+	// each instant is computed from the start so that month ends do not drift
+	var t time.Time
+	switch {
+	case it.step%MonthUnit == 0:
+		t = addMonthsClamped(it.start, int(it.step/MonthUnit)*it.index)
+	case it.step%Day == 0:
+		t = it.start.AddDate(0, 0, int(it.step/Day)*it.index)
+	default:
+		t = it.start.Add(time.Duration(it.step) * time.Millisecond * time.Duration(it.index))
+	}
+	if !t.Before(it.end) {
+		it.step = 0
+		return time.Time{}, false
+	}
+	it.index++
+	return t, true
+}
+
+// addMonthsClamped adds months to t like AddDate but clamps the day to the last day of the target
+// month instead of overflowing into the next one, so Jan 31 plus one month is Feb 28 or 29.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	if last := DaysInMonth(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	hour, min, sec := t.Clock()
+	return time.Date(first.Year(), first.Month(), day, hour, min, sec, t.Nanosecond(), t.Location())
+}
+
+// MergeRanges merges overlapping and adjacent ranges and drops empty ones.
+//
+// Parameters:
+// - ranges: the ranges in any order; the slice is not modified
+//
+// Returns:
+// - []TimeRange: disjoint, non-adjacent ranges in chronological order
+func MergeRanges(ranges []TimeRange) []TimeRange {
+	sorted := make([]TimeRange, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	var merged []TimeRange
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && !r.Start.After(merged[last].End) {
+			merged[last].End = latest(merged[last].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// SubtractRanges removes a set of ranges from another, e.g. busy times from working hours to
+// find available windows.
+//
+// Parameters:
+// - ranges: the ranges to subtract from, in any order
+// - remove: the ranges to remove, in any order
+//
+// Returns:
+// - []TimeRange: the remaining instants as disjoint ranges in chronological order
+func SubtractRanges(ranges, remove []TimeRange) []TimeRange {
+	result := MergeRanges(ranges)
+	for _, cut := range MergeRanges(remove) {
+		var next []TimeRange
+		for _, r := range result {
+			next = append(next, r.Subtract(cut)...)
+		}
+		result = next
+	}
+	return result
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
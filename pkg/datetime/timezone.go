@@ -0,0 +1,396 @@
+package datetime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownZone is returned when a time zone name cannot be loaded
+	ErrUnknownZone = errors.New("datetime: unknown time zone")
+	// ErrAmbiguousTime is returned by DisambiguateReject for local times that occur twice
+	ErrAmbiguousTime = errors.New("datetime: ambiguous local time")
+	// ErrSkippedTime is returned by DisambiguateReject for local times skipped by a DST change
+	ErrSkippedTime = errors.New("datetime: skipped local time")
+)
+
+var (
+	zoneCacheMu sync.RWMutex
+	zoneCache   = map[string]*time.Location{}
+)
+
+// LoadZone loads an IANA time zone such as "Asia/Shanghai" like time.LoadLocation, caching the
+// result. Minimal containers without a zone database can embed one by building with -tags tzdata.
+//
+// Parameters:
+// - name: the zone name; "" and "UTC" are UTC and "Local" is the local zone
+//
+// Returns:
+// - *time.Location: the zone
+// - error: ErrUnknownZone if the zone cannot be loaded
+func LoadZone(name string) (*time.Location, error) {
+	zoneCacheMu.RLock()
+	loc, ok := zoneCache[name]
+	zoneCacheMu.RUnlock()
+	if ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrUnknownZone, name, err)
+	}
+	zoneCacheMu.Lock()
+	zoneCache[name] = loc
+	zoneCacheMu.Unlock()
+	return loc, nil
+}
+
+// ConvertZone returns the same instant in another time zone.
+//
+// Parameters:
+// - t: the time
+// - zone: the IANA name of the target zone
+//
+// Returns:
+// - time.Time: t in the target zone
+// - error: ErrUnknownZone if the zone cannot be loaded
+func ConvertZone(t time.Time, zone string) (time.Time, error) {
+	loc, err := LoadZone(zone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
+
+// ConvertLocalTime interprets the wall clock of a time in one zone and returns it in another,
+// e.g. "09:00 in New York is 15:00 in Berlin". The location of wall is ignored.
+//
+// Parameters:
+// - wall: the date and time of day in the source zone
+// - from: the IANA name of the source zone
+// - to: the IANA name of the target zone
+// - d: how to resolve local times that are ambiguous or skipped in the source zone
+//
+// Returns:
+// - time.Time: the instant in the target zone
+// - error: ErrUnknownZone, or ErrAmbiguousTime or ErrSkippedTime with DisambiguateReject
+func ConvertLocalTime(wall time.Time, from, to string, d Disambiguation) (time.Time, error) {
+	fromLoc, err := LoadZone(from)
+	if err != nil {
+		return time.Time{}, err
+	}
+	toLoc, err := LoadZone(to)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := ResolveLocalTime(wall, fromLoc, d)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(toLoc), nil
+}
+
+// InZone returns the DateTime in another time zone.
+//
+// Parameters:
+// - zone: the IANA name of the zone
+//
+// Returns:
+// - *DateTime: the same instant in the zone
+// - error: ErrUnknownZone if the zone cannot be loaded
+func (dt *DateTime) InZone(zone string) (*DateTime, error) {
+	t, err := ConvertZone(dt.Time, zone)
+	if err != nil {
+		return nil, err
+	}
+	return NewDateTime(t), nil
+}
+
+// ZoneInfo is a time zone at an instant
+type ZoneInfo struct {
+	Name         string        // IANA name, e.g. "Europe/Berlin"
+	Country      string        // ISO 3166 country code, empty if unknown
+	Abbreviation string        // e.g. "CEST"; zones without one use the offset, e.g. "+08"
+	Offset       time.Duration // offset from UTC
+	IsDST        bool
+	Time         time.Time // the instant in the zone
+}
+
+// OffsetString formats the offset as "+08:00" or "-03:30"
+func (z ZoneInfo) OffsetString() string {
+	sign, offset := '+', z.Offset
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	minutes := int(offset / time.Minute)
+	return fmt.Sprintf("%c%02d:%02d", sign, minutes/60, minutes%60)
+}
+
+// String formats the zone as "Europe/Berlin (CEST, UTC+02:00)"
+func (z ZoneInfo) String() string {
+	return z.Name + " (" + z.Abbreviation + ", UTC" + z.OffsetString() + ")"
+}
+
+// zoneInfo describes a loaded zone at an instant
+func zoneInfo(name, country string, loc *time.Location, at time.Time) ZoneInfo {
+	t := at.In(loc)
+	abbreviation, offset := t.Zone()
+	return ZoneInfo{
+		Name:         name,
+		Country:      country,
+		Abbreviation: abbreviation,
+		Offset:       time.Duration(offset) * time.Second,
+		IsDST:        t.IsDST(),
+		Time:         t,
+	}
+}
+
+// zoneCountry returns the country of a canonical zone
+func zoneCountry(name string) string {
+	i := sort.Search(len(ianaZones), func(i int) bool { return ianaZones[i].name >= name })
+	if i < len(ianaZones) && ianaZones[i].name == name {
+		return ianaZones[i].country
+	}
+	return ""
+}
+
+// ListZones lists the canonical IANA time zones with their offsets at an instant, ordered by
+// offset and then by name. Zones missing from the zone database are left out.
+//
+// Parameters:
+// - at: the instant, e.g. time.Now()
+//
+// Returns:
+// - []ZoneInfo: the zones
+func ListZones(at time.Time) []ZoneInfo {
+	zones := make([]ZoneInfo, 0, len(ianaZones))
+	for _, zone := range ianaZones {
+		if loc, err := LoadZone(zone.name); err == nil {
+			zones = append(zones, zoneInfo(zone.name, zone.country, loc, at))
+		}
+	}
+	sort.SliceStable(zones, func(i, j int) bool { return zones[i].Offset < zones[j].Offset })
+	return zones
+}
+
+// ZonesOfCountry returns the canonical IANA time zones of a country, ordered by name.
+//
+// Parameters:
+// - country: the ISO 3166 country code, e.g. "CN"
+//
+// Returns:
+// - []string: the zone names, empty for unknown countries
+func ZonesOfCountry(country string) []string {
+	var names []string
+	for _, zone := range ianaZones {
+		if zone.country == country {
+			names = append(names, zone.name)
+		}
+	}
+	return names
+}
+
+// WorldClock returns an instant in several time zones, in the given order.
+//
+// Parameters:
+// - at: the instant
+// - zones: the IANA zone names
+//
+// Returns:
+// - []ZoneInfo: the instant in each zone
+// - error: ErrUnknownZone for the first zone that cannot be loaded
+func WorldClock(at time.Time, zones ...string) ([]ZoneInfo, error) {
+	infos := make([]ZoneInfo, 0, len(zones))
+	for _, name := range zones {
+		loc, err := LoadZone(name)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, zoneInfo(name, zoneCountry(name), loc, at))
+	}
+	return infos, nil
+}
+
+// FormatInZone formats an instant in a time zone with a Go layout; use "MST" in the layout for
+// the zone abbreviation, e.g. "2006-01-02 15:04 MST".
+//
+// Parameters:
+// - t: the instant
+// - zone: the IANA zone name
+// - layout: the Go layout
+//
+// Returns:
+// - string: the formatted time
+// - error: ErrUnknownZone if the zone cannot be loaded
+func FormatInZone(t time.Time, zone, layout string) (string, error) {
+	local, err := ConvertZone(t, zone)
+	if err != nil {
+		return "", err
+	}
+	return local.Format(layout), nil
+}
+
+// ZoneTransition is a change of the UTC offset or abbreviation of a time zone
+type ZoneTransition struct {
+	At     time.Time     // the first instant with the new offset, in the zone
+	Before time.Duration // offset before the transition
+	After  time.Duration // offset from the transition on
+}
+
+// ZoneTransitions returns the transitions of a time zone in [start, end), e.g. its DST changes.
+//
+// Parameters:
+// - loc: the zone
+// - start: the start of the range
+// - end: the exclusive end of the range
+//
+// Returns:
+// - []ZoneTransition: the transitions in chronological order
+func ZoneTransitions(loc *time.Location, start, end time.Time) []ZoneTransition {
+	var transitions []ZoneTransition
+	for t := start.In(loc); t.Before(end); {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		_, before := t.Zone()
+		_, after := next.Zone()
+		transitions = append(transitions, ZoneTransition{
+			At:     next,
+			Before: time.Duration(before) * time.Second,
+			After:  time.Duration(after) * time.Second,
+		})
+		t = next
+	}
+	return transitions
+}
+
+// LocalTimeKind classifies a wall-clock time in a time zone
+type LocalTimeKind int
+
+const (
+	// LocalTimeUnique is a local time that occurs exactly once
+	LocalTimeUnique LocalTimeKind = iota
+	// LocalTimeAmbiguous is a local time that occurs twice, when clocks are turned back
+	LocalTimeAmbiguous
+	// LocalTimeSkipped is a local time that does not occur, when clocks are turned forward
+	LocalTimeSkipped
+)
+
+// String returns the name of the kind
+func (k LocalTimeKind) String() string {
+	switch k {
+	case LocalTimeUnique:
+		return "unique"
+	case LocalTimeAmbiguous:
+		return "ambiguous"
+	case LocalTimeSkipped:
+		return "skipped"
+	default:
+		return "LocalTimeKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// LocalTime describes how a wall-clock time maps to instants in a time zone. For a unique time
+// Earlier and Later are the same instant. For an ambiguous time they are its two occurrences. For
+// a skipped time they are the wall-clock time read with the offset after and before the gap,
+// which moves it backward and forward by the length of the gap, e.g. 02:30 on a spring-forward
+// day in New York gives 01:30 EST and 03:30 EDT.
+type LocalTime struct {
+	Kind    LocalTimeKind
+	Earlier time.Time
+	Later   time.Time
+}
+
+// InspectLocalTime classifies a wall-clock time in a time zone.
+//
+// Parameters:
+// - wall: the date and time of day; its location is ignored
+// - loc: the time zone
+//
+// Returns:
+// - LocalTime: the kind and the candidate instants in loc
+func InspectLocalTime(wall time.Time, loc *time.Location) LocalTime {
+	year, month, day := wall.Date()
+	hour, minute, second := wall.Clock()
+	naive := time.Date(year, month, day, hour, minute, second, wall.Nanosecond(), time.UTC)
+
+	// the offsets in effect around the wall-clock time include those before and after any transition
+	var offsets []int
+	for _, probe := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := naive.Add(probe).In(loc).Zone()
+		if len(offsets) == 0 || offsets[len(offsets)-1] != offset {
+			offsets = append(offsets, offset)
+		}
+	}
+	var matches []time.Time
+	for _, offset := range offsets {
+		t := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		y, m, d := t.Date()
+		h, mi, s := t.Clock()
+		if time.Date(y, m, d, h, mi, s, t.Nanosecond(), time.UTC).Equal(naive) && (len(matches) == 0 || !matches[0].Equal(t)) {
+			matches = append(matches, t)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Before(matches[j]) })
+
+	switch len(matches) {
+	case 1:
+		return LocalTime{Kind: LocalTimeUnique, Earlier: matches[0], Later: matches[0]}
+	case 0:
+		before := naive.Add(-time.Duration(offsets[0]) * time.Second).In(loc)
+		after := naive.Add(-time.Duration(offsets[len(offsets)-1]) * time.Second).In(loc)
+		if after.After(before) {
+			before, after = after, before
+		}
+		return LocalTime{Kind: LocalTimeSkipped, Earlier: after, Later: before}
+	default:
+		return LocalTime{Kind: LocalTimeAmbiguous, Earlier: matches[0], Later: matches[len(matches)-1]}
+	}
+}
+
+// Disambiguation selects the instant of an ambiguous or skipped local time
+type Disambiguation int
+
+const (
+	// DisambiguateCompatible uses the earlier occurrence of an ambiguous time and moves a skipped
+	// time forward by the length of the gap, as time.Date usually does and RFC 5545 requires
+	DisambiguateCompatible Disambiguation = iota
+	// DisambiguateEarlier uses the earlier instant in both cases
+	DisambiguateEarlier
+	// DisambiguateLater uses the later instant in both cases
+	DisambiguateLater
+	// DisambiguateReject returns ErrAmbiguousTime or ErrSkippedTime
+	DisambiguateReject
+)
+
+// ResolveLocalTime returns the instant of a wall-clock time in a time zone, resolving ambiguous
+// and skipped times explicitly instead of relying on time.Date.
+//
+// Parameters:
+// - wall: the date and time of day; its location is ignored
+// - loc: the time zone
+// - d: the resolution policy
+//
+// Returns:
+// - time.Time: the instant in loc
+// - error: ErrAmbiguousTime or ErrSkippedTime with DisambiguateReject
+func ResolveLocalTime(wall time.Time, loc *time.Location, d Disambiguation) (time.Time, error) {
+	local := InspectLocalTime(wall, loc)
+	switch {
+	case local.Kind == LocalTimeUnique:
+		return local.Earlier, nil
+	case d == DisambiguateReject && local.Kind == LocalTimeAmbiguous:
+		return time.Time{}, fmt.Errorf("%w: %s in %s", ErrAmbiguousTime, wall.Format("2006-01-02 15:04:05"), loc)
+	case d == DisambiguateReject:
+		return time.Time{}, fmt.Errorf("%w: %s in %s", ErrSkippedTime, wall.Format("2006-01-02 15:04:05"), loc)
+	case d == DisambiguateLater || (d == DisambiguateCompatible && local.Kind == LocalTimeSkipped):
+		return local.Later, nil
+	default:
+		return local.Earlier, nil
+	}
+}
@@ -0,0 +1,10 @@
+//go:build tzdata
+
+package datetime
+
+// Building with -tags tzdata embeds the IANA time zone database, adding about 450 KB to the
+// binary, so that LoadZone and ListZones work in minimal containers without /usr/share/zoneinfo.
+import _ "time/tzdata"
+
+// EmbeddedTZData reports whether the time zone database is embedded with the tzdata build tag
+const EmbeddedTZData = true
@@ -0,0 +1,7 @@
+//go:build !tzdata
+
+package datetime
+
+// EmbeddedTZData reports whether the time zone database is embedded with the tzdata build tag;
+// without it, time zones are loaded from the system
+const EmbeddedTZData = false
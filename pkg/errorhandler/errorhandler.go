@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 )
 
 // ErrorLevel represents the severity level of an error
@@ -43,6 +44,14 @@ func (e *CustomError) Error() string {
 		e.Code, e.Level, e.Message, e.Context, e.Original)
 }
 
+// Unwrap returns the original error, so errors.Is and errors.As can match it
+//
+// Returns:
+// - error: the original error, or nil
+func (e *CustomError) Unwrap() error {
+	return e.Original
+}
+
 // NewError creates a new custom error
 //
 // Parameters:
@@ -175,7 +184,9 @@ func NewAggregateError(errors []error) *AggregateError {
 	return &AggregateError{Errors: errors}
 }
 
-// Multi-language support (example)
+// Multi-language support
+var languageMu sync.RWMutex
+
 var languageMessages = map[string]map[int]string{
 	"en": {
 		1001: "Resource not found",
@@ -200,6 +211,8 @@ var languageMessages = map[string]map[int]string{
 // Returns:
 // - string: the localized error message
 func GetLocalizedMessage(code int, lang string) string {
+	languageMu.RLock()
+	defer languageMu.RUnlock()
 	if messages, exists := languageMessages[lang]; exists {
 		if message, exists := messages[code]; exists {
 			return message
@@ -207,3 +220,34 @@ func GetLocalizedMessage(code int, lang string) string {
 	}
 	return fmt.Sprintf("Unknown error code: %d", code)
 }
+
+// RegisterLocalizedMessages adds or replaces localized messages for a language
+//
+// Parameters:
+// - lang: the language code
+// - messages: the messages keyed by error code
+func RegisterLocalizedMessages(lang string, messages map[int]string) {
+	languageMu.Lock()
+	defer languageMu.Unlock()
+	if _, exists := languageMessages[lang]; !exists {
+		languageMessages[lang] = make(map[int]string, len(messages))
+	}
+	for code, message := range messages {
+		languageMessages[lang][code] = message
+	}
+}
+
+// HasLocalizedMessage checks whether a message is registered for the code and language
+//
+// Parameters:
+// - code: the error code
+// - lang: the language code
+//
+// Returns:
+// - bool: true if a message exists, false otherwise
+func HasLocalizedMessage(code int, lang string) bool {
+	languageMu.RLock()
+	defer languageMu.RUnlock()
+	_, exists := languageMessages[lang][code]
+	return exists
+}
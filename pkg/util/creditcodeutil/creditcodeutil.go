@@ -1,7 +1,9 @@
 package creditcodeutil
 
 import (
+	"errors"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
 )
@@ -9,52 +11,258 @@ import (
 // CreditCodeUtil 社会信用代码工具类
 type CreditCodeUtil struct{}
 
+// 统一社会信用代码（GB 32100-2015）的组成：
+// 第 1 位登记管理部门代码，第 2 位机构类别代码，第 3-8 位登记管理机关行政区划码，
+// 第 9-17 位主体标识码（组织机构代码，GB 11714），第 18 位校验码。
+const (
+	// CreditCodeLength 统一社会信用代码长度
+	CreditCodeLength = 18
+
+	// creditCodeChars 代码字符集，不使用 I、O、Z、S、V，字符在其中的下标即为其代码值
+	creditCodeChars = "0123456789ABCDEFGHJKLMNPQRTUWXY"
+)
+
+// creditCodeWeights 第 1-17 位的加权因子，即 3^(i) mod 31
+var creditCodeWeights = [17]int{1, 3, 9, 27, 19, 26, 16, 17, 20, 29, 25, 13, 8, 24, 10, 30, 28}
+
+// orgCodeWeights 组织机构代码本体代码第 1-8 位的加权因子（GB 11714）
+var orgCodeWeights = [8]int{3, 7, 9, 10, 5, 8, 4, 2}
+
+// 校验失败时返回的错误
+var (
+	ErrInvalidLength                = errors.New("统一社会信用代码长度必须为 18 位")
+	ErrInvalidChar                  = errors.New("统一社会信用代码含有非法字符")
+	ErrInvalidRegistrationAuthority = errors.New("无效的登记管理部门代码")
+	ErrInvalidOrganizationType      = errors.New("无效的机构类别代码")
+	ErrInvalidRegionCode            = errors.New("无效的登记管理机关行政区划码")
+	ErrInvalidOrganizationCode      = errors.New("无效的组织机构代码")
+	ErrInvalidCheckCode             = errors.New("统一社会信用代码校验码错误")
+)
+
+// registrationAuthority 登记管理部门及其机构类别
+type registrationAuthority struct {
+	name  string
+	types map[byte]string
+}
+
+// registrationAuthorities 登记管理部门代码和机构类别代码（GB 32100-2015 附录 A）
+var registrationAuthorities = map[byte]registrationAuthority{
+	'1': {"机构编制", map[byte]string{'1': "机关", '2': "事业单位", '3': "中央编办直接管理机构编制的群众团体", '9': "其他"}},
+	'2': {"外交", map[byte]string{'1': "外国常驻新闻机构", '9': "其他"}},
+	'3': {"司法行政", map[byte]string{'1': "律师执业机构", '2': "公证处", '3': "基层法律服务所", '4': "司法鉴定机构", '5': "仲裁委员会", '9': "其他"}},
+	'4': {"文化", map[byte]string{'1': "外国在华文化中心", '9': "其他"}},
+	'5': {"民政", map[byte]string{'1': "社会团体", '2': "民办非企业单位", '3': "基金会", '9': "其他"}},
+	'6': {"旅游", map[byte]string{'1': "外国旅游部门常驻代表机构", '2': "港澳台地区旅游部门常驻内地（大陆）代表机构", '9': "其他"}},
+	'7': {"宗教", map[byte]string{'1': "宗教活动场所", '2': "宗教院校", '9': "其他"}},
+	'8': {"工会", map[byte]string{'1': "基层工会", '9': "其他"}},
+	'9': {"工商", map[byte]string{'1': "企业", '2': "个体工商户", '3': "农民专业合作社"}},
+	'A': {"中央军委改革和编制办公室", map[byte]string{'1': "军队事业单位", '9': "其他"}},
+	'N': {"农业", map[byte]string{'1': "组级集体经济组织", '2': "村级集体经济组织", '3': "乡镇级集体经济组织", '9': "其他"}},
+	'Y': {"其他", map[byte]string{'1': "其他"}},
+}
+
+// registrationAuthorityCodes 按顺序排列的登记管理部门代码
+const registrationAuthorityCodes = "123456789ANY"
+
+// provinceCodes 行政区划码的省级前两位，10 表示国家级登记管理机关
+var provinceCodes = map[string]bool{
+	"10": true,
+	"11": true, "12": true, "13": true, "14": true, "15": true,
+	"21": true, "22": true, "23": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "36": true, "37": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true,
+	"50": true, "51": true, "52": true, "53": true, "54": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true,
+	"71": true, "81": true, "82": true,
+}
+
+// CreditCode 统一社会信用代码的各组成部分
+type CreditCode struct {
+	Code                      string // 完整代码
+	RegistrationAuthority     byte   // 登记管理部门代码（第 1 位）
+	RegistrationAuthorityName string // 登记管理部门名称
+	OrganizationType          byte   // 机构类别代码（第 2 位）
+	OrganizationTypeName      string // 机构类别名称
+	RegionCode                string // 登记管理机关行政区划码（第 3-8 位）
+	OrganizationCode          string // 主体标识码，即组织机构代码（第 9-17 位）
+	CheckCode                 byte   // 校验码（第 18 位）
+}
+
 // isValidChar 校验字符是否符合要求
 func isValidChar(char rune) bool {
-	return (char >= '0' && char <= '9') || (char >= 'A' && char <= 'Z')
+	return char < 0x80 && strings.IndexByte(creditCodeChars, byte(char)) >= 0
 }
 
-// ValidateCreditCode 校验社会信用代码
+// ValidateCreditCode 校验社会信用代码，包括字符集、登记管理部门和机构类别代码、
+// 行政区划码、组织机构代码校验位以及第 18 位校验码
 func ValidateCreditCode(code string) bool {
-	if len(code) != 18 {
-		return false
-	}
+	_, err := ParseCreditCode(code)
+	return err == nil
+}
 
-	// 校验每个字符是否有效
+// ParseCreditCode 校验并解析社会信用代码，校验失败时返回对应的错误
+func ParseCreditCode(code string) (*CreditCode, error) {
+	if len(code) != CreditCodeLength {
+		return nil, ErrInvalidLength
+	}
 	for _, char := range code {
 		if !isValidChar(char) {
-			return false
+			return nil, ErrInvalidChar
 		}
 	}
 
-	// 计算校验位
-	// 这里可以根据需要实现具体的校验逻辑
-	// 当前实现仅返回true，需替换为实际的校验逻辑
-	return true
+	authority, ok := registrationAuthorities[code[0]]
+	if !ok {
+		return nil, ErrInvalidRegistrationAuthority
+	}
+	typeName, ok := authority.types[code[1]]
+	if !ok {
+		return nil, ErrInvalidOrganizationType
+	}
+	if !isValidRegionCode(code[2:8]) {
+		return nil, ErrInvalidRegionCode
+	}
+	if orgCheck, err := OrganizationCodeCheckDigit(code[8:16]); err != nil || orgCheck != code[16] {
+		return nil, ErrInvalidOrganizationCode
+	}
+	if check, _ := CreditCodeCheckCode(code[:17]); check != code[17] {
+		return nil, ErrInvalidCheckCode
+	}
+
+	return &CreditCode{
+		Code:                      code,
+		RegistrationAuthority:     code[0],
+		RegistrationAuthorityName: authority.name,
+		OrganizationType:          code[1],
+		OrganizationTypeName:      typeName,
+		RegionCode:                code[2:8],
+		OrganizationCode:          code[8:17],
+		CheckCode:                 code[17],
+	}, nil
 }
 
-// randomChar 生成随机字符
-func randomChar() rune {
-	if rand.Intn(2) == 0 {
-		return rune(rand.Intn(10) + '0') // 生成数字
+// CreditCodeCheckCode 根据前 17 位计算第 18 位校验码：C18 = 31 - (∑Ci×Wi mod 31)，结果为 31 时取 0
+func CreditCodeCheckCode(code17 string) (byte, error) {
+	if len(code17) != CreditCodeLength-1 {
+		return 0, ErrInvalidLength
 	}
-	return rune(rand.Intn(26) + 'A') // 生成大写字母
+	sum := 0
+	for i := 0; i < len(code17); i++ {
+		value := strings.IndexByte(creditCodeChars, code17[i])
+		if value < 0 {
+			return 0, ErrInvalidChar
+		}
+		sum += value * creditCodeWeights[i]
+	}
+	return creditCodeChars[(31-sum%31)%31], nil
 }
 
-// RandomCreditCode 生成随机社会信用代码
+// OrganizationCodeCheckDigit 根据 8 位本体代码计算组织机构代码校验位（GB 11714）：
+// C9 = 11 - (∑Ci×Wi mod 11)，结果为 10 时取 X，为 11 时取 0
+func OrganizationCodeCheckDigit(code8 string) (byte, error) {
+	if len(code8) != 8 {
+		return 0, ErrInvalidOrganizationCode
+	}
+	sum := 0
+	for i := 0; i < len(code8); i++ {
+		var value int
+		switch char := code8[i]; {
+		case char >= '0' && char <= '9':
+			value = int(char - '0')
+		case char >= 'A' && char <= 'Z':
+			value = int(char-'A') + 10
+		default:
+			return 0, ErrInvalidOrganizationCode
+		}
+		sum += value * orgCodeWeights[i]
+	}
+	switch check := 11 - sum%11; check {
+	case 10:
+		return 'X', nil
+	case 11:
+		return '0', nil
+	default:
+		return byte('0' + check), nil
+	}
+}
+
+// GenerateCreditCode 由各组成部分生成社会信用代码，自动补全组织机构代码校验位和第 18 位校验码
+// orgCode 为 8 位组织机构本体代码
+func GenerateCreditCode(authority, orgType byte, regionCode, orgCode string) (string, error) {
+	reg, ok := registrationAuthorities[authority]
+	if !ok {
+		return "", ErrInvalidRegistrationAuthority
+	}
+	if _, ok := reg.types[orgType]; !ok {
+		return "", ErrInvalidOrganizationType
+	}
+	if !isValidRegionCode(regionCode) {
+		return "", ErrInvalidRegionCode
+	}
+	for _, char := range orgCode {
+		if !isValidChar(char) {
+			return "", ErrInvalidOrganizationCode
+		}
+	}
+	orgCheck, err := OrganizationCodeCheckDigit(orgCode)
+	if err != nil {
+		return "", err
+	}
+
+	code17 := string([]byte{authority, orgType}) + regionCode + orgCode + string(orgCheck)
+	check, err := CreditCodeCheckCode(code17)
+	if err != nil {
+		return "", err
+	}
+	return code17 + string(check), nil
+}
+
+// sampleRegionCodes 生成随机代码时使用的行政区划码
+var sampleRegionCodes = []string{
+	"100000", "110105", "110108", "120101", "310104", "310115", "320102", "330106",
+	"370102", "420106", "440106", "440305", "500103", "510107", "610113",
+}
+
+// randomChar 从代码字符集中生成随机字符
+func randomChar() byte {
+	return creditCodeChars[rand.Intn(len(creditCodeChars))]
+}
+
+// RandomCreditCode 生成随机的、可以通过 ValidateCreditCode 校验的社会信用代码
 func RandomCreditCode() string {
-	var sb strings.Builder
-	sb.WriteRune(randomChar()) // 第一部分：登记管理部门代码
-	sb.WriteRune(randomChar()) // 第二部分：机构类别代码
-	for i := 0; i < 6; i++ {
-		sb.WriteRune(rune(rand.Intn(10) + '0')) // 第三部分：登记管理机关行政区划码
+	// 第一部分：登记管理部门代码；第二部分：机构类别代码
+	authority := registrationAuthorityCodes[rand.Intn(len(registrationAuthorityCodes))]
+	types := make([]byte, 0, 6)
+	for code := range registrationAuthorities[authority].types {
+		types = append(types, code)
 	}
-	for i := 0; i < 9; i++ {
-		sb.WriteRune(randomChar()) // 第四部分：主体标识码
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	orgType := types[rand.Intn(len(types))]
+
+	// 第三部分：登记管理机关行政区划码
+	region := sampleRegionCodes[rand.Intn(len(sampleRegionCodes))]
+
+	// 第四部分：主体标识码的本体代码，校验位与第五部分校验码由 GenerateCreditCode 计算
+	orgCode := make([]byte, 8)
+	for i := range orgCode {
+		orgCode[i] = randomChar()
 	}
-	sb.WriteRune(randomChar()) // 第五部分：校验码
 
-	return sb.String()
+	code, _ := GenerateCreditCode(authority, orgType, region, string(orgCode))
+	return code
+}
+
+// isValidRegionCode 校验行政区划码：6 位数字且省级代码有效
+func isValidRegionCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+	return provinceCodes[code[:2]]
 }
 
 func init() {
@@ -0,0 +1,154 @@
+package hashutil
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrChecksumMismatch 校验和不匹配。
+	// ErrChecksumMismatch is returned when the content does not match the expected checksum.
+	ErrChecksumMismatch = errors.New("hashutil: checksum mismatch")
+	// ErrInvalidChecksum 校验和格式错误。
+	// ErrInvalidChecksum is returned for checksums that cannot be decoded.
+	ErrInvalidChecksum = errors.New("hashutil: invalid checksum")
+)
+
+// ParseChecksum 解析带算法前缀的校验和，支持 "sha256:<hex>"、"sha256=<hex>" 和 SRI 形式 "sha256-<base64>"。
+// ParseChecksum parses a checksum with an algorithm prefix: "sha256:<hex>", "sha256=<hex>"
+// or the Subresource Integrity form "sha256-<base64>".
+// 参数 (param): checksum string - 校验和。 the checksum.
+// 返回值 (return): Algorithm - 算法。 the algorithm.
+// 返回值 (return): Sum - 期望的摘要值。 the expected digest.
+// 返回值 (return): error - 格式错误。 a format error.
+func ParseChecksum(checksum string) (Algorithm, Sum, error) {
+	checksum = strings.TrimSpace(checksum)
+	// SRI 的 Base64 值可能以 "=" 结尾，算法名称本身也可能含 "-"（如 "sha3-256"），
+	// 因此对每个分隔符从右向左尝试，取最长的合法算法名称作为前缀。
+	// SRI values may end with "=" padding and algorithm names may contain "-" (e.g. "sha3-256"),
+	// so try each separator from the right and use the longest prefix that names an algorithm.
+	for _, sep := range []byte{':', '=', '-'} {
+		for i := strings.LastIndexByte(checksum, sep); i > 0; i = strings.LastIndexByte(checksum[:i], sep) {
+			alg, err := ParseAlgorithm(checksum[:i])
+			if err != nil {
+				continue
+			}
+			sum, err := decodeChecksum(alg, checksum[i+1:])
+			if err != nil {
+				return 0, nil, err
+			}
+			return alg, sum, nil
+		}
+	}
+	return 0, nil, ErrInvalidChecksum
+}
+
+// VerifyChecksum 以流的方式校验 io.Reader 的内容，期望值可以是十六进制或 Base64。
+// VerifyChecksum checks the content of a reader against a hex or base64 digest, streaming the content.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): r io.Reader - 数据源。 the reader.
+// 参数 (param): expected string - 期望的摘要值。 the expected digest.
+// 返回值 (return): error - 不匹配时为 ErrChecksumMismatch。 ErrChecksumMismatch when the digests differ.
+func VerifyChecksum(alg Algorithm, r io.Reader, expected string) error {
+	want, err := decodeChecksum(alg, expected)
+	if err != nil {
+		return err
+	}
+	got, err := DigestReader(alg, r)
+	if err != nil {
+		return err
+	}
+	if !got.Equal(want) {
+		return fmt.Errorf("%w: %s is %s, expected %s", ErrChecksumMismatch, alg, got.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// VerifyFileChecksum 校验文件的摘要，期望值可以是十六进制或 Base64。
+// VerifyFileChecksum checks a file against a hex or base64 digest.
+// 参数 (param): path string - 文件路径。 the file path.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): expected string - 期望的摘要值。 the expected digest.
+// 返回值 (return): error - 不匹配时为 ErrChecksumMismatch。 ErrChecksumMismatch when the digests differ.
+func VerifyFileChecksum(path string, alg Algorithm, expected string) error {
+	want, err := decodeChecksum(alg, expected)
+	if err != nil {
+		return err
+	}
+	got, err := DigestFile(alg, path)
+	if err != nil {
+		return err
+	}
+	if !got.Equal(want) {
+		return fmt.Errorf("%w: %s of %s is %s, expected %s", ErrChecksumMismatch, alg, path, got.Hex(), want.Hex())
+	}
+	return nil
+}
+
+// VerifyFile 使用带算法前缀的校验和校验文件，格式见 ParseChecksum。
+// VerifyFile checks a file against a prefixed checksum such as "sha256:<hex>", see ParseChecksum.
+// 参数 (param): path string - 文件路径。 the file path.
+// 参数 (param): checksum string - 校验和。 the checksum.
+// 返回值 (return): error - 不匹配时为 ErrChecksumMismatch。 ErrChecksumMismatch when the digests differ.
+func VerifyFile(path, checksum string) error {
+	alg, want, err := ParseChecksum(checksum)
+	if err != nil {
+		return err
+	}
+	return VerifyFileChecksum(path, alg, want.Hex())
+}
+
+// ParseChecksumList 解析 sha256sum 等工具输出的校验和列表，支持 GNU 格式 "<hex>  name"、
+// "<hex> *name" 和 BSD 格式 "SHA256 (name) = <hex>"，空行和 # 注释会被忽略。
+// ParseChecksumList parses a checksum list such as a SHA256SUMS file, in GNU format
+// ("<hex>  name" or "<hex> *name") or BSD format ("SHA256 (name) = <hex>"). Blank lines
+// and # comments are skipped.
+// 参数 (param): r io.Reader - 校验和列表。 the list.
+// 返回值 (return): map[string]string - 文件名到十六进制摘要的映射。 file names to hex digests.
+// 返回值 (return): error - 格式错误。 a format error.
+func ParseChecksumList(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var name, sum string
+		if open := strings.Index(text, " ("); open > 0 && strings.Contains(text, ") = ") {
+			closeIdx := strings.LastIndex(text, ") = ")
+			name, sum = text[open+2:closeIdx], text[closeIdx+4:]
+		} else if fields := strings.SplitN(text, " ", 2); len(fields) == 2 {
+			sum, name = fields[0], strings.TrimPrefix(strings.TrimLeft(fields[1], " "), "*")
+		}
+		if _, err := hex.DecodeString(sum); err != nil || sum == "" || name == "" {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidChecksum, line)
+		}
+		sums[name] = strings.ToLower(sum)
+	}
+	return sums, scanner.Err()
+}
+
+// decodeChecksum 解码十六进制或 Base64 形式的摘要，并检查长度。
+// decodeChecksum decodes a hex or base64 digest and checks its length.
+func decodeChecksum(alg Algorithm, s string) (Sum, error) {
+	if !alg.Available() {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	s = strings.TrimSpace(s)
+	size := alg.Size()
+	if b, err := hex.DecodeString(s); err == nil && len(b) == size {
+		return b, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil && len(b) == size {
+			return b, nil
+		}
+	}
+	return nil, ErrInvalidChecksum
+}
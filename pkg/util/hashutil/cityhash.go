@@ -0,0 +1,175 @@
+package hashutil
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// CityHash 使用的常量。
+// Constants of CityHash.
+const (
+	cityK0 = 0xc3a5c85c97cb3127
+	cityK1 = 0xb492b66fbe98f273
+	cityK2 = 0x9ae16a3b2f90404f
+
+	cityKMul = 0x9ddfea08eb382d69
+)
+
+// CityHash64 CityHash64 算法（CityHash 1.1），不分配内存。
+// CityHash64 computes the 64-bit CityHash (version 1.1) of data without allocating.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 返回值 (return): uint64 - 哈希值。 the hash value.
+func CityHash64(data []byte) uint64 {
+	n := len(data)
+	switch {
+	case n <= 16:
+		return cityHash0to16(data)
+	case n <= 32:
+		return cityHash17to32(data)
+	case n <= 64:
+		return cityHash33to64(data)
+	}
+
+	// 超过 64 字节时先处理尾部，然后以 64 字节为单位循环，状态保存在 v、w、x、y、z 中。
+	// For inputs over 64 bytes hash the end first, then loop over 64-byte chunks
+	// keeping 56 bytes of state in v, w, x, y and z.
+	x := le64(data, n-40)
+	y := le64(data, n-16) + le64(data, n-56)
+	z := cityHash16(le64(data, n-48)+uint64(n), le64(data, n-24))
+	v1, v2 := cityWeakHash32(data[n-64:], uint64(n), z)
+	w1, w2 := cityWeakHash32(data[n-32:], y+cityK1, x)
+	x = x*cityK1 + le64(data, 0)
+
+	for rest := data[:(n-1)&^63]; len(rest) > 0; rest = rest[64:] {
+		x = bits.RotateLeft64(x+y+v1+le64(rest, 8), -37) * cityK1
+		y = bits.RotateLeft64(y+v2+le64(rest, 48), -42) * cityK1
+		x ^= w2
+		y += v1 + le64(rest, 40)
+		z = bits.RotateLeft64(z+w1, -33) * cityK1
+		v1, v2 = cityWeakHash32(rest, v2*cityK1, x+w1)
+		w1, w2 = cityWeakHash32(rest[32:], z+w2, y+le64(rest, 16))
+		z, x = x, z
+	}
+	return cityHash16(cityHash16(v1, w1)+cityShiftMix(y)*cityK1+z, cityHash16(v2, w2)+x)
+}
+
+// CityHash64WithSeed 带种子的 CityHash64。
+// CityHash64WithSeed computes the seeded 64-bit CityHash.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 参数 (param): seed uint64 - 种子。 the seed.
+// 返回值 (return): uint64 - 哈希值。 the hash value.
+func CityHash64WithSeed(data []byte, seed uint64) uint64 {
+	return CityHash64WithSeeds(data, cityK2, seed)
+}
+
+// CityHash64WithSeeds 带两个种子的 CityHash64。
+// CityHash64WithSeeds computes the 64-bit CityHash with two seeds.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 参数 (param): seed0, seed1 uint64 - 种子。 the seeds.
+// 返回值 (return): uint64 - 哈希值。 the hash value.
+func CityHash64WithSeeds(data []byte, seed0, seed1 uint64) uint64 {
+	return cityHash16(CityHash64(data)-seed0, seed1)
+}
+
+// cityHash16 把 128 位值哈希为 64 位（Murmur 风格）。
+// cityHash16 hashes a 128-bit value to 64 bits, Murmur-style.
+func cityHash16(u, v uint64) uint64 {
+	return cityHash16Mul(u, v, cityKMul)
+}
+
+// cityHash16Mul 使用指定乘数的 cityHash16。
+// cityHash16Mul is cityHash16 with a custom multiplier.
+func cityHash16Mul(u, v, mul uint64) uint64 {
+	a := (u ^ v) * mul
+	a ^= a >> 47
+	b := (v ^ a) * mul
+	b ^= b >> 47
+	return b * mul
+}
+
+// cityShiftMix CityHash 的移位混合函数。
+// cityShiftMix is the shift mix of CityHash.
+func cityShiftMix(v uint64) uint64 {
+	return v ^ (v >> 47)
+}
+
+// cityHash0to16 处理 0 到 16 字节的输入。
+// cityHash0to16 hashes inputs of 0 to 16 bytes.
+func cityHash0to16(s []byte) uint64 {
+	n := len(s)
+	switch {
+	case n >= 8:
+		mul := cityK2 + uint64(n)*2
+		a := le64(s, 0) + cityK2
+		b := le64(s, n-8)
+		c := bits.RotateLeft64(b, -37)*mul + a
+		d := (bits.RotateLeft64(a, -25) + b) * mul
+		return cityHash16Mul(c, d, mul)
+	case n >= 4:
+		mul := cityK2 + uint64(n)*2
+		a := uint64(le32(s, 0))
+		return cityHash16Mul(uint64(n)+a<<3, uint64(le32(s, n-4)), mul)
+	case n > 0:
+		y := uint32(s[0]) + uint32(s[n>>1])<<8
+		z := uint32(n) + uint32(s[n-1])<<2
+		return cityShiftMix(uint64(y)*cityK2^uint64(z)*cityK0) * cityK2
+	default:
+		return cityK2
+	}
+}
+
+// cityHash17to32 处理 17 到 32 字节的输入。
+// cityHash17to32 hashes inputs of 17 to 32 bytes.
+func cityHash17to32(s []byte) uint64 {
+	n := len(s)
+	mul := cityK2 + uint64(n)*2
+	a := le64(s, 0) * cityK1
+	b := le64(s, 8)
+	c := le64(s, n-8) * mul
+	d := le64(s, n-16) * cityK2
+	return cityHash16Mul(
+		bits.RotateLeft64(a+b, -43)+bits.RotateLeft64(c, -30)+d,
+		a+bits.RotateLeft64(b+cityK2, -18)+c,
+		mul,
+	)
+}
+
+// cityHash33to64 处理 33 到 64 字节的输入。
+// cityHash33to64 hashes inputs of 33 to 64 bytes.
+func cityHash33to64(s []byte) uint64 {
+	n := len(s)
+	mul := cityK2 + uint64(n)*2
+	a := le64(s, 0) * cityK2
+	b := le64(s, 8)
+	c := le64(s, n-24)
+	d := le64(s, n-32)
+	e := le64(s, 16) * cityK2
+	f := le64(s, 24) * 9
+	g := le64(s, n-8)
+	h := le64(s, n-16) * mul
+	u := bits.RotateLeft64(a+g, -43) + (bits.RotateLeft64(b, -30)+c)*9
+	v := ((a + g) ^ d) + f + 1
+	w := bits.ReverseBytes64((u+v)*mul) + h
+	x := bits.RotateLeft64(e+f, -42) + c
+	y := (bits.ReverseBytes64((v+w)*mul) + g) * mul
+	z := e + f + c
+	a = bits.ReverseBytes64((x+z)*mul+y) + b
+	b = cityShiftMix((z+a)*mul+d+h) * mul
+	return b + x
+}
+
+// cityWeakHash32 对 32 字节输入及两个种子计算 128 位的弱哈希。
+// cityWeakHash32 returns a quick 128-bit hash of 32 bytes and two seeds.
+func cityWeakHash32(s []byte, a, b uint64) (uint64, uint64) {
+	w := binary.LittleEndian.Uint64(s)
+	x := binary.LittleEndian.Uint64(s[8:])
+	y := binary.LittleEndian.Uint64(s[16:])
+	z := binary.LittleEndian.Uint64(s[24:])
+	a += w
+	b = bits.RotateLeft64(b+a+z, -21)
+	c := a
+	a += x
+	a += y
+	b += bits.RotateLeft64(a, -44)
+	return a + z, b + c
+}
@@ -0,0 +1,331 @@
+package hashutil
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// Algorithm 摘要算法。
+// Algorithm identifies a cryptographic digest algorithm.
+type Algorithm int
+
+const (
+	// MD5 仅用于兼容旧系统的校验和，不要用于安全场景。
+	// MD5 is only suitable for legacy checksums, never for security.
+	MD5 Algorithm = iota + 1
+	// SHA1 仅用于兼容旧系统，不要用于新的签名。
+	// SHA1 is only suitable for legacy systems, never for new signatures.
+	SHA1
+	SHA224
+	SHA256
+	SHA384
+	SHA512
+	SHA3_224
+	SHA3_256
+	SHA3_384
+	SHA3_512
+	BLAKE2b256
+	BLAKE2b384
+	BLAKE2b512
+)
+
+// algorithmNames 算法名称，与 sha256sum 等工具及 SRI 的写法一致。
+// algorithmNames are the canonical lower-case names, as used by sha256sum and SRI.
+var algorithmNames = map[Algorithm]string{
+	MD5:        "md5",
+	SHA1:       "sha1",
+	SHA224:     "sha224",
+	SHA256:     "sha256",
+	SHA384:     "sha384",
+	SHA512:     "sha512",
+	SHA3_224:   "sha3-224",
+	SHA3_256:   "sha3-256",
+	SHA3_384:   "sha3-384",
+	SHA3_512:   "sha3-512",
+	BLAKE2b256: "blake2b-256",
+	BLAKE2b384: "blake2b-384",
+	BLAKE2b512: "blake2b-512",
+}
+
+// ErrUnsupportedAlgorithm 不支持的摘要算法。
+// ErrUnsupportedAlgorithm is returned for unknown algorithm names or values.
+var ErrUnsupportedAlgorithm = errors.New("hashutil: unsupported digest algorithm")
+
+// String 返回算法名称。
+// String returns the lower-case algorithm name, e.g. "sha256".
+func (a Algorithm) String() string {
+	if name, ok := algorithmNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("Algorithm(%d)", int(a))
+}
+
+// Available 判断算法是否受支持。
+// Available reports whether the algorithm is supported.
+func (a Algorithm) Available() bool {
+	_, ok := algorithmNames[a]
+	return ok
+}
+
+// New 创建算法的 hash.Hash，不支持的算法会 panic。
+// New returns a new hash.Hash for the algorithm; it panics for unsupported values.
+// 返回值 (return): hash.Hash - 新的摘要计算器。
+// 返回值 (return): hash.Hash - a new digest.
+func (a Algorithm) New() hash.Hash {
+	switch a {
+	case MD5:
+		return md5.New()
+	case SHA1:
+		return sha1.New()
+	case SHA224:
+		return sha256.New224()
+	case SHA256:
+		return sha256.New()
+	case SHA384:
+		return sha512.New384()
+	case SHA512:
+		return sha512.New()
+	case SHA3_224:
+		return sha3.New224()
+	case SHA3_256:
+		return sha3.New256()
+	case SHA3_384:
+		return sha3.New384()
+	case SHA3_512:
+		return sha3.New512()
+	case BLAKE2b256, BLAKE2b384, BLAKE2b512:
+		h, _ := blake2b.New(a.Size(), nil)
+		return h
+	default:
+		panic(ErrUnsupportedAlgorithm.Error() + ": " + a.String())
+	}
+}
+
+// Size 返回摘要的字节长度。
+// Size returns the digest length in bytes.
+func (a Algorithm) Size() int {
+	switch a {
+	case MD5:
+		return md5.Size
+	case SHA1:
+		return sha1.Size
+	case SHA224, SHA3_224:
+		return 28
+	case SHA256, SHA3_256, BLAKE2b256:
+		return 32
+	case SHA384, SHA3_384, BLAKE2b384:
+		return 48
+	case SHA512, SHA3_512, BLAKE2b512:
+		return 64
+	default:
+		return 0
+	}
+}
+
+// ParseAlgorithm 按名称解析算法，忽略大小写、"-" 和 "_"，例如 "SHA-256"、"sha3_256"、"blake2b"。
+// ParseAlgorithm parses an algorithm name, ignoring case, "-" and "_", e.g. "SHA-256", "sha3_256" or "blake2b".
+// 参数 (param): name string - 算法名称。 the algorithm name.
+// 返回值 (return): Algorithm - 算法。 the algorithm.
+// 返回值 (return): error - 不支持时为 ErrUnsupportedAlgorithm。 ErrUnsupportedAlgorithm for unknown names.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	normalized := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(name))
+	if normalized == "blake2b" {
+		return BLAKE2b512, nil
+	}
+	for alg, canonical := range algorithmNames {
+		if strings.ReplaceAll(canonical, "-", "") == normalized {
+			return alg, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, name)
+}
+
+// Sum 摘要值，可输出为十六进制或 Base64。
+// Sum is a digest value that can be rendered as hex or base64.
+type Sum []byte
+
+// Hex 返回小写十六进制字符串。
+// Hex returns the lower-case hex encoding.
+func (s Sum) Hex() string {
+	return hex.EncodeToString(s)
+}
+
+// Base64 返回标准 Base64 字符串。
+// Base64 returns the standard base64 encoding.
+func (s Sum) Base64() string {
+	return base64.StdEncoding.EncodeToString(s)
+}
+
+// Base64URL 返回无填充的 URL 安全 Base64 字符串。
+// Base64URL returns the unpadded URL-safe base64 encoding.
+func (s Sum) Base64URL() string {
+	return base64.RawURLEncoding.EncodeToString(s)
+}
+
+// String 返回十六进制字符串。
+// String returns the hex encoding.
+func (s Sum) String() string {
+	return s.Hex()
+}
+
+// Equal 以常量时间比较两个摘要。
+// Equal compares two digests in constant time.
+func (s Sum) Equal(other []byte) bool {
+	return hmac.Equal(s, other)
+}
+
+// Digest 计算字节切片的摘要。
+// Digest computes the digest of a byte slice.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): data []byte - 数据。 the data.
+// 返回值 (return): Sum - 摘要值。 the digest.
+func Digest(alg Algorithm, data []byte) Sum {
+	h := alg.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// DigestString 计算字符串的摘要。
+// DigestString computes the digest of a string.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): input string - 字符串。 the string.
+// 返回值 (return): Sum - 摘要值。 the digest.
+func DigestString(alg Algorithm, input string) Sum {
+	h := alg.New()
+	io.WriteString(h, input)
+	return h.Sum(nil)
+}
+
+// DigestReader 以流的方式计算 io.Reader 的摘要，不会把内容全部读入内存。
+// DigestReader computes the digest of a reader, streaming its content.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): r io.Reader - 数据源。 the reader.
+// 返回值 (return): Sum - 摘要值。 the digest.
+// 返回值 (return): error - 读取错误。 a read error.
+func DigestReader(alg Algorithm, r io.Reader) (Sum, error) {
+	h := alg.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// DigestFile 以流的方式计算文件的摘要。
+// DigestFile computes the digest of a file, streaming its content.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): path string - 文件路径。 the file path.
+// 返回值 (return): Sum - 摘要值。 the digest.
+// 返回值 (return): error - 打开或读取错误。 an open or read error.
+func DigestFile(alg Algorithm, path string) (Sum, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return DigestReader(alg, f)
+}
+
+// HMAC 计算字节切片的 HMAC。
+// HMAC computes the HMAC of a byte slice.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): key []byte - 密钥。 the key.
+// 参数 (param): data []byte - 数据。 the data.
+// 返回值 (return): Sum - HMAC 值。 the MAC.
+func HMAC(alg Algorithm, key, data []byte) Sum {
+	mac := hmac.New(alg.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// HMACString 计算字符串的 HMAC。
+// HMACString computes the HMAC of a string.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): key string - 密钥。 the key.
+// 参数 (param): input string - 字符串。 the string.
+// 返回值 (return): Sum - HMAC 值。 the MAC.
+func HMACString(alg Algorithm, key, input string) Sum {
+	mac := hmac.New(alg.New, []byte(key))
+	io.WriteString(mac, input)
+	return mac.Sum(nil)
+}
+
+// HMACReader 以流的方式计算 io.Reader 的 HMAC。
+// HMACReader computes the HMAC of a reader, streaming its content.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): key []byte - 密钥。 the key.
+// 参数 (param): r io.Reader - 数据源。 the reader.
+// 返回值 (return): Sum - HMAC 值。 the MAC.
+// 返回值 (return): error - 读取错误。 a read error.
+func HMACReader(alg Algorithm, key []byte, r io.Reader) (Sum, error) {
+	mac := hmac.New(alg.New, key)
+	if _, err := io.Copy(mac, r); err != nil {
+		return nil, err
+	}
+	return mac.Sum(nil), nil
+}
+
+// HMACFile 以流的方式计算文件的 HMAC。
+// HMACFile computes the HMAC of a file, streaming its content.
+// 参数 (param): alg Algorithm - 摘要算法。 the algorithm.
+// 参数 (param): key []byte - 密钥。 the key.
+// 参数 (param): path string - 文件路径。 the file path.
+// 返回值 (return): Sum - HMAC 值。 the MAC.
+// 返回值 (return): error - 打开或读取错误。 an open or read error.
+func HMACFile(alg Algorithm, key []byte, path string) (Sum, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return HMACReader(alg, key, f)
+}
+
+// MD5Hex 返回字符串 MD5 摘要的十六进制形式。
+// MD5Hex returns the hex MD5 digest of a string.
+func MD5Hex(input string) string {
+	return DigestString(MD5, input).Hex()
+}
+
+// SHA1Hex 返回字符串 SHA-1 摘要的十六进制形式。
+// SHA1Hex returns the hex SHA-1 digest of a string.
+func SHA1Hex(input string) string {
+	return DigestString(SHA1, input).Hex()
+}
+
+// SHA256Hex 返回字符串 SHA-256 摘要的十六进制形式。
+// SHA256Hex returns the hex SHA-256 digest of a string.
+func SHA256Hex(input string) string {
+	return DigestString(SHA256, input).Hex()
+}
+
+// SHA512Hex 返回字符串 SHA-512 摘要的十六进制形式。
+// SHA512Hex returns the hex SHA-512 digest of a string.
+func SHA512Hex(input string) string {
+	return DigestString(SHA512, input).Hex()
+}
+
+// SHA3_256Hex 返回字符串 SHA3-256 摘要的十六进制形式。
+// SHA3_256Hex returns the hex SHA3-256 digest of a string.
+func SHA3_256Hex(input string) string {
+	return DigestString(SHA3_256, input).Hex()
+}
+
+// HmacSHA256Hex 返回字符串 HMAC-SHA256 的十六进制形式。
+// HmacSHA256Hex returns the hex HMAC-SHA256 of a string.
+func HmacSHA256Hex(key, input string) string {
+	return HMACString(SHA256, key, input).Hex()
+}
@@ -0,0 +1,137 @@
+package hashutil
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	murmur32C1 = 0xcc9e2d51
+	murmur32C2 = 0x1b873593
+
+	murmur128C1 = 0x87c37b91114253d5
+	murmur128C2 = 0x4cf5ad432745937f
+)
+
+// Murmur3Hash32 MurmurHash3 x86 32 位算法，与 Guava、Cassandra 等实现的结果一致。
+// Murmur3Hash32 computes the 32-bit MurmurHash3 (x86_32) of data, compatible with the
+// reference implementation and libraries such as Guava. It does not allocate.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 参数 (param): seed uint32 - 种子。 the seed.
+// 返回值 (return): uint32 - 哈希值。 the hash value.
+func Murmur3Hash32(data []byte, seed uint32) uint32 {
+	h := seed
+	n := len(data) / 4 * 4
+	for i := 0; i < n; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= murmur32C1
+		k = bits.RotateLeft32(k, 15)
+		k *= murmur32C2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	switch tail := data[n:]; len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= murmur32C1
+		k = bits.RotateLeft32(k, 15)
+		k *= murmur32C2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	return fmix32(h)
+}
+
+// Murmur3Hash128 MurmurHash3 x64 128 位算法，返回两个 64 位的半值。
+// Murmur3Hash128 computes the 128-bit MurmurHash3 (x64_128) of data and returns it as
+// two 64-bit halves in the order of the reference implementation. It does not allocate.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 参数 (param): seed uint32 - 种子。 the seed.
+// 返回值 (return): uint64, uint64 - 哈希值的高、低两部分。 the two halves of the hash.
+func Murmur3Hash128(data []byte, seed uint32) (uint64, uint64) {
+	h1, h2 := uint64(seed), uint64(seed)
+	n := len(data) / 16 * 16
+	for i := 0; i < n; i += 16 {
+		k1 := binary.LittleEndian.Uint64(data[i:])
+		k2 := binary.LittleEndian.Uint64(data[i+8:])
+
+		k1 *= murmur128C1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= murmur128C2
+		h1 ^= k1
+		h1 = bits.RotateLeft64(h1, 27)
+		h1 += h2
+		h1 = h1*5 + 0x52dce729
+
+		k2 *= murmur128C2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= murmur128C1
+		h2 ^= k2
+		h2 = bits.RotateLeft64(h2, 31)
+		h2 += h1
+		h2 = h2*5 + 0x38495ab5
+	}
+
+	tail := data[n:]
+	var k1, k2 uint64
+	if len(tail) > 8 {
+		for i := len(tail) - 1; i >= 8; i-- {
+			k2 = k2<<8 | uint64(tail[i])
+		}
+		k2 *= murmur128C2
+		k2 = bits.RotateLeft64(k2, 33)
+		k2 *= murmur128C1
+		h2 ^= k2
+	}
+	if len(tail) > 0 {
+		for i := min(len(tail), 8) - 1; i >= 0; i-- {
+			k1 = k1<<8 | uint64(tail[i])
+		}
+		k1 *= murmur128C1
+		k1 = bits.RotateLeft64(k1, 31)
+		k1 *= murmur128C2
+		h1 ^= k1
+	}
+
+	h1 ^= uint64(len(data))
+	h2 ^= uint64(len(data))
+	h1 += h2
+	h2 += h1
+	h1 = fmix64(h1)
+	h2 = fmix64(h2)
+	h1 += h2
+	h2 += h1
+	return h1, h2
+}
+
+// fmix32 MurmurHash3 的 32 位终结混合函数。
+// fmix32 is the 32-bit finalization mix of MurmurHash3.
+func fmix32(h uint32) uint32 {
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// fmix64 MurmurHash3 的 64 位终结混合函数。
+// fmix64 is the 64-bit finalization mix of MurmurHash3.
+func fmix64(k uint64) uint64 {
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return k
+}
@@ -0,0 +1,206 @@
+package hashutil
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// JumpHash Google 的跳跃一致性哈希（Lamping & Veach），把键映射到 [0, buckets) 中的桶。
+// 桶数从 n 增加到 n+1 时只有 1/(n+1) 的键会移动，但只能在末尾增删桶。
+// JumpHash is Google's jump consistent hash (Lamping & Veach). It maps a key to a
+// bucket in [0, buckets); growing from n to n+1 buckets moves only 1/(n+1) of the
+// keys, but buckets can only be added or removed at the end.
+// 参数 (param): key uint64 - 键的哈希值。 the hashed key.
+// 参数 (param): buckets int - 桶数。 the number of buckets.
+// 返回值 (return): int - 桶编号，buckets <= 0 时为 -1。 the bucket, or -1 if buckets <= 0.
+func JumpHash(key uint64, buckets int) int {
+	if buckets <= 0 {
+		return -1
+	}
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// JumpHashString 使用 XXH3Hash64 对字符串键进行跳跃一致性哈希。
+// JumpHashString applies JumpHash to a string key hashed with XXH3Hash64.
+// 参数 (param): key string - 键。 the key.
+// 参数 (param): buckets int - 桶数。 the number of buckets.
+// 返回值 (return): int - 桶编号。 the bucket.
+func JumpHashString(key string, buckets int) int {
+	return JumpHash(XXH3Hash64([]byte(key)), buckets)
+}
+
+// Rendezvous 最高随机权重（HRW）哈希：每个键选择得分最高的节点。
+// 与哈希环相比不需要虚拟节点，任意节点都可以增删，但查找为 O(节点数)。读操作无锁。
+// Rendezvous implements highest random weight (HRW) hashing: each key goes to the
+// node with the highest score. Unlike a ring it needs no virtual nodes and any node
+// can be removed, at the cost of O(nodes) lookups. Lookups are lock-free.
+type Rendezvous struct {
+	hash HashFunc
+
+	mu    sync.Mutex // 串行化写操作 serializes writers
+	nodes atomic.Pointer[[]rendezvousNode]
+}
+
+// rendezvousNode 节点及其权重和哈希种子。
+// rendezvousNode is a node with its weight and hash seed.
+type rendezvousNode struct {
+	name   string
+	weight float64
+	seed   uint64
+}
+
+// NewRendezvous 创建 HRW 哈希。
+// NewRendezvous creates an HRW hash.
+// 参数 (param): hash HashFunc - 哈希函数，为 nil 时使用 XXH3Hash64。 the hash function; XXH3Hash64 if nil.
+// 返回值 (return): *Rendezvous - 新的 HRW 哈希。 the new HRW hash.
+func NewRendezvous(hash HashFunc) *Rendezvous {
+	if hash == nil {
+		hash = XXH3Hash64
+	}
+	r := &Rendezvous{hash: hash}
+	r.nodes.Store(&[]rendezvousNode{})
+	return r
+}
+
+// Add 以权重 1 添加节点。
+// Add adds nodes with weight 1.
+// 参数 (param): nodes ...string - 节点名称。 the node names.
+func (r *Rendezvous) Add(nodes ...string) {
+	for _, node := range nodes {
+		r.AddWeighted(node, 1)
+	}
+}
+
+// AddWeighted 添加节点或修改其权重，键按权重比例分配；权重 <= 0 时移除节点。
+// AddWeighted adds a node or changes its weight; keys are shared in proportion to
+// the weights. A weight <= 0 removes the node.
+// 参数 (param): node string - 节点名称。 the node name.
+// 参数 (param): weight float64 - 权重。 the weight.
+func (r *Rendezvous) AddWeighted(node string, weight float64) {
+	r.update(func(nodes []rendezvousNode) []rendezvousNode {
+		nodes = removeRendezvousNode(nodes, node)
+		if weight > 0 {
+			nodes = append(nodes, rendezvousNode{name: node, weight: weight, seed: r.hash([]byte(node))})
+		}
+		return nodes
+	})
+}
+
+// Remove 移除节点，只有映射到这些节点的键会被重新分配。
+// Remove removes nodes; only the keys that mapped to them move.
+// 参数 (param): nodes ...string - 节点名称。 the node names.
+func (r *Rendezvous) Remove(nodes ...string) {
+	r.update(func(list []rendezvousNode) []rendezvousNode {
+		for _, node := range nodes {
+			list = removeRendezvousNode(list, node)
+		}
+		return list
+	})
+}
+
+// Get 返回键所在的节点。
+// Get returns the node a key maps to.
+// 参数 (param): key string - 键。 the key.
+// 返回值 (return): string - 节点名称。 the node.
+// 返回值 (return): bool - 没有节点时为 false。 false if there are no nodes.
+func (r *Rendezvous) Get(key string) (string, bool) {
+	nodes := *r.nodes.Load()
+	if len(nodes) == 0 {
+		return "", false
+	}
+	h := r.hash([]byte(key))
+	best, bestScore := 0, math.Inf(-1)
+	for i := range nodes {
+		if score := nodes[i].score(h); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return nodes[best].name, true
+}
+
+// GetN 返回得分最高的 n 个节点，按得分从高到低排列，用于副本放置。
+// GetN returns the n highest-scoring nodes in descending order, for replica placement.
+// 参数 (param): key string - 键。 the key.
+// 参数 (param): n int - 节点数。 the number of nodes.
+// 返回值 (return): []string - 节点列表。 the nodes.
+func (r *Rendezvous) GetN(key string, n int) []string {
+	nodes := *r.nodes.Load()
+	if n > len(nodes) {
+		n = len(nodes)
+	}
+	if n <= 0 {
+		return nil
+	}
+	h := r.hash([]byte(key))
+	type scored struct {
+		name  string
+		score float64
+	}
+	all := make([]scored, len(nodes))
+	for i := range nodes {
+		all[i] = scored{nodes[i].name, nodes[i].score(h)}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].score > all[j].score })
+	result := make([]string, n)
+	for i := range result {
+		result[i] = all[i].name
+	}
+	return result
+}
+
+// Nodes 返回按名称排序的节点列表。
+// Nodes returns the node names in sorted order.
+func (r *Rendezvous) Nodes() []string {
+	nodes := *r.nodes.Load()
+	names := make([]string, len(nodes))
+	for i := range nodes {
+		names[i] = nodes[i].name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len 返回节点数。
+// Len returns the number of nodes.
+func (r *Rendezvous) Len() int {
+	return len(*r.nodes.Load())
+}
+
+// update 在写锁内复制节点列表、修改并发布。
+// update copies the node list under the write lock, applies fn and publishes the result.
+func (r *Rendezvous) update(fn func([]rendezvousNode) []rendezvousNode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := *r.nodes.Load()
+	nodes := fn(append([]rendezvousNode(nil), old...))
+	r.nodes.Store(&nodes)
+}
+
+// score 计算节点对键的得分。采用对数加权法：score = -weight / ln(u)，
+// 其中 u 是由键和节点派生的 (0, 1) 内的均匀随机数。
+// score computes the node's score for a key with the logarithmic method,
+// score = -weight / ln(u), where u is uniform in (0, 1) and derived from the key and node.
+func (n *rendezvousNode) score(keyHash uint64) float64 {
+	h := fmix64(keyHash ^ n.seed)
+	u := (float64(h>>11) + 0.5) / (1 << 53)
+	return -n.weight / math.Log(u)
+}
+
+// removeRendezvousNode 从列表中删除节点。
+// removeRendezvousNode removes a node from the list.
+func removeRendezvousNode(nodes []rendezvousNode, name string) []rendezvousNode {
+	for i := range nodes {
+		if nodes[i].name == name {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,213 @@
+package hashutil
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// HashFunc 64 位哈希函数，例如 XXH3Hash64 或 CityHash64。
+// HashFunc is a 64-bit hash function such as XXH3Hash64 or CityHash64.
+type HashFunc func(data []byte) uint64
+
+// DefaultReplicas 每单位权重的默认虚拟节点数。
+// DefaultReplicas is the default number of virtual nodes per unit of weight.
+const DefaultReplicas = 160
+
+// Ring 带虚拟节点的一致性哈希环。读操作无锁，可与成员变更并发进行：
+// 每次变更都会生成新的快照并原子替换。
+// Ring is a consistent-hash ring with weighted virtual nodes. Lookups are lock-free
+// and safe during membership changes: every change builds a new snapshot that is
+// swapped in atomically.
+type Ring struct {
+	hash     HashFunc
+	replicas int
+
+	mu    sync.Mutex // 串行化写操作 serializes writers
+	state atomic.Pointer[ringState]
+}
+
+// ringState 哈希环的不可变快照。
+// ringState is an immutable snapshot of the ring.
+type ringState struct {
+	points  []ringPoint
+	weights map[string]int
+}
+
+// ringPoint 环上的一个虚拟节点。
+// ringPoint is a virtual node on the ring.
+type ringPoint struct {
+	hash uint64
+	node string
+}
+
+// NewRing 创建一致性哈希环。
+// NewRing creates a consistent-hash ring.
+// 参数 (param): replicas int - 每单位权重的虚拟节点数，<= 0 时使用 DefaultReplicas。
+// 参数 (param): replicas int - virtual nodes per unit of weight; DefaultReplicas if <= 0.
+// 参数 (param): hash HashFunc - 哈希函数，为 nil 时使用 XXH3Hash64。 the hash function; XXH3Hash64 if nil.
+// 返回值 (return): *Ring - 新的哈希环。 the new ring.
+func NewRing(replicas int, hash HashFunc) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	if hash == nil {
+		hash = XXH3Hash64
+	}
+	r := &Ring{hash: hash, replicas: replicas}
+	r.state.Store(&ringState{weights: map[string]int{}})
+	return r
+}
+
+// Add 以权重 1 添加节点，已存在的节点会被忽略。
+// Add adds nodes with weight 1; nodes already on the ring are left unchanged.
+// 参数 (param): nodes ...string - 节点名称。 the node names.
+func (r *Ring) Add(nodes ...string) {
+	r.update(func(weights map[string]int) {
+		for _, node := range nodes {
+			if _, ok := weights[node]; !ok {
+				weights[node] = 1
+			}
+		}
+	})
+}
+
+// AddWeighted 添加节点或修改其权重，虚拟节点数与权重成正比；权重 <= 0 时移除节点。
+// AddWeighted adds a node or changes its weight. The number of virtual nodes is
+// proportional to the weight; a weight <= 0 removes the node.
+// 参数 (param): node string - 节点名称。 the node name.
+// 参数 (param): weight int - 权重。 the weight.
+func (r *Ring) AddWeighted(node string, weight int) {
+	r.update(func(weights map[string]int) {
+		if weight <= 0 {
+			delete(weights, node)
+		} else {
+			weights[node] = weight
+		}
+	})
+}
+
+// Remove 移除节点，只有映射到这些节点的键会被重新分配。
+// Remove removes nodes; only the keys that mapped to them move.
+// 参数 (param): nodes ...string - 节点名称。 the node names.
+func (r *Ring) Remove(nodes ...string) {
+	r.update(func(weights map[string]int) {
+		for _, node := range nodes {
+			delete(weights, node)
+		}
+	})
+}
+
+// Get 返回键所在的节点。
+// Get returns the node a key maps to.
+// 参数 (param): key string - 键。 the key.
+// 返回值 (return): string - 节点名称。 the node.
+// 返回值 (return): bool - 环为空时为 false。 false if the ring is empty.
+func (r *Ring) Get(key string) (string, bool) {
+	s := r.state.Load()
+	if len(s.points) == 0 {
+		return "", false
+	}
+	return s.points[s.search(r.hash([]byte(key)))].node, true
+}
+
+// GetN 沿顺时针方向返回最多 n 个不同的节点，用于副本放置。第一个节点与 Get 的结果相同。
+// GetN returns up to n distinct nodes walking clockwise from the key, for replica
+// placement. The first node is the one returned by Get.
+// 参数 (param): key string - 键。 the key.
+// 参数 (param): n int - 节点数。 the number of nodes.
+// 返回值 (return): []string - 节点列表，节点不足时长度小于 n。 the nodes; fewer than n if the ring is smaller.
+func (r *Ring) GetN(key string, n int) []string {
+	s := r.state.Load()
+	if n > len(s.weights) {
+		n = len(s.weights)
+	}
+	if n <= 0 {
+		return nil
+	}
+	result := make([]string, 0, n)
+	start := s.search(r.hash([]byte(key)))
+	for i := 0; i < len(s.points) && len(result) < n; i++ {
+		node := s.points[(start+i)%len(s.points)].node
+		if !containsString(result, node) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// Nodes 返回按名称排序的节点列表。
+// Nodes returns the node names in sorted order.
+func (r *Ring) Nodes() []string {
+	s := r.state.Load()
+	nodes := make([]string, 0, len(s.weights))
+	for node := range s.weights {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Len 返回节点数。
+// Len returns the number of nodes.
+func (r *Ring) Len() int {
+	return len(r.state.Load().weights)
+}
+
+// update 在写锁内复制权重表、修改并重建快照。
+// update copies the weights under the write lock, applies fn and publishes a rebuilt snapshot.
+func (r *Ring) update(fn func(weights map[string]int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.state.Load()
+	weights := make(map[string]int, len(old.weights)+1)
+	for node, w := range old.weights {
+		weights[node] = w
+	}
+	fn(weights)
+
+	total := 0
+	for _, w := range weights {
+		total += w * r.replicas
+	}
+	points := make([]ringPoint, 0, total)
+	buf := make([]byte, 0, 64)
+	for node, w := range weights {
+		for i := 0; i < w*r.replicas; i++ {
+			buf = strconv.AppendInt(append(append(buf[:0], node...), '#'), int64(i), 10)
+			points = append(points, ringPoint{hash: r.hash(buf), node: node})
+		}
+	}
+	// 哈希相同时按节点名排序，保证结果与插入顺序无关。
+	// Ties are broken by node name so the layout does not depend on insertion order.
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].hash != points[j].hash {
+			return points[i].hash < points[j].hash
+		}
+		return points[i].node < points[j].node
+	})
+	r.state.Store(&ringState{points: points, weights: weights})
+}
+
+// search 返回第一个哈希值 >= h 的虚拟节点下标，超过末尾时回绕到 0。
+// search returns the index of the first virtual node with hash >= h, wrapping to 0.
+func (s *ringState) search(h uint64) int {
+	i := sort.Search(len(s.points), func(i int) bool { return s.points[i].hash >= h })
+	if i == len(s.points) {
+		return 0
+	}
+	return i
+}
+
+// containsString 判断切片是否包含字符串。
+// containsString reports whether the slice contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
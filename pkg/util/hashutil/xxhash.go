@@ -0,0 +1,306 @@
+package hashutil
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	xxPrime32_1 = 0x9e3779b1
+	xxPrime32_2 = 0x85ebca77
+	xxPrime32_3 = 0xc2b2ae3d
+
+	xxPrime64_1 = 0x9e3779b185ebca87
+	xxPrime64_2 = 0xc2b2ae3d27d4eb4f
+	xxPrime64_3 = 0x165667b19e3779f9
+	xxPrime64_4 = 0x85ebca77c2b2ae63
+	xxPrime64_5 = 0x27d4eb2f165667c5
+
+	xxPrimeMX1 = 0x165667919e3779f9
+	xxPrimeMX2 = 0x9fb21c651e98df25
+)
+
+// XXHash64 xxHash 64 位算法（XXH64），不分配内存。
+// XXHash64 computes the 64-bit xxHash (XXH64) of data without allocating.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 参数 (param): seed uint64 - 种子。 the seed.
+// 返回值 (return): uint64 - 哈希值。 the hash value.
+func XXHash64(data []byte, seed uint64) uint64 {
+	n := len(data)
+	var h uint64
+	if n >= 32 {
+		v1 := seed + xxPrime64_1 + xxPrime64_2
+		v2 := seed + xxPrime64_2
+		v3 := seed
+		v4 := seed - xxPrime64_1
+		for len(data) >= 32 {
+			v1 = xxh64Round(v1, binary.LittleEndian.Uint64(data))
+			v2 = xxh64Round(v2, binary.LittleEndian.Uint64(data[8:]))
+			v3 = xxh64Round(v3, binary.LittleEndian.Uint64(data[16:]))
+			v4 = xxh64Round(v4, binary.LittleEndian.Uint64(data[24:]))
+			data = data[32:]
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxh64MergeRound(h, v1)
+		h = xxh64MergeRound(h, v2)
+		h = xxh64MergeRound(h, v3)
+		h = xxh64MergeRound(h, v4)
+	} else {
+		h = seed + xxPrime64_5
+	}
+	h += uint64(n)
+
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= xxh64Round(0, binary.LittleEndian.Uint64(data))
+		h = bits.RotateLeft64(h, 27)*xxPrime64_1 + xxPrime64_4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(data)) * xxPrime64_1
+		h = bits.RotateLeft64(h, 23)*xxPrime64_2 + xxPrime64_3
+		data = data[4:]
+	}
+	for _, b := range data {
+		h ^= uint64(b) * xxPrime64_5
+		h = bits.RotateLeft64(h, 11) * xxPrime64_1
+	}
+	return xxh64Avalanche(h)
+}
+
+// xxh64Round XXH64 的单轮累加。
+// xxh64Round is one XXH64 accumulation round.
+func xxh64Round(acc, input uint64) uint64 {
+	acc += input * xxPrime64_2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime64_1
+}
+
+// xxh64MergeRound 把一个累加器合并到结果中。
+// xxh64MergeRound merges an accumulator into the hash.
+func xxh64MergeRound(h, v uint64) uint64 {
+	h ^= xxh64Round(0, v)
+	return h*xxPrime64_1 + xxPrime64_4
+}
+
+// xxh64Avalanche XXH64 的终结混合函数。
+// xxh64Avalanche is the XXH64 final mix.
+func xxh64Avalanche(h uint64) uint64 {
+	h ^= h >> 33
+	h *= xxPrime64_2
+	h ^= h >> 29
+	h *= xxPrime64_3
+	h ^= h >> 32
+	return h
+}
+
+// xxh3Secret XXH3 的默认密钥。
+// xxh3Secret is the default XXH3 secret.
+var xxh3Secret = [192]byte{
+	0xb8, 0xfe, 0x6c, 0x39, 0x23, 0xa4, 0x4b, 0xbe, 0x7c, 0x01, 0x81, 0x2c, 0xf7, 0x21, 0xad, 0x1c,
+	0xde, 0xd4, 0x6d, 0xe9, 0x83, 0x90, 0x97, 0xdb, 0x72, 0x40, 0xa4, 0xa4, 0xb7, 0xb3, 0x67, 0x1f,
+	0xcb, 0x79, 0xe6, 0x4e, 0xcc, 0xc0, 0xe5, 0x78, 0x82, 0x5a, 0xd0, 0x7d, 0xcc, 0xff, 0x72, 0x21,
+	0xb8, 0x08, 0x46, 0x74, 0xf7, 0x43, 0x24, 0x8e, 0xe0, 0x35, 0x90, 0xe6, 0x81, 0x3a, 0x26, 0x4c,
+	0x3c, 0x28, 0x52, 0xbb, 0x91, 0xc3, 0x00, 0xcb, 0x88, 0xd0, 0x65, 0x8b, 0x1b, 0x53, 0x2e, 0xa3,
+	0x71, 0x64, 0x48, 0x97, 0xa2, 0x0d, 0xf9, 0x4e, 0x38, 0x19, 0xef, 0x46, 0xa9, 0xde, 0xac, 0xd8,
+	0xa8, 0xfa, 0x76, 0x3f, 0xe3, 0x9c, 0x34, 0x3f, 0xf9, 0xdc, 0xbb, 0xc7, 0xc7, 0x0b, 0x4f, 0x1d,
+	0x8a, 0x51, 0xe0, 0x4b, 0xcd, 0xb4, 0x59, 0x31, 0xc8, 0x9f, 0x7e, 0xc9, 0xd9, 0x78, 0x73, 0x64,
+	0xea, 0xc5, 0xac, 0x83, 0x34, 0xd3, 0xeb, 0xc3, 0xc5, 0x81, 0xa0, 0xff, 0xfa, 0x13, 0x63, 0xeb,
+	0x17, 0x0d, 0xdd, 0x51, 0xb7, 0xf0, 0xda, 0x49, 0xd3, 0x16, 0x55, 0x26, 0x29, 0xd4, 0x68, 0x9e,
+	0x2b, 0x16, 0xbe, 0x58, 0x7d, 0x47, 0xa1, 0xfc, 0x8f, 0xf8, 0xb8, 0xd1, 0x7a, 0xd0, 0x31, 0xce,
+	0x45, 0xcb, 0x3a, 0x8f, 0x95, 0x16, 0x04, 0x28, 0xaf, 0xd7, 0xfb, 0xca, 0xbb, 0x4b, 0x40, 0x7e,
+}
+
+const (
+	xxh3StripeLen          = 64
+	xxh3SecretConsumeRate  = 8
+	xxh3MidsizeMax         = 240
+	xxh3MidsizeStartOffset = 3
+	xxh3MidsizeLastOffset  = 17
+	xxh3SecretSizeMin      = 136
+	xxh3LastAccStart       = 7
+	xxh3MergeAccStart      = 11
+)
+
+// XXH3Hash64 XXH3 64 位算法，与 xxHash 0.8 的 XXH3_64bits 一致，不分配内存。
+// XXH3Hash64 computes the 64-bit XXH3 hash of data, matching XXH3_64bits of xxHash 0.8.
+// It does not allocate.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 返回值 (return): uint64 - 哈希值。 the hash value.
+func XXH3Hash64(data []byte) uint64 {
+	return XXH3Hash64WithSeed(data, 0)
+}
+
+// XXH3Hash64WithSeed 带种子的 XXH3 64 位算法，与 XXH3_64bits_withSeed 一致。
+// XXH3Hash64WithSeed computes the seeded 64-bit XXH3 hash, matching XXH3_64bits_withSeed.
+// 参数 (param): data []byte - 需要被哈希的数据。 the data to hash.
+// 参数 (param): seed uint64 - 种子。 the seed.
+// 返回值 (return): uint64 - 哈希值。 the hash value.
+func XXH3Hash64WithSeed(data []byte, seed uint64) uint64 {
+	n := len(data)
+	s := xxh3Secret[:]
+	switch {
+	case n == 0:
+		return xxh64Avalanche(seed ^ (le64(s, 56) ^ le64(s, 64)))
+	case n <= 3:
+		c1, c2, c3 := uint32(data[0]), uint32(data[n>>1]), uint32(data[n-1])
+		combined := c1<<16 | c2<<24 | c3 | uint32(n)<<8
+		bitflip := uint64(le32(s, 0)^le32(s, 4)) + seed
+		return xxh64Avalanche(uint64(combined) ^ bitflip)
+	case n <= 8:
+		seed ^= uint64(bits.ReverseBytes32(uint32(seed))) << 32
+		input1, input2 := le32(data, 0), le32(data, n-4)
+		bitflip := (le64(s, 8) ^ le64(s, 16)) - seed
+		input64 := uint64(input2) + uint64(input1)<<32
+		return xxh3RRMXMX(input64^bitflip, uint64(n))
+	case n <= 16:
+		bitflip1 := (le64(s, 24) ^ le64(s, 32)) + seed
+		bitflip2 := (le64(s, 40) ^ le64(s, 48)) - seed
+		lo := le64(data, 0) ^ bitflip1
+		hi := le64(data, n-8) ^ bitflip2
+		acc := uint64(n) + bits.ReverseBytes64(lo) + hi + mulFold64(lo, hi)
+		return xxh3Avalanche(acc)
+	case n <= 128:
+		acc := uint64(n) * xxPrime64_1
+		if n > 32 {
+			if n > 64 {
+				if n > 96 {
+					acc += xxh3Mix16(data, 48, s, 96, seed)
+					acc += xxh3Mix16(data, n-64, s, 112, seed)
+				}
+				acc += xxh3Mix16(data, 32, s, 64, seed)
+				acc += xxh3Mix16(data, n-48, s, 80, seed)
+			}
+			acc += xxh3Mix16(data, 16, s, 32, seed)
+			acc += xxh3Mix16(data, n-32, s, 48, seed)
+		}
+		acc += xxh3Mix16(data, 0, s, 0, seed)
+		acc += xxh3Mix16(data, n-16, s, 16, seed)
+		return xxh3Avalanche(acc)
+	case n <= xxh3MidsizeMax:
+		acc := uint64(n) * xxPrime64_1
+		rounds := n / 16
+		for i := 0; i < 8; i++ {
+			acc += xxh3Mix16(data, 16*i, s, 16*i, seed)
+		}
+		acc = xxh3Avalanche(acc)
+		for i := 8; i < rounds; i++ {
+			acc += xxh3Mix16(data, 16*i, s, 16*(i-8)+xxh3MidsizeStartOffset, seed)
+		}
+		acc += xxh3Mix16(data, n-16, s, xxh3SecretSizeMin-xxh3MidsizeLastOffset, seed)
+		return xxh3Avalanche(acc)
+	default:
+		if seed == 0 {
+			return xxh3HashLong(data, s)
+		}
+		// 带种子的长输入使用由种子派生的密钥，数组在栈上分配。
+		// Long seeded inputs use a secret derived from the seed; the array stays on the stack.
+		var custom [192]byte
+		for i := 0; i < len(custom); i += 16 {
+			binary.LittleEndian.PutUint64(custom[i:], le64(s, i)+seed)
+			binary.LittleEndian.PutUint64(custom[i+8:], le64(s, i+8)-seed)
+		}
+		return xxh3HashLong(data, custom[:])
+	}
+}
+
+// xxh3HashLong 处理超过 240 字节的输入。
+// xxh3HashLong hashes inputs longer than 240 bytes.
+func xxh3HashLong(data, secret []byte) uint64 {
+	acc := [8]uint64{
+		xxPrime32_3, xxPrime64_1, xxPrime64_2, xxPrime64_3,
+		xxPrime64_4, xxPrime32_2, xxPrime64_5, xxPrime32_1,
+	}
+	n := len(data)
+	stripesPerBlock := (len(secret) - xxh3StripeLen) / xxh3SecretConsumeRate
+	blockLen := xxh3StripeLen * stripesPerBlock
+	blocks := (n - 1) / blockLen
+
+	for b := 0; b < blocks; b++ {
+		xxh3Accumulate(&acc, data[b*blockLen:], secret, stripesPerBlock)
+		xxh3ScrambleAcc(&acc, secret[len(secret)-xxh3StripeLen:])
+	}
+	stripes := ((n - 1) - blockLen*blocks) / xxh3StripeLen
+	xxh3Accumulate(&acc, data[blocks*blockLen:], secret, stripes)
+	xxh3Accumulate512(&acc, data[n-xxh3StripeLen:], secret[len(secret)-xxh3StripeLen-xxh3LastAccStart:])
+
+	result := uint64(n) * xxPrime64_1
+	for i := 0; i < 4; i++ {
+		result += mulFold64(acc[2*i]^le64(secret, xxh3MergeAccStart+16*i), acc[2*i+1]^le64(secret, xxh3MergeAccStart+16*i+8))
+	}
+	return xxh3Avalanche(result)
+}
+
+// xxh3Accumulate 累加若干个 64 字节的条带。
+// xxh3Accumulate accumulates a number of 64-byte stripes.
+func xxh3Accumulate(acc *[8]uint64, data, secret []byte, stripes int) {
+	for i := 0; i < stripes; i++ {
+		xxh3Accumulate512(acc, data[i*xxh3StripeLen:], secret[i*xxh3SecretConsumeRate:])
+	}
+}
+
+// xxh3Accumulate512 累加一个 64 字节的条带。
+// xxh3Accumulate512 accumulates one 64-byte stripe.
+func xxh3Accumulate512(acc *[8]uint64, data, secret []byte) {
+	for i := 0; i < 8; i++ {
+		v := le64(data, 8*i)
+		key := v ^ le64(secret, 8*i)
+		acc[i^1] += v
+		acc[i] += uint64(uint32(key)) * (key >> 32)
+	}
+}
+
+// xxh3ScrambleAcc 每个块结束后打乱累加器。
+// xxh3ScrambleAcc scrambles the accumulators after each block.
+func xxh3ScrambleAcc(acc *[8]uint64, secret []byte) {
+	for i := range acc {
+		a := acc[i]
+		a ^= a >> 47
+		a ^= le64(secret, 8*i)
+		acc[i] = a * xxPrime32_1
+	}
+}
+
+// xxh3Mix16 混合 16 字节输入与 16 字节密钥。
+// xxh3Mix16 mixes 16 bytes of input with 16 bytes of secret.
+func xxh3Mix16(data []byte, di int, secret []byte, si int, seed uint64) uint64 {
+	lo := le64(data, di)
+	hi := le64(data, di+8)
+	return mulFold64(lo^(le64(secret, si)+seed), hi^(le64(secret, si+8)-seed))
+}
+
+// xxh3Avalanche XXH3 的终结混合函数。
+// xxh3Avalanche is the XXH3 final mix.
+func xxh3Avalanche(h uint64) uint64 {
+	h ^= h >> 37
+	h *= xxPrimeMX1
+	h ^= h >> 32
+	return h
+}
+
+// xxh3RRMXMX 4 到 8 字节输入的终结混合函数。
+// xxh3RRMXMX is the final mix for 4 to 8 byte inputs.
+func xxh3RRMXMX(h, n uint64) uint64 {
+	h ^= bits.RotateLeft64(h, 49) ^ bits.RotateLeft64(h, 24)
+	h *= xxPrimeMX2
+	h ^= (h >> 35) + n
+	h *= xxPrimeMX2
+	return h ^ (h >> 28)
+}
+
+// mulFold64 计算 128 位乘积并把高低 64 位异或。
+// mulFold64 multiplies to 128 bits and folds the halves with XOR.
+func mulFold64(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+// le64 读取小端 64 位整数。
+// le64 reads a little-endian uint64 at offset i.
+func le64(b []byte, i int) uint64 {
+	return binary.LittleEndian.Uint64(b[i:])
+}
+
+// le32 读取小端 32 位整数。
+// le32 reads a little-endian uint32 at offset i.
+func le32(b []byte, i int) uint32 {
+	return binary.LittleEndian.Uint32(b[i:])
+}
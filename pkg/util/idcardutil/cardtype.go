@@ -0,0 +1,239 @@
+package idcardutil
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CardType 证件类型
+type CardType int
+
+const (
+	CardTypeUnknown                     CardType = iota // 未知
+	CardTypeMainland                                    // 居民身份证（18 位）
+	CardTypeMainland15                                  // 第一代居民身份证（15 位）
+	CardTypeResidencePermit                             // 港澳台居民居住证
+	CardTypeHongKong                                    // 香港身份证
+	CardTypeMacau                                       // 澳门身份证
+	CardTypeTaiwan                                      // 台湾身份证
+	CardTypeHKMacauTravelPermit                         // 港澳居民来往内地通行证
+	CardTypeTaiwanTravelPermit                          // 台湾居民来往大陆通行证
+	CardTypeForeignerPermanentResidence                 // 外国人永久居留身份证（2023 版）
+)
+
+var cardTypeNames = map[CardType]string{
+	CardTypeMainland:                    "居民身份证",
+	CardTypeMainland15:                  "第一代居民身份证",
+	CardTypeResidencePermit:             "港澳台居民居住证",
+	CardTypeHongKong:                    "香港身份证",
+	CardTypeMacau:                       "澳门身份证",
+	CardTypeTaiwan:                      "台湾身份证",
+	CardTypeHKMacauTravelPermit:         "港澳居民来往内地通行证",
+	CardTypeTaiwanTravelPermit:          "台湾居民来往大陆通行证",
+	CardTypeForeignerPermanentResidence: "外国人永久居留身份证",
+}
+
+// String 返回证件类型的中文名称
+func (t CardType) String() string {
+	if name, ok := cardTypeNames[t]; ok {
+		return name
+	}
+	return "未知"
+}
+
+// IdCardInfo ParseIdCard 的解析结果，证件中不包含的信息为零值
+type IdCardInfo struct {
+	Number      string    // 规范化后的证件号码
+	Type        CardType  // 证件类型
+	Birth       time.Time // 出生日期
+	Gender      string    // 性别：男、女
+	RegionCode  string    // 行政区划码
+	Province    string    // 省级名称
+	City        string    // 市级名称
+	District    string    // 区县级名称
+	Nationality string    // 国籍代码（ISO 3166-1 数字代码），仅外国人永久居留身份证
+}
+
+var (
+	idCard15Pattern         = regexp.MustCompile(`^\d{15}$`)
+	hkCardPattern           = regexp.MustCompile(`^([A-Z]{1,2})(\d{6})\(?([0-9A])\)?$`)
+	macauCardPattern        = regexp.MustCompile(`^[157]\d{6}\(\d\)$`)
+	taiwanCardPattern       = regexp.MustCompile(`^[A-Z][1289]\d{8}$`)
+	hkMacauPermitPattern    = regexp.MustCompile(`^[HM]\d{8}(\d{2})?$`)
+	taiwanPermitPattern     = regexp.MustCompile(`^\d{8}(\d{2})?$`)
+	foreignerCardPattern    = regexp.MustCompile(`^9\d{16}[\dX]$`)
+	hkMacauTaiwanRegionCode = map[string]string{"81": "香港特别行政区", "82": "澳门特别行政区", "83": "台湾省"}
+)
+
+// provinceCodePrefixes 有效的省级行政区划码前两位
+var provinceCodePrefixes = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true,
+	"21": true, "22": true, "23": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "36": true, "37": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true,
+	"50": true, "51": true, "52": true, "53": true, "54": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true,
+}
+
+// taiwanLetterCodes 台湾身份证首字母对应的数值
+var taiwanLetterCodes = map[byte]int{
+	'A': 10, 'B': 11, 'C': 12, 'D': 13, 'E': 14, 'F': 15, 'G': 16, 'H': 17, 'I': 34,
+	'J': 18, 'K': 19, 'L': 20, 'M': 21, 'N': 22, 'O': 35, 'P': 23, 'Q': 24, 'R': 25,
+	'S': 26, 'T': 27, 'U': 28, 'V': 29, 'W': 32, 'X': 30, 'Y': 31, 'Z': 33,
+}
+
+// IsValidCard15 验证 15 位第一代身份证是否合法，出生年份按 19xx 处理
+func IsValidCard15(idCard string) bool {
+	return idCard15Pattern.MatchString(idCard) && isValidBirth("19"+idCard[6:12])
+}
+
+// Convert15To18 将 15 位身份证转换为 18 位：出生年份补 19 并追加校验位
+func Convert15To18(idCard string) (string, error) {
+	if !IsValidCard15(idCard) {
+		return "", ErrInvalidIdCard
+	}
+	idCard17 := idCard[:6] + "19" + idCard[6:]
+	return idCard17 + string(checkDigit18(idCard17)), nil
+}
+
+// IsValidHKCard 验证香港身份证，如 A123456(3)，括号可省略
+// 校验规则：单字母前补空格（值 36），字母 A-Z 为 10-35，按权重 9-1 加权求和后能被 11 整除，校验位 A 表示 10
+func IsValidHKCard(idCard string) bool {
+	m := hkCardPattern.FindStringSubmatch(idCard)
+	if m == nil {
+		return false
+	}
+	letters, digits, check := m[1], m[2], m[3]
+	sum := 0
+	if len(letters) == 1 {
+		sum += 36 * 9
+	} else {
+		sum += int(letters[0]-'A'+10) * 9
+	}
+	sum += int(letters[len(letters)-1]-'A'+10) * 8
+	for i := 0; i < 6; i++ {
+		sum += int(digits[i]-'0') * (7 - i)
+	}
+	if check == "A" {
+		sum += 10
+	} else {
+		sum += int(check[0] - '0')
+	}
+	return sum%11 == 0
+}
+
+// IsValidMacauCard 验证澳门身份证格式，如 1234567(8)，首位为 1、5 或 7
+func IsValidMacauCard(idCard string) bool {
+	return macauCardPattern.MatchString(idCard)
+}
+
+// IsValidTaiwanCard 验证台湾身份证，如 A123456789
+// 校验规则：首字母换算为两位数字，连同其余 9 位按权重 1、9、8、7、6、5、4、3、2、1、1 加权求和后能被 10 整除
+func IsValidTaiwanCard(idCard string) bool {
+	if !taiwanCardPattern.MatchString(idCard) {
+		return false
+	}
+	code := taiwanLetterCodes[idCard[0]]
+	sum := code/10 + code%10*9
+	for i := 1; i < 9; i++ {
+		sum += int(idCard[i]-'0') * (9 - i)
+	}
+	sum += int(idCard[9] - '0')
+	return sum%10 == 0
+}
+
+// IsValidHKMacauTravelPermit 验证港澳居民来往内地通行证，H（香港）或 M（澳门）加 8 位或 10 位数字
+func IsValidHKMacauTravelPermit(permit string) bool {
+	return hkMacauPermitPattern.MatchString(permit)
+}
+
+// IsValidTaiwanTravelPermit 验证台湾居民来往大陆通行证，8 位或 10 位数字
+func IsValidTaiwanTravelPermit(permit string) bool {
+	return taiwanPermitPattern.MatchString(permit)
+}
+
+// IsValidForeignerPermanentResidenceCard 验证 2023 版外国人永久居留身份证
+// 组成：9 + 2 位受理地省级代码 + 3 位国籍代码 + 8 位出生日期 + 3 位顺序码 + 1 位校验码（与居民身份证算法相同）
+func IsValidForeignerPermanentResidenceCard(idCard string) bool {
+	return foreignerCardPattern.MatchString(idCard) &&
+		provinceCodePrefixes[idCard[1:3]] &&
+		isValidBirth(idCard[6:14]) &&
+		validate18IDCard(idCard)
+}
+
+// ParseIdCard 识别并解析各类证件号码，返回证件类型、出生日期、性别和所属地区
+// 号码中的小写字母和首尾空格会被规范化
+func ParseIdCard(idCard string) (*IdCardInfo, error) {
+	number := strings.ToUpper(strings.TrimSpace(idCard))
+	info := &IdCardInfo{Number: number}
+
+	switch {
+	// 外国人永久居留身份证同样满足 18 位身份证的格式和校验规则，需要先判断
+	case IsValidForeignerPermanentResidenceCard(number):
+		info.Type = CardTypeForeignerPermanentResidence
+		info.setBirthAndGender(number[6:14], number[16])
+		info.setRegion(number[1:3] + "0000")
+		info.Nationality = number[3:6]
+	case IsValidCard(number):
+		info.Type = CardTypeMainland
+		info.setBirthAndGender(number[6:14], number[16])
+		info.setRegion(number[:6])
+		if _, ok := hkMacauTaiwanRegionCode[number[:2]]; ok {
+			info.Type = CardTypeResidencePermit
+		}
+	case IsValidCard15(number):
+		info.Type = CardTypeMainland15
+		info.setBirthAndGender("19"+number[6:12], number[14])
+		info.setRegion(number[:6])
+	case IsValidHKCard(number):
+		m := hkCardPattern.FindStringSubmatch(number)
+		info.Type = CardTypeHongKong
+		info.Number = m[1] + m[2] + "(" + m[3] + ")"
+		info.setRegion("810000")
+	case IsValidMacauCard(number):
+		info.Type = CardTypeMacau
+		info.setRegion("820000")
+	case IsValidTaiwanCard(number):
+		info.Type = CardTypeTaiwan
+		info.setRegion("830000")
+		if number[1] == '1' || number[1] == '8' {
+			info.Gender = "男"
+		} else {
+			info.Gender = "女"
+		}
+	case IsValidHKMacauTravelPermit(number):
+		info.Type = CardTypeHKMacauTravelPermit
+		if number[0] == 'H' {
+			info.setRegion("810000")
+		} else {
+			info.setRegion("820000")
+		}
+	case IsValidTaiwanTravelPermit(number):
+		info.Type = CardTypeTaiwanTravelPermit
+		info.setRegion("830000")
+	default:
+		return nil, ErrInvalidIdCard
+	}
+	return info, nil
+}
+
+// setBirthAndGender 设置出生日期和性别，顺序码末位奇数为男、偶数为女
+func (info *IdCardInfo) setBirthAndGender(birth string, genderCode byte) {
+	info.Birth, _ = time.Parse("20060102", birth)
+	if (genderCode-'0')%2 == 1 {
+		info.Gender = "男"
+	} else {
+		info.Gender = "女"
+	}
+}
+
+// setRegion 设置行政区划码及对应的省、市、区名称
+func (info *IdCardInfo) setRegion(code string) {
+	info.RegionCode = code
+	if name, ok := hkMacauTaiwanRegionCode[code[:2]]; ok {
+		info.Province = name
+		return
+	}
+	info.Province, info.City, info.District = lookupRegion(code)
+}
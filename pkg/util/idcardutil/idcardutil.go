@@ -3,7 +3,6 @@ package idcardutil
 import (
 	"errors"
 	"regexp"
-	"sync"
 	"time"
 )
 
@@ -25,25 +24,25 @@ type District struct {
 	Label string `json:"label"`
 }
 
-var (
-	once      sync.Once
-	provinces []Province
-)
-
-func loadProvinces() {
+// loadProvinces 将内嵌的行政区划数据转换为省、市、区结构
+func loadProvinces() []Province {
+	var provinces []Province
 	for _, p := range idCardJSONData {
 		province := Province{
 			Code:  p["code"].(string),
 			Label: p["label"].(string),
 		}
 
-		for _, c := range p["list"].([]map[string]interface{}) {
+		// 部分节点缺少下级列表，使用 comma-ok 断言避免 panic
+		cities, _ := p["list"].([]map[string]interface{})
+		for _, c := range cities {
 			city := City{
 				Code:  c["code"].(string),
 				Label: c["label"].(string),
 			}
 
-			for _, d := range c["list"].([]map[string]interface{}) {
+			districts, _ := c["list"].([]map[string]interface{})
+			for _, d := range districts {
 				district := District{
 					Code:  d["code"].(string),
 					Label: d["label"].(string),
@@ -54,34 +53,45 @@ func loadProvinces() {
 		}
 		provinces = append(provinces, province)
 	}
+	return provinces
 }
 
 var idCard18Pattern = regexp.MustCompile(`^\d{17}(\d|X)$`)
 
-// IsValidCard 验证身份证是否合法
+// ErrInvalidIdCard 证件号码无效
+var ErrInvalidIdCard = errors.New("无效的身份证号码")
+
+// IsValidCard 验证身份证是否合法，包括出生日期和校验位
 func IsValidCard(idCard string) bool {
-	return len(idCard) == 18 && idCard18Pattern.MatchString(idCard) && validate18IDCard(idCard)
+	return len(idCard) == 18 && idCard18Pattern.MatchString(idCard) && isValidBirth(idCard[6:14]) && validate18IDCard(idCard)
+}
+
+// isValidBirth 验证 yyyyMMdd 格式的出生日期是否为有效日期
+func isValidBirth(birth string) bool {
+	_, err := time.Parse("20060102", birth)
+	return err == nil && birth >= "18000101"
 }
 
 // 验证18位身份证
 func validate18IDCard(idCard string) bool {
+	return checkDigit18(idCard[:17]) == idCard[17]
+}
+
+// checkDigit18 计算 18 位身份证前 17 位对应的校验位（GB 11643）
+func checkDigit18(idCard17 string) byte {
 	// 校验位计算
 	weights := []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
 	checksum := 0
 	for i := 0; i < 17; i++ {
-		num := int(idCard[i] - '0')
-		checksum += num * weights[i]
+		checksum += int(idCard17[i]-'0') * weights[i]
 	}
-	checkDigits := "10X98765432"
-	checkDigit := checkDigits[checksum%11]
-
-	return checkDigit == idCard[17]
+	return "10X98765432"[checksum%11]
 }
 
 // GetBirthByIdCard 获取生日
 func GetBirthByIdCard(idCard string) (string, error) {
 	if !IsValidCard(idCard) {
-		return "", errors.New("无效的身份证号码")
+		return "", ErrInvalidIdCard
 	}
 	return idCard[6:14], nil
 }
@@ -108,7 +118,7 @@ func GetAgeByIdCard(idCard string, currentDate time.Time) (int, error) {
 // GetGenderByIdCard 获取性别
 func GetGenderByIdCard(idCard string) (string, error) {
 	if !IsValidCard(idCard) {
-		return "", errors.New("无效的身份证号码")
+		return "", ErrInvalidIdCard
 	}
 
 	genderCode := idCard[16]
@@ -122,27 +132,41 @@ func GetGenderByIdCard(idCard string) (string, error) {
 // GetRegionByIdCard 获取省份和城市
 func GetRegionByIdCard(idCard string) (string, error) {
 	if !IsValidCard(idCard) {
-		return "", errors.New("无效的身份证号码")
+		return "", ErrInvalidIdCard
 	}
 
-	code := idCard[:6]
-
-	once.Do(loadProvinces)
+	province, city, district := lookupRegion(idCard[:6])
+	switch {
+	case province == "":
+		return "未知", nil
+	case city == "":
+		return province, nil
+	case district == "":
+		return province + " " + city, nil
+	}
+	return province + " " + city + " " + district, nil
+}
 
-	for _, province := range provinces {
-		if province.Code[:2] == code[:2] { // 省级匹配
-			for _, city := range province.List {
-				if city.Code[:4] == code[:4] { // 市级匹配
-					for _, district := range city.List {
-						if district.Code == code { // 区级匹配
-							return province.Label + " " + city.Label + " " + district.Label, nil
-						}
-					}
-					return province.Label + " " + city.Label, nil
+// lookupRegion 按 6 位行政区划码查找省、市、区名称，未匹配的级别为空字符串
+func lookupRegion(code string) (province, city, district string) {
+	for _, p := range currentRegions().provinces {
+		if p.Code[:2] != code[:2] { // 省级匹配
+			continue
+		}
+		// 直辖市的市级代码与省级相同，因此先按完整代码匹配区级
+		for _, c := range p.List {
+			for _, d := range c.List {
+				if d.Code == code { // 区级匹配
+					return p.Label, c.Label, d.Label
 				}
 			}
-			return province.Label, nil
 		}
+		for _, c := range p.List {
+			if c.Code[:4] == code[:4] { // 市级匹配
+				return p.Label, c.Label, ""
+			}
+		}
+		return p.Label, "", ""
 	}
-	return "未知", nil
+	return "", "", ""
 }
@@ -0,0 +1,314 @@
+package idcardutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// RegionLevel 行政区划级别
+type RegionLevel int
+
+const (
+	RegionLevelProvince RegionLevel = 1 // 省级
+	RegionLevelCity     RegionLevel = 2 // 地级
+	RegionLevelDistrict RegionLevel = 3 // 县级
+)
+
+// Region 行政区划
+type Region struct {
+	Code       string      // 6 位行政区划码
+	Name       string      // 名称
+	Level      RegionLevel // 级别，由代码推断：后四位为 0 为省级，后两位为 0 为地级，否则为县级
+	ParentCode string      // 上级行政区划码，顶级为空
+}
+
+// ErrInvalidRegionData 行政区划数据无效
+var ErrInvalidRegionData = errors.New("无效的行政区划数据")
+
+// regionData 行政区划数据的不可变快照
+type regionData struct {
+	provinces []Province
+	regions   map[string]*Region
+	children  map[string][]*Region
+	top       []*Region
+}
+
+var (
+	once    sync.Once
+	regions atomic.Pointer[regionData]
+)
+
+// currentRegions 返回当前的行政区划数据，首次调用时加载内嵌数据
+func currentRegions() *regionData {
+	once.Do(func() {
+		if regions.Load() == nil {
+			regions.Store(buildRegionData(loadProvinces()))
+		}
+	})
+	return regions.Load()
+}
+
+// buildRegionData 展开省、市、区结构并建立按代码的索引
+// 上下级关系由代码推断，因此与直辖市市级代码重复、缺少省级节点等数据差异无关
+func buildRegionData(provinces []Province) *regionData {
+	d := &regionData{
+		provinces: provinces,
+		regions:   map[string]*Region{},
+		children:  map[string][]*Region{},
+	}
+	add := func(code, name string) {
+		if _, ok := d.regions[code]; !ok {
+			d.regions[code] = &Region{Code: code, Name: name, Level: regionLevel(code)}
+		}
+	}
+	for _, p := range provinces {
+		add(p.Code, p.Label)
+		for _, c := range p.List {
+			add(c.Code, c.Label)
+			for _, dist := range c.List {
+				add(dist.Code, dist.Label)
+			}
+		}
+	}
+
+	for code, r := range d.regions {
+		r.ParentCode = d.parentCode(code)
+		if r.ParentCode == "" {
+			d.top = append(d.top, r)
+		} else {
+			d.children[r.ParentCode] = append(d.children[r.ParentCode], r)
+		}
+	}
+	sortRegions(d.top)
+	for _, list := range d.children {
+		sortRegions(list)
+	}
+	return d
+}
+
+// parentCode 按代码推断已存在的上级行政区划码
+func (d *regionData) parentCode(code string) string {
+	var candidates []string
+	switch regionLevel(code) {
+	case RegionLevelDistrict:
+		candidates = []string{code[:4] + "00", code[:2] + "0000"}
+	case RegionLevelCity:
+		candidates = []string{code[:2] + "0000"}
+	}
+	for _, c := range candidates {
+		if _, ok := d.regions[c]; ok {
+			return c
+		}
+	}
+	return ""
+}
+
+// regionLevel 按代码推断行政区划级别
+func regionLevel(code string) RegionLevel {
+	switch {
+	case strings.HasSuffix(code, "0000"):
+		return RegionLevelProvince
+	case strings.HasSuffix(code, "00"):
+		return RegionLevelCity
+	default:
+		return RegionLevelDistrict
+	}
+}
+
+// sortRegions 按代码排序
+func sortRegions(list []*Region) {
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+}
+
+// copyRegions 复制为值切片，避免调用方修改内部数据
+func copyRegions(list []*Region) []Region {
+	result := make([]Region, len(list))
+	for i, r := range list {
+		result[i] = *r
+	}
+	return result
+}
+
+// GetRegion 按行政区划码查找行政区划
+func GetRegion(code string) (Region, bool) {
+	if r, ok := currentRegions().regions[code]; ok {
+		return *r, true
+	}
+	return Region{}, false
+}
+
+// GetProvinces 返回所有顶级行政区划（通常为省级），按代码排序
+func GetProvinces() []Region {
+	return copyRegions(currentRegions().top)
+}
+
+// GetChildren 返回下级行政区划，按代码排序；直辖市返回其下的区县
+func GetChildren(code string) []Region {
+	return copyRegions(currentRegions().children[code])
+}
+
+// GetRegionPath 返回从顶级到指定行政区划的路径，代码不存在时返回 nil
+func GetRegionPath(code string) []Region {
+	d := currentRegions()
+	var path []Region
+	for r, ok := d.regions[code]; ok; r, ok = d.regions[r.ParentCode] {
+		path = append([]Region{*r}, path...)
+	}
+	return path
+}
+
+// GetRegionFullName 返回行政区划的完整名称，如 "河北省/石家庄市/长安区"，代码不存在时返回空字符串
+func GetRegionFullName(code, sep string) string {
+	path := GetRegionPath(code)
+	names := make([]string, len(path))
+	for i, r := range path {
+		names[i] = r.Name
+	}
+	return strings.Join(names, sep)
+}
+
+// FindRegionsByName 按完整名称查找行政区划，同名的区划按代码排序全部返回
+func FindRegionsByName(name string) []Region {
+	var result []*Region
+	for _, r := range currentRegions().regions {
+		if r.Name == name {
+			result = append(result, r)
+		}
+	}
+	sortRegions(result)
+	return copyRegions(result)
+}
+
+// regionSuffixes 模糊匹配时去除的行政区划名称后缀，较长的后缀在前
+var regionSuffixes = []string{
+	"维吾尔自治区", "壮族自治区", "回族自治区", "特别行政区", "自治区", "自治州", "自治县", "自治旗",
+	"地区", "新区", "林区", "省", "市", "区", "县", "盟", "旗",
+}
+
+// shortRegionName 去除行政区划名称的后缀，如 "深圳市" 返回 "深圳"；去除后不足两个字时保留原名称
+func shortRegionName(name string) string {
+	for _, suffix := range regionSuffixes {
+		if short := strings.TrimSuffix(name, suffix); short != name && utf8.RuneCountInString(short) >= 2 {
+			return short
+		}
+	}
+	return name
+}
+
+// SearchRegions 按名称模糊查找行政区划，匹配程度依次为：完整名称相同、去除后缀后相同
+// （如 "石家庄" 匹配 "石家庄市"）、名称前缀、名称包含关键字。结果按匹配程度、级别和代码排序
+func SearchRegions(keyword string) []Region {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return nil
+	}
+	shortKeyword := shortRegionName(keyword)
+
+	type match struct {
+		region *Region
+		rank   int
+	}
+	var matches []match
+	for _, r := range currentRegions().regions {
+		rank := 0
+		switch {
+		case r.Name == keyword:
+			rank = 1
+		case shortRegionName(r.Name) == shortKeyword:
+			rank = 2
+		case strings.HasPrefix(r.Name, shortKeyword):
+			rank = 3
+		case strings.Contains(r.Name, shortKeyword):
+			rank = 4
+		default:
+			continue
+		}
+		matches = append(matches, match{r, rank})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.region.Level != b.region.Level {
+			return a.region.Level < b.region.Level
+		}
+		return a.region.Code < b.region.Code
+	})
+
+	result := make([]Region, len(matches))
+	for i, m := range matches {
+		result[i] = *m.region
+	}
+	return result
+}
+
+// LoadRegions 从 JSON 加载行政区划数据并替换当前数据（包括 GetRegionByIdCard 和 ParseIdCard 使用的数据）
+// JSON 格式与内嵌数据相同：[{"code": "110000", "label": "北京市", "list": [{"code": ..., "label": ..., "list": [...]}]}]
+func LoadRegions(r io.Reader) error {
+	var provinces []Province
+	if err := json.NewDecoder(r).Decode(&provinces); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRegionData, err)
+	}
+	if len(provinces) == 0 {
+		return ErrInvalidRegionData
+	}
+	for _, p := range provinces {
+		if !isValidRegionNode(p.Code, p.Label) {
+			return ErrInvalidRegionData
+		}
+		for _, c := range p.List {
+			if !isValidRegionNode(c.Code, c.Label) {
+				return ErrInvalidRegionData
+			}
+			for _, d := range c.List {
+				if !isValidRegionNode(d.Code, d.Label) {
+					return ErrInvalidRegionData
+				}
+			}
+		}
+	}
+
+	data := buildRegionData(provinces)
+	// 确保内嵌数据的延迟加载不会覆盖新数据
+	once.Do(func() {})
+	regions.Store(data)
+	return nil
+}
+
+// LoadRegionsFromFile 从 JSON 文件加载行政区划数据，格式见 LoadRegions
+func LoadRegionsFromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return LoadRegions(f)
+}
+
+// ResetRegions 恢复为内嵌的行政区划数据
+func ResetRegions() {
+	once.Do(func() {})
+	regions.Store(buildRegionData(loadProvinces()))
+}
+
+// isValidRegionNode 校验行政区划节点：代码为 6 位数字且名称非空
+func isValidRegionNode(code, name string) bool {
+	if len(code) != 6 || name == "" {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
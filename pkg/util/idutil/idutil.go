@@ -5,7 +5,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"sync"
-	"time"
+
+	"GoFast/pkg/datetime"
 )
 
 // RandomUUID 生成带 "-" 的 UUID (版本4)
@@ -47,7 +48,7 @@ func ObjectId() string {
 	}
 
 	// 时间戳部分，前4个字节是当前秒数
-	timestamp := uint32(time.Now().Unix())
+	timestamp := uint32(datetime.GetCurrentTime().Unix())
 	b[0] = byte(timestamp >> 24)
 	b[1] = byte(timestamp >> 16)
 	b[2] = byte(timestamp >> 8)
@@ -62,9 +63,10 @@ type Snowflake struct {
 	timestamp int64
 	sequence  int64
 	nodeID    int64
+	clock     datetime.Clock // 时间源，为 nil 时使用 datetime.GetClock
 }
 
-// NewSnowflake 创建一个新的 Snowflake 生成器
+// NewSnowflake 创建一个新的 Snowflake 生成器，使用 datetime.SetClock 设置的时钟
 func NewSnowflake(nodeID int64) *Snowflake {
 	return &Snowflake{
 		timestamp: 0,
@@ -73,6 +75,14 @@ func NewSnowflake(nodeID int64) *Snowflake {
 	}
 }
 
+// NewSnowflakeWithClock 创建一个使用指定时钟的 Snowflake 生成器，测试中可传入 datetime.FakeClock
+func NewSnowflakeWithClock(nodeID int64, clock datetime.Clock) *Snowflake {
+	return &Snowflake{
+		nodeID: nodeID,
+		clock:  clock,
+	}
+}
+
 // NextId 生成下一个 Snowflake ID
 func (s *Snowflake) NextId() int64 {
 	s.Lock()
@@ -86,12 +96,17 @@ func (s *Snowflake) NextId() int64 {
 	timestampShift := sequenceBits + nodeIDBits
 	sequenceMask := int64(-1 ^ (-1 << sequenceBits))
 
-	now := time.Now().UnixNano() / 1e6
+	clock := s.clock
+	if clock == nil {
+		clock = datetime.GetClock()
+	}
+	now := clock.Now().UnixNano() / 1e6
 	if s.timestamp == now {
 		s.sequence = (s.sequence + 1) & sequenceMask
 		if s.sequence == 0 {
+			// 序列号用尽时等待下一毫秒；使用 FakeClock 时需由其他 goroutine 推进时钟
 			for now <= s.timestamp {
-				now = time.Now().UnixNano() / 1e6
+				now = clock.Now().UnixNano() / 1e6
 			}
 		}
 	} else {
@@ -0,0 +1,233 @@
+package sketchutil
+
+import (
+	"math"
+	"math/bits"
+)
+
+// BloomFilter 布隆过滤器：判断元素“可能存在”或“一定不存在”。非并发安全。
+// BloomFilter is a Bloom filter: it answers "possibly present" or "definitely
+// absent". It is not safe for concurrent use.
+type BloomFilter struct {
+	m     uint64   // 位数 number of bits
+	k     uint32   // 哈希函数个数 number of hash functions
+	words []uint64 // 位数组 bit array
+	count uint64   // 已添加的元素数 number of Add calls
+}
+
+// OptimalBloomSize 根据期望元素数和误判率计算最优的位数和哈希函数个数。
+// OptimalBloomSize computes the optimal number of bits and hash functions for the
+// expected number of items and false-positive rate.
+// 参数 (param): n uint64 - 期望元素数。 the expected number of items.
+// 参数 (param): fpRate float64 - 误判率，取值 (0, 1)。 the false-positive rate in (0, 1).
+// 返回值 (return): uint64 - 位数。 the number of bits.
+// 返回值 (return): uint32 - 哈希函数个数。 the number of hash functions.
+func OptimalBloomSize(n uint64, fpRate float64) (uint64, uint32) {
+	if n == 0 {
+		n = 1
+	}
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	if k < 1 {
+		k = 1
+	}
+	return uint64(m), uint32(k)
+}
+
+// NewBloomFilter 按期望元素数和误判率创建布隆过滤器。
+// NewBloomFilter creates a Bloom filter sized for the expected number of items and false-positive rate.
+// 参数 (param): n uint64 - 期望元素数。 the expected number of items.
+// 参数 (param): fpRate float64 - 误判率，取值 (0, 1)。 the false-positive rate in (0, 1).
+// 返回值 (return): *BloomFilter - 新的过滤器。 the new filter.
+// 返回值 (return): error - 参数无效时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters.
+func NewBloomFilter(n uint64, fpRate float64) (*BloomFilter, error) {
+	if !(fpRate > 0 && fpRate < 1) {
+		return nil, ErrInvalidParameter
+	}
+	m, k := OptimalBloomSize(n, fpRate)
+	return NewBloomFilterWithSize(m, k)
+}
+
+// NewBloomFilterWithSize 按指定的位数和哈希函数个数创建布隆过滤器。
+// NewBloomFilterWithSize creates a Bloom filter with m bits and k hash functions.
+// 参数 (param): m uint64 - 位数。 the number of bits.
+// 参数 (param): k uint32 - 哈希函数个数。 the number of hash functions.
+// 返回值 (return): *BloomFilter - 新的过滤器。 the new filter.
+// 返回值 (return): error - 参数无效时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters.
+func NewBloomFilterWithSize(m uint64, k uint32) (*BloomFilter, error) {
+	if m == 0 || k == 0 || k > 64 || m > 1<<40 {
+		return nil, ErrInvalidParameter
+	}
+	return &BloomFilter{m: m, k: k, words: make([]uint64, (m+63)/64)}, nil
+}
+
+// Add 添加元素。
+// Add adds an item.
+// 参数 (param): data []byte - 元素。 the item.
+func (f *BloomFilter) Add(data []byte) {
+	h1, h2 := baseHashes(data)
+	for i := uint32(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		f.words[loc>>6] |= 1 << (loc & 63)
+	}
+	f.count++
+}
+
+// AddString 添加字符串元素。
+// AddString adds a string item.
+func (f *BloomFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Contains 判断元素是否可能存在；返回 false 时一定不存在。
+// Contains reports whether the item may be present; false means definitely absent.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): bool - 是否可能存在。 whether the item may be present.
+func (f *BloomFilter) Contains(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	for i := uint32(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		if f.words[loc>>6]&(1<<(loc&63)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsString 判断字符串元素是否可能存在。
+// ContainsString reports whether the string item may be present.
+func (f *BloomFilter) ContainsString(s string) bool {
+	return f.Contains([]byte(s))
+}
+
+// TestAndAdd 判断元素是否可能存在，然后添加它，常用于去重。
+// TestAndAdd reports whether the item may have been present, then adds it; useful for dedup.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): bool - 添加前是否可能存在。 whether the item may have been present.
+func (f *BloomFilter) TestAndAdd(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	present := true
+	for i := uint32(0); i < f.k; i++ {
+		loc := location(h1, h2, i, f.m)
+		word, mask := loc>>6, uint64(1)<<(loc&63)
+		if f.words[word]&mask == 0 {
+			present = false
+			f.words[word] |= mask
+		}
+	}
+	f.count++
+	return present
+}
+
+// Cap 返回位数。
+// Cap returns the number of bits.
+func (f *BloomFilter) Cap() uint64 {
+	return f.m
+}
+
+// K 返回哈希函数个数。
+// K returns the number of hash functions.
+func (f *BloomFilter) K() uint32 {
+	return f.k
+}
+
+// Count 返回调用 Add 的次数（合并后为各过滤器之和）。
+// Count returns the number of Add calls, summed over merged filters.
+func (f *BloomFilter) Count() uint64 {
+	return f.count
+}
+
+// EstimatedCount 根据已置位的位数估算不同元素的个数，合并后依然准确。
+// EstimatedCount estimates the number of distinct items from the number of set
+// bits; unlike Count it stays accurate after merges and duplicate adds.
+// 返回值 (return): uint64 - 估算值。 the estimate.
+func (f *BloomFilter) EstimatedCount() uint64 {
+	set := f.setBits()
+	if set == f.m {
+		return f.count
+	}
+	return uint64(math.Round(-float64(f.m) / float64(f.k) * math.Log(1-float64(set)/float64(f.m))))
+}
+
+// FalsePositiveRate 根据当前的填充率估算误判率。
+// FalsePositiveRate estimates the current false-positive rate from the fill ratio.
+// 返回值 (return): float64 - 误判率。 the false-positive rate.
+func (f *BloomFilter) FalsePositiveRate() float64 {
+	return math.Pow(float64(f.setBits())/float64(f.m), float64(f.k))
+}
+
+// Merge 把另一个参数相同的过滤器合并进来（并集）。
+// Merge adds the items of another filter with the same parameters (set union).
+// 参数 (param): other *BloomFilter - 另一个过滤器。 the other filter.
+// 返回值 (return): error - 参数不同时为 ErrIncompatible。 ErrIncompatible if the parameters differ.
+func (f *BloomFilter) Merge(other *BloomFilter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i, w := range other.words {
+		f.words[i] |= w
+	}
+	f.count += other.count
+	return nil
+}
+
+// Clone 返回过滤器的副本。
+// Clone returns a copy of the filter.
+func (f *BloomFilter) Clone() *BloomFilter {
+	c := *f
+	c.words = append([]uint64(nil), f.words...)
+	return &c
+}
+
+// Reset 清空过滤器。
+// Reset removes all items.
+func (f *BloomFilter) Reset() {
+	clear(f.words)
+	f.count = 0
+}
+
+// MarshalBinary 序列化过滤器。
+// MarshalBinary encodes the filter.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	buf := header(tagBloom, 20+8*len(f.words))
+	buf = appendUint64(buf, f.m)
+	buf = appendUint32(buf, f.k)
+	buf = appendUint64(buf, f.count)
+	for _, w := range f.words {
+		buf = appendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary 反序列化过滤器。
+// UnmarshalBinary decodes a filter encoded by MarshalBinary.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	r := newReader(data, tagBloom)
+	m, k, count := r.uint64(), r.uint32(), r.uint64()
+	if r.err == nil && uint64(len(r.data)) != (m+63)/64*8 {
+		return ErrInvalidData
+	}
+	decoded, err := NewBloomFilterWithSize(m, k)
+	if r.err != nil || err != nil {
+		return ErrInvalidData
+	}
+	for i := range decoded.words {
+		decoded.words[i] = r.uint64()
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	decoded.count = count
+	*f = *decoded
+	return nil
+}
+
+// setBits 返回已置位的位数。
+// setBits returns the number of set bits.
+func (f *BloomFilter) setBits() uint64 {
+	var n int
+	for _, w := range f.words {
+		n += bits.OnesCount64(w)
+	}
+	return uint64(n)
+}
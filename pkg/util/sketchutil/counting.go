@@ -0,0 +1,198 @@
+package sketchutil
+
+import (
+	"math"
+)
+
+// maxCounter 计数器的饱和值，饱和后的计数器不再减少，以免产生漏判。
+// maxCounter is the saturation value of a counter; saturated counters are never
+// decremented so that removals cannot cause false negatives.
+const maxCounter = math.MaxUint8
+
+// CountingBloomFilter 计数布隆过滤器：用 8 位计数器代替位，支持删除元素。非并发安全。
+// CountingBloomFilter is a counting Bloom filter: it uses 8-bit counters instead of
+// bits so items can be removed. It is not safe for concurrent use.
+type CountingBloomFilter struct {
+	k        uint32
+	counters []uint8
+	count    uint64
+}
+
+// NewCountingBloomFilter 按期望元素数和误判率创建计数布隆过滤器，占用内存是普通过滤器的 8 倍。
+// NewCountingBloomFilter creates a counting Bloom filter sized for the expected
+// number of items and false-positive rate; it uses 8 times the memory of a BloomFilter.
+// 参数 (param): n uint64 - 期望元素数。 the expected number of items.
+// 参数 (param): fpRate float64 - 误判率，取值 (0, 1)。 the false-positive rate in (0, 1).
+// 返回值 (return): *CountingBloomFilter - 新的过滤器。 the new filter.
+// 返回值 (return): error - 参数无效时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters.
+func NewCountingBloomFilter(n uint64, fpRate float64) (*CountingBloomFilter, error) {
+	if !(fpRate > 0 && fpRate < 1) {
+		return nil, ErrInvalidParameter
+	}
+	m, k := OptimalBloomSize(n, fpRate)
+	return NewCountingBloomFilterWithSize(m, k)
+}
+
+// NewCountingBloomFilterWithSize 按指定的计数器个数和哈希函数个数创建计数布隆过滤器。
+// NewCountingBloomFilterWithSize creates a counting Bloom filter with m counters and k hash functions.
+// 参数 (param): m uint64 - 计数器个数。 the number of counters.
+// 参数 (param): k uint32 - 哈希函数个数。 the number of hash functions.
+// 返回值 (return): *CountingBloomFilter - 新的过滤器。 the new filter.
+// 返回值 (return): error - 参数无效时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters.
+func NewCountingBloomFilterWithSize(m uint64, k uint32) (*CountingBloomFilter, error) {
+	if m == 0 || k == 0 || k > 64 || m > 1<<36 {
+		return nil, ErrInvalidParameter
+	}
+	return &CountingBloomFilter{k: k, counters: make([]uint8, m)}, nil
+}
+
+// Add 添加元素。
+// Add adds an item.
+// 参数 (param): data []byte - 元素。 the item.
+func (f *CountingBloomFilter) Add(data []byte) {
+	h1, h2 := baseHashes(data)
+	m := uint64(len(f.counters))
+	for i := uint32(0); i < f.k; i++ {
+		if loc := location(h1, h2, i, m); f.counters[loc] < maxCounter {
+			f.counters[loc]++
+		}
+	}
+	f.count++
+}
+
+// AddString 添加字符串元素。
+// AddString adds a string item.
+func (f *CountingBloomFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Remove 删除元素。只能删除添加过的元素，否则可能导致其他元素被漏判。
+// Remove removes an item. Only remove items that were added, otherwise other items
+// may become false negatives.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): bool - 元素不存在时为 false，此时过滤器不变。 false, leaving the filter unchanged, if the item is absent.
+func (f *CountingBloomFilter) Remove(data []byte) bool {
+	if !f.Contains(data) {
+		return false
+	}
+	h1, h2 := baseHashes(data)
+	m := uint64(len(f.counters))
+	for i := uint32(0); i < f.k; i++ {
+		if loc := location(h1, h2, i, m); f.counters[loc] < maxCounter {
+			f.counters[loc]--
+		}
+	}
+	if f.count > 0 {
+		f.count--
+	}
+	return true
+}
+
+// RemoveString 删除字符串元素。
+// RemoveString removes a string item.
+func (f *CountingBloomFilter) RemoveString(s string) bool {
+	return f.Remove([]byte(s))
+}
+
+// Contains 判断元素是否可能存在；返回 false 时一定不存在。
+// Contains reports whether the item may be present; false means definitely absent.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): bool - 是否可能存在。 whether the item may be present.
+func (f *CountingBloomFilter) Contains(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	m := uint64(len(f.counters))
+	for i := uint32(0); i < f.k; i++ {
+		if f.counters[location(h1, h2, i, m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsString 判断字符串元素是否可能存在。
+// ContainsString reports whether the string item may be present.
+func (f *CountingBloomFilter) ContainsString(s string) bool {
+	return f.Contains([]byte(s))
+}
+
+// Cap 返回计数器个数。
+// Cap returns the number of counters.
+func (f *CountingBloomFilter) Cap() uint64 {
+	return uint64(len(f.counters))
+}
+
+// K 返回哈希函数个数。
+// K returns the number of hash functions.
+func (f *CountingBloomFilter) K() uint32 {
+	return f.k
+}
+
+// Count 返回添加次数减去成功删除的次数。
+// Count returns the number of adds minus successful removals.
+func (f *CountingBloomFilter) Count() uint64 {
+	return f.count
+}
+
+// Merge 把另一个参数相同的过滤器合并进来，计数器饱和相加。
+// Merge adds the items of another filter with the same parameters; counters add with saturation.
+// 参数 (param): other *CountingBloomFilter - 另一个过滤器。 the other filter.
+// 返回值 (return): error - 参数不同时为 ErrIncompatible。 ErrIncompatible if the parameters differ.
+func (f *CountingBloomFilter) Merge(other *CountingBloomFilter) error {
+	if len(f.counters) != len(other.counters) || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		f.counters[i] = uint8(min(int(f.counters[i])+int(c), maxCounter))
+	}
+	f.count += other.count
+	return nil
+}
+
+// BloomFilter 把计数器转换为普通布隆过滤器，便于以更小的体积发布。
+// BloomFilter converts the counters to a plain Bloom filter, e.g. to publish a smaller snapshot.
+// 返回值 (return): *BloomFilter - 包含相同元素的过滤器。 a filter containing the same items.
+func (f *CountingBloomFilter) BloomFilter() *BloomFilter {
+	b, _ := NewBloomFilterWithSize(uint64(len(f.counters)), f.k)
+	for i, c := range f.counters {
+		if c != 0 {
+			b.words[i>>6] |= 1 << (uint(i) & 63)
+		}
+	}
+	b.count = f.count
+	return b
+}
+
+// Reset 清空过滤器。
+// Reset removes all items.
+func (f *CountingBloomFilter) Reset() {
+	clear(f.counters)
+	f.count = 0
+}
+
+// MarshalBinary 序列化过滤器。
+// MarshalBinary encodes the filter.
+func (f *CountingBloomFilter) MarshalBinary() ([]byte, error) {
+	buf := header(tagCounting, 20+len(f.counters))
+	buf = appendUint64(buf, uint64(len(f.counters)))
+	buf = appendUint32(buf, f.k)
+	buf = appendUint64(buf, f.count)
+	return append(buf, f.counters...), nil
+}
+
+// UnmarshalBinary 反序列化过滤器。
+// UnmarshalBinary decodes a filter encoded by MarshalBinary.
+func (f *CountingBloomFilter) UnmarshalBinary(data []byte) error {
+	r := newReader(data, tagCounting)
+	m, k, count := r.uint64(), r.uint32(), r.uint64()
+	if r.err != nil || uint64(len(r.data)) != m {
+		return ErrInvalidData
+	}
+	decoded, err := NewCountingBloomFilterWithSize(m, k)
+	if err != nil {
+		return ErrInvalidData
+	}
+	copy(decoded.counters, r.take(int(m)))
+	decoded.count = count
+	*f = *decoded
+	return nil
+}
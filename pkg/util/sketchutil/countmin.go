@@ -0,0 +1,164 @@
+package sketchutil
+
+import (
+	"math"
+)
+
+// CountMinSketch Count-Min Sketch：以固定内存估算元素出现的频次。估算值不会小于真实值，
+// 以 1-delta 的概率不超过真实值 + epsilon*总数。非并发安全。
+// CountMinSketch estimates item frequencies in fixed memory. Estimates never
+// undercount, and with probability 1-delta overcount by at most epsilon times the
+// total count. It is not safe for concurrent use.
+type CountMinSketch struct {
+	width    uint32
+	depth    uint32
+	counters []uint64 // depth 行 width 列 depth rows of width counters
+	total    uint64
+}
+
+// NewCountMinSketch 按误差和置信度创建 Count-Min Sketch。
+// NewCountMinSketch creates a Count-Min Sketch from its error bounds.
+// 参数 (param): epsilon float64 - 相对误差，取值 (0, 1)。 the relative error in (0, 1).
+// 参数 (param): delta float64 - 超出误差的概率，取值 (0, 1)。 the probability of exceeding the error, in (0, 1).
+// 返回值 (return): *CountMinSketch - 新的 sketch。 the new sketch.
+// 返回值 (return): error - 参数无效时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters.
+func NewCountMinSketch(epsilon, delta float64) (*CountMinSketch, error) {
+	if !(epsilon > 0 && epsilon < 1) || !(delta > 0 && delta < 1) {
+		return nil, ErrInvalidParameter
+	}
+	width := math.Ceil(math.E / epsilon)
+	depth := math.Ceil(math.Log(1 / delta))
+	if width > math.MaxUint32 {
+		return nil, ErrInvalidParameter
+	}
+	return NewCountMinSketchWithSize(uint32(width), uint32(depth))
+}
+
+// NewCountMinSketchWithSize 按指定的宽度和深度创建 Count-Min Sketch。
+// NewCountMinSketchWithSize creates a Count-Min Sketch with the given width and depth.
+// 参数 (param): width uint32 - 每行的计数器个数。 the number of counters per row.
+// 参数 (param): depth uint32 - 行数（哈希函数个数）。 the number of rows (hash functions).
+// 返回值 (return): *CountMinSketch - 新的 sketch。 the new sketch.
+// 返回值 (return): error - 参数无效时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters.
+func NewCountMinSketchWithSize(width, depth uint32) (*CountMinSketch, error) {
+	if width == 0 || depth == 0 || depth > 64 || uint64(width)*uint64(depth) > 1<<32 {
+		return nil, ErrInvalidParameter
+	}
+	return &CountMinSketch{width: width, depth: depth, counters: make([]uint64, uint64(width)*uint64(depth))}, nil
+}
+
+// Add 把元素的频次增加 count。
+// Add increments the frequency of an item by count.
+// 参数 (param): data []byte - 元素。 the item.
+// 参数 (param): count uint64 - 增量。 the increment.
+func (s *CountMinSketch) Add(data []byte, count uint64) {
+	h1, h2 := baseHashes(data)
+	for i := uint32(0); i < s.depth; i++ {
+		s.counters[s.index(h1, h2, i)] += count
+	}
+	s.total += count
+}
+
+// AddString 把字符串元素的频次增加 count。
+// AddString increments the frequency of a string item by count.
+func (s *CountMinSketch) AddString(str string, count uint64) {
+	s.Add([]byte(str), count)
+}
+
+// Estimate 估算元素的频次。
+// Estimate returns the estimated frequency of an item.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): uint64 - 估算值，不小于真实值。 the estimate, never below the true frequency.
+func (s *CountMinSketch) Estimate(data []byte) uint64 {
+	h1, h2 := baseHashes(data)
+	est := uint64(math.MaxUint64)
+	for i := uint32(0); i < s.depth; i++ {
+		est = min(est, s.counters[s.index(h1, h2, i)])
+	}
+	return est
+}
+
+// EstimateString 估算字符串元素的频次。
+// EstimateString returns the estimated frequency of a string item.
+func (s *CountMinSketch) EstimateString(str string) uint64 {
+	return s.Estimate([]byte(str))
+}
+
+// Width 返回每行的计数器个数。
+// Width returns the number of counters per row.
+func (s *CountMinSketch) Width() uint32 {
+	return s.width
+}
+
+// Depth 返回行数。
+// Depth returns the number of rows.
+func (s *CountMinSketch) Depth() uint32 {
+	return s.depth
+}
+
+// TotalCount 返回所有增量之和。
+// TotalCount returns the sum of all increments.
+func (s *CountMinSketch) TotalCount() uint64 {
+	return s.total
+}
+
+// Merge 把另一个尺寸相同的 sketch 合并进来，结果等价于对两者的输入一起计数。
+// Merge adds another sketch of the same size, as if both inputs had been counted together.
+// 参数 (param): other *CountMinSketch - 另一个 sketch。 the other sketch.
+// 返回值 (return): error - 尺寸不同时为 ErrIncompatible。 ErrIncompatible if the sizes differ.
+func (s *CountMinSketch) Merge(other *CountMinSketch) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		s.counters[i] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Reset 清空所有计数。
+// Reset clears all counts.
+func (s *CountMinSketch) Reset() {
+	clear(s.counters)
+	s.total = 0
+}
+
+// MarshalBinary 序列化 sketch。
+// MarshalBinary encodes the sketch.
+func (s *CountMinSketch) MarshalBinary() ([]byte, error) {
+	buf := header(tagCountMin, 16+8*len(s.counters))
+	buf = appendUint32(buf, s.width)
+	buf = appendUint32(buf, s.depth)
+	buf = appendUint64(buf, s.total)
+	for _, c := range s.counters {
+		buf = appendUint64(buf, c)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary 反序列化 sketch。
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary.
+func (s *CountMinSketch) UnmarshalBinary(data []byte) error {
+	r := newReader(data, tagCountMin)
+	width, depth, total := r.uint32(), r.uint32(), r.uint64()
+	if r.err != nil || uint64(len(r.data)) != uint64(width)*uint64(depth)*8 {
+		return ErrInvalidData
+	}
+	decoded, err := NewCountMinSketchWithSize(width, depth)
+	if err != nil {
+		return ErrInvalidData
+	}
+	for i := range decoded.counters {
+		decoded.counters[i] = r.uint64()
+	}
+	decoded.total = total
+	*s = *decoded
+	return nil
+}
+
+// index 返回第 row 行中元素对应的计数器下标。
+// index returns the counter index of an item in the given row.
+func (s *CountMinSketch) index(h1, h2 uint64, row uint32) uint64 {
+	return uint64(row)*uint64(s.width) + location(h1, h2, row, uint64(s.width))
+}
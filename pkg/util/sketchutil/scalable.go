@@ -0,0 +1,217 @@
+package sketchutil
+
+import (
+	"math"
+)
+
+// 可扩展布隆过滤器的增长参数（Almeida 等，2007）。
+// Growth parameters of the scalable Bloom filter (Almeida et al., 2007).
+const (
+	scalableGrowth     = 2   // 每个新过滤器的容量倍数 capacity multiplier of each new filter
+	scalableTightening = 0.8 // 每个新过滤器的误判率倍数 false-positive multiplier of each new filter
+)
+
+// ScalableBloomFilter 可扩展布隆过滤器：元素数超过容量时追加更大、误判率更低的过滤器，
+// 使总误判率始终不超过设定值（合并除外，见 Merge）。适用于无法预知元素数的场景。非并发安全。
+// ScalableBloomFilter is a scalable Bloom filter: when full it appends a larger filter
+// with a tighter false-positive rate, so the compound rate stays below the target no
+// matter how many items are added (merging aside, see Merge). It is not safe for concurrent use.
+type ScalableBloomFilter struct {
+	initial uint64
+	fpRate  float64
+	filters []*BloomFilter
+}
+
+// NewScalableBloomFilter 创建可扩展布隆过滤器。
+// NewScalableBloomFilter creates a scalable Bloom filter.
+// 参数 (param): initial uint64 - 第一个过滤器的容量。 the capacity of the first filter.
+// 参数 (param): fpRate float64 - 总误判率上限，取值 (0, 1)。 the bound on the compound false-positive rate, in (0, 1).
+// 返回值 (return): *ScalableBloomFilter - 新的过滤器。 the new filter.
+// 返回值 (return): error - 参数无效或第一个过滤器过大时为 ErrInvalidParameter。 ErrInvalidParameter for invalid parameters or a first filter that is too large.
+func NewScalableBloomFilter(initial uint64, fpRate float64) (*ScalableBloomFilter, error) {
+	if initial == 0 || !(fpRate > 0 && fpRate < 1) {
+		return nil, ErrInvalidParameter
+	}
+	f := &ScalableBloomFilter{initial: initial, fpRate: fpRate}
+	first, err := NewBloomFilter(f.capacity(0), f.filterRate(0))
+	if err != nil {
+		return nil, err
+	}
+	f.filters = []*BloomFilter{first}
+	return f, nil
+}
+
+// Add 添加元素，已可能存在的元素不会占用容量。
+// Add adds an item; items that may already be present do not use up capacity.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): bool - 添加前元素是否可能存在。 whether the item may have been present.
+func (f *ScalableBloomFilter) Add(data []byte) bool {
+	if f.Contains(data) {
+		return true
+	}
+	if n := len(f.filters); f.filters[n-1].count >= f.capacity(n-1) {
+		// 新过滤器过大或误判率下溢时继续使用最后一个过滤器。
+		// Keep filling the last filter once a new one would be too large or its rate underflows.
+		if filter, err := NewBloomFilter(f.capacity(n), f.filterRate(n)); err == nil {
+			f.filters = append(f.filters, filter)
+		}
+	}
+	f.filters[len(f.filters)-1].Add(data)
+	return false
+}
+
+// AddString 添加字符串元素。
+// AddString adds a string item.
+func (f *ScalableBloomFilter) AddString(s string) bool {
+	return f.Add([]byte(s))
+}
+
+// Contains 判断元素是否可能存在；返回 false 时一定不存在。
+// Contains reports whether the item may be present; false means definitely absent.
+// 参数 (param): data []byte - 元素。 the item.
+// 返回值 (return): bool - 是否可能存在。 whether the item may be present.
+func (f *ScalableBloomFilter) Contains(data []byte) bool {
+	for i := len(f.filters) - 1; i >= 0; i-- {
+		if f.filters[i].Contains(data) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsString 判断字符串元素是否可能存在。
+// ContainsString reports whether the string item may be present.
+func (f *ScalableBloomFilter) ContainsString(s string) bool {
+	return f.Contains([]byte(s))
+}
+
+// Count 返回添加的不同元素数（不含被误判为已存在的元素）。
+// Count returns the number of distinct items added, excluding those falsely reported as present.
+func (f *ScalableBloomFilter) Count() uint64 {
+	var n uint64
+	for _, filter := range f.filters {
+		n += filter.count
+	}
+	return n
+}
+
+// Filters 返回内部过滤器的个数。
+// Filters returns the number of internal filters.
+func (f *ScalableBloomFilter) Filters() int {
+	return len(f.filters)
+}
+
+// SizeBits 返回所有过滤器的总位数。
+// SizeBits returns the total number of bits of all filters.
+func (f *ScalableBloomFilter) SizeBits() uint64 {
+	var n uint64
+	for _, filter := range f.filters {
+		n += filter.m
+	}
+	return n
+}
+
+// FalsePositiveRate 根据各过滤器的填充率估算总误判率。
+// FalsePositiveRate estimates the compound false-positive rate from the fill ratios.
+// 返回值 (return): float64 - 误判率。 the false-positive rate.
+func (f *ScalableBloomFilter) FalsePositiveRate() float64 {
+	miss := 1.0
+	for _, filter := range f.filters {
+		miss *= 1 - filter.FalsePositiveRate()
+	}
+	return 1 - miss
+}
+
+// Merge 把另一个参数相同的过滤器合并进来：同一层的过滤器按位或，多出的层直接复制。
+// 注意：合并后的一层可能超过其容量，总误判率因此不再保证低于设定值；
+// FalsePositiveRate 会反映实际的估算值。
+// Merge adds the items of another filter created with the same parameters: filters at
+// the same level are OR-ed together and extra levels are copied.
+// Note that a merged level can hold more items than its capacity, so the compound
+// false-positive rate is no longer guaranteed to stay below the target after a merge;
+// FalsePositiveRate reports the actual estimate.
+// 参数 (param): other *ScalableBloomFilter - 另一个过滤器。 the other filter.
+// 返回值 (return): error - 参数不同时为 ErrIncompatible。 ErrIncompatible if the parameters differ.
+func (f *ScalableBloomFilter) Merge(other *ScalableBloomFilter) error {
+	if f.initial != other.initial || f.fpRate != other.fpRate {
+		return ErrIncompatible
+	}
+	for i, filter := range other.filters {
+		if i < len(f.filters) {
+			if err := f.filters[i].Merge(filter); err != nil {
+				return err
+			}
+		} else {
+			f.filters = append(f.filters, filter.Clone())
+		}
+	}
+	return nil
+}
+
+// Reset 清空过滤器，只保留第一个过滤器。
+// Reset removes all items, keeping only the first filter.
+func (f *ScalableBloomFilter) Reset() {
+	f.filters = f.filters[:1]
+	f.filters[0].Reset()
+}
+
+// MarshalBinary 序列化过滤器。
+// MarshalBinary encodes the filter.
+func (f *ScalableBloomFilter) MarshalBinary() ([]byte, error) {
+	buf := header(tagScalable, 20)
+	buf = appendUint64(buf, f.initial)
+	buf = appendUint64(buf, math.Float64bits(f.fpRate))
+	buf = appendUint32(buf, uint32(len(f.filters)))
+	for _, filter := range f.filters {
+		data, _ := filter.MarshalBinary()
+		buf = appendUint32(buf, uint32(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary 反序列化过滤器。每一层的位数和哈希函数个数须与其容量和误判率相符。
+// UnmarshalBinary decodes a filter encoded by MarshalBinary. The bit count and number of
+// hash functions of each level must match its capacity and false-positive rate.
+func (f *ScalableBloomFilter) UnmarshalBinary(data []byte) error {
+	r := newReader(data, tagScalable)
+	initial, fpRate, n := r.uint64(), math.Float64frombits(r.uint64()), r.uint32()
+	if r.err != nil {
+		return r.err
+	}
+	decoded, err := NewScalableBloomFilter(initial, fpRate)
+	if err != nil || n == 0 || uint64(n) > uint64(len(r.data)) {
+		return ErrInvalidData
+	}
+	decoded.filters = decoded.filters[:0]
+	for i := uint32(0); i < n; i++ {
+		filter := new(BloomFilter)
+		if err := filter.UnmarshalBinary(r.take(int(r.uint32()))); err != nil {
+			return ErrInvalidData
+		}
+		if m, k := OptimalBloomSize(decoded.capacity(int(i)), decoded.filterRate(int(i))); filter.m != m || filter.k != k {
+			return ErrInvalidData
+		}
+		decoded.filters = append(decoded.filters, filter)
+	}
+	if err := r.done(); err != nil {
+		return err
+	}
+	*f = *decoded
+	return nil
+}
+
+// capacity 返回第 i 个过滤器的容量。
+// capacity returns the capacity of the i-th filter.
+func (f *ScalableBloomFilter) capacity(i int) uint64 {
+	return f.initial * uint64(math.Pow(scalableGrowth, float64(i)))
+}
+
+// filterRate 返回第 i 个过滤器的误判率。各层误判率构成公比为 scalableTightening 的
+// 等比数列，首项为 fpRate*(1-scalableTightening)，因此总和不超过 fpRate。
+// filterRate returns the false-positive rate of the i-th filter. The rates form a
+// geometric series with ratio scalableTightening starting at fpRate*(1-scalableTightening),
+// so their sum never exceeds fpRate.
+func (f *ScalableBloomFilter) filterRate(i int) float64 {
+	return f.fpRate * (1 - scalableTightening) * math.Pow(scalableTightening, float64(i))
+}
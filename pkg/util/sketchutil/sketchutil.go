@@ -0,0 +1,134 @@
+// Package sketchutil 提供内存有界的概率数据结构：布隆过滤器（含计数与可扩展变体）、
+// Count-Min Sketch 和 HyperLogLog。所有结构都基于 hashutil 的哈希函数，可合并，
+// 并实现了 encoding.BinaryMarshaler 以便持久化。
+// Package sketchutil provides memory-bounded probabilistic data structures: Bloom
+// filters (with counting and scalable variants), Count-Min Sketch and HyperLogLog.
+// All of them use hashutil hashes, can be merged, and implement
+// encoding.BinaryMarshaler for persistence.
+package sketchutil
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"GoFast/pkg/util/hashutil"
+)
+
+var (
+	// ErrIncompatible 合并参数不同的结构时返回。
+	// ErrIncompatible is returned when merging structures with different parameters.
+	ErrIncompatible = errors.New("sketchutil: incompatible parameters")
+	// ErrInvalidData 反序列化的数据无效时返回。
+	// ErrInvalidData is returned when unmarshaling invalid data.
+	ErrInvalidData = errors.New("sketchutil: invalid serialized data")
+	// ErrInvalidParameter 构造参数无效时返回。
+	// ErrInvalidParameter is returned for invalid constructor parameters.
+	ErrInvalidParameter = errors.New("sketchutil: invalid parameter")
+)
+
+// 序列化格式的类型标记和版本。
+// Type tags and version of the serialized formats.
+const (
+	formatVersion = 1
+
+	tagBloom       = 'B'
+	tagCounting    = 'C'
+	tagScalable    = 'S'
+	tagCountMin    = 'M'
+	tagHyperLogLog = 'H'
+)
+
+// baseHashes 使用 MurmurHash3 128 位计算两个独立的哈希值，
+// 其余位置通过双重哈希 h1 + i*h2 得到（Kirsch–Mitzenmacher）。
+// baseHashes computes two independent hashes with 128-bit MurmurHash3; further
+// positions are derived by double hashing h1 + i*h2 (Kirsch–Mitzenmacher).
+func baseHashes(data []byte) (uint64, uint64) {
+	h1, h2 := hashutil.Murmur3Hash128(data, 0)
+	// 置 h2 为奇数保证其非零，避免所有位置都退化为 h1。m 不一定是 2 的幂，
+	// h2 与 m 有公因子时位置仍可能重复，对误判率的影响可以忽略。
+	// Forcing h2 odd keeps it non-zero, so the positions never all collapse to h1. m is
+	// not a power of two, so positions can still repeat when h2 shares a factor with m;
+	// the effect on the false-positive rate is negligible.
+	return h1, h2 | 1
+}
+
+// location 返回第 i 个哈希位置。
+// location returns the i-th hash position in [0, m).
+func location(h1, h2 uint64, i uint32, m uint64) uint64 {
+	return (h1 + uint64(i)*h2) % m
+}
+
+// header 写入类型标记和版本。
+// header starts a serialized value with its type tag and version.
+func header(tag byte, size int) []byte {
+	buf := make([]byte, 0, 2+size)
+	return append(buf, tag, formatVersion)
+}
+
+// appendUint32 以大端序追加 uint32。
+// appendUint32 appends v in big-endian order.
+func appendUint32(buf []byte, v uint32) []byte {
+	return binary.BigEndian.AppendUint32(buf, v)
+}
+
+// appendUint64 以大端序追加 uint64。
+// appendUint64 appends v in big-endian order.
+func appendUint64(buf []byte, v uint64) []byte {
+	return binary.BigEndian.AppendUint64(buf, v)
+}
+
+// reader 按大端序读取序列化数据，出错后所有读取都返回零值。
+// reader reads big-endian serialized data; after an error every read returns zero.
+type reader struct {
+	data []byte
+	err  error
+}
+
+// newReader 校验类型标记和版本并返回读取器。
+// newReader checks the type tag and version and returns a reader for the rest.
+func newReader(data []byte, tag byte) *reader {
+	if len(data) < 2 || data[0] != tag || data[1] != formatVersion {
+		return &reader{err: ErrInvalidData}
+	}
+	return &reader{data: data[2:]}
+}
+
+func (r *reader) take(n int) []byte {
+	if r.err != nil || n < 0 || len(r.data) < n {
+		r.err = ErrInvalidData
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint8() uint8 {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// done 检查数据是否恰好读完。
+// done reports the first error, or ErrInvalidData if bytes are left over.
+func (r *reader) done() error {
+	if r.err == nil && len(r.data) != 0 {
+		r.err = ErrInvalidData
+	}
+	return r.err
+}
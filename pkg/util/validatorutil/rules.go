@@ -0,0 +1,435 @@
+package validatorutil
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"GoFast/pkg/errorhandler"
+	netutils "GoFast/pkg/net"
+	"GoFast/pkg/util/creditcodeutil"
+	"GoFast/pkg/util/idcardutil"
+)
+
+// 内置规则的错误码。
+// Error codes of the built-in rules.
+const (
+	CodeRequired        = 3001
+	CodeMin             = 3002
+	CodeMax             = 3003
+	CodeLen             = 3004
+	CodeEq              = 3005
+	CodeNe              = 3006
+	CodeGt              = 3007
+	CodeGte             = 3008
+	CodeLt              = 3009
+	CodeLte             = 3010
+	CodeOneOf           = 3011
+	CodeEmail           = 3012
+	CodeURL             = 3013
+	CodeIP              = 3014
+	CodeIPv4            = 3015
+	CodeIPv6            = 3016
+	CodeIdCard          = 3017
+	CodeCreditCode      = 3018
+	CodeMobile          = 3019
+	CodeUUID            = 3020
+	CodeAlpha           = 3021
+	CodeAlphaNum        = 3022
+	CodeNumeric         = 3023
+	CodeRegexp          = 3024
+	CodeEqField         = 3025
+	CodeNeField         = 3026
+	CodeGtField         = 3027
+	CodeGteField        = 3028
+	CodeLtField         = 3029
+	CodeLteField        = 3030
+	CodeRequiredWith    = 3031
+	CodeRequiredWithout = 3032
+)
+
+var (
+	emailPattern  = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9](?:[a-zA-Z0-9\-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9\-]*[a-zA-Z0-9])?)*\.[a-zA-Z]{2,}$`)
+	mobilePattern = regexp.MustCompile(`^(?:\+?86)?1[3-9]\d{9}$`)
+	uuidPattern   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	regexpCache sync.Map // pattern -> *regexp.Regexp
+
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// builtinRules 内置规则表。required、omitempty 和 dive 由校验器直接处理。
+var builtinRules = map[string]rule{
+	"required":         {code: CodeRequired},
+	"min":              {code: CodeMin, fn: compareRule(func(c int) bool { return c >= 0 }), check: checkNumber},
+	"max":              {code: CodeMax, fn: compareRule(func(c int) bool { return c <= 0 }), check: checkNumber},
+	"len":              {code: CodeLen, fn: compareRule(func(c int) bool { return c == 0 }), check: checkNumber},
+	"eq":               {code: CodeEq, fn: equalRule(true), check: checkEqual},
+	"ne":               {code: CodeNe, fn: equalRule(false), check: checkEqual},
+	"gt":               {code: CodeGt, fn: compareRule(func(c int) bool { return c > 0 }), check: checkNumber},
+	"gte":              {code: CodeGte, fn: compareRule(func(c int) bool { return c >= 0 }), check: checkNumber},
+	"lt":               {code: CodeLt, fn: compareRule(func(c int) bool { return c < 0 }), check: checkNumber},
+	"lte":              {code: CodeLte, fn: compareRule(func(c int) bool { return c <= 0 }), check: checkNumber},
+	"oneof":            {code: CodeOneOf, fn: oneOf},
+	"email":            {code: CodeEmail, fn: stringRule(emailPattern.MatchString)},
+	"url":              {code: CodeURL, fn: stringRule(isURL)},
+	"ip":               {code: CodeIP, fn: stringRule(netutils.IsValidIP)},
+	"ipv4":             {code: CodeIPv4, fn: stringRule(isIPv4)},
+	"ipv6":             {code: CodeIPv6, fn: stringRule(isIPv6)},
+	"idcard":           {code: CodeIdCard, fn: stringRule(idcardutil.IsValidCard)},
+	"creditcode":       {code: CodeCreditCode, fn: stringRule(creditcodeutil.ValidateCreditCode)},
+	"mobile":           {code: CodeMobile, fn: stringRule(mobilePattern.MatchString)},
+	"uuid":             {code: CodeUUID, fn: stringRule(uuidPattern.MatchString)},
+	"alpha":            {code: CodeAlpha, fn: stringRule(allRunes(unicode.IsLetter))},
+	"alphanum":         {code: CodeAlphaNum, fn: stringRule(allRunes(func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }))},
+	"numeric":          {code: CodeNumeric, fn: stringRule(isNumeric)},
+	"regexp":           {code: CodeRegexp, fn: matchRegexp, check: checkRegexp},
+	"eqfield":          {code: CodeEqField, fn: fieldRule(false, func(c int) bool { return c == 0 }), check: checkSibling},
+	"nefield":          {code: CodeNeField, fn: fieldRule(false, func(c int) bool { return c != 0 }), check: checkSibling},
+	"gtfield":          {code: CodeGtField, fn: fieldRule(true, func(c int) bool { return c > 0 }), check: checkSibling},
+	"gtefield":         {code: CodeGteField, fn: fieldRule(true, func(c int) bool { return c >= 0 }), check: checkSibling},
+	"ltfield":          {code: CodeLtField, fn: fieldRule(true, func(c int) bool { return c < 0 }), check: checkSibling},
+	"ltefield":         {code: CodeLteField, fn: fieldRule(true, func(c int) bool { return c <= 0 }), check: checkSibling},
+	"required_with":    {code: CodeRequiredWith, fn: requiredWith(true), check: checkSibling},
+	"required_without": {code: CodeRequiredWithout, fn: requiredWith(false), check: checkSibling},
+}
+
+func init() {
+	errorhandler.RegisterLocalizedMessages("en", map[int]string{
+		CodeCustomRule:      "{field} is invalid",
+		CodeRequired:        "{field} is required",
+		CodeMin:             "{field} must be at least {param}",
+		CodeMax:             "{field} must be at most {param}",
+		CodeLen:             "{field} must be exactly {param}",
+		CodeEq:              "{field} must equal {param}",
+		CodeNe:              "{field} must not equal {param}",
+		CodeGt:              "{field} must be greater than {param}",
+		CodeGte:             "{field} must be greater than or equal to {param}",
+		CodeLt:              "{field} must be less than {param}",
+		CodeLte:             "{field} must be less than or equal to {param}",
+		CodeOneOf:           "{field} must be one of [{param}]",
+		CodeEmail:           "{field} must be a valid email address",
+		CodeURL:             "{field} must be a valid URL",
+		CodeIP:              "{field} must be a valid IP address",
+		CodeIPv4:            "{field} must be a valid IPv4 address",
+		CodeIPv6:            "{field} must be a valid IPv6 address",
+		CodeIdCard:          "{field} must be a valid ID card number",
+		CodeCreditCode:      "{field} must be a valid unified social credit code",
+		CodeMobile:          "{field} must be a valid mobile number",
+		CodeUUID:            "{field} must be a valid UUID",
+		CodeAlpha:           "{field} must contain only letters",
+		CodeAlphaNum:        "{field} must contain only letters and digits",
+		CodeNumeric:         "{field} must be numeric",
+		CodeRegexp:          "{field} has an invalid format",
+		CodeEqField:         "{field} must equal {param}",
+		CodeNeField:         "{field} must not equal {param}",
+		CodeGtField:         "{field} must be greater than {param}",
+		CodeGteField:        "{field} must be greater than or equal to {param}",
+		CodeLtField:         "{field} must be less than {param}",
+		CodeLteField:        "{field} must be less than or equal to {param}",
+		CodeRequiredWith:    "{field} is required when {param} is present",
+		CodeRequiredWithout: "{field} is required when {param} is absent",
+	})
+	errorhandler.RegisterLocalizedMessages("zh", map[int]string{
+		CodeCustomRule:      "{field}无效",
+		CodeRequired:        "{field}为必填字段",
+		CodeMin:             "{field}最小为{param}",
+		CodeMax:             "{field}最大为{param}",
+		CodeLen:             "{field}必须为{param}",
+		CodeEq:              "{field}必须等于{param}",
+		CodeNe:              "{field}不能等于{param}",
+		CodeGt:              "{field}必须大于{param}",
+		CodeGte:             "{field}必须大于或等于{param}",
+		CodeLt:              "{field}必须小于{param}",
+		CodeLte:             "{field}必须小于或等于{param}",
+		CodeOneOf:           "{field}必须是[{param}]中的一个",
+		CodeEmail:           "{field}必须是有效的邮箱地址",
+		CodeURL:             "{field}必须是有效的URL",
+		CodeIP:              "{field}必须是有效的IP地址",
+		CodeIPv4:            "{field}必须是有效的IPv4地址",
+		CodeIPv6:            "{field}必须是有效的IPv6地址",
+		CodeIdCard:          "{field}必须是有效的身份证号码",
+		CodeCreditCode:      "{field}必须是有效的统一社会信用代码",
+		CodeMobile:          "{field}必须是有效的手机号码",
+		CodeUUID:            "{field}必须是有效的UUID",
+		CodeAlpha:           "{field}只能包含字母",
+		CodeAlphaNum:        "{field}只能包含字母和数字",
+		CodeNumeric:         "{field}必须是数字",
+		CodeRegexp:          "{field}格式不正确",
+		CodeEqField:         "{field}必须等于{param}",
+		CodeNeField:         "{field}不能等于{param}",
+		CodeGtField:         "{field}必须大于{param}",
+		CodeGteField:        "{field}必须大于或等于{param}",
+		CodeLtField:         "{field}必须小于{param}",
+		CodeLteField:        "{field}必须小于或等于{param}",
+		CodeRequiredWith:    "当{param}存在时{field}为必填字段",
+		CodeRequiredWithout: "当{param}不存在时{field}为必填字段",
+	})
+}
+
+// compareRule 构造数值比较规则：数字比较数值，字符串比较字符数，集合比较长度，
+// time.Duration 的参数可写作 "1s"。
+func compareRule(accept func(c int) bool) RuleFunc {
+	return func(ctx FieldContext) bool {
+		v := ctx.Value
+		var actual, limit float64
+		switch v.Kind() {
+		case reflect.String:
+			actual = float64(utf8.RuneCountInString(v.String()))
+		case reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+			actual = float64(v.Len())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			actual = float64(v.Int())
+			if v.Type() == durationType {
+				d, err := time.ParseDuration(ctx.Param)
+				if err != nil {
+					return false
+				}
+				return accept(compareFloat(actual, float64(d)))
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			actual = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			actual = v.Float()
+		default:
+			return false
+		}
+		limit, err := strconv.ParseFloat(ctx.Param, 64)
+		if err != nil {
+			return false
+		}
+		return accept(compareFloat(actual, limit))
+	}
+}
+
+// equalRule 构造 eq / ne 规则：字符串比较内容，其余类型与 compareRule 相同。
+func equalRule(want bool) RuleFunc {
+	numeric := compareRule(func(c int) bool { return c == 0 })
+	return func(ctx FieldContext) bool {
+		if ctx.Value.Kind() == reflect.String {
+			return (ctx.Value.String() == ctx.Param) == want
+		}
+		return numeric(ctx) == want
+	}
+}
+
+// checkNumber 校验比较规则的参数：time.Duration 字段为时长，其余为数字。
+func checkNumber(param string, t reflect.Type, _ reflect.Value) error {
+	if t == durationType {
+		_, err := time.ParseDuration(param)
+		return err
+	}
+	_, err := strconv.ParseFloat(param, 64)
+	return err
+}
+
+// checkEqual 校验 eq / ne 的参数，字符串字段接受任意参数。
+func checkEqual(param string, t reflect.Type, parent reflect.Value) error {
+	if t != nil && t.Kind() == reflect.String {
+		return nil
+	}
+	return checkNumber(param, t, parent)
+}
+
+// compareFloat 比较两个浮点数，返回 -1、0 或 1。
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// oneOf 校验值是否在空格分隔的候选列表中。
+func oneOf(ctx FieldContext) bool {
+	var actual string
+	switch ctx.Value.Kind() {
+	case reflect.String:
+		actual = ctx.Value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = strconv.FormatInt(ctx.Value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = strconv.FormatUint(ctx.Value.Uint(), 10)
+	default:
+		return false
+	}
+	for _, candidate := range strings.Fields(ctx.Param) {
+		if candidate == actual {
+			return true
+		}
+	}
+	return false
+}
+
+// stringRule 将字符串判断函数包装为规则，非字符串字段校验失败。
+func stringRule(fn func(string) bool) RuleFunc {
+	return func(ctx FieldContext) bool {
+		return ctx.Value.Kind() == reflect.String && fn(ctx.Value.String())
+	}
+}
+
+// allRunes 判断字符串非空且所有字符都满足条件。
+func allRunes(fn func(rune) bool) func(string) bool {
+	return func(s string) bool {
+		if s == "" {
+			return false
+		}
+		for _, r := range s {
+			if !fn(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// isURL 判断是否为带协议和主机的绝对 URL。
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
+
+// isIPv4 判断是否为 IPv4 地址。
+func isIPv4(s string) bool {
+	return netutils.IsValidIP(s) && strings.Contains(s, ".") && !strings.Contains(s, ":")
+}
+
+// isIPv6 判断是否为 IPv6 地址。
+func isIPv6(s string) bool {
+	return netutils.IsValidIP(s) && strings.Contains(s, ":")
+}
+
+// isNumeric 判断字符串是否为十进制数字（可带符号和小数点）。
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil && !strings.ContainsAny(s, "eEinfINFxX")
+}
+
+// matchRegexp 校验字符串是否匹配参数中的正则表达式。
+func matchRegexp(ctx FieldContext) bool {
+	if ctx.Value.Kind() != reflect.String {
+		return false
+	}
+	cached, ok := regexpCache.Load(ctx.Param)
+	if !ok {
+		re, err := regexp.Compile(ctx.Param)
+		if err != nil {
+			return false
+		}
+		cached, _ = regexpCache.LoadOrStore(ctx.Param, re)
+	}
+	return cached.(*regexp.Regexp).MatchString(ctx.Value.String())
+}
+
+// checkRegexp 校验正则表达式参数能否编译。
+func checkRegexp(param string, _ reflect.Type, _ reflect.Value) error {
+	_, err := regexp.Compile(param)
+	return err
+}
+
+// fieldRule 构造跨字段比较规则，参数为同一结构体中的字段名。ordered 表示规则需要大小比较。
+func fieldRule(ordered bool, accept func(c int) bool) RuleFunc {
+	return func(ctx FieldContext) bool {
+		other, ok := siblingField(ctx)
+		if !ok {
+			return false
+		}
+		c, ok := compareValues(ctx.Value, indirect(other), ordered)
+		return ok && accept(c)
+	}
+}
+
+// requiredWith 构造 required_with / required_without 规则。
+func requiredWith(whenPresent bool) RuleFunc {
+	return func(ctx FieldContext) bool {
+		other, ok := siblingField(ctx)
+		if !ok {
+			return false
+		}
+		if isZero(other) == whenPresent {
+			return true
+		}
+		return !isZero(ctx.Value)
+	}
+}
+
+// siblingField 返回父结构体中参数指定的字段。
+func siblingField(ctx FieldContext) (reflect.Value, bool) {
+	if !ctx.Parent.IsValid() || ctx.Parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	other := ctx.Parent.FieldByName(ctx.Param)
+	return other, other.IsValid()
+}
+
+// checkSibling 校验跨字段规则的参数是父结构体中的字段。dive 之后的元素和 Var 的值没有父结构体。
+func checkSibling(param string, _ reflect.Type, parent reflect.Value) error {
+	if !parent.IsValid() || parent.Kind() != reflect.Struct {
+		return errors.New("cross-field rules need a parent struct and cannot follow dive")
+	}
+	if _, ok := parent.Type().FieldByName(param); !ok {
+		return fmt.Errorf("no field %q in %s", param, parent.Type().Name())
+	}
+	return nil
+}
+
+// compareValues 比较两个同类值，支持数字、字符串和 time.Time；其余类型只能比较相等，
+// 在需要大小比较时返回 false。
+func compareValues(a, b reflect.Value, ordered bool) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+	if ta, ok := a.Interface().(time.Time); ok {
+		tb, ok := b.Interface().(time.Time)
+		if !ok {
+			return 0, false
+		}
+		return ta.Compare(tb), true
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		fa, okA := toFloat(a)
+		fb, okB := toFloat(b)
+		if !okA || !okB {
+			return 0, false
+		}
+		return compareFloat(fa, fb), true
+	case reflect.String:
+		if b.Kind() != reflect.String {
+			return 0, false
+		}
+		return strings.Compare(a.String(), b.String()), true
+	}
+	if ordered || a.Type() != b.Type() {
+		return 0, false
+	}
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return 0, true
+	}
+	return 1, true
+}
+
+// toFloat 将数值类型转换为 float64。
+func toFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,525 @@
+package validatorutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"GoFast/pkg/errorhandler"
+)
+
+// ValidatorUtil 包提供基于结构体标签的校验功能。
+// ValidatorUtil package provides tag-based struct validation.
+//
+// 标签示例 (tag example):
+//
+//	type User struct {
+//		Name   string   `validate:"required,min=1,max=64"`
+//		Email  string   `validate:"omitempty,email"`
+//		Role   string   `validate:"oneof=admin user guest"`
+//		Pass   string   `validate:"required,min=8"`
+//		Repeat string   `validate:"eqfield=Pass"`
+//		Tags   []string `validate:"max=5,dive,min=1"`
+//	}
+
+// ErrInvalidTag 标签本身有误，例如未知规则、参数格式错误或无法使用的跨字段规则。
+// ErrInvalidTag is returned for a malformed tag, such as an unknown rule, a malformed
+// parameter or a cross-field rule that has no parent struct.
+var ErrInvalidTag = errors.New("validatorutil: invalid tag")
+
+// CodeCustomRule 自定义规则未指定错误码时使用的错误码。
+// CodeCustomRule is the error code used for custom rules registered without a code.
+const CodeCustomRule = 3000
+
+// RuleFunc 校验规则函数，返回 true 表示校验通过。
+// RuleFunc is a validation rule; it returns true when the field is valid.
+type RuleFunc func(ctx FieldContext) bool
+
+// FieldContext 校验规则的上下文。
+// FieldContext carries the field being validated to a RuleFunc.
+type FieldContext struct {
+	Value  reflect.Value // 字段值（指针已解引用） the field value, with pointers dereferenced
+	Param  string        // 规则参数，例如 min=1 中的 "1" the rule parameter, e.g. "1" in min=1
+	Field  string        // 字段名 the field name
+	Path   string        // 字段路径，例如 "Items[0].Name" the field path, e.g. "Items[0].Name"
+	Parent reflect.Value // 字段所在的结构体，可用于跨字段校验 the struct containing the field, for cross-field rules
+}
+
+// rule 已注册的规则。check 在应用规则前校验参数，自定义规则为 nil。
+type rule struct {
+	code  int
+	fn    RuleFunc
+	check paramCheck
+}
+
+// paramCheck 校验规则参数是否适用于字段类型 t 和所在结构体 parent。
+type paramCheck func(param string, t reflect.Type, parent reflect.Value) error
+
+// Validator 校验器，保存已注册的规则和解析过的标签缓存。
+// Validator holds the registered rules and a cache of parsed struct tags.
+type Validator struct {
+	mu      sync.RWMutex
+	rules   map[string]rule
+	tagName string
+	cache   sync.Map // reflect.Type -> []fieldSpec
+}
+
+// New 创建包含全部内置规则的校验器。
+// New creates a Validator with all built-in rules registered.
+// 返回值 (return): *Validator - 新的校验器。
+// 返回值 (return): *Validator - the new Validator.
+func New() *Validator {
+	v := &Validator{rules: make(map[string]rule), tagName: "validate"}
+	for name, r := range builtinRules {
+		v.rules[name] = r
+	}
+	return v
+}
+
+// SetTagName 设置读取规则的结构体标签名，默认为 "validate"。
+// SetTagName sets the struct tag read for rules; the default is "validate".
+// 参数 (param): name string - 标签名。
+// 参数 (param): name string - the tag name.
+func (v *Validator) SetTagName(name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tagName = name
+	v.cache.Range(func(key, _ interface{}) bool {
+		v.cache.Delete(key)
+		return true
+	})
+}
+
+// RegisterRule 注册或替换一个规则。错误码用于通过 errorhandler 获取本地化消息。
+// RegisterRule registers or replaces a rule. The code is used to look up localized
+// messages through errorhandler.
+// 参数 (param): name string - 规则名。 the rule name.
+// 参数 (param): code int - 错误码，0 表示使用 CodeCustomRule。 the error code, 0 means CodeCustomRule.
+// 参数 (param): fn RuleFunc - 规则函数。 the rule function.
+func (v *Validator) RegisterRule(name string, code int, fn RuleFunc) {
+	if code == 0 {
+		code = CodeCustomRule
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule{code: code, fn: fn}
+}
+
+// Struct 校验结构体及其嵌套的结构体、切片和映射。
+// Struct validates a struct and the structs nested in its fields, slices and maps.
+// 参数 (param): obj interface{} - 结构体或结构体指针。
+// 参数 (param): obj interface{} - a struct or a pointer to a struct.
+// 返回值 (return): error - 校验失败时为 ValidationErrors，否则为 nil。
+// 返回值 (return): error - ValidationErrors when validation fails, otherwise nil.
+func (v *Validator) Struct(obj interface{}) error {
+	val := reflect.ValueOf(obj)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return errors.New("validatorutil: object must be a non-nil struct")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return errors.New("validatorutil: object must be a struct")
+	}
+
+	var errs ValidationErrors
+	if err := v.validateStruct(val, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Var 使用标签语法校验单个值，例如 Var(email, "required,email")。
+// Var validates a single value with tag syntax, e.g. Var(email, "required,email").
+// 参数 (param): value interface{} - 待校验的值。 the value to validate.
+// 参数 (param): tag string - 规则标签。 the rules.
+// 返回值 (return): error - 校验失败时为 ValidationErrors，否则为 nil。
+// 返回值 (return): error - ValidationErrors when validation fails, otherwise nil.
+func (v *Validator) Var(value interface{}, tag string) error {
+	rules, err := parseRules(tag)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTag, err)
+	}
+	var errs ValidationErrors
+	if err := v.validateField(reflect.ValueOf(value), reflect.Value{}, "", "", rules, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// fieldSpec 结构体字段的解析结果。
+type fieldSpec struct {
+	index int
+	name  string
+	rules []ruleSpec
+	skip  bool
+}
+
+// ruleSpec 单个规则及其参数。
+type ruleSpec struct {
+	name  string
+	param string
+}
+
+// specsFor 返回结构体类型的字段规则，并缓存解析结果。
+func (v *Validator) specsFor(t reflect.Type) ([]fieldSpec, error) {
+	if cached, ok := v.cache.Load(t); ok {
+		return cached.([]fieldSpec), nil
+	}
+	// 持有读锁直到写入缓存，避免 SetTagName 之后仍缓存按旧标签名解析的结果
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	tagName := v.tagName
+
+	var specs []fieldSpec
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get(tagName)
+		spec := fieldSpec{index: i, name: field.Name, skip: tag == "-"}
+		if !spec.skip {
+			rules, err := parseRules(tag)
+			if err != nil {
+				return nil, fmt.Errorf("%w: field %s.%s: %v", ErrInvalidTag, t.Name(), field.Name, err)
+			}
+			spec.rules = rules
+		}
+		specs = append(specs, spec)
+	}
+	v.cache.Store(t, specs)
+	return specs, nil
+}
+
+// parseRules 解析规则标签，逗号可用 "\," 转义。
+func parseRules(tag string) ([]ruleSpec, error) {
+	var rules []ruleSpec
+	var current strings.Builder
+	flush := func() error {
+		part := strings.TrimSpace(current.String())
+		current.Reset()
+		if part == "" {
+			return nil
+		}
+		name, param, _ := strings.Cut(part, "=")
+		if name == "" {
+			return fmt.Errorf("invalid rule %q", part)
+		}
+		rules = append(rules, ruleSpec{name: strings.TrimSpace(name), param: param})
+		return nil
+	}
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// validateStruct 校验结构体的每个字段。
+func (v *Validator) validateStruct(sv reflect.Value, path string, errs *ValidationErrors) error {
+	specs, err := v.specsFor(sv.Type())
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.skip {
+			continue
+		}
+		fieldPath := joinPath(path, spec.name)
+		if err := v.validateField(sv.Field(spec.index), sv, spec.name, fieldPath, spec.rules, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateField 对字段应用规则，然后递归校验其中的结构体。
+func (v *Validator) validateField(fv, parent reflect.Value, name, path string, rules []ruleSpec, errs *ValidationErrors) error {
+	for i, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if isZero(fv) {
+				return nil
+			}
+			continue
+		case "dive":
+			return v.dive(fv, path, rules[i+1:], errs)
+		case "required":
+			if isZero(fv) {
+				errs.add(path, name, r, builtinRules["required"].code, fv)
+				return nil
+			}
+			continue
+		}
+
+		v.mu.RLock()
+		registered, ok := v.rules[r.name]
+		v.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("%w: unknown rule %q on %s", ErrInvalidTag, r.name, path)
+		}
+		if registered.check != nil {
+			if err := registered.check(r.param, fieldType(fv), parent); err != nil {
+				return fmt.Errorf("%w: rule %q on %s: %v", ErrInvalidTag, r.name, path, err)
+			}
+		}
+
+		value := indirect(fv)
+		if !value.IsValid() {
+			// nil 指针只由 required 和跨字段规则处理
+			if !strings.HasPrefix(r.name, "required_") {
+				continue
+			}
+		}
+		ctx := FieldContext{Value: value, Param: r.param, Field: name, Path: path, Parent: parent}
+		if !registered.fn(ctx) {
+			errs.add(path, name, r, registered.code, fv)
+		}
+	}
+	return v.descend(fv, path, errs)
+}
+
+// dive 对切片、数组或映射的每个元素应用剩余规则。
+func (v *Validator) dive(fv reflect.Value, path string, rules []ruleSpec, errs *ValidationErrors) error {
+	value := indirect(fv)
+	if !value.IsValid() {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if err := v.validateField(value.Index(i), reflect.Value{}, elemPath, elemPath, rules, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			elemPath := fmt.Sprintf("%s[%v]", path, iter.Key().Interface())
+			if err := v.validateField(iter.Value(), reflect.Value{}, elemPath, elemPath, rules, errs); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("validatorutil: dive used on non-collection field %s", path)
+	}
+	return nil
+}
+
+// descend 递归校验字段中嵌套的结构体。
+func (v *Validator) descend(fv reflect.Value, path string, errs *ValidationErrors) error {
+	value := indirect(fv)
+	if !value.IsValid() {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type().PkgPath() == "time" {
+			return nil
+		}
+		return v.validateStruct(value, path, errs)
+	case reflect.Slice, reflect.Array:
+		if !containsStructs(value.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := v.descend(value.Index(i), fmt.Sprintf("%s[%d]", path, i), errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !containsStructs(value.Type().Elem()) {
+			return nil
+		}
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := v.descend(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key().Interface()), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// containsStructs 判断类型（解引用后）是否为需要递归校验的结构体。
+func containsStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Interface
+}
+
+// indirect 解引用指针和接口，nil 时返回无效值。
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// fieldType 返回字段解引用后的类型；nil 指针返回其元素类型，无效值返回 nil。
+func fieldType(v reflect.Value) reflect.Type {
+	if value := indirect(v); value.IsValid() {
+		return value.Type()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// isZero 判断值是否为零值（nil 指针、空字符串、空集合等）。
+func isZero(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.IsNil() || v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// joinPath 拼接字段路径。
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// defaultValidator 包级函数使用的默认校验器。
+var defaultValidator = New()
+
+// Validate 使用默认校验器校验结构体。
+// Validate validates a struct with the default Validator.
+// 参数 (param): obj interface{} - 结构体或结构体指针。
+// 参数 (param): obj interface{} - a struct or a pointer to a struct.
+// 返回值 (return): error - 校验失败时为 ValidationErrors，否则为 nil。
+// 返回值 (return): error - ValidationErrors when validation fails, otherwise nil.
+func Validate(obj interface{}) error {
+	return defaultValidator.Struct(obj)
+}
+
+// ValidateVar 使用默认校验器校验单个值。
+// ValidateVar validates a single value with the default Validator.
+// 参数 (param): value interface{} - 待校验的值。 the value to validate.
+// 参数 (param): tag string - 规则标签。 the rules.
+// 返回值 (return): error - 校验失败时为 ValidationErrors，否则为 nil。
+// 返回值 (return): error - ValidationErrors when validation fails, otherwise nil.
+func ValidateVar(value interface{}, tag string) error {
+	return defaultValidator.Var(value, tag)
+}
+
+// RegisterRule 在默认校验器上注册规则。
+// RegisterRule registers a rule on the default Validator.
+// 参数 (param): name string - 规则名。 the rule name.
+// 参数 (param): code int - 错误码，0 表示使用 CodeCustomRule。 the error code, 0 means CodeCustomRule.
+// 参数 (param): fn RuleFunc - 规则函数。 the rule function.
+func RegisterRule(name string, code int, fn RuleFunc) {
+	defaultValidator.RegisterRule(name, code, fn)
+}
+
+// FieldError 单个字段的校验错误。
+// FieldError describes a single failed rule.
+type FieldError struct {
+	Path  string      // 字段路径 the field path, e.g. "Address.City"
+	Field string      // 字段名 the field name
+	Rule  string      // 失败的规则 the failed rule
+	Param string      // 规则参数 the rule parameter
+	Code  int         // 错误码 the error code used for localization
+	Value interface{} // 字段值 the field value
+}
+
+// Error 返回英文错误消息。
+// Error returns the English error message.
+func (e *FieldError) Error() string {
+	return e.Localize("en")
+}
+
+// Localize 通过 errorhandler 返回指定语言的错误消息，消息模板中的 {field} 和 {param} 会被替换。
+// Localize returns the message for the language registered in errorhandler,
+// replacing {field} and {param} in the template.
+// 参数 (param): lang string - 语言代码，例如 "en"、"zh"。 the language code, e.g. "en", "zh".
+// 返回值 (return): string - 本地化消息。 the localized message.
+func (e *FieldError) Localize(lang string) string {
+	if !errorhandler.HasLocalizedMessage(e.Code, lang) {
+		return fmt.Sprintf("%s: failed on the '%s' rule", e.Path, e.Rule)
+	}
+	message := errorhandler.GetLocalizedMessage(e.Code, lang)
+	return strings.NewReplacer("{field}", e.Path, "{param}", e.Param).Replace(message)
+}
+
+// ValidationErrors 校验错误列表。
+// ValidationErrors is the list of failed rules returned by Validate.
+type ValidationErrors []*FieldError
+
+// Error 返回所有错误消息，以分号分隔。
+// Error returns all messages separated by semicolons.
+func (e ValidationErrors) Error() string {
+	return strings.Join(e.Localize("en"), "; ")
+}
+
+// Localize 返回所有错误的本地化消息。
+// Localize returns the localized message of every error.
+// 参数 (param): lang string - 语言代码。 the language code.
+// 返回值 (return): []string - 本地化消息列表。 the localized messages.
+func (e ValidationErrors) Localize(lang string) []string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Localize(lang)
+	}
+	return messages
+}
+
+// Fields 返回按字段路径分组的错误。
+// Fields returns the errors grouped by field path.
+// 返回值 (return): map[string][]*FieldError - 字段路径到错误的映射。 the errors keyed by path.
+func (e ValidationErrors) Fields() map[string][]*FieldError {
+	result := make(map[string][]*FieldError, len(e))
+	for _, err := range e {
+		result[err.Path] = append(result[err.Path], err)
+	}
+	return result
+}
+
+// add 追加一个字段错误。
+func (e *ValidationErrors) add(path, name string, r ruleSpec, code int, value reflect.Value) {
+	var v interface{}
+	if value.IsValid() && value.CanInterface() {
+		v = value.Interface()
+	}
+	*e = append(*e, &FieldError{Path: path, Field: name, Rule: r.name, Param: r.param, Code: code, Value: v})
+}
@@ -0,0 +1,276 @@
+package config_test
+
+import (
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"GoFast/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.json": `{"server": {"host": "localhost", "port": 8080}, "tags": ["a", "b"]}`,
+		"app.yaml": "server:\n  host: localhost\n  port: 8080\ntags:\n  - a\n  - b\n",
+		"app.toml": "tags = ['a', \"b\"]\n\n[server]\nhost = \"localhost\" # comment\nport = 8_080\n",
+		"app.ini":  "tags = a,b\n\n[server]\nhost = \"localhost\"\nport: 8080\n",
+		"app.env":  "SERVER_HOST=localhost\nexport SERVER_PORT=8080 # comment\nTAGS='a,b'\n",
+	}
+	for name, content := range files {
+		cfg := config.New(config.NewFileSource(writeFile(t, dir, name, content)))
+		assert.NoError(t, cfg.Load(), name)
+		assert.Equal(t, "localhost", cfg.GetString("server.host", ""), name)
+		assert.Equal(t, 8080, cfg.GetInt("server.port", 0), name)
+		assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("tags"), name)
+	}
+}
+
+func TestTOMLDocument(t *testing.T) {
+	doc := `
+title = "TOML"
+multi = """
+Roses are red\
+   Violets are blue"""
+path = 'C:\Users\app'
+hex = 0xff
+ratio = 1.5e2
+enabled = true
+created = 1979-05-27 07:32:00Z
+site."google.com" = true
+point = { x = 1, y = [1, 2,] }
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+name = "Nail"
+[products.meta]
+sku = 284758393
+`
+	cfg := config.New(config.NewFileSource(writeFile(t, t.TempDir(), "doc.toml", doc)))
+	assert.NoError(t, cfg.Load())
+	assert.Equal(t, "Roses are redViolets are blue", cfg.GetString("multi", ""))
+	assert.Equal(t, `C:\Users\app`, cfg.GetString("path", ""))
+	assert.Equal(t, 255, cfg.GetInt("hex", 0))
+	assert.Equal(t, 150.0, cfg.GetFloat("ratio", 0))
+	assert.True(t, cfg.GetBool("enabled", false))
+	assert.Equal(t, "1979-05-27 07:32:00Z", cfg.GetString("created", ""))
+	assert.Equal(t, 1, cfg.GetInt("point.x", 0))
+
+	var doc2 struct {
+		Created  time.Time
+		Products []struct {
+			Name string
+			Meta struct {
+				Sku int64
+			}
+		}
+	}
+	assert.NoError(t, cfg.Bind(&doc2))
+	assert.Equal(t, 1979, doc2.Created.Year())
+	assert.Len(t, doc2.Products, 2)
+	assert.Equal(t, "Nail", doc2.Products[1].Name)
+	assert.Equal(t, int64(284758393), doc2.Products[1].Meta.Sku)
+
+	for _, bad := range []string{"a = \nb = 1", "a = [1, 2", "a = 1\na = 2", "[t\nx = 1"} {
+		cfg := config.New(config.NewFileSource(writeFile(t, t.TempDir(), "bad.toml", bad)))
+		assert.Error(t, cfg.Load(), bad)
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := writeFile(t, dir, "app.yaml", "server:\n  host: file\n  port: 1000\n  mode: file\n  name: file\n")
+	envPath := writeFile(t, dir, ".env", "APP_SERVER_PORT=2000\nAPP_SERVER_MODE=dotenv\n")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("server-name", "default-flag", "")
+	fs.Int("server.port", 0, "")
+	assert.NoError(t, fs.Parse([]string{"-server.port=4000"}))
+
+	env := &config.EnvSource{Prefix: "APP", Environ: func() []string {
+		return []string{"APP_SERVER_PORT=3000", "OTHER_SERVER_HOST=ignored"}
+	}}
+
+	// Registration order is deliberately reversed; priority decides precedence
+	cfg := config.New(
+		config.NewFlagSource(fs),
+		env,
+		config.NewDotEnvSource(envPath, "APP"),
+		config.NewFileSource(yamlPath),
+		config.NewMapSource(map[string]interface{}{"server.host": "default", "server.timeout": "5s"}),
+	)
+	assert.NoError(t, cfg.Load())
+	assert.Equal(t, "file", cfg.GetString("server.host", ""))
+	assert.Equal(t, "dotenv", cfg.GetString("server.mode", ""))
+	assert.Equal(t, 4000, cfg.GetInt("server.port", 0))
+	assert.Equal(t, "file", cfg.GetString("server.name", ""), "unset flags must not override")
+	assert.Equal(t, 5*time.Second, cfg.GetDuration("server.timeout", 0))
+	assert.False(t, cfg.IsSet("server.missing"))
+
+	section, ok := cfg.Get("server")
+	assert.True(t, ok)
+	assert.Equal(t, "dotenv", section.(map[string]interface{})["mode"])
+}
+
+func TestEnvAndFlagsOverrideUnderscoreKeys(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := writeFile(t, dir, "app.yaml", "max_connections: 10\ndatabase:\n  max_conns: 5\n  dsn: file\n")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("database-max-conns", 0, "")
+	assert.NoError(t, fs.Parse([]string{"-database-max-conns=7"}))
+	env := &config.EnvSource{Prefix: "APP", Environ: func() []string {
+		return []string{"APP_MAX_CONNECTIONS=99", "APP_DATABASE_TIMEOUT=1m"}
+	}}
+
+	cfg := config.New(config.NewFileSource(yamlPath), env, config.NewFlagSource(fs))
+	assert.NoError(t, cfg.Load())
+	assert.Equal(t, 99, cfg.GetInt("max_connections", 0))
+	assert.Equal(t, 7, cfg.GetInt("database.max_conns", 0))
+	assert.Equal(t, []string{"database.dsn", "database.max_conns", "database.timeout", "max_connections"}, cfg.Keys())
+
+	var db DatabaseConfig
+	assert.NoError(t, cfg.BindKey("database", &db))
+	assert.Equal(t, DatabaseConfig{DSN: "file", MaxConns: 7, Timeout: time.Minute}, db)
+
+	// A key only set by the environment is found under either spelling
+	env.Environ = func() []string { return []string{"APP_POOL_IDLE_TIMEOUT=30s"} }
+	cfg = config.New(env)
+	assert.NoError(t, cfg.Load())
+	assert.Equal(t, 30*time.Second, cfg.GetDuration("pool.idle_timeout", 0))
+	assert.Equal(t, 30*time.Second, cfg.GetDuration("pool.idle.timeout", 0))
+}
+
+type DatabaseConfig struct {
+	DSN      string        `config:"dsn" required:"true"`
+	MaxConns int           `config:"max_conns" default:"10"`
+	Timeout  time.Duration `config:"timeout" default:"30s"`
+}
+
+type AppConfig struct {
+	Name       string            `config:"name" default:"gofast"`
+	Debug      bool              `config:"debug"`
+	UploadSize config.ByteSize   `config:"upload_size" default:"10MB"`
+	Hosts      []string          `config:"hosts" default:"a, b"`
+	Ports      []int             `config:"ports"`
+	Labels     map[string]string `config:"labels"`
+	Ratio      *float64          `config:"ratio"`
+	Database   DatabaseConfig    `config:"database"`
+	Ignored    string            `config:"-"`
+}
+
+func TestBind(t *testing.T) {
+	cfg := config.New(config.NewMapSource(map[string]interface{}{
+		"debug":              "yes",
+		"ports":              "80, 443",
+		"labels":             map[string]interface{}{"env": "prod"},
+		"ratio":              0.5,
+		"upload_size":        "1.5GB",
+		"database.dsn":       "postgres://localhost",
+		"database.timeout":   "1m",
+		"ignored":            "value",
+		"database.max_conns": int64(25),
+	}))
+	assert.NoError(t, cfg.Load())
+
+	var app AppConfig
+	assert.NoError(t, cfg.Bind(&app))
+	assert.Equal(t, "gofast", app.Name)
+	assert.True(t, app.Debug)
+	assert.Equal(t, config.ByteSize(1.5*float64(config.GigaByte)), app.UploadSize)
+	assert.Equal(t, []string{"a", "b"}, app.Hosts)
+	assert.Equal(t, []int{80, 443}, app.Ports)
+	assert.Equal(t, map[string]string{"env": "prod"}, app.Labels)
+	assert.Equal(t, 0.5, *app.Ratio)
+	assert.Equal(t, "postgres://localhost", app.Database.DSN)
+	assert.Equal(t, 25, app.Database.MaxConns)
+	assert.Equal(t, time.Minute, app.Database.Timeout)
+	assert.Empty(t, app.Ignored)
+
+	var db DatabaseConfig
+	assert.NoError(t, cfg.BindKey("database", &db))
+	assert.Equal(t, app.Database, db)
+}
+
+func TestBindErrors(t *testing.T) {
+	cfg := config.New(config.NewMapSource(map[string]interface{}{"ports": "80,http", "debug": "maybe"}))
+	assert.NoError(t, cfg.Load())
+
+	var app AppConfig
+	err := cfg.Bind(&app)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ports[1]")
+	assert.Contains(t, err.Error(), "debug")
+	assert.Contains(t, err.Error(), "database.dsn")
+
+	var db DatabaseConfig
+	err = cfg.BindKey("database", &db)
+	assert.ErrorIs(t, err, config.ErrRequired)
+}
+
+func TestByteSize(t *testing.T) {
+	cases := map[string]config.ByteSize{
+		"512":                 512,
+		"1KB":                 config.KiloByte,
+		"10MB":                10 * config.MegaByte,
+		"2 GiB":               2 * config.GigaByte,
+		"0.5k":                512,
+		"1T":                  config.TeraByte,
+		"8191PB":              8191 * config.PetaByte,
+		"9223372036854775807": math.MaxInt64,
+	}
+	for input, expected := range cases {
+		size, err := config.ParseByteSize(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, size, input)
+	}
+	for _, input := range []string{"", "MB", "10XB", "-1MB", "8192PB", "8192.0PB", "9223372036854775808", "99999999999999999999KB"} {
+		_, err := config.ParseByteSize(input)
+		assert.Error(t, err, input)
+	}
+	assert.Equal(t, "10MB", (10 * config.MegaByte).String())
+	assert.Equal(t, "1500B", config.ByteSize(1500).String())
+}
+
+func TestIntOverflow(t *testing.T) {
+	cfg := config.New(config.NewMapSource(map[string]interface{}{
+		"min":      float64(math.MinInt64),
+		"over":     float64(1 << 63),
+		"over_str": "9223372036854775808.0",
+		"frac":     1.5,
+	}))
+	assert.NoError(t, cfg.Load())
+	assert.Equal(t, -1, cfg.GetInt("over", -1))
+	assert.Equal(t, -1, cfg.GetInt("over_str", -1))
+	assert.Equal(t, -1, cfg.GetInt("frac", -1))
+
+	var out struct {
+		Min  int64 `config:"min"`
+		Over int64 `config:"over"`
+	}
+	err := cfg.Bind(&out)
+	assert.Error(t, err)
+	assert.Equal(t, int64(math.MinInt64), out.Min)
+}
+
+func TestMissingFile(t *testing.T) {
+	source := config.NewFileSource(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, config.New(source).Load())
+
+	source.Optional = true
+	assert.NoError(t, config.New(source).Load())
+}
@@ -0,0 +1,166 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GoFast/pkg/config"
+	"GoFast/pkg/errorhandler"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHandler collects errors reported through errorhandler
+type recordingHandler struct {
+	errs []error
+}
+
+func (h *recordingHandler) HandleError(err error) {
+	h.errs = append(h.errs, err)
+}
+
+func TestDiff(t *testing.T) {
+	event := config.Diff(
+		map[string]interface{}{"a": 1, "b": "x", "c": []interface{}{1}},
+		map[string]interface{}{"b": "y", "c": []interface{}{1}, "d": true},
+	)
+	assert.Equal(t, []string{"d"}, event.Added)
+	assert.Equal(t, []string{"a"}, event.Removed)
+	assert.Equal(t, []string{"b"}, event.Modified)
+	assert.Equal(t, []string{"a", "b", "d"}, event.Changed())
+	assert.True(t, config.Diff(map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1}).Empty())
+}
+
+func TestWatcherReload(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "app.yaml", "server:\n  port: 8080\n  host: localhost\n")
+	cfg := config.New(config.NewFileSource(path))
+	assert.NoError(t, cfg.Load())
+
+	cfg.AddValidator(func(next *config.Config) error {
+		if next.GetInt("server.port", 0) <= 0 {
+			return errors.New("server.port must be positive")
+		}
+		return nil
+	})
+
+	var events []config.ChangeEvent
+	unsubscribe := cfg.Subscribe(func(e config.ChangeEvent) { events = append(events, e) })
+
+	handler := &recordingHandler{}
+	errorhandler.RegisterErrorHandler(handler)
+
+	watcher := config.NewWatcher(cfg, time.Hour)
+	changed, err := watcher.Check()
+	assert.False(t, changed)
+	assert.NoError(t, err)
+
+	// A valid edit is applied and reported as a diff
+	writeFile(t, "", path, "server:\n  port: 9090\n  debug: true\n")
+	changed, err = watcher.Check()
+	assert.True(t, changed)
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, cfg.GetInt("server.port", 0))
+	assert.Len(t, events, 1)
+	assert.Equal(t, []string{"server.debug"}, events[0].Added)
+	assert.Equal(t, []string{"server.host"}, events[0].Removed)
+	assert.Equal(t, []string{"server.port"}, events[0].Modified)
+
+	// A broken file keeps the previous snapshot and surfaces the error
+	writeFile(t, "", path, "server: [unclosed\n")
+	changed, err = watcher.Check()
+	assert.True(t, changed)
+	var customErr *errorhandler.CustomError
+	assert.True(t, errors.As(err, &customErr))
+	assert.Equal(t, config.ErrCodeReloadFailed, customErr.Code)
+	assert.Equal(t, 9090, cfg.GetInt("server.port", 0))
+	assert.Len(t, handler.errs, 1)
+
+	// A file rejected by a validator is not applied either
+	writeFile(t, "", path, "server:\n  port: -1\n")
+	_, err = watcher.Check()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "server.port must be positive")
+	assert.Equal(t, 9090, cfg.GetInt("server.port", 0))
+
+	// Rewriting identical content does not trigger a reload
+	writeFile(t, "", path, "server:\n  port: -1\n")
+	changed, _ = watcher.Check()
+	assert.False(t, changed)
+
+	unsubscribe()
+	writeFile(t, "", path, "server:\n  port: 7070\n")
+	_, err = watcher.Check()
+	assert.NoError(t, err)
+	assert.Equal(t, 7070, cfg.GetInt("server.port", 0))
+	assert.Len(t, events, 1)
+}
+
+func TestWatcherPolling(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "app.json", `{"level": "info"}`)
+	cfg := config.New(config.NewFileSource(path))
+	assert.NoError(t, cfg.Load())
+
+	updates := make(chan config.ChangeEvent, 1)
+	cfg.Subscribe(func(e config.ChangeEvent) { updates <- e })
+
+	watcher := cfg.Watch(10 * time.Millisecond)
+	defer watcher.Stop()
+
+	writeFile(t, "", path, `{"level": "debug"}`)
+	select {
+	case event := <-updates:
+		assert.Equal(t, []string{"level"}, event.Modified)
+		assert.Equal(t, "debug", cfg.GetString("level", ""))
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not pick up the change")
+	}
+
+	// Non-positive intervals fall back to DefaultWatchInterval instead of panicking in the ticker
+	for _, interval := range []time.Duration{0, -time.Second} {
+		cfg.Watch(interval).Stop()
+	}
+}
+
+func TestSubscriberIsolation(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "app.yaml", "hosts: [a, b]\nport: 1\n")
+	cfg := config.New(config.NewFileSource(path))
+	assert.NoError(t, cfg.Load())
+
+	reloaded := make(chan error, 1)
+	cfg.Subscribe(func(e config.ChangeEvent) {
+		// Events carry copies, so subscribers cannot modify the live configuration
+		e.New["port"] = 99
+		e.New["hosts"].([]interface{})[0] = "mutated"
+		delete(e.Old, "port")
+		// Reload from a subscriber must not deadlock
+		_, err := cfg.Reload()
+		reloaded <- err
+	})
+	var second config.ChangeEvent
+	cfg.Subscribe(func(e config.ChangeEvent) { second = e })
+
+	writeFile(t, "", path, "hosts: [a, b]\nport: 2\n")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		event, err := cfg.Reload()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"port"}, event.Modified)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reload from a subscriber deadlocked")
+	}
+	assert.NoError(t, <-reloaded)
+
+	assert.Equal(t, 2, cfg.GetInt("port", 0))
+	assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("hosts"))
+	assert.Equal(t, 2, second.New["port"])
+	assert.Equal(t, 1, second.Old["port"])
+	assert.Equal(t, "a", second.New["hosts"].([]interface{})[0])
+
+	all := cfg.All()
+	all["hosts"].([]interface{})[1] = "mutated"
+	assert.Equal(t, []string{"a", "b"}, cfg.GetStringSlice("hosts"))
+}
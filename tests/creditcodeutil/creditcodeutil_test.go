@@ -2,12 +2,13 @@ package creditcodeutil_test
 
 import (
 	"GoFast/pkg/util/creditcodeutil"
+	"errors"
 	"testing"
 )
 
 // TestValidateCreditCode 测试 ValidateCreditCode 方法
 func TestValidateCreditCode(t *testing.T) {
-	validCode := "91310110MA1GL3QW03"       // 18位社会信用代码
+	validCode := "91310110MA1GL3QW32"       // 18位社会信用代码
 	invalidCodeLength := "123456789"        // 长度不足
 	invalidCodeChar := "91310110MA1GL3QW0@" // 含有非法字符
 
@@ -18,6 +19,12 @@ func TestValidateCreditCode(t *testing.T) {
 		{validCode, true},
 		{invalidCodeLength, false},
 		{invalidCodeChar, false},
+		{"9144030071526726XG", true},
+		{"91330100716105852F", true},
+		{"12100000400000624D", true},
+		{"91310110MA1GL3QW33", false}, // 校验码错误
+		{"91310110MA1GL3QWO2", false}, // 含有不使用的字母 O
+		{"9144030071526726xg", false}, // 小写字母
 	}
 
 	for _, test := range tests {
@@ -38,7 +45,75 @@ func TestRandomCreditCode(t *testing.T) {
 	}
 
 	// 测试生成的代码是否可以通过 ValidateCreditCode 校验
-	if !creditcodeutil.ValidateCreditCode(code) {
-		t.Errorf("RandomCreditCode() 返回的代码未能通过校验: %s", code)
+	for i := 0; i < 1000; i++ {
+		if code := creditcodeutil.RandomCreditCode(); !creditcodeutil.ValidateCreditCode(code) {
+			t.Fatalf("RandomCreditCode() 返回的代码未能通过校验: %s", code)
+		}
+	}
+}
+
+// TestParseCreditCode 测试 ParseCreditCode 方法
+func TestParseCreditCode(t *testing.T) {
+	cc, err := creditcodeutil.ParseCreditCode("9144030071526726XG")
+	if err != nil {
+		t.Fatalf("ParseCreditCode() 返回错误: %v", err)
+	}
+	expected := creditcodeutil.CreditCode{
+		Code:                      "9144030071526726XG",
+		RegistrationAuthority:     '9',
+		RegistrationAuthorityName: "工商",
+		OrganizationType:          '1',
+		OrganizationTypeName:      "企业",
+		RegionCode:                "440300",
+		OrganizationCode:          "71526726X",
+		CheckCode:                 'G',
+	}
+	if *cc != expected {
+		t.Errorf("ParseCreditCode() = %+v, 预期 %+v", *cc, expected)
+	}
+
+	tests := []struct {
+		code string
+		err  error
+	}{
+		{"91310110", creditcodeutil.ErrInvalidLength},
+		{"91310110MA1GL3QW0@", creditcodeutil.ErrInvalidChar},
+		{"B1310110MA1GL3QW32", creditcodeutil.ErrInvalidRegistrationAuthority},
+		{"94310110MA1GL3QW32", creditcodeutil.ErrInvalidOrganizationType},
+		{"91990110MA1GL3QW32", creditcodeutil.ErrInvalidRegionCode},
+		{"91310110MA1GL3QW42", creditcodeutil.ErrInvalidOrganizationCode},
+		{"91310110MA1GL3QW33", creditcodeutil.ErrInvalidCheckCode},
+	}
+	for _, test := range tests {
+		if _, err := creditcodeutil.ParseCreditCode(test.code); !errors.Is(err, test.err) {
+			t.Errorf("ParseCreditCode(%s) 错误为 %v, 预期 %v", test.code, err, test.err)
+		}
+	}
+}
+
+// TestCheckCodes 测试校验码计算方法
+func TestCheckCodes(t *testing.T) {
+	if check, err := creditcodeutil.CreditCodeCheckCode("91330100716105852"); err != nil || check != 'F' {
+		t.Errorf("CreditCodeCheckCode() = %c, %v, 预期 F", check, err)
+	}
+	if check, err := creditcodeutil.OrganizationCodeCheckDigit("71526726"); err != nil || check != 'X' {
+		t.Errorf("OrganizationCodeCheckDigit() = %c, %v, 预期 X", check, err)
+	}
+	if _, err := creditcodeutil.OrganizationCodeCheckDigit("7152672"); err == nil {
+		t.Errorf("OrganizationCodeCheckDigit() 未对长度错误的代码返回错误")
+	}
+}
+
+// TestGenerateCreditCode 测试 GenerateCreditCode 方法
+func TestGenerateCreditCode(t *testing.T) {
+	code, err := creditcodeutil.GenerateCreditCode('9', '1', "440300", "71526726")
+	if err != nil || code != "9144030071526726XG" {
+		t.Errorf("GenerateCreditCode() = %s, %v, 预期 9144030071526726XG", code, err)
+	}
+	if _, err := creditcodeutil.GenerateCreditCode('9', '9', "440300", "71526726"); !errors.Is(err, creditcodeutil.ErrInvalidOrganizationType) {
+		t.Errorf("GenerateCreditCode() 错误为 %v, 预期 %v", err, creditcodeutil.ErrInvalidOrganizationType)
+	}
+	if _, err := creditcodeutil.GenerateCreditCode('9', '1', "440300", "7152672I"); !errors.Is(err, creditcodeutil.ErrInvalidOrganizationCode) {
+		t.Errorf("GenerateCreditCode() 错误为 %v, 预期 %v", err, creditcodeutil.ErrInvalidOrganizationCode)
 	}
 }
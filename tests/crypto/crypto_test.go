@@ -0,0 +1,254 @@
+package crypto_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"GoFast/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var algorithms = []crypto.Algorithm{
+	crypto.AES128GCM,
+	crypto.AES256GCM,
+	crypto.ChaCha20Poly1305,
+	crypto.XChaCha20Poly1305,
+}
+
+// fastParams are deliberately cheap so the tests run quickly
+var fastParams = []crypto.KDFParams{
+	{Algorithm: crypto.KDFPBKDF2, Time: 1000},
+	{Algorithm: crypto.KDFScrypt, Time: 10, Memory: 8, Threads: 1},
+	{Algorithm: crypto.KDFArgon2id, Time: 1, Memory: 64, Threads: 1},
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte("the quick brown fox jumps over the lazy dog")
+	for _, alg := range algorithms {
+		key, err := crypto.GenerateKey(alg)
+		assert.NoError(t, err)
+		assert.Len(t, key, alg.KeySize())
+
+		sealed, err := crypto.Encrypt(alg, key, plaintext)
+		assert.NoError(t, err, alg.String())
+		opened, err := crypto.Decrypt(key, sealed)
+		assert.NoError(t, err, alg.String())
+		assert.Equal(t, plaintext, opened, alg.String())
+
+		env, err := crypto.ParseEnvelope(sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, alg, env.Algorithm)
+		assert.Equal(t, uint8(crypto.Version), env.Version)
+		assert.Equal(t, sealed, env.Marshal())
+
+		// Same plaintext, fresh nonce
+		again, _ := crypto.Encrypt(alg, key, plaintext)
+		assert.NotEqual(t, sealed, again)
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	key, _ := crypto.GenerateKey(crypto.AES256GCM)
+	sealed, _ := crypto.Encrypt(crypto.AES256GCM, key, []byte("secret"))
+
+	otherKey, _ := crypto.GenerateKey(crypto.AES256GCM)
+	_, err := crypto.Decrypt(otherKey, sealed)
+	assert.ErrorIs(t, err, crypto.ErrAuthFailed)
+
+	// Flipping a bit in the ciphertext or in the authenticated header both fail
+	for _, i := range []int{len(sealed) - 1, 4} {
+		tampered := bytes.Clone(sealed)
+		tampered[i] ^= 1
+		_, err = crypto.Decrypt(key, tampered)
+		assert.Error(t, err)
+	}
+
+	_, err = crypto.Decrypt(key, sealed[:10])
+	assert.ErrorIs(t, err, crypto.ErrInvalidEnvelope)
+	_, err = crypto.Decrypt(key, []byte("not an envelope at all"))
+	assert.ErrorIs(t, err, crypto.ErrInvalidEnvelope)
+	_, err = crypto.Decrypt(key[:16], sealed)
+	assert.ErrorIs(t, err, crypto.ErrInvalidKey)
+	_, err = crypto.Encrypt(crypto.Algorithm(99), key, nil)
+	assert.ErrorIs(t, err, crypto.ErrUnsupportedAlgorithm)
+}
+
+func TestPasswordEncryption(t *testing.T) {
+	password := []byte("correct horse battery staple")
+	for _, params := range fastParams {
+		sealed, err := crypto.EncryptWithPassword(crypto.ChaCha20Poly1305, password, params, []byte("hello"))
+		assert.NoError(t, err, params.Algorithm.String())
+
+		env, _ := crypto.ParseEnvelope(sealed)
+		assert.Equal(t, params, env.KDF)
+		assert.Len(t, env.Salt, crypto.DefaultSaltSize)
+
+		opened, err := crypto.DecryptWithPassword(password, sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello"), opened)
+
+		_, err = crypto.DecryptWithPassword([]byte("wrong"), sealed)
+		assert.ErrorIs(t, err, crypto.ErrAuthFailed)
+		_, err = crypto.Decrypt(make([]byte, 32), sealed)
+		assert.ErrorIs(t, err, crypto.ErrInvalidEnvelope)
+	}
+
+	_, err := crypto.EncryptWithPassword(crypto.AES256GCM, password, crypto.KDFParams{Algorithm: crypto.KDFPBKDF2, Time: 1}, nil)
+	assert.Error(t, err)
+}
+
+func TestKDFPolicy(t *testing.T) {
+	policy := crypto.DefaultKDFPolicy()
+	for _, params := range []crypto.KDFParams{crypto.DefaultPBKDF2Params(), crypto.DefaultScryptParams(), crypto.DefaultArgon2idParams()} {
+		assert.NoError(t, policy.Check(params), params.Algorithm.String())
+	}
+
+	password := []byte("correct horse battery staple")
+	sealed, err := crypto.EncryptWithPassword(crypto.AES256GCM, password, fastParams[2], []byte("hello"))
+	assert.NoError(t, err)
+	var stream bytes.Buffer
+	w, _ := crypto.NewPasswordEncryptWriter(&stream, crypto.AES256GCM, password, fastParams[2])
+	_ = w.Close()
+
+	// a crafted header must be rejected before any key is derived
+	for _, params := range []crypto.KDFParams{
+		{Algorithm: crypto.KDFPBKDF2, Time: 0xFFFFFFFF},
+		{Algorithm: crypto.KDFScrypt, Time: 30, Memory: 8, Threads: 1},
+		{Algorithm: crypto.KDFScrypt, Time: 10, Memory: 8, Threads: 255},
+		{Algorithm: crypto.KDFArgon2id, Time: 1, Memory: 0xFFFFFFFF, Threads: 1},
+		{Algorithm: crypto.KDFArgon2id, Time: 0xFFFFFFFF, Memory: 64, Threads: 1},
+		{Algorithm: crypto.KDFArgon2id, Time: 11, Memory: 64, Threads: 1},
+		{Algorithm: crypto.KDFArgon2id, Time: 1000000, Memory: 64 * 1024, Threads: 4},
+	} {
+		env, _ := crypto.ParseEnvelope(sealed)
+		env.KDF = params
+		_, err := crypto.DecryptWithPassword(password, env.Marshal())
+		assert.ErrorIs(t, err, crypto.ErrKDFCost, params.Algorithm.String())
+
+		env, _ = crypto.ParseEnvelope(stream.Bytes())
+		env.KDF = params
+		_, err = crypto.NewPasswordDecryptReader(bytes.NewReader(env.Marshal()), password)
+		assert.ErrorIs(t, err, crypto.ErrKDFCost, params.Algorithm.String())
+	}
+
+	strict := crypto.KDFPolicy{MaxIterations: 1, MaxPasses: 1, MaxMemory: 32, MaxThreads: 1}
+	_, err = crypto.DecryptWithPasswordPolicy(password, sealed, strict)
+	assert.ErrorIs(t, err, crypto.ErrKDFCost)
+	_, err = crypto.NewPasswordDecryptReaderPolicy(bytes.NewReader(stream.Bytes()), password, strict)
+	assert.ErrorIs(t, err, crypto.ErrKDFCost)
+	opened, err := crypto.DecryptWithPasswordPolicy(password, sealed, crypto.KDFPolicy{MaxIterations: 1, MaxPasses: 1, MaxMemory: 64, MaxThreads: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), opened)
+}
+
+func TestDeriveKey(t *testing.T) {
+	// RFC 7914 section 12
+	key, err := crypto.DeriveKey([]byte("password"), []byte("NaCl"),
+		crypto.KDFParams{Algorithm: crypto.KDFScrypt, Time: 10, Memory: 8, Threads: 16}, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640", hex.EncodeToString(key))
+
+	key, err = crypto.DeriveKey([]byte("password"), []byte("salt"), crypto.KDFParams{Algorithm: crypto.KDFPBKDF2, Time: 4096}, 32)
+	assert.NoError(t, err)
+	assert.Equal(t, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a", hex.EncodeToString(key))
+
+	key, err = crypto.DeriveKey([]byte("password"), []byte("somesalt"), fastParams[2], 32)
+	assert.NoError(t, err)
+	again, _ := crypto.DeriveKey([]byte("password"), []byte("somesalt"), fastParams[2], 32)
+	assert.Equal(t, key, again)
+
+	_, err = crypto.DeriveKey([]byte("password"), []byte("somesalt"), crypto.KDFParams{Algorithm: crypto.KDFArgon2id}, 32)
+	assert.Error(t, err)
+	_, err = crypto.DeriveKey([]byte("password"), []byte("somesalt"), crypto.KDFParams{Algorithm: crypto.KDFNone}, 32)
+	assert.ErrorIs(t, err, crypto.ErrUnsupportedAlgorithm)
+}
+
+func TestStream(t *testing.T) {
+	key, _ := crypto.GenerateKey(crypto.AES256GCM)
+	for _, size := range []int{0, 1, crypto.DefaultChunkSize - 1, crypto.DefaultChunkSize, crypto.DefaultChunkSize*3 + 17} {
+		plaintext := bytes.Repeat([]byte{0xA5}, size)
+		var sealed bytes.Buffer
+		assert.NoError(t, crypto.EncryptStream(&sealed, bytes.NewReader(plaintext), crypto.AES256GCM, key))
+
+		var opened bytes.Buffer
+		assert.NoError(t, crypto.DecryptStream(&opened, bytes.NewReader(sealed.Bytes()), key), size)
+		assert.Equal(t, size, opened.Len())
+		assert.True(t, bytes.Equal(plaintext, opened.Bytes()))
+
+		// One-shot decryption refuses streamed envelopes
+		_, err := crypto.Decrypt(key, sealed.Bytes())
+		assert.ErrorIs(t, err, crypto.ErrInvalidEnvelope)
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	key, _ := crypto.GenerateKey(crypto.XChaCha20Poly1305)
+	plaintext := bytes.Repeat([]byte("0123456789"), crypto.DefaultChunkSize/5)
+	var buf bytes.Buffer
+	w, err := crypto.NewEncryptWriter(&buf, crypto.XChaCha20Poly1305, key)
+	assert.NoError(t, err)
+	// Small writes are buffered into full chunks
+	for i := 0; i < len(plaintext); i += 1000 {
+		_, err = w.Write(plaintext[i:min(i+1000, len(plaintext))])
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	sealed := buf.Bytes()
+
+	env, err := crypto.ParseEnvelope(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(crypto.DefaultChunkSize), env.ChunkSize)
+	headerLen := len(sealed) - len(env.Ciphertext)
+	chunkLen := crypto.DefaultChunkSize + 16
+
+	readAll := func(data []byte) ([]byte, error) {
+		r, err := crypto.NewDecryptReader(bytes.NewReader(data), key)
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+
+	opened, err := readAll(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	// Dropping the final chunk is detected as truncation
+	_, err = readAll(sealed[:headerLen+chunkLen])
+	assert.ErrorIs(t, err, crypto.ErrTruncated)
+
+	// Cutting inside the final chunk or swapping chunks fails authentication
+	_, err = readAll(sealed[:len(sealed)-1])
+	assert.ErrorIs(t, err, crypto.ErrAuthFailed)
+	swapped := bytes.Clone(sealed)
+	copy(swapped[headerLen:], sealed[headerLen+chunkLen:headerLen+2*chunkLen])
+	copy(swapped[headerLen+chunkLen:], sealed[headerLen:headerLen+chunkLen])
+	_, err = readAll(swapped)
+	assert.ErrorIs(t, err, crypto.ErrAuthFailed)
+
+	_, err = readAll(sealed[:8])
+	assert.ErrorIs(t, err, crypto.ErrInvalidEnvelope)
+}
+
+func TestPasswordStream(t *testing.T) {
+	password := []byte("stream password")
+	var buf bytes.Buffer
+	w, err := crypto.NewPasswordEncryptWriter(&buf, crypto.AES128GCM, password, fastParams[2])
+	assert.NoError(t, err)
+	_, _ = w.Write([]byte("streamed with a password"))
+	assert.NoError(t, w.Close())
+	_, err = w.Write([]byte("late"))
+	assert.Error(t, err)
+
+	r, err := crypto.NewPasswordDecryptReader(bytes.NewReader(buf.Bytes()), password)
+	assert.NoError(t, err)
+	opened, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "streamed with a password", string(opened))
+
+	_, err = crypto.NewDecryptReader(bytes.NewReader(buf.Bytes()), make([]byte, 16))
+	assert.True(t, errors.Is(err, crypto.ErrInvalidEnvelope))
+}
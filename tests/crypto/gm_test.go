@@ -0,0 +1,275 @@
+package crypto_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"GoFast/pkg/crypto/sm2"
+	"GoFast/pkg/crypto/sm3"
+	"GoFast/pkg/crypto/sm4"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// fixedK feeds a predetermined nonce k to the SM2 functions, which read k as
+// 32 big-endian random bytes
+func fixedK(k string) *bytes.Reader {
+	return bytes.NewReader(mustHex(k))
+}
+
+func TestSM3(t *testing.T) {
+	// GB/T 32905-2016 appendix A
+	sum := sm3.Sum([]byte("abc"))
+	assert.Equal(t, "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0", hex.EncodeToString(sum[:]))
+	sum = sm3.Sum([]byte(strings.Repeat("abcd", 16)))
+	assert.Equal(t, "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732", hex.EncodeToString(sum[:]))
+
+	// Incremental writes match the one-shot digest
+	h := sm3.New()
+	for _, part := range []string{"ab", "cdabcdabcdab", strings.Repeat("cdab", 12), "cd"} {
+		h.Write([]byte(part))
+	}
+	assert.Equal(t, sum[:], h.Sum(nil))
+	assert.Equal(t, sm3.Size, h.Size())
+	h.Reset()
+	empty := sm3.Sum(nil)
+	assert.Equal(t, empty[:], h.Sum(nil))
+
+	mac := hmac.New(sm3.New, []byte("key"))
+	mac.Write([]byte("message"))
+	assert.Len(t, mac.Sum(nil), sm3.Size)
+}
+
+func TestSM4(t *testing.T) {
+	// GB/T 32907-2016 appendix A
+	key := mustHex("0123456789abcdeffedcba9876543210")
+	block, err := sm4.NewCipher(key)
+	assert.NoError(t, err)
+	out := make([]byte, 16)
+	block.Encrypt(out, key)
+	assert.Equal(t, "681edf34d206965e86b3e94f536e4246", hex.EncodeToString(out))
+	block.Decrypt(out, out)
+	assert.Equal(t, key, out)
+
+	// Example 2: one million iterations
+	if !testing.Short() {
+		data := append([]byte(nil), key...)
+		for i := 0; i < 1000000; i++ {
+			block.Encrypt(data, data)
+		}
+		assert.Equal(t, "595298c7c6fd271f0402f804c33d3f66", hex.EncodeToString(data))
+	}
+
+	_, err = sm4.NewCipher(key[:8])
+	assert.Error(t, err)
+}
+
+func TestSM4Modes(t *testing.T) {
+	key := mustHex("0123456789abcdeffedcba9876543210")
+	iv := mustHex("000102030405060708090a0b0c0d0e0f")
+	plaintext := []byte("0123456789abcdef0123456789abcdef!")
+
+	ecb, err := sm4.EncryptECB(key, plaintext)
+	assert.NoError(t, err)
+	assert.Len(t, ecb, 48)
+	decrypted, err := sm4.DecryptECB(key, ecb)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	cbc, err := sm4.EncryptCBC(key, iv, plaintext)
+	assert.NoError(t, err)
+	assert.Equal(t, "9d193c43fdc9ac44b40c27629ea9df0cb8e075fe283adf662aefc549b2aa2368f94e486faaa45bf40400dc6221e276a5", hex.EncodeToString(cbc))
+	decrypted, err = sm4.DecryptCBC(key, iv, cbc)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+	_, err = sm4.DecryptCBC(key, iv, cbc[:40])
+	assert.ErrorIs(t, err, sm4.ErrNotFullBlocks)
+	_, err = sm4.EncryptCBC(key, iv[:8], plaintext)
+	assert.ErrorIs(t, err, sm4.ErrInvalidIV)
+
+	ctr, err := sm4.XORKeyStreamCTR(key, iv, plaintext)
+	assert.NoError(t, err)
+	assert.Len(t, ctr, len(plaintext))
+	decrypted, _ = sm4.XORKeyStreamCTR(key, iv, ctr)
+	assert.Equal(t, plaintext, decrypted)
+
+	nonce := mustHex("00001234567800000000abcd")
+	gcm, err := sm4.EncryptGCM(key, nonce, []byte("hello sm4 gcm mode"), []byte("aad"))
+	assert.NoError(t, err)
+	assert.Equal(t, "d53c5f3649ed0c29964b0041bf5f6da9f77ddbe130ef9af9460a8445866dabf4f355", hex.EncodeToString(gcm))
+	decrypted, err = sm4.DecryptGCM(key, nonce, gcm, []byte("aad"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello sm4 gcm mode", string(decrypted))
+	_, err = sm4.DecryptGCM(key, nonce, gcm, []byte("other"))
+	assert.ErrorIs(t, err, sm4.ErrAuthFailed)
+}
+
+func TestPKCS7(t *testing.T) {
+	for n := 0; n <= 32; n++ {
+		padded := sm4.Pad(bytes.Repeat([]byte{'x'}, n))
+		assert.Zero(t, len(padded)%sm4.BlockSize)
+		assert.Greater(t, len(padded), n)
+		unpadded, err := sm4.Unpad(padded)
+		assert.NoError(t, err)
+		assert.Len(t, unpadded, n)
+	}
+	_, err := sm4.Unpad(bytes.Repeat([]byte{0}, 16))
+	assert.ErrorIs(t, err, sm4.ErrInvalidPadding)
+	_, err = sm4.Unpad(append(bytes.Repeat([]byte{1}, 14), 3, 2))
+	assert.ErrorIs(t, err, sm4.ErrInvalidPadding)
+}
+
+// The key pair and nonce of the GM/T 0003.5 examples on the recommended curve
+const (
+	sm2PrivateKey = "3945208f7b2144b13f36e38ac6d39f95889393692860b51a42fb81ef4df7c5b8"
+	sm2PublicKey  = "0409f9df311e5421a150dd7d161e4bc5c672179fad1833fc076bb08ff356f35020ccea490ce26775a52dc6ea718cc1aa600aed05fbf35e084a6632f6072da9ad13"
+	sm2K          = "59276e27d506861a16680f3ad9c02dccef3cc1fa3cdbe4ce6d54b80deac1bc21"
+)
+
+func TestSM2Sign(t *testing.T) {
+	priv, err := sm2.NewPrivateKey(mustHex(sm2PrivateKey))
+	assert.NoError(t, err)
+	assert.Equal(t, sm2PublicKey, hex.EncodeToString(priv.PublicKey.Bytes()))
+
+	za, err := sm2.ZA(&priv.PublicKey, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "b2e14c5c79c6df5b85f4fe7ed8db7a262b9da7e07ccb0ea9f4747b8ccda8a4f3", hex.EncodeToString(za))
+
+	message := []byte("message digest")
+	r, s, err := sm2.SignRS(fixedK(sm2K), priv, nil, message)
+	assert.NoError(t, err)
+	assert.Equal(t, "f5a03b0648d2c4630eeac513e1bb81a15944da3827d5b74143ac7eaceee720b3", hex.EncodeToString(r.Bytes()))
+	assert.Equal(t, "b1b6aa29df212fd8763182bc0d421ca1bb9038fd1f7f42d4840b69c485bbc1aa", hex.EncodeToString(s.Bytes()))
+	assert.True(t, sm2.VerifyRS(&priv.PublicKey, nil, message, r, s))
+
+	// Signature produced by an independent implementation
+	external := mustHex("304502205689103f17713a64d0bbccc85042643d342e74ffe9c98e209e9271067054acec022100b4af293dbb5ed6d080404cdd7d87c4b46ec802c6bcd5eff6bb48c62ea643ceba")
+	assert.True(t, sm2.Verify(&priv.PublicKey, nil, message, external))
+	assert.False(t, sm2.Verify(&priv.PublicKey, []byte("other id"), message, external))
+	assert.False(t, sm2.Verify(&priv.PublicKey, nil, []byte("message digesT"), external))
+
+	key, err := sm2.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	sig, err := key.Sign(rand.Reader, message, &sm2.SignerOpts{UID: []byte("alice@example.com")})
+	assert.NoError(t, err)
+	assert.True(t, sm2.Verify(&key.PublicKey, []byte("alice@example.com"), message, sig))
+	assert.False(t, sm2.Verify(&priv.PublicKey, []byte("alice@example.com"), message, sig))
+	assert.False(t, sm2.Verify(&key.PublicKey, nil, message, []byte{0x30, 0x00}))
+}
+
+func TestSM2ScalarMult(t *testing.T) {
+	// The constant-time arithmetic must agree with the generic curve implementation
+	n := sm2.P256().Params().N
+	scalars := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(15), big.NewInt(16), new(big.Int).Sub(n, big.NewInt(2))}
+	for i := 0; i < 20; i++ {
+		d, _ := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(2)))
+		scalars = append(scalars, d.Add(d, big.NewInt(1)))
+	}
+	for _, d := range scalars {
+		priv, err := sm2.NewPrivateKey(d.FillBytes(make([]byte, 32)))
+		assert.NoError(t, err, d.String())
+		x, y := sm2.P256().ScalarBaseMult(d.Bytes())
+		assert.Equal(t, x, priv.X, d.String())
+		assert.Equal(t, y, priv.Y, d.String())
+	}
+}
+
+func TestSM2Encrypt(t *testing.T) {
+	priv, _ := sm2.NewPrivateKey(mustHex(sm2PrivateKey))
+	message := []byte("encryption standard")
+
+	ciphertext, err := sm2.Encrypt(fixedK(sm2K), &priv.PublicKey, message, sm2.C1C3C2)
+	assert.NoError(t, err)
+	assert.Equal(t, "04"+
+		"04ebfc718e8d1798620432268e77feb6415e2ede0e073c0f4f640ecd2e149a73"+
+		"e858f9d81e5430a57b36daab8f950a3c64e6ee6a63094d99283aff767e124df0"+
+		"59983c18f809e262923c53aec295d30383b54e39d609d160afcb1908d0bd8766"+
+		"21886ca989ca9c7d58087307ca93092d651efa", hex.EncodeToString(ciphertext))
+
+	// Ciphertext produced by an independent implementation
+	external := mustHex("04dd66cab6a391df5d1858c3cada123c0a01512c3e6326aba8162b7dce37937a3e95b989cdb41ddf6c70a67ee869a6e69e1eeaefee61ac7831cccf274a51b266fa1e0db99b358f4c61829969dfa8f746509bbfb5ed90cc2553b6f4629b7f7abd6a3ed6a8dcdc3c7194924ef3d3cb5e2db9ef5b93")
+	decrypted, err := sm2.Decrypt(priv, external, sm2.C1C3C2)
+	assert.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+
+	for _, mode := range []sm2.CipherMode{sm2.C1C3C2, sm2.C1C2C3} {
+		for _, msg := range [][]byte{nil, []byte("x"), bytes.Repeat([]byte("long message "), 50)} {
+			ciphertext, err := sm2.Encrypt(rand.Reader, &priv.PublicKey, msg, mode)
+			assert.NoError(t, err)
+			assert.Len(t, ciphertext, len(msg)+97)
+			decrypted, err := sm2.Decrypt(priv, ciphertext, mode)
+			assert.NoError(t, err)
+			assert.Equal(t, len(msg), len(decrypted))
+			assert.True(t, bytes.Equal(msg, decrypted))
+
+			ciphertext[len(ciphertext)-1] ^= 1
+			_, err = sm2.Decrypt(priv, ciphertext, mode)
+			assert.ErrorIs(t, err, sm2.ErrDecryption)
+		}
+	}
+	_, err = sm2.Decrypt(priv, []byte{4, 1, 2}, sm2.C1C3C2)
+	assert.ErrorIs(t, err, sm2.ErrDecryption)
+}
+
+func TestSM2Keys(t *testing.T) {
+	priv, _ := sm2.NewPrivateKey(mustHex(sm2PrivateKey))
+
+	// DER encodings produced by an independent implementation
+	pkcs8 := mustHex("308187020100301306072a8648ce3d020106082a811ccf5501822d046d306b02010104203945208f7b2144b13f36e38ac6d39f95889393692860b51a42fb81ef4df7c5b8a1440342000409f9df311e5421a150dd7d161e4bc5c672179fad1833fc076bb08ff356f35020ccea490ce26775a52dc6ea718cc1aa600aed05fbf35e084a6632f6072da9ad13")
+	pkix := mustHex("3059301306072a8648ce3d020106082a811ccf5501822d0342000409f9df311e5421a150dd7d161e4bc5c672179fad1833fc076bb08ff356f35020ccea490ce26775a52dc6ea718cc1aa600aed05fbf35e084a6632f6072da9ad13")
+
+	parsed, err := sm2.ParsePKCS8PrivateKey(pkcs8)
+	assert.NoError(t, err)
+	assert.True(t, priv.Equal(parsed))
+	der, err := sm2.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	assert.Equal(t, pkcs8, der)
+
+	pub, err := sm2.ParsePKIXPublicKey(pkix)
+	assert.NoError(t, err)
+	assert.True(t, priv.PublicKey.Equal(pub))
+	der, err = sm2.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	assert.Equal(t, pkix, der)
+
+	sec1, err := sm2.MarshalECPrivateKey(priv)
+	assert.NoError(t, err)
+	parsed, err = sm2.ParseECPrivateKey(sec1)
+	assert.NoError(t, err)
+	assert.True(t, priv.Equal(parsed))
+
+	privPEM, err := sm2.MarshalPrivateKeyPEM(priv)
+	assert.NoError(t, err)
+	parsed, err = sm2.ParsePrivateKeyPEM(privPEM)
+	assert.NoError(t, err)
+	assert.True(t, priv.Equal(parsed))
+	pubPEM, err := sm2.MarshalPublicKeyPEM(&priv.PublicKey)
+	assert.NoError(t, err)
+	pub, err = sm2.ParsePublicKeyPEM(pubPEM)
+	assert.NoError(t, err)
+	assert.True(t, priv.PublicKey.Equal(pub))
+
+	// Off-curve points and out-of-range scalars are rejected
+	bad := mustHex(sm2PublicKey)
+	bad[64] ^= 1
+	_, err = sm2.NewPublicKey(bad)
+	assert.ErrorIs(t, err, sm2.ErrInvalidKey)
+	_, err = sm2.NewPrivateKey(make([]byte, 32))
+	assert.ErrorIs(t, err, sm2.ErrInvalidKey)
+	_, err = sm2.ParsePKIXPublicKey(pkcs8)
+	assert.ErrorIs(t, err, sm2.ErrInvalidKey)
+	_, err = sm2.ParsePublicKeyPEM([]byte("garbage"))
+	assert.ErrorIs(t, err, sm2.ErrInvalidPEM)
+}
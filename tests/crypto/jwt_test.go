@@ -0,0 +1,308 @@
+package crypto_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"GoFast/pkg/crypto/jwt"
+	"GoFast/pkg/errorhandler"
+	"github.com/stretchr/testify/assert"
+)
+
+type userClaims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+}
+
+// assertCode checks the sentinel and the errorhandler code of a Parse error
+func assertCode(t *testing.T, err error, sentinel error, code int) {
+	t.Helper()
+	assert.ErrorIs(t, err, sentinel)
+	var custom *errorhandler.CustomError
+	if assert.True(t, errors.As(err, &custom)) {
+		assert.Equal(t, code, custom.Code)
+	}
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	cases := []struct {
+		alg  jwt.Algorithm
+		key  interface{}
+		pub  interface{}
+		size int
+	}{
+		{jwt.HS256, secret, secret, 32},
+		{jwt.HS384, secret, secret, 48},
+		{jwt.HS512, secret, secret, 64},
+		{jwt.RS256, testRSAKey(t), testRSAKey(t).Public(), 256},
+		{jwt.ES256, ecKey, ecKey.Public(), 64},
+		{jwt.EdDSA, edKey, edKey.Public(), 64},
+	}
+	now := time.Now()
+	for _, c := range cases {
+		t.Run(string(c.alg), func(t *testing.T) {
+			claims := userClaims{
+				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    "gofast",
+					Subject:   "42",
+					Audience:  jwt.Audience{"api"},
+					ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+					IssuedAt:  jwt.NewNumericDate(now),
+				},
+				Role: "admin",
+			}
+			token, err := jwt.NewSigner(c.alg, c.key).WithKeyID("k1").Sign(claims)
+			assert.NoError(t, err)
+
+			var got userClaims
+			parsed, err := jwt.Parse(token, c.pub, &got, c.alg)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.alg, parsed.Header.Algorithm)
+				assert.Equal(t, "k1", parsed.Header.KeyID)
+				assert.Len(t, parsed.Signature, c.size)
+				assert.Equal(t, "admin", got.Role)
+				assert.Equal(t, "42", got.Subject)
+				assert.True(t, got.ExpiresAt.Equal(claims.ExpiresAt.Time))
+			}
+
+			// Flip a bit of the payload
+			parts := strings.Split(token, ".")
+			payload := []byte(parts[1])
+			payload[4] ^= 1
+			tampered := parts[0] + "." + string(payload) + "." + parts[2]
+			_, err = jwt.Parse(tampered, c.pub, nil, c.alg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJWTSignatureInvalid(t *testing.T) {
+	token, _ := jwt.Sign(jwt.HS256, []byte("secret-one"), jwt.RegisteredClaims{Subject: "a"})
+	_, err := jwt.Parse(token, []byte("secret-two"), nil, jwt.HS256)
+	assertCode(t, err, jwt.ErrSignatureInvalid, jwt.CodeInvalidSignature)
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+	token, _ = jwt.Sign(jwt.EdDSA, edKey, jwt.RegisteredClaims{})
+	_, err = jwt.Parse(token, otherPub, nil, jwt.EdDSA)
+	assertCode(t, err, jwt.ErrSignatureInvalid, jwt.CodeInvalidSignature)
+}
+
+func TestJWTAlgorithmRestrictions(t *testing.T) {
+	secret := []byte("secret")
+	token, _ := jwt.Sign(jwt.HS256, secret, jwt.RegisteredClaims{})
+
+	// The token algorithm must be in the accepted list
+	_, err := jwt.Parse(token, secret, nil, jwt.RS256)
+	assertCode(t, err, jwt.ErrUnsupportedAlgorithm, jwt.CodeUnsupportedAlg)
+
+	// "none" is never accepted
+	unsigned := "eyJhbGciOiJub25lIn0." + strings.Split(token, ".")[1] + "."
+	_, err = jwt.Parse(unsigned, secret, nil, jwt.Algorithm("none"))
+	assertCode(t, err, jwt.ErrUnsupportedAlgorithm, jwt.CodeUnsupportedAlg)
+
+	_, err = jwt.Sign(jwt.Algorithm("none"), secret, jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, jwt.ErrUnsupportedAlgorithm)
+	_, err = jwt.Sign(jwt.RS256, secret, jwt.RegisteredClaims{})
+	assert.ErrorIs(t, err, jwt.ErrInvalidKey)
+}
+
+func TestJWTTimeClaims(t *testing.T) {
+	secret := []byte("secret")
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sign := func(c jwt.RegisteredClaims) string {
+		token, err := jwt.Sign(jwt.HS256, secret, c)
+		assert.NoError(t, err)
+		return token
+	}
+	validator := func(now time.Time, leeway time.Duration) *jwt.Validator {
+		v := jwt.NewValidator(secret, jwt.HS256)
+		v.Now = func() time.Time { return now }
+		v.Leeway = leeway
+		return v
+	}
+
+	expiring := sign(jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(base)})
+	_, err := validator(base.Add(-time.Second), 0).Parse(expiring, nil)
+	assert.NoError(t, err)
+	_, err = validator(base, 0).Parse(expiring, nil)
+	assertCode(t, err, jwt.ErrExpired, jwt.CodeExpired)
+	_, err = validator(base.Add(20*time.Second), 30*time.Second).Parse(expiring, nil)
+	assert.NoError(t, err)
+	_, err = validator(base.Add(30*time.Second), 30*time.Second).Parse(expiring, nil)
+	assertCode(t, err, jwt.ErrExpired, jwt.CodeExpired)
+
+	future := sign(jwt.RegisteredClaims{NotBefore: jwt.NewNumericDate(base)})
+	_, err = validator(base.Add(-time.Second), 0).Parse(future, nil)
+	assertCode(t, err, jwt.ErrNotValidYet, jwt.CodeNotValidYet)
+	_, err = validator(base.Add(-10*time.Second), 10*time.Second).Parse(future, nil)
+	assert.NoError(t, err)
+	_, err = validator(base, 0).Parse(future, nil)
+	assert.NoError(t, err)
+
+	issuedLater := sign(jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(base)})
+	_, err = validator(base.Add(-time.Minute), 0).Parse(issuedLater, nil)
+	assertCode(t, err, jwt.ErrNotValidYet, jwt.CodeNotValidYet)
+
+	// Expired and not-yet-valid are distinguishable from bad signatures
+	assert.False(t, errors.Is(err, jwt.ErrSignatureInvalid))
+
+	v := validator(base, 0)
+	v.RequireExp = true
+	_, err = v.Parse(future, nil)
+	assertCode(t, err, jwt.ErrMissingClaim, jwt.CodeInvalidClaims)
+}
+
+func TestJWTIdentityClaims(t *testing.T) {
+	secret := []byte("secret")
+	token, _ := jwt.Sign(jwt.HS256, secret, jwt.RegisteredClaims{
+		Issuer:   "gofast",
+		Subject:  "42",
+		Audience: jwt.Audience{"api", "web"},
+	})
+
+	v := jwt.NewValidator(secret, jwt.HS256)
+	v.Issuer, v.Audience, v.Subject = "gofast", "web", "42"
+	_, err := v.Parse(token, nil)
+	assert.NoError(t, err)
+
+	v.Audience = "admin"
+	_, err = v.Parse(token, nil)
+	assertCode(t, err, jwt.ErrInvalidAudience, jwt.CodeInvalidClaims)
+
+	v.Audience, v.Issuer = "", "other"
+	_, err = v.Parse(token, nil)
+	assertCode(t, err, jwt.ErrInvalidIssuer, jwt.CodeInvalidClaims)
+}
+
+func TestJWTMalformed(t *testing.T) {
+	for _, token := range []string{"", "a.b", "a.b.c.d", "!!.e30.", "e30.!!.", "bnVsbA.e30."} {
+		_, err := jwt.Parse(token, []byte("k"), nil, jwt.HS256)
+		assertCode(t, err, jwt.ErrMalformed, jwt.CodeMalformed)
+	}
+}
+
+// TestJWTRFC7515 verifies the HMAC example of RFC 7515 appendix A.1
+func TestJWTRFC7515(t *testing.T) {
+	const token = "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	ks, err := jwt.ParseKeySet([]byte(`{"keys":[{"kty":"oct",` +
+		`"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}]}`))
+	assert.NoError(t, err)
+
+	v := &jwt.Validator{Keys: ks, Algorithms: []jwt.Algorithm{jwt.HS256}}
+	v.Now = func() time.Time { return time.Unix(1300819000, 0) }
+	var claims jwt.MapClaims
+	parsed, err := v.Parse(token, &claims)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "joe", parsed.Claims.Issuer)
+		assert.Equal(t, true, claims["http://example.com/is_root"])
+		assert.Equal(t, json.Number("1300819380"), claims["exp"])
+	}
+
+	v.Now = nil
+	_, err = v.Parse(token, nil)
+	assertCode(t, err, jwt.ErrExpired, jwt.CodeExpired)
+}
+
+func TestJWTKeySet(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey := testRSAKey(t)
+
+	var ks jwt.KeySet
+	for kid, key := range map[string]interface{}{"rsa": rsaKey, "ec": ecKey, "ed": edPub} {
+		jwk, err := jwt.NewJWK(kid, key)
+		assert.NoError(t, err)
+		ks.Keys = append(ks.Keys, jwk)
+	}
+	data, err := json.Marshal(ks)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"d"`)
+
+	parsed, err := jwt.ParseKeySet(data)
+	assert.NoError(t, err)
+	v := &jwt.Validator{Keys: parsed, Algorithms: []jwt.Algorithm{jwt.RS256, jwt.ES256, jwt.EdDSA}}
+
+	for _, s := range []*jwt.Signer{
+		jwt.NewSigner(jwt.RS256, rsaKey).WithKeyID("rsa"),
+		jwt.NewSigner(jwt.ES256, ecKey).WithKeyID("ec"),
+		jwt.NewSigner(jwt.EdDSA, edKey).WithKeyID("ed"),
+	} {
+		token, err := s.Sign(jwt.RegisteredClaims{Subject: s.KeyID})
+		assert.NoError(t, err)
+		got, err := v.Parse(token, nil)
+		if assert.NoError(t, err, s.KeyID) {
+			assert.Equal(t, s.KeyID, got.Claims.Subject)
+		}
+	}
+
+	// Unknown kid, missing kid with several keys, and a kid pointing at the wrong key type
+	for _, s := range []*jwt.Signer{
+		jwt.NewSigner(jwt.EdDSA, edKey).WithKeyID("missing"),
+		jwt.NewSigner(jwt.EdDSA, edKey),
+	} {
+		token, _ := s.Sign(jwt.RegisteredClaims{})
+		_, err = v.Parse(token, nil)
+		assertCode(t, err, jwt.ErrKeyNotFound, jwt.CodeKeyNotFound)
+	}
+	token, _ := jwt.NewSigner(jwt.EdDSA, edKey).WithKeyID("ec").Sign(jwt.RegisteredClaims{})
+	_, err = v.Parse(token, nil)
+	assertCode(t, err, jwt.ErrSignatureInvalid, jwt.CodeInvalidSignature)
+
+	// Keys restricted by "alg" or "use"
+	restricted, err := jwt.ParseKeySet([]byte(`{"keys":[` +
+		`{"kty":"oct","kid":"a","alg":"HS512","k":"c2VjcmV0"},` +
+		`{"kty":"oct","kid":"b","use":"enc","k":"c2VjcmV0"}]}`))
+	assert.NoError(t, err)
+	v = &jwt.Validator{Keys: restricted, Algorithms: []jwt.Algorithm{jwt.HS256}}
+	for _, kid := range []string{"a", "b"} {
+		token, _ := jwt.NewSigner(jwt.HS256, []byte("secret")).WithKeyID(kid).Sign(jwt.RegisteredClaims{})
+		_, err = v.Parse(token, nil)
+		assertCode(t, err, jwt.ErrKeyNotFound, jwt.CodeKeyNotFound)
+	}
+
+	_, err = jwt.ParseKeySet([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}]}`))
+	assert.ErrorIs(t, err, jwt.ErrInvalidKey)
+	_, err = jwt.ParseKeySet([]byte(`{"keys":[{"kty":"foo"}]}`))
+	assert.ErrorIs(t, err, jwt.ErrInvalidKey)
+	_, err = jwt.ParseKeySet([]byte(`{"keys":[]}`))
+	assert.ErrorIs(t, err, jwt.ErrInvalidKey)
+
+	// Unusable keys are skipped rather than failing the whole set
+	var mixed jwt.KeySet
+	mixed.Keys = append(mixed.Keys,
+		jwt.JWK{KeyType: "EC", KeyID: "secp256k1", Curve: "secp256k1", X: "AA", Y: "AA"},
+		jwt.JWK{KeyType: "OKP", KeyID: "x25519", Curve: "X25519", X: "AA"},
+		jwt.JWK{KeyType: "PQC", KeyID: "future"},
+	)
+	ecJWK, _ := jwt.NewJWK("ec", ecKey)
+	mixed.Keys = append(mixed.Keys, ecJWK)
+	data, _ = json.Marshal(mixed)
+	parsed, err = jwt.ParseKeySet(data)
+	if assert.NoError(t, err) && assert.Len(t, parsed.Keys, 1) {
+		v = &jwt.Validator{Keys: parsed, Algorithms: []jwt.Algorithm{jwt.ES256}}
+		for _, s := range []*jwt.Signer{jwt.NewSigner(jwt.ES256, ecKey).WithKeyID("ec"), jwt.NewSigner(jwt.ES256, ecKey)} {
+			token, _ := s.Sign(jwt.RegisteredClaims{Subject: "ec"})
+			_, err = v.Parse(token, nil)
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestJWTLocalizedMessages(t *testing.T) {
+	assert.True(t, errorhandler.HasLocalizedMessage(jwt.CodeExpired, "en"))
+	assert.True(t, errorhandler.HasLocalizedMessage(jwt.CodeInvalidSignature, "zh"))
+}
@@ -0,0 +1,154 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"GoFast/pkg/crypto/otp"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHOTPRFC4226 checks the test values of RFC 4226 appendix D
+func TestHOTPRFC4226(t *testing.T) {
+	h := otp.NewHOTP([]byte("12345678901234567890"))
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, want := range expected {
+		code, err := h.Generate(uint64(counter))
+		assert.NoError(t, err)
+		assert.Equal(t, want, code)
+	}
+
+	next, ok := h.Verify("969429", 1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(4), next)
+	next, ok = h.Verify("969429", 4, 5)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(4), next)
+}
+
+// TestTOTPRFC6238 checks the test values of RFC 6238 appendix B
+func TestTOTPRFC6238(t *testing.T) {
+	secrets := map[otp.Algorithm]string{
+		otp.SHA1:   "12345678901234567890",
+		otp.SHA256: "12345678901234567890123456789012",
+		otp.SHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	vectors := []struct {
+		unix  int64
+		codes map[otp.Algorithm]string
+	}{
+		{59, map[otp.Algorithm]string{otp.SHA1: "94287082", otp.SHA256: "46119246", otp.SHA512: "90693936"}},
+		{1111111109, map[otp.Algorithm]string{otp.SHA1: "07081804", otp.SHA256: "68084774", otp.SHA512: "25091201"}},
+		{1111111111, map[otp.Algorithm]string{otp.SHA1: "14050471", otp.SHA256: "67062674", otp.SHA512: "99943326"}},
+		{1234567890, map[otp.Algorithm]string{otp.SHA1: "89005924", otp.SHA256: "91819424", otp.SHA512: "93441116"}},
+		{2000000000, map[otp.Algorithm]string{otp.SHA1: "69279037", otp.SHA256: "90698825", otp.SHA512: "38618901"}},
+		{20000000000, map[otp.Algorithm]string{otp.SHA1: "65353130", otp.SHA256: "77737706", otp.SHA512: "47863826"}},
+	}
+	for _, v := range vectors {
+		for alg, want := range v.codes {
+			totp := &otp.TOTP{Secret: []byte(secrets[alg]), Digits: 8, Algorithm: alg}
+			code, err := totp.GenerateAt(time.Unix(v.unix, 0))
+			assert.NoError(t, err)
+			assert.Equal(t, want, code, "%s at %d", alg, v.unix)
+		}
+	}
+}
+
+func TestTOTPSkew(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	totp := otp.NewTOTP([]byte("12345678901234567890"))
+	totp.Now = func() time.Time { return now }
+
+	code, err := totp.Generate()
+	assert.NoError(t, err)
+	assert.Len(t, code, 6)
+	assert.True(t, totp.Verify(code))
+	assert.Equal(t, 10*time.Second, totp.Remaining())
+
+	previous, _ := totp.GenerateAt(now.Add(-30 * time.Second))
+	step, ok := totp.VerifyAt(previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+
+	stale, _ := totp.GenerateAt(now.Add(-60 * time.Second))
+	assert.False(t, totp.Verify(stale))
+	totp.Skew = 2
+	assert.True(t, totp.Verify(stale))
+	totp.Skew = 0
+	assert.False(t, totp.Verify(previous))
+	assert.False(t, totp.Verify("12345"))
+
+	// Custom period
+	totp.Period = time.Minute
+	assert.Equal(t, uint64(1700000000/60), totp.Step(now))
+}
+
+func TestOTPErrors(t *testing.T) {
+	_, err := otp.NewHOTP(nil).Generate(0)
+	assert.ErrorIs(t, err, otp.ErrInvalidSecret)
+	_, err = (&otp.HOTP{Secret: []byte("k"), Digits: 4}).Generate(0)
+	assert.ErrorIs(t, err, otp.ErrInvalidDigits)
+	_, err = (&otp.HOTP{Secret: []byte("k"), Algorithm: "MD5"}).Generate(0)
+	assert.ErrorIs(t, err, otp.ErrUnsupportedAlgorithm)
+}
+
+func TestOTPSecret(t *testing.T) {
+	secret, err := otp.GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, otp.DefaultSecretSize)
+
+	encoded := otp.EncodeSecret([]byte("12345678901234567890"))
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", encoded)
+	decoded, err := otp.DecodeSecret("gezd gnbv gy3t qojq gezd gnbv gy3t qojq")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("12345678901234567890"), decoded)
+	_, err = otp.DecodeSecret("not base32!")
+	assert.ErrorIs(t, err, otp.ErrInvalidSecret)
+}
+
+func TestOTPKeyURI(t *testing.T) {
+	totp := otp.NewTOTP([]byte("12345678901234567890"))
+	uri := totp.Key("Example Co", "alice@example.com").URI()
+	assert.Equal(t, "otpauth://totp/Example%20Co:alice@example.com?issuer=Example%20Co&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri)
+
+	key, err := otp.ParseURI(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, "totp", key.Type)
+	assert.Equal(t, "Example Co", key.Issuer)
+	assert.Equal(t, "alice@example.com", key.Account)
+	assert.Equal(t, otp.SHA1, key.Algorithm)
+	assert.Equal(t, 6, key.Digits)
+	assert.Equal(t, otp.DefaultPeriod, key.Period)
+
+	custom := &otp.TOTP{Secret: []byte("12345678901234567890"), Digits: 8, Algorithm: otp.SHA256, Period: time.Minute}
+	uri = custom.Key("", "bob").URI()
+	assert.Contains(t, uri, "algorithm=SHA256")
+	assert.Contains(t, uri, "digits=8")
+	assert.Contains(t, uri, "period=60")
+	key, err = otp.ParseURI(uri)
+	assert.NoError(t, err)
+	want, _ := custom.GenerateAt(time.Unix(1700000000, 0))
+	got, _ := key.TOTP().GenerateAt(time.Unix(1700000000, 0))
+	assert.Equal(t, want, got)
+
+	hotpURI := otp.NewHOTP([]byte("12345678901234567890")).Key("Example", "carol", 7).URI()
+	assert.True(t, strings.HasPrefix(hotpURI, "otpauth://hotp/Example:carol?"))
+	key, err = otp.ParseURI(hotpURI)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), key.Counter)
+	code, _ := key.HOTP().Generate(key.Counter)
+	assert.Equal(t, "162583", code)
+
+	for _, bad := range []string{
+		"https://totp/x?secret=GEZDGNBV",
+		"otpauth://sms/x?secret=GEZDGNBV",
+		"otpauth://totp/x",
+		"otpauth://totp/x?secret=GEZDGNBV&digits=12",
+		"otpauth://totp/x?secret=GEZDGNBV&algorithm=MD5",
+		"otpauth://hotp/x?secret=GEZDGNBV",
+	} {
+		_, err := otp.ParseURI(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,119 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+
+	"GoFast/pkg/crypto/password"
+	"github.com/stretchr/testify/assert"
+)
+
+// Low-cost parameters keep the tests fast
+var (
+	fastArgon2id = password.Params{Algorithm: password.Argon2id, Time: 1, Memory: 1024, Threads: 1}
+	fastScrypt   = password.Params{Algorithm: password.Scrypt, Time: 10, Memory: 8, Threads: 1}
+	fastBcrypt   = password.Params{Algorithm: password.Bcrypt, Time: 4}
+)
+
+func TestPasswordHashVerify(t *testing.T) {
+	for _, params := range []password.Params{fastArgon2id, fastScrypt, fastBcrypt} {
+		t.Run(string(params.Algorithm), func(t *testing.T) {
+			h, err := password.NewHasher(params)
+			assert.NoError(t, err)
+
+			encoded, err := h.Hash("correct horse battery staple")
+			assert.NoError(t, err)
+			other, _ := h.Hash("correct horse battery staple")
+			assert.NotEqual(t, encoded, other, "salts must be random")
+
+			ok, err := h.Verify("correct horse battery staple", encoded)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			ok, err = password.Verify("Correct horse battery staple", encoded)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+			assert.ErrorIs(t, password.Check("wrong", encoded), password.ErrMismatch)
+
+			identified, err := password.Identify(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, params, identified)
+			assert.False(t, h.NeedsRehash(encoded))
+		})
+	}
+}
+
+func TestPasswordPHCFormat(t *testing.T) {
+	h, _ := password.NewHasher(fastArgon2id)
+	encoded, _ := h.Hash("secret")
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.Len(t, strings.Split(encoded, "$"), 6)
+
+	h, _ = password.NewHasher(fastScrypt)
+	encoded, _ = h.Hash("secret")
+	assert.True(t, strings.HasPrefix(encoded, "$scrypt$ln=10,r=8,p=1$"))
+
+	// Reference vector of the Argon2 C implementation
+	ok, err := password.Verify("password",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// OpenBSD bcrypt vector
+	ok, err = password.Verify("U*U", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	weak, _ := password.NewHasher(fastArgon2id)
+	encoded, _ := weak.Hash("secret")
+	assert.True(t, password.NeedsRehash(encoded))
+
+	strong, err := password.NewHasher(password.Params{Algorithm: password.Argon2id, Time: 2, Memory: 1024, Threads: 1})
+	assert.NoError(t, err)
+	assert.True(t, strong.NeedsRehash(encoded))
+
+	// Switching algorithms also requires a rehash
+	bcryptHasher, _ := password.NewHasher(fastBcrypt)
+	assert.True(t, bcryptHasher.NeedsRehash(encoded))
+	assert.True(t, weak.NeedsRehash("not a hash"))
+
+	// The package defaults use Argon2id
+	encoded, err = password.Hash("secret")
+	assert.NoError(t, err)
+	assert.False(t, password.NeedsRehash(encoded))
+	assert.Equal(t, password.DefaultArgon2idParams(), mustIdentify(t, encoded))
+}
+
+func mustIdentify(t *testing.T, encoded string) password.Params {
+	t.Helper()
+	p, err := password.Identify(encoded)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestPasswordInvalid(t *testing.T) {
+	_, err := password.NewHasher(password.Params{Algorithm: "md5"})
+	assert.ErrorIs(t, err, password.ErrUnsupportedAlgorithm)
+	_, err = password.NewHasher(password.Params{Algorithm: password.Bcrypt, Time: 40})
+	assert.Error(t, err)
+	_, err = password.NewHasher(password.Params{Algorithm: password.Scrypt, Time: 4, Memory: 8, Threads: 1})
+	assert.Error(t, err)
+
+	for _, encoded := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=1024,t=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=1024,t=1,p=1$c29tZXNhbHQ$AAAA",
+		"$scrypt$ln=10,r=8,p=1$c29tZXNhbHQ",
+		"$2a$05$short",
+	} {
+		_, err := password.Verify("x", encoded)
+		assert.ErrorIs(t, err, password.ErrInvalidHash, encoded)
+	}
+	_, err = password.Verify("x", "$argon2id$v=16$m=1024,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc")
+	assert.ErrorIs(t, err, password.ErrUnsupportedAlgorithm)
+	_, err = password.Verify("x", "$pbkdf2$i=1000$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc")
+	assert.ErrorIs(t, err, password.ErrUnsupportedAlgorithm)
+}
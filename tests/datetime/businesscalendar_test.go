@@ -0,0 +1,231 @@
+package datetime_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+// holidays2024 is an excerpt of the 2024 schedule of the State Council
+const holidays2024 = `{"year": 2024, "days": [
+	{"name": "元旦", "date": "2024-01-01", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-04", "isOffDay": false},
+	{"name": "春节", "date": "2024-02-10", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-11", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-12", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-13", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-14", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-15", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-16", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-17", "isOffDay": true},
+	{"name": "春节", "date": "2024-02-18", "isOffDay": false},
+	{"name": "国庆节", "date": "2024-09-29", "isOffDay": false},
+	{"name": "国庆节", "date": "2024-10-01", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-02", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-03", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-04", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-05", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-06", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-07", "isOffDay": true},
+	{"name": "国庆节", "date": "2024-10-12", "isOffDay": false}
+]}`
+
+func newCalendar2024(t *testing.T) *datetime.BusinessCalendar {
+	t.Helper()
+	c := datetime.NewBusinessCalendar()
+	if err := c.LoadHolidays(strings.NewReader(holidays2024)); err != nil {
+		t.Fatalf("LoadHolidays() error = %v", err)
+	}
+	return c
+}
+
+func at(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestBusinessCalendarWorkdays(t *testing.T) {
+	c := newCalendar2024(t)
+	tests := []struct {
+		date    time.Time
+		workday bool
+		holiday string
+	}{
+		{date(2024, 1, 1), false, "元旦"},
+		{date(2024, 1, 2), true, ""},
+		{date(2024, 1, 6), false, ""},
+		{date(2024, 2, 4), true, ""}, // 调休上班的星期日
+		{date(2024, 2, 12), false, "春节"},
+		{date(2024, 2, 18), true, ""},
+		{date(2024, 10, 12), true, ""},
+		{time.Date(2024, 10, 1, 23, 0, 0, 0, time.FixedZone("CST", 8*3600)), false, "国庆节"},
+	}
+	for _, test := range tests {
+		if workday := c.IsWorkday(test.date); workday != test.workday {
+			t.Errorf("IsWorkday(%v) = %v; expected %v", test.date, workday, test.workday)
+		}
+		if name, ok := c.HolidayName(test.date); name != test.holiday || ok != (test.holiday != "") || c.IsHoliday(test.date) != ok {
+			t.Errorf("HolidayName(%v) = %q, %v; expected %q", test.date, name, ok, test.holiday)
+		}
+	}
+}
+
+func TestBusinessCalendarAddWorkdays(t *testing.T) {
+	c := newCalendar2024(t)
+	tests := []struct {
+		date     time.Time
+		n        int
+		expected time.Time
+	}{
+		{at(2024, 2, 8, 10, 30), 1, at(2024, 2, 9, 10, 30)},
+		{at(2024, 2, 9, 10, 30), 1, at(2024, 2, 18, 10, 30)},
+		{at(2024, 2, 9, 10, 30), 2, at(2024, 2, 19, 10, 30)},
+		{at(2024, 2, 18, 10, 30), -1, at(2024, 2, 9, 10, 30)},
+		{at(2024, 9, 27, 0, 0), 5, at(2024, 10, 10, 0, 0)},
+		{at(2024, 2, 12, 0, 0), 0, at(2024, 2, 12, 0, 0)},
+	}
+	for _, test := range tests {
+		if result := c.AddWorkdays(test.date, test.n); !result.Equal(test.expected) {
+			t.Errorf("AddWorkdays(%v, %d) = %v; expected %v", test.date, test.n, result, test.expected)
+		}
+		if test.n != 0 {
+			if n := c.WorkdaysBetween(test.date, test.expected); n != test.n {
+				t.Errorf("WorkdaysBetween(%v, %v) = %d; expected %d", test.date, test.expected, n, test.n)
+			}
+		}
+	}
+
+	if next := c.NextWorkday(date(2024, 9, 30)); !next.Equal(date(2024, 10, 8)) {
+		t.Errorf("NextWorkday(2024-09-30) = %v", next)
+	}
+	if previous := c.PreviousWorkday(date(2024, 10, 8)); !previous.Equal(date(2024, 9, 30)) {
+		t.Errorf("PreviousWorkday(2024-10-08) = %v", previous)
+	}
+	// 2024 has 366 days, 104 weekend days, 11 days of the excerpted holidays on weekdays and 4 make-up workdays
+	if n := c.WorkdaysBetween(date(2023, 12, 31), date(2024, 12, 31)); n != 366-104-11+4 {
+		t.Errorf("WorkdaysBetween(2024) = %d", n)
+	}
+	if n := c.WorkdaysBetween(date(2024, 1, 5), date(2024, 1, 5)); n != 0 {
+		t.Errorf("WorkdaysBetween(same day) = %d", n)
+	}
+}
+
+func TestBusinessCalendarWorkingTime(t *testing.T) {
+	c := newCalendar2024(t)
+	if err := c.SetWorkingHours("13:30-18:00", "09:00-12:00"); err != nil {
+		t.Fatalf("SetWorkingHours() error = %v", err)
+	}
+	tests := []struct {
+		start    time.Time
+		d        time.Duration
+		expected time.Time
+	}{
+		{at(2024, 1, 2, 9, 0), time.Hour, at(2024, 1, 2, 10, 0)},
+		{at(2024, 1, 2, 11, 0), 2 * time.Hour, at(2024, 1, 2, 14, 30)},
+		{at(2024, 1, 2, 12, 30), 30 * time.Minute, at(2024, 1, 2, 14, 0)},
+		{at(2024, 1, 2, 7, 0), 3 * time.Hour, at(2024, 1, 2, 12, 0)},
+		{at(2024, 1, 2, 17, 0), 2 * time.Hour, at(2024, 1, 3, 10, 0)},
+		{at(2024, 1, 2, 19, 0), time.Hour, at(2024, 1, 3, 10, 0)},
+		// 16 business hours from Friday afternoon before the Spring Festival, with the make-up workday on Sunday
+		{at(2024, 2, 9, 14, 0), 16 * time.Hour, at(2024, 2, 19, 15, 0)},
+		{at(2024, 1, 3, 10, 0), -2 * time.Hour, at(2024, 1, 2, 17, 0)},
+		{at(2024, 1, 2, 14, 0), -time.Hour, at(2024, 1, 2, 11, 30)},
+		{at(2024, 2, 19, 15, 0), -16 * time.Hour, at(2024, 2, 9, 14, 0)},
+		{at(2024, 1, 6, 10, 0), 0, at(2024, 1, 6, 10, 0)},
+	}
+	for _, test := range tests {
+		if result := c.AddWorkingTime(test.start, test.d); !result.Equal(test.expected) {
+			t.Errorf("AddWorkingTime(%v, %v) = %v; expected %v", test.start, test.d, result, test.expected)
+		}
+		if test.d > 0 {
+			if d := c.WorkingTimeBetween(test.start, test.expected); d != test.d {
+				t.Errorf("WorkingTimeBetween(%v, %v) = %v; expected %v", test.start, test.expected, d, test.d)
+			}
+		}
+	}
+	if d := c.WorkingTimeBetween(at(2024, 1, 3, 10, 0), at(2024, 1, 2, 17, 0)); d != -2*time.Hour {
+		t.Errorf("WorkingTimeBetween(reversed) = %v", d)
+	}
+
+	for _, tm := range []time.Time{at(2024, 1, 2, 9, 0), at(2024, 1, 2, 17, 59)} {
+		if !c.IsWorkingTime(tm) {
+			t.Errorf("IsWorkingTime(%v) = false", tm)
+		}
+	}
+	for _, tm := range []time.Time{at(2024, 1, 2, 12, 0), at(2024, 1, 2, 18, 0), at(2024, 1, 1, 10, 0), at(2024, 1, 6, 10, 0)} {
+		if c.IsWorkingTime(tm) {
+			t.Errorf("IsWorkingTime(%v) = true", tm)
+		}
+	}
+}
+
+func TestBusinessCalendarConfiguration(t *testing.T) {
+	c := datetime.NewBusinessCalendar()
+	for _, periods := range [][]string{nil, {"9:00-18:00x"}, {"18:00-09:00"}, {"09:00-12:00", "11:00-13:00"}, {"09:00"}} {
+		if err := c.SetWorkingHours(periods...); !errors.Is(err, datetime.ErrInvalidWorkingHours) {
+			t.Errorf("SetWorkingHours(%q) error = %v; expected %v", periods, err, datetime.ErrInvalidWorkingHours)
+		}
+	}
+	if err := c.SetWorkingHours("00:00-24:00"); err != nil {
+		t.Errorf("SetWorkingHours(00:00-24:00) error = %v", err)
+	}
+
+	allDays := []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	if err := c.SetWeekend(allDays...); err == nil {
+		t.Errorf("SetWeekend(all days) error = nil")
+	}
+	if err := c.SetWeekend(time.Friday, time.Saturday); err != nil {
+		t.Fatalf("SetWeekend() error = %v", err)
+	}
+	if c.IsWorkday(date(2024, 1, 5)) || !c.IsWorkday(date(2024, 1, 7)) {
+		t.Errorf("SetWeekend(Friday, Saturday) not applied")
+	}
+
+	c.AddHolidayRange(date(2024, 5, 1), date(2024, 5, 5), "劳动节")
+	c.AddWorkday(date(2024, 5, 11), "劳动节")
+	if c.IsWorkday(date(2024, 5, 5)) || !c.IsWorkday(date(2024, 5, 11)) || c.IsWorkday(date(2024, 5, 6)) == false {
+		t.Errorf("AddHolidayRange/AddWorkday not applied")
+	}
+	c.AddHoliday(date(2024, 5, 11), "override")
+	if c.IsWorkday(date(2024, 5, 11)) {
+		t.Errorf("AddHoliday did not override the workday")
+	}
+}
+
+func TestBusinessCalendarLoadHolidays(t *testing.T) {
+	multi := `[{"year": 2024, "days": [{"name": "元旦", "date": "2024-01-01", "isOffDay": true}]},
+		{"year": 2025, "days": [{"name": "春节", "date": "2025-01-26", "isOffDay": false}]}]`
+	path := filepath.Join(t.TempDir(), "holidays.json")
+	if err := os.WriteFile(path, []byte(multi), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := datetime.NewBusinessCalendar()
+	if err := c.LoadHolidaysFromFile(path); err != nil {
+		t.Fatalf("LoadHolidaysFromFile() error = %v", err)
+	}
+	if c.IsWorkday(date(2024, 1, 1)) || !c.IsWorkday(date(2025, 1, 26)) {
+		t.Errorf("LoadHolidaysFromFile() did not load both years")
+	}
+
+	invalid := []string{
+		``,
+		`{"year": 2024, "days": [{"name": "元旦", "date": "2024/01/01", "isOffDay": true}]}`,
+		`{"year": 2024, "days": [{"name": "元旦", "date": "2024-01-02"}]}`,
+		`{"year": 2024, "days": [{"name": "元旦", "date": "2024-01-03", "isOffDay": true}, {"date": "bad", "isOffDay": true}]}`,
+	}
+	for _, data := range invalid {
+		if err := c.LoadHolidays(strings.NewReader(data)); !errors.Is(err, datetime.ErrInvalidHolidayData) {
+			t.Errorf("LoadHolidays(%q) error = %v; expected %v", data, err, datetime.ErrInvalidHolidayData)
+		}
+	}
+	if c.IsHoliday(date(2024, 1, 3)) {
+		t.Errorf("LoadHolidays() applied part of invalid data")
+	}
+	if err := c.LoadHolidaysFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("LoadHolidaysFromFile(missing) error = nil")
+	}
+}
@@ -0,0 +1,171 @@
+package datetime_test
+
+import (
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+var clockEpoch = time.Date(2024, 8, 15, 9, 0, 0, 0, time.UTC)
+
+// received returns the value waiting on a channel, if any
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClockTimers(t *testing.T) {
+	clock := datetime.NewFakeClock(clockEpoch)
+	if now := clock.Now(); !now.Equal(clockEpoch) {
+		t.Fatalf("Now() = %v", now)
+	}
+	timer := clock.NewTimer(10 * time.Second)
+	after := clock.After(5 * time.Second)
+	ticker := clock.NewTicker(4 * time.Second)
+
+	clock.Advance(3 * time.Second)
+	if _, ok := received(timer.C()); ok {
+		t.Errorf("timer fired early")
+	}
+	if clock.Since(clockEpoch) != 3*time.Second {
+		t.Errorf("Since() = %v", clock.Since(clockEpoch))
+	}
+
+	clock.Advance(2 * time.Second)
+	if tick, ok := received(ticker.C()); !ok || !tick.Equal(clockEpoch.Add(4*time.Second)) {
+		t.Errorf("ticker = %v, %v; expected the tick at +4s", tick, ok)
+	}
+	if fired, ok := received(after); !ok || !fired.Equal(clockEpoch.Add(5*time.Second)) {
+		t.Errorf("After() = %v, %v; expected +5s", fired, ok)
+	}
+
+	// like time.Ticker, ticks that are not received are dropped
+	clock.Advance(20 * time.Second)
+	if tick, ok := received(ticker.C()); !ok || !tick.Equal(clockEpoch.Add(8*time.Second)) {
+		t.Errorf("ticker = %v, %v; expected the tick at +8s", tick, ok)
+	}
+	if _, ok := received(ticker.C()); ok {
+		t.Errorf("ticker buffered more than one tick")
+	}
+	if fired, ok := received(timer.C()); !ok || !fired.Equal(clockEpoch.Add(10*time.Second)) {
+		t.Errorf("timer = %v, %v; expected +10s", fired, ok)
+	}
+	if timer.Stop() {
+		t.Errorf("Stop() of a fired timer = true")
+	}
+	if timer.Reset(time.Second) {
+		t.Errorf("Reset() of a fired timer = true")
+	}
+	if !timer.Stop() {
+		t.Errorf("Stop() of an active timer = false")
+	}
+	clock.Advance(time.Minute)
+	if _, ok := received(timer.C()); ok {
+		t.Errorf("stopped timer fired")
+	}
+
+	received(ticker.C()) // drop the tick buffered during the last minute
+	ticker.Reset(time.Hour)
+	clock.Advance(59 * time.Minute)
+	if _, ok := received(ticker.C()); ok {
+		t.Errorf("reset ticker fired early")
+	}
+	ticker.Stop()
+	clock.Advance(2 * time.Hour)
+	if _, ok := received(ticker.C()); ok {
+		t.Errorf("stopped ticker fired")
+	}
+	if _, ok := received(clock.After(0)); !ok {
+		t.Errorf("After(0) did not fire immediately")
+	}
+}
+
+func TestFakeClockOrder(t *testing.T) {
+	clock := datetime.NewFakeClock(clockEpoch)
+	var fired []time.Duration
+	timers := map[time.Duration]datetime.Timer{}
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		timers[d] = clock.NewTimer(d)
+	}
+	clock.Set(clockEpoch.Add(time.Minute))
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		if at, ok := received(timers[d].C()); ok {
+			fired = append(fired, at.Sub(clockEpoch))
+		}
+	}
+	if len(fired) != 3 || fired[0] != time.Second || fired[1] != 2*time.Second || fired[2] != 3*time.Second {
+		t.Errorf("timers fired at %v", fired)
+	}
+	if now := clock.Now(); !now.Equal(clockEpoch.Add(time.Minute)) {
+		t.Errorf("Now() after Set = %v", now)
+	}
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	clock := datetime.NewFakeClock(clockEpoch)
+	done := make(chan time.Time)
+	go func() {
+		clock.Sleep(time.Hour)
+		done <- clock.Now()
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	select {
+	case <-done:
+		t.Fatal("Sleep returned early")
+	default:
+	}
+	clock.Advance(30 * time.Minute)
+	if woke := <-done; !woke.Equal(clockEpoch.Add(time.Hour)) {
+		t.Errorf("Sleep woke at %v", woke)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	clock := datetime.NewFakeClock(clockEpoch)
+	datetime.SetClock(clock)
+	defer datetime.SetClock(nil)
+
+	if now := datetime.GetCurrentTime(); !now.Equal(clockEpoch) {
+		t.Errorf("GetCurrentTime() = %v", now)
+	}
+	if now := datetime.Now(); !now.Equal(clockEpoch) {
+		t.Errorf("Now() = %v", now)
+	}
+	interval := datetime.NewTimeInterval()
+	clock.Advance(90 * time.Minute)
+	if minutes := interval.ElapsedMinutes(); minutes != 90 {
+		t.Errorf("ElapsedMinutes() = %d", minutes)
+	}
+	if s := datetime.NewDateTime(clockEpoch).Humanize(datetime.LocaleEnglish); s != "1 hour ago" {
+		t.Errorf("Humanize() = %q", s)
+	}
+	if now := datetime.GetCurrentUTCTime(); !now.Equal(clockEpoch.Add(90 * time.Minute)) {
+		t.Errorf("GetCurrentUTCTime() = %v", now)
+	}
+
+	datetime.SetClock(nil)
+	if now := datetime.GetCurrentTime(); time.Since(now) > time.Minute || time.Since(now) < 0 {
+		t.Errorf("GetCurrentTime() after SetClock(nil) = %v", now)
+	}
+}
+
+func TestRealClock(t *testing.T) {
+	clock := datetime.NewRealClock()
+	start := clock.Now()
+	timer := clock.NewTimer(time.Millisecond)
+	<-timer.C()
+	ticker := clock.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+	<-clock.After(time.Millisecond)
+	clock.Sleep(time.Millisecond)
+	if clock.Since(start) < 3*time.Millisecond {
+		t.Errorf("Since() = %v", clock.Since(start))
+	}
+}
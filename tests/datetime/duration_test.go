@@ -0,0 +1,185 @@
+package datetime_test
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+func TestFormatDurationCompact(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{350 * time.Millisecond, "350ms"},
+		{28*time.Hour + 3*time.Minute, "1d 4h 3m"},
+		{time.Hour + 5*time.Second + 500*time.Millisecond, "1h 5s"},
+		{-(90 * time.Minute), "-1h 30m"},
+		{14 * 24 * time.Hour, "14d"},
+	}
+	for _, test := range tests {
+		result := datetime.FormatDurationCompact(test.d)
+		if result != test.expected {
+			t.Errorf("FormatDurationCompact(%v) = %q; expected %q", test.d, result, test.expected)
+		}
+		if parsed, err := datetime.ParseDuration(result); err != nil || parsed != test.d.Truncate(time.Second) && test.d.Abs() >= time.Second {
+			t.Errorf("ParseDuration(%q) = %v, %v", result, parsed, err)
+		}
+	}
+}
+
+func TestFormatDurationLong(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		en, zh string
+	}{
+		{0, "0 seconds", "0秒"},
+		{time.Second, "1 second", "1秒"},
+		{28*time.Hour + 3*time.Minute, "1 day 4 hours 3 minutes", "1天4小时3分钟"},
+		{-2 * time.Hour, "-2 hours", "-2小时"},
+	}
+	for _, test := range tests {
+		if result := datetime.FormatDurationLong(test.d, datetime.LocaleEnglish); result != test.en {
+			t.Errorf("FormatDurationLong(%v, en) = %q; expected %q", test.d, result, test.en)
+		}
+		if result := datetime.FormatDurationLong(test.d, datetime.LocaleChinese); result != test.zh {
+			t.Errorf("FormatDurationLong(%v, zh) = %q; expected %q", test.d, result, test.zh)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"1d12h", 36 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1d 4h 3m", 28*time.Hour + 3*time.Minute},
+		{"-1.5d", -36 * time.Hour},
+		{"+90m", 90 * time.Minute},
+		{"1h30m10.5s", time.Hour + 30*time.Minute + 10500*time.Millisecond},
+		{"300ms", 300 * time.Millisecond},
+		{"2µs3ns", 2003 * time.Nanosecond},
+		{"1w2d3h4m5s6ms", 9*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second + 6*time.Millisecond},
+		{"PT1H30M", 90 * time.Minute},
+		{"P1DT4H", 28 * time.Hour},
+		{"P2W", 14 * 24 * time.Hour},
+		{"-PT1.5S", -1500 * time.Millisecond},
+	}
+	for _, test := range tests {
+		result, err := datetime.ParseDuration(test.input)
+		if err != nil || result != test.expected {
+			t.Errorf("ParseDuration(%q) = %v, %v; expected %v", test.input, result, err, test.expected)
+		}
+	}
+
+	for _, input := range []string{"", "d", "1", "1x", "1d-2h", "- 1h", ".h", "1.2.3h", "P1Y", "P1M", "1h 2", "200000w", "PT"} {
+		if result, err := datetime.ParseDuration(input); !errors.Is(err, datetime.ErrInvalidDuration) {
+			t.Errorf("ParseDuration(%q) = %v, %v; expected %v", input, result, err, datetime.ErrInvalidDuration)
+		}
+	}
+}
+
+func TestParseDurationMatchesStdlib(t *testing.T) {
+	inputs := []string{"77.264206450h", "1.000000001s", "0.1ns", "1.999999999999999999999h", ".5m", "3.0000000000000000000001us"}
+	r := rand.New(rand.NewSource(1))
+	units := []string{"ns", "us", "µs", "ms", "s", "m", "h"}
+	digits := func(n int) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteByte(byte('0' + r.Intn(10)))
+		}
+		return b.String()
+	}
+	for i := 0; i < 20000; i++ {
+		var b strings.Builder
+		for parts := 1 + r.Intn(3); parts > 0; parts-- {
+			b.WriteString(strconv.Itoa(r.Intn(100)))
+			if r.Intn(4) > 0 {
+				b.WriteString("." + digits(1+r.Intn(20)))
+			}
+			b.WriteString(units[r.Intn(len(units))])
+		}
+		inputs = append(inputs, b.String())
+	}
+	for _, input := range inputs {
+		want, err := time.ParseDuration(input)
+		if err != nil {
+			t.Fatalf("time.ParseDuration(%q): %v", input, err)
+		}
+		if got, err := datetime.ParseDuration(input); err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected datetime.ISODuration
+		str      string
+	}{
+		{"P1Y2M3DT4H", datetime.ISODuration{Years: 1, Months: 2, Days: 3, Time: 4 * time.Hour}, "P1Y2M3DT4H"},
+		{"P3W", datetime.ISODuration{Weeks: 3}, "P3W"},
+		{"PT36H", datetime.ISODuration{Time: 36 * time.Hour}, "PT36H"},
+		{"PT1M30,5S", datetime.ISODuration{Time: 90500 * time.Millisecond}, "PT1M30.5S"},
+		{"PT0S", datetime.ISODuration{}, "PT0S"},
+		{"P0D", datetime.ISODuration{}, "PT0S"},
+		{"-P1M", datetime.ISODuration{Negative: true, Months: 1}, "-P1M"},
+		{"PT0.5H", datetime.ISODuration{Time: 30 * time.Minute}, "PT30M"},
+	}
+	for _, test := range tests {
+		result, err := datetime.ParseISODuration(test.input)
+		if err != nil || result != test.expected {
+			t.Errorf("ParseISODuration(%q) = %+v, %v; expected %+v", test.input, result, err, test.expected)
+			continue
+		}
+		if str := result.String(); str != test.str {
+			t.Errorf("ISODuration(%q).String() = %q; expected %q", test.input, str, test.str)
+		}
+	}
+
+	for _, input := range []string{"", "P", "1D", "PT", "P1DT", "P1H", "PT1D", "P1D2Y", "P1.5D", "PT1.5H30M", "PT1HM", "P1Y1Y", "P99999999999Y"} {
+		if result, err := datetime.ParseISODuration(input); !errors.Is(err, datetime.ErrInvalidDuration) {
+			t.Errorf("ParseISODuration(%q) = %+v, %v; expected %v", input, result, err, datetime.ErrInvalidDuration)
+		}
+	}
+}
+
+func TestISODurationAddTo(t *testing.T) {
+	d, err := datetime.ParseISODuration("P1M1DT2H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC)
+	if end := d.AddTo(start); !end.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("AddTo(%v) = %v", start, end)
+	}
+	if length := d.DurationFrom(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)); length != 30*24*time.Hour+2*time.Hour {
+		t.Errorf("DurationFrom(2024-02-01) = %v", length)
+	}
+	negative := datetime.ISODuration{Negative: true, Years: 1, Time: time.Hour}
+	if end := negative.AddTo(start); !end.Equal(time.Date(2023, 1, 31, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("AddTo(negative) = %v", end)
+	}
+
+	for d, expected := range map[time.Duration]string{
+		0:                                  "PT0S",
+		28*time.Hour + 3*time.Minute:       "PT28H3M",
+		-1500 * time.Millisecond:           "-PT1.5S",
+		time.Minute + time.Nanosecond:      "PT1M0.000000001S",
+		2*time.Hour + 250*time.Millisecond: "PT2H0.25S",
+	} {
+		if result := datetime.FormatISODuration(d); result != expected {
+			t.Errorf("FormatISODuration(%v) = %q; expected %q", d, result, expected)
+		}
+	}
+}
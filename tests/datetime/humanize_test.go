@@ -0,0 +1,74 @@
+package datetime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+func TestHumanize(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		offset time.Duration
+		en, zh string
+	}{
+		{0, "just now", "刚刚"},
+		{-9 * time.Second, "just now", "刚刚"},
+		{-30 * time.Second, "30 seconds ago", "30秒前"},
+		{-3*time.Minute - 20*time.Second, "3 minutes ago", "3分钟前"},
+		{time.Minute, "in 1 minute", "1分钟后"},
+		{-time.Hour, "1 hour ago", "1小时前"},
+		{-23 * time.Hour, "23 hours ago", "23小时前"},
+		{2 * 24 * time.Hour, "in 2 days", "2天后"},
+		{-3 * 24 * time.Hour, "3 days ago", "3天前"},
+		{-15 * 24 * time.Hour, "2 weeks ago", "2周前"},
+		{-45 * 24 * time.Hour, "1 month ago", "1个月前"},
+		{300 * 24 * time.Hour, "in 10 months", "10个月后"},
+		{-800 * 24 * time.Hour, "2 years ago", "2年前"},
+	}
+	for _, test := range tests {
+		tm := now.Add(test.offset)
+		if result := datetime.Humanize(tm, now, datetime.LocaleEnglish); result != test.en {
+			t.Errorf("Humanize(%v, en) = %q; expected %q", test.offset, result, test.en)
+		}
+		if result := datetime.Humanize(tm, now, datetime.LocaleChinese); result != test.zh {
+			t.Errorf("Humanize(%v, zh) = %q; expected %q", test.offset, result, test.zh)
+		}
+	}
+	if result := datetime.Humanize(now.Add(-time.Hour), now, "xx"); result != "1 hour ago" {
+		t.Errorf("Humanize(unknown locale) = %q", result)
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	if err := datetime.RegisterLocale("de", datetime.LocaleNames{JustNow: "gerade eben"}); !errors.Is(err, datetime.ErrInvalidLocale) {
+		t.Errorf("RegisterLocale(incomplete) error = %v; expected %v", err, datetime.ErrInvalidLocale)
+	}
+	names := datetime.LocaleNames{
+		JustNow:   "gerade eben",
+		Past:      "vor %s",
+		Future:    "in %s",
+		Separator: " ",
+		Units: [7]datetime.UnitNames{
+			{One: "%d Sekunde", Other: "%d Sekunden"},
+			{One: "%d Minute", Other: "%d Minuten"},
+			{One: "%d Stunde", Other: "%d Stunden"},
+			{One: "%d Tag", Other: "%d Tagen"},
+			{One: "%d Woche", Other: "%d Wochen"},
+			{One: "%d Monat", Other: "%d Monaten"},
+			{One: "%d Jahr", Other: "%d Jahren"},
+		},
+	}
+	if err := datetime.RegisterLocale("de", names); err != nil {
+		t.Fatalf("RegisterLocale() error = %v", err)
+	}
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	if result := datetime.Humanize(now.AddDate(0, 0, -3), now, "de"); result != "vor 3 Tagen" {
+		t.Errorf("Humanize(de) = %q", result)
+	}
+	if result := datetime.FormatDurationLong(25*time.Hour, "de"); result != "1 Tag 1 Stunde" {
+		t.Errorf("FormatDurationLong(de) = %q", result)
+	}
+}
@@ -0,0 +1,284 @@
+package datetime_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSolarToLunar(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected datetime.Lunar
+		name     string
+	}{
+		{date(1900, 1, 31), datetime.Lunar{Year: 1900, Month: 1, Day: 1}, "庚子年正月初一"},
+		{date(1933, 1, 26), datetime.Lunar{Year: 1933, Month: 1, Day: 1}, "癸酉年正月初一"},
+		{date(1996, 2, 19), datetime.Lunar{Year: 1996, Month: 1, Day: 1}, "丙子年正月初一"},
+		{date(2024, 2, 9), datetime.Lunar{Year: 2023, Month: 12, Day: 30}, "癸卯年腊月三十"},
+		{date(2024, 2, 10), datetime.Lunar{Year: 2024, Month: 1, Day: 1}, "甲辰年正月初一"},
+		{time.Date(2024, 2, 10, 23, 59, 0, 0, time.FixedZone("CST", 8*3600)), datetime.Lunar{Year: 2024, Month: 1, Day: 1}, "甲辰年正月初一"},
+		{date(2023, 4, 20), datetime.Lunar{Year: 2023, Month: 3, Day: 1}, "癸卯年三月初一"},
+		{date(2023, 3, 22), datetime.Lunar{Year: 2023, Month: 2, Day: 1, IsLeapMonth: true}, "癸卯年闰二月初一"},
+		{date(2020, 6, 20), datetime.Lunar{Year: 2020, Month: 4, Day: 29, IsLeapMonth: true}, "庚子年闰四月廿九"},
+		{date(2020, 6, 21), datetime.Lunar{Year: 2020, Month: 5, Day: 1}, "庚子年五月初一"},
+		{date(2033, 12, 22), datetime.Lunar{Year: 2033, Month: 11, Day: 1, IsLeapMonth: true}, "癸丑年闰冬月初一"},
+		{date(2034, 1, 20), datetime.Lunar{Year: 2033, Month: 12, Day: 1}, "癸丑年腊月初一"},
+		{date(2100, 12, 31), datetime.Lunar{Year: 2100, Month: 12, Day: 1}, "庚申年腊月初一"},
+		// month starts on the day of the new moon in Beijing time
+		{date(1954, 11, 25), datetime.Lunar{Year: 1954, Month: 11, Day: 1}, "甲午年冬月初一"},
+		{date(1956, 12, 2), datetime.Lunar{Year: 1956, Month: 11, Day: 1}, "丙申年冬月初一"},
+		{date(1978, 9, 3), datetime.Lunar{Year: 1978, Month: 8, Day: 1}, "戊午年八月初一"},
+		{date(2057, 9, 28), datetime.Lunar{Year: 2057, Month: 9, Day: 1}, "丁丑年九月初一"},
+		{date(2060, 4, 30), datetime.Lunar{Year: 2060, Month: 4, Day: 1}, "庚辰年四月初一"},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.SolarToLunar(test.date)
+		if err != nil || result != test.expected || result.String() != test.name {
+			t.Errorf("SolarToLunar(%v) = %v %+v, %v; expected %v %+v", test.date, result, result, err, test.name, test.expected)
+		}
+		back, err := result.ToSolar()
+		if err != nil || !back.Equal(date(test.date.Year(), test.date.Month(), test.date.Day())) {
+			t.Errorf("%+v.ToSolar() = %v, %v; expected %v", result, back, err, test.date)
+		}
+	}
+
+	for _, d := range []time.Time{date(1900, 1, 30), date(2101, 2, 1)} {
+		if _, err := datetime.SolarToLunar(d); !errors.Is(err, datetime.ErrLunarOutOfRange) {
+			t.Errorf("SolarToLunar(%v) error = %v; expected %v", d, err, datetime.ErrLunarOutOfRange)
+		}
+	}
+}
+
+func TestLunarToSolar(t *testing.T) {
+	tests := []struct {
+		year, month, day int
+		leap             bool
+		expected         time.Time
+		err              error
+	}{
+		{2024, 8, 15, false, date(2024, 9, 17), nil},
+		{2023, 2, 29, true, date(2023, 4, 19), nil},
+		{2023, 2, 30, true, time.Time{}, datetime.ErrInvalidLunarDate},
+		{2033, 11, 1, true, date(2033, 12, 22), nil},
+		{2023, 3, 1, true, time.Time{}, datetime.ErrInvalidLunarDate},
+		{2024, 1, 30, false, time.Time{}, datetime.ErrInvalidLunarDate},
+		{2024, 13, 1, false, time.Time{}, datetime.ErrInvalidLunarDate},
+		{2024, 1, 0, false, time.Time{}, datetime.ErrInvalidLunarDate},
+		{1899, 1, 1, false, time.Time{}, datetime.ErrLunarOutOfRange},
+		{2101, 1, 1, false, time.Time{}, datetime.ErrLunarOutOfRange},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.LunarToSolar(test.year, test.month, test.day, test.leap)
+		if !errors.Is(err, test.err) || !result.Equal(test.expected) {
+			t.Errorf("LunarToSolar(%d, %d, %d, %v) = %v, %v; expected %v, %v", test.year, test.month, test.day, test.leap, result, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestLunarYearInfo(t *testing.T) {
+	tests := []struct {
+		year, leapMonth, days int
+	}{
+		{2020, 4, 384},
+		{2023, 2, 384},
+		{2024, 0, 354},
+		{2033, 11, 384},
+		{2100, 0, 354},
+		{1899, 0, 0},
+	}
+	for _, test := range tests {
+		if leap, days := datetime.LeapMonth(test.year), datetime.LunarYearDays(test.year); leap != test.leapMonth || days != test.days {
+			t.Errorf("LeapMonth(%d), LunarYearDays(%d) = %d, %d; expected %d, %d", test.year, test.year, leap, days, test.leapMonth, test.days)
+		}
+	}
+	if days := datetime.LunarMonthDays(2023, 2, true); days != 29 {
+		t.Errorf("LunarMonthDays(2023, 2, true) = %d; expected 29", days)
+	}
+	if days := datetime.LunarMonthDays(2024, 2, true); days != 0 {
+		t.Errorf("LunarMonthDays(2024, 2, true) = %d; expected 0", days)
+	}
+}
+
+func TestLunarNames(t *testing.T) {
+	tests := []struct {
+		lunar datetime.Lunar
+		month string
+		day   string
+	}{
+		{datetime.Lunar{Year: 2024, Month: 1, Day: 1}, "正月", "初一"},
+		{datetime.Lunar{Year: 2024, Month: 4, Day: 10, IsLeapMonth: true}, "闰四月", "初十"},
+		{datetime.Lunar{Year: 2024, Month: 11, Day: 15}, "冬月", "十五"},
+		{datetime.Lunar{Year: 2024, Month: 12, Day: 20}, "腊月", "二十"},
+		{datetime.Lunar{Year: 2024, Month: 10, Day: 23}, "十月", "廿三"},
+		{datetime.Lunar{Year: 2024, Month: 8, Day: 30}, "八月", "三十"},
+	}
+	for _, test := range tests {
+		if month, day := test.lunar.MonthName(), test.lunar.DayName(); month != test.month || day != test.day {
+			t.Errorf("%+v names = %v %v; expected %v %v", test.lunar, month, day, test.month, test.day)
+		}
+	}
+
+	lunar := datetime.Lunar{Year: 2024, Month: 1, Day: 1}
+	if lunar.YearGanZhi() != "甲辰" || lunar.Animal() != "龙" {
+		t.Errorf("YearGanZhi(), Animal() = %v, %v", lunar.YearGanZhi(), lunar.Animal())
+	}
+	if result, err := datetime.NewDateTime(date(2024, 9, 17)).Lunar(); err != nil || result.String() != "甲辰年八月十五" {
+		t.Errorf("DateTime.Lunar() = %v, %v", result, err)
+	}
+}
+
+func TestGetGanZhi(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected datetime.GanZhi
+	}{
+		{date(1949, 10, 1), datetime.GanZhi{Year: "己丑", Month: "癸酉", Day: "甲子"}},
+		{date(2024, 2, 3), datetime.GanZhi{Year: "癸卯", Month: "乙丑", Day: "丁酉"}},
+		{date(2024, 2, 4), datetime.GanZhi{Year: "甲辰", Month: "丙寅", Day: "戊戌"}},
+		{date(2024, 1, 5), datetime.GanZhi{Year: "癸卯", Month: "甲子", Day: "戊辰"}},
+		{date(2024, 1, 6), datetime.GanZhi{Year: "癸卯", Month: "乙丑", Day: "己巳"}},
+		{date(2024, 12, 7), datetime.GanZhi{Year: "甲辰", Month: "丙子", Day: "乙巳"}},
+	}
+	for _, test := range tests {
+		if result, err := datetime.GetGanZhi(test.date); err != nil || result != test.expected {
+			t.Errorf("GetGanZhi(%v) = %+v, %v; expected %+v", test.date, result, err, test.expected)
+		}
+	}
+	if _, err := datetime.GetGanZhi(date(2101, 1, 1)); !errors.Is(err, datetime.ErrLunarOutOfRange) {
+		t.Errorf("GetGanZhi(2101) error = %v; expected %v", err, datetime.ErrLunarOutOfRange)
+	}
+}
+
+func TestSolarTerms(t *testing.T) {
+	tests := []struct {
+		year     int
+		term     datetime.SolarTerm
+		expected time.Time
+	}{
+		{2024, datetime.StartOfSpring, date(2024, 2, 4)},
+		{2024, datetime.SpringEquinox, date(2024, 3, 20)},
+		{2024, datetime.PureBrightness, date(2024, 4, 4)},
+		{2024, datetime.SummerSolstice, date(2024, 6, 21)},
+		{2024, datetime.WinterSolstice, date(2024, 12, 21)},
+		{2025, datetime.MinorCold, date(2025, 1, 5)},
+		{2021, datetime.WinterSolstice, date(2021, 12, 21)},
+		{1900, datetime.MinorCold, date(1900, 1, 6)},
+		{2100, datetime.WinterSolstice, date(2100, 12, 22)},
+	}
+	for _, test := range tests {
+		if result, err := datetime.SolarTermDate(test.year, test.term); err != nil || !result.Equal(test.expected) {
+			t.Errorf("SolarTermDate(%d, %v) = %v, %v; expected %v", test.year, test.term, result, err, test.expected)
+		}
+	}
+
+	terms, err := datetime.SolarTermsOfYear(2024)
+	if err != nil || len(terms) != 24 {
+		t.Fatalf("SolarTermsOfYear(2024) = %v, %v", terms, err)
+	}
+	for i := 1; i < len(terms); i++ {
+		if !terms[i-1].Before(terms[i]) {
+			t.Errorf("SolarTermsOfYear(2024) is not ordered at %v", datetime.SolarTerm(i))
+		}
+	}
+	if _, err := datetime.SolarTermsOfYear(2101); !errors.Is(err, datetime.ErrLunarOutOfRange) {
+		t.Errorf("SolarTermsOfYear(2101) error = %v", err)
+	}
+	if _, err := datetime.SolarTermDate(2024, datetime.SolarTerm(24)); !errors.Is(err, datetime.ErrLunarOutOfRange) {
+		t.Errorf("SolarTermDate(2024, 24) error = %v", err)
+	}
+
+	if term, ok := datetime.GetSolarTerm(date(2024, 4, 4)); !ok || term != datetime.PureBrightness || term.String() != "清明" {
+		t.Errorf("GetSolarTerm(2024-04-04) = %v, %v", term, ok)
+	}
+	if term, ok := datetime.GetSolarTerm(date(2024, 4, 5)); ok {
+		t.Errorf("GetSolarTerm(2024-04-05) = %v, %v; expected none", term, ok)
+	}
+	if name := datetime.SolarTerm(30).String(); name != "SolarTerm(30)" {
+		t.Errorf("SolarTerm(30).String() = %v", name)
+	}
+}
+
+func TestCurrentSolarTerm(t *testing.T) {
+	tests := []struct {
+		date  time.Time
+		term  datetime.SolarTerm
+		start time.Time
+	}{
+		{date(2024, 4, 4), datetime.PureBrightness, date(2024, 4, 4)},
+		{date(2024, 4, 18), datetime.PureBrightness, date(2024, 4, 4)},
+		{date(2024, 4, 30), datetime.GrainRain, date(2024, 4, 19)},
+		{date(2024, 5, 2), datetime.GrainRain, date(2024, 4, 19)},
+		{date(2024, 1, 3), datetime.WinterSolstice, date(2023, 12, 22)},
+		{date(2024, 12, 31), datetime.WinterSolstice, date(2024, 12, 21)},
+	}
+	for _, test := range tests {
+		term, start, err := datetime.CurrentSolarTerm(test.date)
+		if err != nil || term != test.term || !start.Equal(test.start) {
+			t.Errorf("CurrentSolarTerm(%v) = %v, %v, %v; expected %v, %v", test.date, term, start, err, test.term, test.start)
+		}
+	}
+	if _, _, err := datetime.CurrentSolarTerm(date(1900, 1, 1)); !errors.Is(err, datetime.ErrLunarOutOfRange) {
+		t.Errorf("CurrentSolarTerm(1900-01-01) error = %v", err)
+	}
+}
+
+func TestFestivals(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected []string
+	}{
+		{date(2024, 2, 10), []string{"春节"}},
+		{date(2024, 2, 9), []string{"除夕"}},
+		{date(2025, 1, 28), []string{"除夕"}},
+		{date(2024, 9, 17), []string{"中秋节"}},
+		{date(2024, 4, 4), []string{"清明节"}},
+		{date(2024, 12, 21), []string{"冬至节"}},
+		{date(2024, 6, 10), []string{"端午节"}},
+		{date(2023, 3, 23), nil}, // 闰二月初二: festivals are not repeated in leap months
+		{date(2023, 2, 21), []string{"龙抬头"}},
+		{date(2024, 3, 1), nil},
+	}
+	for _, test := range tests {
+		if result := datetime.GetFestivals(test.date); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("GetFestivals(%v) = %v; expected %v", test.date, result, test.expected)
+		}
+	}
+
+	dates := []struct {
+		year     int
+		name     string
+		expected time.Time
+	}{
+		{2024, "春节", date(2024, 2, 10)},
+		{2024, "中秋节", date(2024, 9, 17)},
+		{1978, "中秋节", date(1978, 9, 17)},
+		{2023, "除夕", date(2024, 2, 9)},
+		{2023, "腊八节", date(2024, 1, 18)},
+		{2024, "清明节", date(2024, 4, 4)},
+		{2024, "端午节", date(2024, 6, 10)},
+	}
+	for _, test := range dates {
+		if result, err := datetime.FestivalDate(test.year, test.name); err != nil || !result.Equal(test.expected) {
+			t.Errorf("FestivalDate(%d, %v) = %v, %v; expected %v", test.year, test.name, result, err, test.expected)
+		}
+	}
+	if _, err := datetime.FestivalDate(2024, "圣诞节"); !errors.Is(err, datetime.ErrUnknownFestival) {
+		t.Errorf("FestivalDate(圣诞节) error = %v; expected %v", err, datetime.ErrUnknownFestival)
+	}
+	if _, err := datetime.FestivalDate(2101, "除夕"); !errors.Is(err, datetime.ErrLunarOutOfRange) {
+		t.Errorf("FestivalDate(2101, 除夕) error = %v; expected %v", err, datetime.ErrLunarOutOfRange)
+	}
+	if names := datetime.TraditionalFestivals(); len(names) != 16 || names[0] != "春节" || names[15] != "除夕" {
+		t.Errorf("TraditionalFestivals() = %v", names)
+	}
+}
@@ -0,0 +1,202 @@
+package datetime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+func TestFormatDatePattern(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	date := time.Date(2024, time.March, 7, 13, 5, 9, 123456789, shanghai)
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{datetime.NormDatetimePattern, "2024-03-07 13:05:09"},
+		{datetime.NormDatetimeMsPattern, "2024-03-07 13:05:09.123"},
+		{datetime.PureDatetimeMsPattern, "20240307130509123"},
+		{datetime.ChineseDatePattern, "2024年03月07日"},
+		{"yy-M-d H:m:s", "24-3-7 13:5:9"},
+		{"hh:mm a", "01:05 PM"},
+		{"EEE, dd MMM yyyy", "Thu, 07 Mar 2024"},
+		{"EEEE MMMM", "Thursday March"},
+		{"yyyy 'Q'Q QQQ QQQQ", "2024 Q1 Q1 1st quarter"},
+		{"'week' w", "week 10"},
+		{"yyyy-'W'ww-u|DDD|D", "2024-W10-4|067|67"},
+		{"SSSSSS|SSSSSSSSS|S", "123456|123456789|1"},
+		{"z Z ZZZZ ZZZZZ", "CST +0800 GMT+08:00 +08:00"},
+		{"X|XX|XXX", "+08|+0800|+08:00"},
+		{"'It''s' h 'o''clock'", "It's 1 o'clock"},
+		{"''yyyy''", "'2024'"},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.FormatDatePattern(date, test.pattern)
+		if err != nil || result != test.expected {
+			t.Errorf("FormatDatePattern(%q) = %q, %v; expected %q", test.pattern, result, err, test.expected)
+		}
+	}
+
+	utc := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if result, _ := datetime.FormatDatePattern(utc, "X XXX ZZZZZ ZZZZ hh a"); result != "Z Z Z GMT 12 AM" {
+		t.Errorf("FormatDatePattern(utc) = %q", result)
+	}
+	if result, _ := datetime.NewDateTime(utc).FormatPattern(datetime.UTCPattern); result != "2024-01-01T00:00:00Z" {
+		t.Errorf("DateTime.FormatPattern() = %q", result)
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	for _, pattern := range []string{"yyyy-MM-dd'T", "YYYY-MM-dd", "yyyy-MMMMM", "HHH", "SSSSSSSSSS", "k"} {
+		if _, err := datetime.CompilePattern(pattern); !errors.Is(err, datetime.ErrInvalidPattern) {
+			t.Errorf("CompilePattern(%q) error = %v; expected %v", pattern, err, datetime.ErrInvalidPattern)
+		}
+	}
+
+	p1 := datetime.MustCompilePattern(datetime.NormDatetimePattern)
+	p2 := datetime.MustCompilePattern(datetime.NormDatetimePattern)
+	if p1 != p2 {
+		t.Errorf("MustCompilePattern() did not return the cached pattern")
+	}
+	if p1.String() != datetime.NormDatetimePattern {
+		t.Errorf("Pattern.String() = %q", p1.String())
+	}
+}
+
+func TestParseDatePattern(t *testing.T) {
+	tests := []struct {
+		value    string
+		pattern  string
+		expected time.Time
+	}{
+		{"2024-03-07 13:05:09", datetime.NormDatetimePattern, time.Date(2024, 3, 7, 13, 5, 9, 0, time.UTC)},
+		{"2024-03-07 13:05:09.120", datetime.NormDatetimeMsPattern, time.Date(2024, 3, 7, 13, 5, 9, 120000000, time.UTC)},
+		{"20240307130509123", datetime.PureDatetimeMsPattern, time.Date(2024, 3, 7, 13, 5, 9, 123000000, time.UTC)},
+		{"2024年03月07日", datetime.ChineseDatePattern, time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"2024-3-7", "yyyy-M-d", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"2024-12-31", "yyyy-M-d", time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"24/03/07", "yy/MM/dd", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"13:05", "HH:mm", time.Date(1970, 1, 1, 13, 5, 0, 0, time.UTC)},
+		{"12:30 am", "hh:mm a", time.Date(1970, 1, 1, 0, 30, 0, 0, time.UTC)},
+		{"01:30 PM", "hh:mm a", time.Date(1970, 1, 1, 13, 30, 0, 0, time.UTC)},
+		{"Thu, 07 Mar 2024 05:06:07 GMT", datetime.HTTPDatetimePattern, time.Date(2024, 3, 7, 5, 6, 7, 0, time.UTC)},
+		{"March 7, 2024", "MMMM d, yyyy", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-01T00:00:00Z", datetime.UTCWithOffsetPattern, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024 Q3", "yyyy QQQ", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-W10", "yyyy-'W'ww", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"2024-W10 Thu", "yyyy-'W'ww EEE", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"2021-W01", "yyyy-'W'ww", time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"2024-W10-7", "yyyy-'W'ww-u", time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{"2024-067", "yyyy-DDD", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"2024-67", "yyyy-D", time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.ParseDatePattern(test.value, test.pattern)
+		if err != nil || !result.Equal(test.expected) {
+			t.Errorf("ParseDatePattern(%q, %q) = %v, %v; expected %v", test.value, test.pattern, result, err, test.expected)
+		}
+	}
+}
+
+func TestParseDatePatternZones(t *testing.T) {
+	tests := []struct {
+		value   string
+		pattern string
+		offset  int
+	}{
+		{"2024-03-07T13:05:09+08:00", datetime.UTCWithOffsetPattern, 8 * 3600},
+		{"2024-03-07T13:05:09.5-05:30", "yyyy-MM-dd'T'HH:mm:ss.SXXX", -(5*3600 + 30*60)},
+		{"2024-03-07 13:05:09 +0800", "yyyy-MM-dd HH:mm:ss Z", 8 * 3600},
+		{"2024-03-07 13:05:09 GMT+08:00", "yyyy-MM-dd HH:mm:ss ZZZZ", 8 * 3600},
+		{"2024-03-07 13:05:09 +08", "yyyy-MM-dd HH:mm:ss X", 8 * 3600},
+		{"2024-03-07 13:05:09 +0530", "yyyy-MM-dd HH:mm:ss X", 5*3600 + 30*60},
+		{"2024-03-07 13:05:09 UTC", "yyyy-MM-dd HH:mm:ss z", 0},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.ParseDatePattern(test.value, test.pattern)
+		if err != nil {
+			t.Errorf("ParseDatePattern(%q, %q) error = %v", test.value, test.pattern, err)
+			continue
+		}
+		if _, offset := result.Zone(); offset != test.offset || result.Hour() != 13 {
+			t.Errorf("ParseDatePattern(%q, %q) = %v; expected offset %d", test.value, test.pattern, result, test.offset)
+		}
+	}
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	result, err := datetime.ParseDatePatternInLocation("2024-03-07 13:05:09 CST", "yyyy-MM-dd HH:mm:ss z", shanghai)
+	if err != nil || !result.Equal(time.Date(2024, 3, 7, 5, 5, 9, 0, time.UTC)) {
+		t.Errorf("ParseDatePatternInLocation(CST) = %v, %v", result, err)
+	}
+	result, err = datetime.ParseDatePatternInLocation("2024-03-07 13:05", "yyyy-MM-dd HH:mm", shanghai)
+	if err != nil || result.Location() != shanghai || result.Hour() != 13 {
+		t.Errorf("ParseDatePatternInLocation() = %v, %v", result, err)
+	}
+}
+
+func TestParseDatePatternStrict(t *testing.T) {
+	tests := []struct {
+		value   string
+		pattern string
+	}{
+		{"2024-3-07", "yyyy-MM-dd"},                              // too few digits
+		{"2024-03-07 ", "yyyy-MM-dd"},                            // trailing text
+		{"2024-03-07", "yyyy-MM-dd HH:mm"},                       // missing fields
+		{"2024-13-01", "yyyy-MM-dd"},                             // month out of range
+		{"2023-02-29", "yyyy-MM-dd"},                             // invalid date
+		{"2024-04-31", "yyyy-MM-dd"},                             // invalid date
+		{"24:00", "HH:mm"},                                       // hour out of range
+		{"00:30 PM", "hh:mm a"},                                  // clock hour out of range
+		{"13:30 AM", "HH:mm a"},                                  // conflicts with AM/PM
+		{"Fri, 2024-03-07", "EEE, yyyy-MM-dd"},                   // wrong day of week
+		{"2024-03 Q2", "yyyy-MM QQQ"},                            // wrong quarter
+		{"2024-03-07 w11", "yyyy-MM-dd 'w'ww"},                   // wrong week
+		{"2024030", "yyyyMMdd"},                                  // fixed width too short
+		{"2024-Mar-07", "yyyy-MMMM-dd"},                          // short month name for MMMM
+		{"13:05:09.12", "HH:mm:ss.SSS"},                          // too few fraction digits
+		{"2024-03-07T13:05:09+0800", "yyyy-MM-dd'T'HH:mm:ssXXX"}, // offset without colon
+		{"2024-03-07 13:05:09 PST", "yyyy-MM-dd HH:mm:ss z"},     // unknown zone abbreviation
+	}
+
+	for _, test := range tests {
+		if result, err := datetime.ParseDatePattern(test.value, test.pattern); !errors.Is(err, datetime.ErrPatternMismatch) {
+			t.Errorf("ParseDatePattern(%q, %q) = %v, %v; expected %v", test.value, test.pattern, result, err, datetime.ErrPatternMismatch)
+		}
+	}
+	if _, err := datetime.ParseDatePattern("2024", "YYYY"); !errors.Is(err, datetime.ErrInvalidPattern) {
+		t.Errorf("ParseDatePattern(YYYY) error = %v; expected %v", err, datetime.ErrInvalidPattern)
+	}
+}
+
+func TestPatternRoundTrip(t *testing.T) {
+	date := time.Date(1999, time.December, 31, 23, 59, 58, 987000000, time.FixedZone("", -3*3600))
+	for _, pattern := range []string{
+		datetime.UTCMsWithOffsetPattern,
+		datetime.PureDatetimeMsPattern + "Z",
+		"EEEE, MMMM d, yyyy h:mm:ss.SSS a ZZZZ",
+	} {
+		p := datetime.MustCompilePattern(pattern)
+		result, err := p.Parse(p.Format(date))
+		if err != nil || !result.Equal(date) {
+			t.Errorf("round trip %q = %v, %v; expected %v", pattern, result, err, date)
+		}
+	}
+}
+
+func BenchmarkFormatDatePattern(b *testing.B) {
+	date := time.Date(2024, time.March, 7, 13, 5, 9, 123456789, time.UTC)
+	for i := 0; i < b.N; i++ {
+		_, _ = datetime.FormatDatePattern(date, datetime.NormDatetimeMsPattern)
+	}
+}
+
+func BenchmarkParseDatePattern(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = datetime.ParseDatePattern("2024-03-07 13:05:09.123", datetime.NormDatetimeMsPattern)
+	}
+}
@@ -0,0 +1,176 @@
+package datetime_test
+
+import (
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+func TestBeginOfEndOf(t *testing.T) {
+	tm := time.Date(2024, 8, 15, 13, 47, 25, 123456789, time.UTC) // Thursday
+	tests := []struct {
+		unit       datetime.DateUnit
+		begin, end time.Time
+	}{
+		{datetime.Second, time.Date(2024, 8, 15, 13, 47, 25, 0, time.UTC), time.Date(2024, 8, 15, 13, 47, 25, 999999999, time.UTC)},
+		{datetime.Hour, time.Date(2024, 8, 15, 13, 0, 0, 0, time.UTC), time.Date(2024, 8, 15, 13, 59, 59, 999999999, time.UTC)},
+		{datetime.Day, date(2024, 8, 15), time.Date(2024, 8, 15, 23, 59, 59, 999999999, time.UTC)},
+		{datetime.Week, date(2024, 8, 12), time.Date(2024, 8, 18, 23, 59, 59, 999999999, time.UTC)},
+		{datetime.MonthUnit, date(2024, 8, 1), time.Date(2024, 8, 31, 23, 59, 59, 999999999, time.UTC)},
+		{datetime.Quarter, date(2024, 7, 1), time.Date(2024, 9, 30, 23, 59, 59, 999999999, time.UTC)},
+		{datetime.Year, date(2024, 1, 1), time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC)},
+	}
+	for _, test := range tests {
+		if begin := datetime.BeginOf(tm, test.unit); !begin.Equal(test.begin) {
+			t.Errorf("BeginOf(%v, %d) = %v; expected %v", tm, test.unit, begin, test.begin)
+		}
+		if end := datetime.EndOf(tm, test.unit); !end.Equal(test.end) {
+			t.Errorf("EndOf(%v, %d) = %v; expected %v", tm, test.unit, end, test.end)
+		}
+	}
+	if end := datetime.EndOf(date(2024, 2, 10), datetime.MonthUnit); end.Day() != 29 {
+		t.Errorf("EndOf(2024-02, MonthUnit) = %v", end)
+	}
+
+	sunday := datetime.PeriodCalendar{WeekStart: time.Sunday}
+	if begin := sunday.BeginOf(tm, datetime.Week); !begin.Equal(date(2024, 8, 11)) {
+		t.Errorf("BeginOf(Week, Sunday start) = %v", begin)
+	}
+	if end := sunday.EndOf(date(2024, 8, 11), datetime.Week); !end.Equal(time.Date(2024, 8, 17, 23, 59, 59, 999999999, time.UTC)) {
+		t.Errorf("EndOf(Week, Sunday start) = %v", end)
+	}
+}
+
+func TestTruncateCeil(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	tests := []struct {
+		t           time.Time
+		unit        datetime.DateUnit
+		trunc, ceil time.Time
+	}{
+		{time.Date(2024, 8, 15, 13, 47, 0, 0, time.UTC), 15 * datetime.Minute,
+			time.Date(2024, 8, 15, 13, 45, 0, 0, time.UTC), time.Date(2024, 8, 15, 14, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 8, 15, 13, 45, 0, 0, time.UTC), 15 * datetime.Minute,
+			time.Date(2024, 8, 15, 13, 45, 0, 0, time.UTC), time.Date(2024, 8, 15, 13, 45, 0, 0, time.UTC)},
+		// hours are truncated on the wall clock, not from the UTC epoch
+		{time.Date(2024, 8, 15, 13, 47, 0, 0, kolkata), datetime.Hour,
+			time.Date(2024, 8, 15, 13, 0, 0, 0, kolkata), time.Date(2024, 8, 15, 14, 0, 0, 0, kolkata)},
+		{time.Date(2024, 8, 15, 23, 59, 59, 500, time.UTC), datetime.Second,
+			time.Date(2024, 8, 15, 23, 59, 59, 0, time.UTC), time.Date(2024, 8, 16, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 11, 20, 8, 0, 0, 0, time.UTC), datetime.Quarter, date(2024, 10, 1), date(2025, 1, 1)},
+		{date(2024, 1, 1), datetime.Year, date(2024, 1, 1), date(2024, 1, 1)},
+		{time.Date(1969, 12, 31, 22, 10, 0, 0, time.UTC), 6 * datetime.Hour,
+			time.Date(1969, 12, 31, 18, 0, 0, 0, time.UTC), date(1970, 1, 1)},
+	}
+	for _, test := range tests {
+		if result := datetime.Truncate(test.t, test.unit); !result.Equal(test.trunc) {
+			t.Errorf("Truncate(%v, %d) = %v; expected %v", test.t, test.unit, result, test.trunc)
+		}
+		if result := datetime.Ceil(test.t, test.unit); !result.Equal(test.ceil) {
+			t.Errorf("Ceil(%v, %d) = %v; expected %v", test.t, test.unit, result, test.ceil)
+		}
+	}
+	tm := time.Date(2024, 8, 15, 13, 47, 0, 0, time.UTC)
+	if result := datetime.Truncate(tm, 0); !result.Equal(tm) {
+		t.Errorf("Truncate(0) = %v", result)
+	}
+}
+
+func TestPeriodsBetween(t *testing.T) {
+	it := datetime.PeriodsBetween(date(2024, 1, 15), date(2024, 4, 1), datetime.MonthUnit)
+	var months []string
+	for begin, end, ok := it.Next(); ok; begin, end, ok = it.Next() {
+		months = append(months, begin.Format("2006-01-02")+"/"+end.Format("2006-01-02"))
+	}
+	expected := []string{"2024-01-01/2024-02-01", "2024-02-01/2024-03-01", "2024-03-01/2024-04-01"}
+	if len(months) != len(expected) {
+		t.Fatalf("PeriodsBetween(MonthUnit) = %v; expected %v", months, expected)
+	}
+	for i := range expected {
+		if months[i] != expected[i] {
+			t.Errorf("PeriodsBetween(MonthUnit)[%d] = %s; expected %s", i, months[i], expected[i])
+		}
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	// the spring forward day has 23 hours on the wall clock
+	var hours int
+	it = datetime.PeriodsBetween(time.Date(2024, 3, 10, 0, 0, 0, 0, ny), time.Date(2024, 3, 11, 0, 0, 0, 0, ny), datetime.Hour)
+	for _, _, ok := it.Next(); ok; _, _, ok = it.Next() {
+		hours++
+	}
+	if hours != 23 {
+		t.Errorf("PeriodsBetween(Hour) over the DST change = %d hours; expected 23", hours)
+	}
+	if _, _, ok := datetime.PeriodsBetween(date(2024, 1, 1), date(2024, 2, 1), 0).Next(); ok {
+		t.Errorf("PeriodsBetween(0).Next() = true")
+	}
+}
+
+func TestFiscalYear(t *testing.T) {
+	us := datetime.PeriodCalendar{FiscalYearStart: time.October}
+	tests := []struct {
+		date    time.Time
+		year    int
+		quarter int
+		begin   time.Time
+	}{
+		{date(2023, 9, 30), 2023, 4, date(2022, 10, 1)},
+		{date(2023, 10, 1), 2024, 1, date(2023, 10, 1)},
+		{date(2024, 1, 15), 2024, 2, date(2023, 10, 1)},
+		{date(2024, 7, 4), 2024, 4, date(2023, 10, 1)},
+	}
+	for _, test := range tests {
+		if year := us.FiscalYear(test.date); year != test.year {
+			t.Errorf("FiscalYear(%v) = %d; expected %d", test.date, year, test.year)
+		}
+		if quarter := us.FiscalQuarter(test.date); quarter != test.quarter {
+			t.Errorf("FiscalQuarter(%v) = %d; expected %d", test.date, quarter, test.quarter)
+		}
+		if begin := us.BeginOfFiscalYear(test.date); !begin.Equal(test.begin) {
+			t.Errorf("BeginOfFiscalYear(%v) = %v; expected %v", test.date, begin, test.begin)
+		}
+		if end := us.EndOfFiscalYear(test.date); !end.Equal(test.begin.AddDate(1, 0, 0).Add(-time.Nanosecond)) {
+			t.Errorf("EndOfFiscalYear(%v) = %v", test.date, end)
+		}
+	}
+	var calendar datetime.PeriodCalendar
+	if year, quarter := calendar.FiscalYear(date(2024, 12, 31)), calendar.FiscalQuarter(date(2024, 12, 31)); year != 2024 || quarter != 4 {
+		t.Errorf("FiscalYear/FiscalQuarter(default) = %d, %d", year, quarter)
+	}
+}
+
+func TestISOWeek(t *testing.T) {
+	tests := []struct {
+		date       time.Time
+		year, week int
+	}{
+		{date(2021, 1, 3), 2020, 53},
+		{date(2024, 12, 30), 2025, 1},
+		{date(2024, 8, 15), 2024, 33},
+	}
+	for _, test := range tests {
+		year, week := datetime.ISOWeek(test.date)
+		if year != test.year || week != test.week {
+			t.Errorf("ISOWeek(%v) = %d-W%d; expected %d-W%d", test.date, year, week, test.year, test.week)
+		}
+		start := datetime.ISOWeekStart(year, week, time.UTC)
+		if start.Weekday() != time.Monday || test.date.Before(start) || !test.date.Before(start.AddDate(0, 0, 7)) {
+			t.Errorf("ISOWeekStart(%d, %d) = %v", year, week, start)
+		}
+	}
+	for year, weeks := range map[int]int{2015: 53, 2020: 53, 2024: 52, 2026: 53} {
+		if result := datetime.ISOWeeksInYear(year); result != weeks {
+			t.Errorf("ISOWeeksInYear(%d) = %d; expected %d", year, result, weeks)
+		}
+	}
+	for i, quarter := range []int{1, 1, 1, 2, 2, 2, 3, 3, 3, 4, 4, 4} {
+		if result := datetime.QuarterOf(date(2024, time.Month(i+1), 1)); result != quarter {
+			t.Errorf("QuarterOf(month %d) = %d; expected %d", i+1, result, quarter)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package datetime_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+// formatOccurrences formats occurrences as local "2006-01-02 15:04 MST" strings joined by commas
+func formatOccurrences(times []time.Time) string {
+	items := make([]string, len(times))
+	for i, t := range times {
+		items[i] = t.Format("2006-01-02 15:04 MST")
+	}
+	return strings.Join(items, ",")
+}
+
+func TestRecurrenceRFC5545Examples(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		to       string
+		expected string // dates of the occurrences, all at the time of DTSTART
+	}{
+		{
+			"daily for 10 occurrences",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=DAILY;COUNT=10", "",
+			"1997-09-02,1997-09-03,1997-09-04,1997-09-05,1997-09-06,1997-09-07,1997-09-08,1997-09-09,1997-09-10,1997-09-11",
+		},
+		{
+			"weekly on Tuesday and Thursday for five weeks",
+			"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=WEEKLY;COUNT=10;WKST=SU;BYDAY=TU,TH", "",
+			"1997-09-02,1997-09-04,1997-09-09,1997-09-11,1997-09-16,1997-09-18,1997-09-23,1997-09-25,1997-09-30,1997-10-02",
+		},
+		{
+			"every other week on Monday, Wednesday and Friday until December 24",
+			"DTSTART;TZID=America/New_York:19970901T090000\nRRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;WKST=SU;BYDAY=MO,WE,FR", "",
+			"1997-09-01,1997-09-03,1997-09-05,1997-09-15,1997-09-17,1997-09-19,1997-09-29,1997-10-01,1997-10-03," +
+				"1997-10-13,1997-10-15,1997-10-17,1997-10-27,1997-10-29,1997-10-31,1997-11-10,1997-11-12,1997-11-14," +
+				"1997-11-24,1997-11-26,1997-11-28,1997-12-08,1997-12-10,1997-12-12,1997-12-22",
+		},
+		{
+			"monthly on the first Friday",
+			"DTSTART;TZID=America/New_York:19970905T090000\nRRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR", "",
+			"1997-09-05,1997-10-03,1997-11-07,1997-12-05,1998-01-02,1998-02-06,1998-03-06,1998-04-03,1998-05-01,1998-06-05",
+		},
+		{
+			"monthly on the third-to-the-last day",
+			"DTSTART;TZID=America/New_York:19970928T090000\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-3;COUNT=6", "",
+			"1997-09-28,1997-10-29,1997-11-28,1997-12-29,1998-01-29,1998-02-26",
+		},
+		{
+			"last work day of the month",
+			"DTSTART;TZID=America/New_York:19970929T090000\nRRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=7", "",
+			"1997-09-29,1997-09-30,1997-10-31,1997-11-28,1997-12-31,1998-01-30,1998-02-27",
+		},
+		{
+			"every Friday the 13th",
+			"DTSTART;TZID=America/New_York:19970902T090000\nEXDATE;TZID=America/New_York:19970902T090000\nRRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			"20001231T000000Z",
+			"1998-02-13,1998-03-13,1998-11-13,1999-08-13,2000-10-13",
+		},
+		{
+			"yearly on the 20th Monday",
+			"DTSTART;TZID=America/New_York:19970519T090000\nRRULE:FREQ=YEARLY;BYDAY=20MO;COUNT=3", "",
+			"1997-05-19,1998-05-18,1999-05-17",
+		},
+		{
+			"yearly in June and July",
+			"DTSTART;TZID=America/New_York:19970610T090000\nRRULE:FREQ=YEARLY;COUNT=6;BYMONTH=6,7", "",
+			"1997-06-10,1997-07-10,1998-06-10,1998-07-10,1999-06-10,1999-07-10",
+		},
+		{
+			"monthly on the 31st skips short months",
+			"DTSTART:20240131T090000Z\nRRULE:FREQ=MONTHLY;COUNT=5", "",
+			"2024-01-31,2024-03-31,2024-05-31,2024-07-31,2024-08-31",
+		},
+		{
+			"US Presidential Election Day",
+			"DTSTART;TZID=America/New_York:19961105T090000\nRRULE:FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8;COUNT=3", "",
+			"1996-11-05,2000-11-07,2004-11-02",
+		},
+	}
+	for _, test := range tests {
+		rec, err := datetime.ParseRecurrence(test.input, nil)
+		if err != nil {
+			t.Errorf("%s: ParseRecurrence() error = %v", test.name, err)
+			continue
+		}
+		var to time.Time
+		if test.to != "" {
+			to, _ = time.Parse("20060102T150405Z", test.to)
+		}
+		occurrences, err := rec.Occurrences(rec.Start, to)
+		if err != nil {
+			t.Errorf("%s: Occurrences() error = %v", test.name, err)
+			continue
+		}
+		dates := make([]string, len(occurrences))
+		for i, o := range occurrences {
+			dates[i] = o.Format(time.DateOnly)
+			if o.Hour() != 9 || o.Minute() != 0 || o.Location() != rec.Start.Location() {
+				t.Errorf("%s: occurrence %v is not at 09:00 in %v", test.name, o, rec.Start.Location())
+			}
+		}
+		if result := strings.Join(dates, ","); result != test.expected {
+			t.Errorf("%s:\n got %s\nwant %s", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestRecurrenceEveryDayInJanuary(t *testing.T) {
+	rec, err := datetime.ParseRecurrence("DTSTART;TZID=America/New_York:19980101T090000\n"+
+		"RRULE:FREQ=YEARLY;UNTIL=20000131T140000Z;BYMONTH=1;BYDAY=SU,MO,TU,WE,TH,FR,SA", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	occurrences, err := rec.Occurrences(rec.Start, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(occurrences) != 93 || occurrences[31].Format(time.DateOnly) != "1999-01-01" || occurrences[92].Format(time.DateOnly) != "2000-01-31" {
+		t.Errorf("got %d occurrences: %s", len(occurrences), formatOccurrences(occurrences))
+	}
+}
+
+func TestRecurrenceDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	tests := []struct {
+		start    time.Time
+		rule     string
+		expected string
+	}{
+		// 02:30 does not exist on 2024-03-10 and is moved forward by the one hour gap
+		{time.Date(2024, 3, 9, 2, 30, 0, 0, ny), "FREQ=DAILY;COUNT=3", "2024-03-09 02:30 EST,2024-03-10 03:30 EDT,2024-03-11 02:30 EDT"},
+		// 01:30 happens twice on 2024-11-03 and the first one is used
+		{time.Date(2024, 11, 2, 1, 30, 0, 0, ny), "FREQ=DAILY;COUNT=3", "2024-11-02 01:30 EDT,2024-11-03 01:30 EDT,2024-11-04 01:30 EST"},
+		// weekly meetings stay at 09:00 local time
+		{time.Date(2024, 3, 4, 9, 0, 0, 0, ny), "FREQ=WEEKLY;COUNT=3", "2024-03-04 09:00 EST,2024-03-11 09:00 EDT,2024-03-18 09:00 EDT"},
+		// hourly rules step in elapsed time
+		{time.Date(2024, 3, 10, 0, 0, 0, 0, ny), "FREQ=HOURLY;COUNT=4", "2024-03-10 00:00 EST,2024-03-10 01:00 EST,2024-03-10 03:00 EDT,2024-03-10 04:00 EDT"},
+	}
+	for _, test := range tests {
+		rule, err := datetime.ParseRRule(test.rule, ny)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := &datetime.Recurrence{Start: test.start, Rule: rule}
+		occurrences, err := rec.Occurrences(test.start, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := formatOccurrences(occurrences); result != test.expected {
+			t.Errorf("%v %s:\n got %s\nwant %s", test.start, test.rule, result, test.expected)
+		}
+	}
+}
+
+func TestRecurrenceSubDaily(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"DTSTART;TZID=America/New_York:19970902T090000\nRRULE:FREQ=HOURLY;INTERVAL=3;UNTIL=19970902T210000Z",
+			"1997-09-02 09:00 EDT,1997-09-02 12:00 EDT,1997-09-02 15:00 EDT"},
+		{"DTSTART:20240107T230000Z\nRRULE:FREQ=MINUTELY;INTERVAL=20;BYDAY=MO;COUNT=4",
+			"2024-01-07 23:00 UTC,2024-01-08 00:00 UTC,2024-01-08 00:20 UTC,2024-01-08 00:40 UTC"},
+		{"DTSTART:20240101T000000Z\nRRULE:FREQ=SECONDLY;INTERVAL=30;BYMONTH=12;COUNT=3",
+			"2024-01-01 00:00 UTC,2024-12-01 00:00 UTC,2024-12-01 00:00 UTC"},
+	}
+	for _, test := range tests {
+		rec, err := datetime.ParseRecurrence(test.input, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		occurrences, err := rec.Occurrences(rec.Start, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := formatOccurrences(occurrences); result != test.expected {
+			t.Errorf("%q:\n got %s\nwant %s", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestRecurrenceBetween(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	rec, err := datetime.ParseRecurrence("DTSTART;TZID=Europe/Berlin:20000103T090000\nRRULE:FREQ=WEEKLY;BYDAY=MO\n"+
+		"EXDATE;TZID=Europe/Berlin:20240401T090000\nRDATE:20240403T100000Z,20240325T080000Z", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	it, err := rec.Between(time.Date(2024, 3, 25, 9, 0, 0, 0, berlin), time.Date(2024, 4, 15, 9, 0, 0, 0, berlin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var occurrences []time.Time
+	for o, ok := it.Next(); ok; o, ok = it.Next() {
+		occurrences = append(occurrences, o)
+	}
+	// the RDATE on 03-25 is the rule occurrence at 09:00 CET and is not repeated
+	expected := "2024-03-25 09:00 CET,2024-04-03 10:00 UTC,2024-04-08 09:00 CEST"
+	if result := formatOccurrences(occurrences); result != expected {
+		t.Errorf("Between():\n got %s\nwant %s", result, expected)
+	}
+	if o, ok := it.Next(); ok {
+		t.Errorf("Next() after the end = %v", o)
+	}
+
+	// unbounded iteration is lazy
+	it, err = rec.Between(rec.Start, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if o, ok := it.Next(); !ok || o.Format(time.DateOnly) != []string{"2000-01-03", "2000-01-10", "2000-01-17"}[i] {
+			t.Errorf("Next() = %v, %v", o, ok)
+		}
+	}
+
+	rdates := &datetime.Recurrence{Start: date(2024, 1, 1), RDates: []time.Time{date(2024, 3, 1), date(2024, 2, 1), date(2024, 2, 1)}}
+	occurrences, err = rdates.Occurrences(date(2024, 1, 15), time.Time{})
+	if err != nil || formatOccurrences(occurrences) != "2024-02-01 00:00 UTC,2024-03-01 00:00 UTC" {
+		t.Errorf("Occurrences(RDATE only) = %s, %v", formatOccurrences(occurrences), err)
+	}
+	if _, err := (&datetime.Recurrence{}).Between(time.Time{}, time.Time{}); !errors.Is(err, datetime.ErrInvalidRecurrence) {
+		t.Errorf("Between(zero start) error = %v", err)
+	}
+}
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=12", "FREQ=MONTHLY;COUNT=12;BYDAY=-1FR"},
+		{"freq=weekly;interval=2;byday=mo,we,+2fr;wkst=su", ""},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;WKST=SU", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;WKST=SU"},
+		{"FREQ=YEARLY;UNTIL=20301231;BYMONTH=1,7;BYMONTHDAY=1,-1;BYSETPOS=1", "FREQ=YEARLY;UNTIL=20301231T235959Z;BYMONTH=1,7;BYMONTHDAY=1,-1;BYSETPOS=1"},
+		{"FREQ=DAILY;UNTIL=20240101T120000Z", "FREQ=DAILY;UNTIL=20240101T120000Z"},
+	}
+	for _, test := range tests {
+		rule, err := datetime.ParseRRule(test.input, nil)
+		if test.expected == "" {
+			if !errors.Is(err, datetime.ErrInvalidRecurrence) {
+				t.Errorf("ParseRRule(%q) error = %v; expected %v", test.input, err, datetime.ErrInvalidRecurrence)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRRule(%q) error = %v", test.input, err)
+			continue
+		}
+		if result := rule.String(); result != test.expected {
+			t.Errorf("ParseRRule(%q).String() = %q; expected %q", test.input, result, test.expected)
+		}
+	}
+
+	for _, input := range []string{
+		"", "COUNT=3", "FREQ=FORTNIGHTLY", "FREQ=DAILY;COUNT=0", "FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;INTERVAL=-1", "FREQ=WEEKLY;BYMONTHDAY=1", "FREQ=DAILY;BYDAY=1MO", "FREQ=MONTHLY;BYDAY=0MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32", "FREQ=YEARLY;BYMONTH=13", "FREQ=DAILY;BYHOUR=9", "FREQ=DAILY;FREQ=DAILY",
+		"FREQ=DAILY;BYSETPOS=0", "FREQ=DAILY;WKST=XX", "FREQ=DAILY;UNTIL=2024",
+	} {
+		if _, err := datetime.ParseRRule(input, nil); !errors.Is(err, datetime.ErrInvalidRecurrence) {
+			t.Errorf("ParseRRule(%q) error = %v; expected %v", input, err, datetime.ErrInvalidRecurrence)
+		}
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"RRULE:FREQ=DAILY",
+		"DTSTART:20240101T000000Z\nDTSTART:20240102T000000Z",
+		"DTSTART:2024-01-01",
+		"DTSTART:20240101T000000Z\nRRULE:FREQ=DAILY\nRRULE:FREQ=WEEKLY",
+		"DTSTART:20240101T000000Z\nSUMMARY:meeting",
+		"DTSTART:20240101T000000Z\nEXDATE:",
+	} {
+		if _, err := datetime.ParseRecurrence(input, nil); !errors.Is(err, datetime.ErrInvalidRecurrence) {
+			t.Errorf("ParseRecurrence(%q) error = %v; expected %v", input, err, datetime.ErrInvalidRecurrence)
+		}
+	}
+	if _, err := datetime.ParseRecurrence("DTSTART;TZID=Nowhere/City:20240101T000000", nil); err == nil {
+		t.Errorf("ParseRecurrence(unknown TZID) error = nil")
+	}
+}
+
+func BenchmarkRecurrenceBetween(b *testing.B) {
+	rec, err := datetime.ParseRecurrence("DTSTART:20000103T090000Z\nRRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	from, to := date(2024, 1, 1), date(2025, 1, 1)
+	for i := 0; i < b.N; i++ {
+		if _, err := rec.Occurrences(from, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package datetime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+func TestParse(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min, sec, nsec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+	}
+	tests := []struct {
+		value    string
+		expected time.Time
+		pattern  string
+	}{
+		{"2024-03-05", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy-MM-dd"},
+		{"2024-03-05T14:30:00Z", utc(2024, 3, 5, 14, 30, 0, 0), "yyyy-MM-dd'T'HH:mm:ssX"},
+		{"2024-03-05T14:30:00.123456+08:00", utc(2024, 3, 5, 6, 30, 0, 123456000), "yyyy-MM-dd'T'HH:mm:ss.SSSSSSXXX"},
+		{"2024-03-05 14:30:00,5 -0500", utc(2024, 3, 5, 19, 30, 0, 500000000), "yyyy-MM-dd HH:mm:ss,S XX"},
+		{"2024-03-05T14:30", utc(2024, 3, 5, 14, 30, 0, 0), "yyyy-MM-dd'T'HH:mm"},
+		{"20240305T143000Z", utc(2024, 3, 5, 14, 30, 0, 0), "yyyyMMdd'T'HHmmssX"},
+		{"Tue, 05 Mar 2024 14:30:00 GMT", utc(2024, 3, 5, 14, 30, 0, 0), "EEE, dd MMM yyyy HH:mm:ss z"},
+		{"Tue, 5 Mar 2024 14:30:00 +0800", utc(2024, 3, 5, 6, 30, 0, 0), "EEE, d MMM yyyy HH:mm:ss XX"},
+		{"Tue, 5 Mar 2024 09:30:00 EST", utc(2024, 3, 5, 14, 30, 0, 0), "EEE, d MMM yyyy HH:mm:ss z"},
+		{"Tuesday, 05-Mar-24 14:30:00 UTC", utc(2024, 3, 5, 14, 30, 0, 0), "EEEE, dd-MMM-yy HH:mm:ss z"},
+		{"Tue Mar  5 14:30:00 2024", utc(2024, 3, 5, 14, 30, 0, 0), "EEE MMM  d HH:mm:ss yyyy"},
+		{"Tue Mar 05 14:30:00 -0700 2024", utc(2024, 3, 5, 21, 30, 0, 0), "EEE MMM dd HH:mm:ss XX yyyy"},
+		{"2024/3/5", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy/M/d"},
+		{"2024.03.05 8:05", utc(2024, 3, 5, 8, 5, 0, 0), "yyyy.MM.dd H:mm"},
+		{"20240305", utc(2024, 3, 5, 0, 0, 0, 0), "yyyyMMdd"},
+		{"20240305143000", utc(2024, 3, 5, 14, 30, 0, 0), "yyyyMMddHHmmss"},
+		{"03/05/2024", utc(2024, 3, 5, 0, 0, 0, 0), "MM/dd/yyyy"},
+		{"25/12/2024", utc(2024, 12, 25, 0, 0, 0, 0), "dd/MM/yyyy"},
+		{"2024年3月5日", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy年M月d日"},
+		{"2024年3月5日 14时30分", utc(2024, 3, 5, 14, 30, 0, 0), "yyyy年M月d日 HH时mm分"},
+		{"2024年03月05日14点30分15秒", utc(2024, 3, 5, 14, 30, 15, 0), "yyyy年MM月dd日HH点mm分ss秒"},
+		{"2024年3月5日下午2点", utc(2024, 3, 5, 14, 0, 0, 0), "yyyy年M月d日ah点"},
+		{"2024年3月5日上午9点30分", utc(2024, 3, 5, 9, 30, 0, 0), "yyyy年M月d日ah点mm分"},
+		{"2024年3月5日凌晨12点", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy年M月d日ahh点"},
+		{"2024-03-05 晚上8:05", utc(2024, 3, 5, 20, 5, 0, 0), "yyyy-MM-dd ah:mm"},
+		{"Mar 5, 2024 2:30 PM", utc(2024, 3, 5, 14, 30, 0, 0), "MMM d, yyyy h:mm a"},
+		{"March 5th, 2024 at 9am", utc(2024, 3, 5, 9, 0, 0, 0), "MMMM d'th', yyyy 'at' ha"},
+		{"5 March 2024", utc(2024, 3, 5, 0, 0, 0, 0), "d MMMM yyyy"},
+		{"12:30:45", utc(1970, 1, 1, 12, 30, 45, 0), "HH:mm:ss"},
+		{"1709649000", utc(2024, 3, 5, 14, 30, 0, 0), datetime.PatternUnix},
+		{"1709649000.25", utc(2024, 3, 5, 14, 30, 0, 250000000), datetime.PatternUnix},
+		{"1709649000123", utc(2024, 3, 5, 14, 30, 0, 123000000), datetime.PatternUnixMilli},
+		{"1709649000123456", utc(2024, 3, 5, 14, 30, 0, 123456000), datetime.PatternUnixMicro},
+		{"1709649000123456789", utc(2024, 3, 5, 14, 30, 0, 123456789), datetime.PatternUnixNano},
+		{"  2024-03-05  ", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy-MM-dd"},
+		{"2024", utc(2024, 1, 1, 0, 0, 0, 0), "yyyy"},
+		{"202403", utc(2024, 3, 1, 0, 0, 0, 0), "yyyyMM"},
+		{"170964900", utc(1975, 6, 2, 18, 15, 0, 0), datetime.PatternUnix},
+		{"2024-W10-2", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy-'W'ww-u"},
+		{"2024W102", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy'W'wwu"},
+		{"2024-W10", utc(2024, 3, 4, 0, 0, 0, 0), "yyyy-'W'ww"},
+		{"2025-W01-1", utc(2024, 12, 30, 0, 0, 0, 0), "yyyy-'W'ww-u"},
+		{"2020-W53-7T14:30:00Z", utc(2021, 1, 3, 14, 30, 0, 0), "yyyy-'W'ww-u'T'HH:mm:ssX"},
+		{"2024-065", utc(2024, 3, 5, 0, 0, 0, 0), "yyyy-DDD"},
+		{"2024065", utc(2024, 3, 5, 0, 0, 0, 0), "yyyyDDD"},
+		{"2024-366T14:30:00+08:00", utc(2024, 12, 31, 6, 30, 0, 0), "yyyy-DDD'T'HH:mm:ssXXX"},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.Parse(test.value)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", test.value, err)
+			continue
+		}
+		if !result.Time.Equal(test.expected) || result.Pattern != test.pattern {
+			t.Errorf("Parse(%q) = %v, %q; expected %v, %q", test.value, result.Time, result.Pattern, test.expected, test.pattern)
+		}
+	}
+}
+
+func TestParseWithOptions(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		value    string
+		opts     datetime.ParseOptions
+		expected time.Time
+	}{
+		{"03/05/2024", datetime.ParseOptions{DayFirst: true}, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"03/25/2024", datetime.ParseOptions{DayFirst: true}, time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)},
+		{"3.5.24", datetime.ParseOptions{DayFirst: true}, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-05 14:30", datetime.ParseOptions{Location: shanghai}, time.Date(2024, 3, 5, 14, 30, 0, 0, shanghai)},
+		{"2024-03-05 14:30:00 CST", datetime.ParseOptions{Location: shanghai}, time.Date(2024, 3, 5, 14, 30, 0, 0, shanghai)},
+		{"2024-03-05 14:30:00 CST", datetime.ParseOptions{}, time.Date(2024, 3, 5, 20, 30, 0, 0, time.UTC)},
+		{"2024-03-05T14:30:00Z", datetime.ParseOptions{Location: shanghai}, time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		result, err := datetime.ParseWithOptions(test.value, test.opts)
+		if err != nil || !result.Time.Equal(test.expected) {
+			t.Errorf("ParseWithOptions(%q, %+v) = %+v, %v; expected %v", test.value, test.opts, result, err, test.expected)
+		}
+	}
+
+	result, err := datetime.ParseWithOptions("1709649000", datetime.ParseOptions{Location: shanghai})
+	if err != nil || result.Time.Location() != shanghai || result.Time.Hour() != 22 {
+		t.Errorf("ParseWithOptions(unix) = %+v, %v", result, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, value := range []string{"", "   ", "hello", "2024-03-05 foo", "1/2/3/4", "2024-03-05T14:30:00+8", "12:30:45:10", "123", "-1", "12345"} {
+		if result, err := datetime.Parse(value); !errors.Is(err, datetime.ErrUnrecognizedFormat) {
+			t.Errorf("Parse(%q) = %+v, %v; expected %v", value, result, err, datetime.ErrUnrecognizedFormat)
+		}
+	}
+	for _, value := range []string{"2023-02-29", "2024-13-45", "Mon, 5 Mar 2024", "2024-03-05 25:00", "2023-366", "2024-W10-8", "2021-W53"} {
+		if result, err := datetime.Parse(value); !errors.Is(err, datetime.ErrPatternMismatch) {
+			t.Errorf("Parse(%q) = %+v, %v; expected %v", value, result, err, datetime.ErrPatternMismatch)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package datetime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+// hours returns the range [from:00, to:00) on 2024-08-15 UTC
+func hours(from, to int) datetime.TimeRange {
+	return datetime.TimeRange{Start: time.Date(2024, 8, 15, from, 0, 0, 0, time.UTC), End: time.Date(2024, 8, 15, to, 0, 0, 0, time.UTC)}
+}
+
+func equalRanges(a, b []datetime.TimeRange) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Start.Equal(b[i].Start) || !a[i].End.Equal(b[i].End) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewTimeRange(t *testing.T) {
+	if _, err := datetime.NewTimeRange(date(2024, 1, 2), date(2024, 1, 1)); !errors.Is(err, datetime.ErrInvalidRange) {
+		t.Errorf("NewTimeRange(end before start) error = %v; expected %v", err, datetime.ErrInvalidRange)
+	}
+	r, err := datetime.NewTimeRange(date(2024, 1, 1), date(2024, 1, 1))
+	if err != nil || !r.IsEmpty() || r.Duration() != 0 {
+		t.Errorf("NewTimeRange(empty) = %v, %v", r, err)
+	}
+	if s := hours(9, 10).String(); s != "[2024-08-15T09:00:00Z, 2024-08-15T10:00:00Z)" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestTimeRangeRelations(t *testing.T) {
+	r := hours(9, 12)
+	if !r.Contains(r.Start) || r.Contains(r.End) || r.Duration() != 3*time.Hour {
+		t.Errorf("Contains/Duration of %v are wrong", r)
+	}
+	if !r.ContainsRange(hours(10, 12)) || r.ContainsRange(hours(10, 13)) || !r.ContainsRange(hours(20, 20)) {
+		t.Errorf("ContainsRange of %v is wrong", r)
+	}
+	tests := []struct {
+		other        datetime.TimeRange
+		overlaps     bool
+		intersection datetime.TimeRange
+		union        []datetime.TimeRange
+		subtract     []datetime.TimeRange
+	}{
+		{hours(10, 11), true, hours(10, 11), []datetime.TimeRange{hours(9, 12)}, []datetime.TimeRange{hours(9, 10), hours(11, 12)}},
+		{hours(11, 14), true, hours(11, 12), []datetime.TimeRange{hours(9, 14)}, []datetime.TimeRange{hours(9, 11)}},
+		{hours(8, 10), true, hours(9, 10), []datetime.TimeRange{hours(8, 12)}, []datetime.TimeRange{hours(10, 12)}},
+		{hours(12, 13), false, datetime.TimeRange{}, []datetime.TimeRange{hours(9, 13)}, []datetime.TimeRange{hours(9, 12)}},
+		{hours(14, 15), false, datetime.TimeRange{}, []datetime.TimeRange{hours(9, 12), hours(14, 15)}, []datetime.TimeRange{hours(9, 12)}},
+		{hours(8, 13), true, hours(9, 12), []datetime.TimeRange{hours(8, 13)}, nil},
+		{hours(10, 10), false, datetime.TimeRange{}, []datetime.TimeRange{hours(9, 12)}, []datetime.TimeRange{hours(9, 12)}},
+	}
+	for _, test := range tests {
+		if overlaps := r.Overlaps(test.other); overlaps != test.overlaps || test.other.Overlaps(r) != overlaps {
+			t.Errorf("Overlaps(%v) = %v; expected %v", test.other, overlaps, test.overlaps)
+		}
+		intersection, ok := r.Intersect(test.other)
+		if ok != test.overlaps || intersection != test.intersection {
+			t.Errorf("Intersect(%v) = %v, %v; expected %v", test.other, intersection, ok, test.intersection)
+		}
+		if union := r.Union(test.other); !equalRanges(union, test.union) {
+			t.Errorf("Union(%v) = %v; expected %v", test.other, union, test.union)
+		}
+		if rest := r.Subtract(test.other); !equalRanges(rest, test.subtract) {
+			t.Errorf("Subtract(%v) = %v; expected %v", test.other, rest, test.subtract)
+		}
+	}
+}
+
+func TestMergeAndSubtractRanges(t *testing.T) {
+	merged := datetime.MergeRanges([]datetime.TimeRange{hours(14, 15), hours(9, 10), hours(10, 11), hours(20, 20), hours(13, 16), hours(9, 10)})
+	if expected := []datetime.TimeRange{hours(9, 11), hours(13, 16)}; !equalRanges(merged, expected) {
+		t.Errorf("MergeRanges() = %v; expected %v", merged, expected)
+	}
+	if merged := datetime.MergeRanges(nil); len(merged) != 0 {
+		t.Errorf("MergeRanges(nil) = %v", merged)
+	}
+
+	working := []datetime.TimeRange{hours(9, 12), hours(13, 18)}
+	busy := []datetime.TimeRange{hours(10, 11), hours(11, 14), hours(17, 19)}
+	available := datetime.SubtractRanges(working, busy)
+	if expected := []datetime.TimeRange{hours(9, 10), hours(14, 17)}; !equalRanges(available, expected) {
+		t.Errorf("SubtractRanges() = %v; expected %v", available, expected)
+	}
+}
+
+func TestTimeRangeSplit(t *testing.T) {
+	r := datetime.TimeRange{Start: time.Date(2024, 8, 12, 18, 0, 0, 0, time.UTC), End: time.Date(2024, 8, 14, 9, 0, 0, 0, time.UTC)}
+	expected := []datetime.TimeRange{
+		{Start: r.Start, End: date(2024, 8, 13)},
+		{Start: date(2024, 8, 13), End: date(2024, 8, 14)},
+		{Start: date(2024, 8, 14), End: r.End},
+	}
+	if pieces := r.Split(datetime.Day); !equalRanges(pieces, expected) {
+		t.Errorf("Split(Day) = %v; expected %v", pieces, expected)
+	}
+	if pieces := r.Split(datetime.MonthUnit); !equalRanges(pieces, []datetime.TimeRange{r}) {
+		t.Errorf("Split(MonthUnit) = %v", pieces)
+	}
+	if pieces := hours(9, 9).Split(datetime.Hour); pieces != nil {
+		t.Errorf("Split(empty) = %v", pieces)
+	}
+}
+
+func TestTimeRangeIterate(t *testing.T) {
+	tests := []struct {
+		r        datetime.TimeRange
+		step     datetime.DateUnit
+		expected []time.Time
+	}{
+		{hours(9, 10), 20 * datetime.Minute, []time.Time{
+			time.Date(2024, 8, 15, 9, 0, 0, 0, time.UTC), time.Date(2024, 8, 15, 9, 20, 0, 0, time.UTC), time.Date(2024, 8, 15, 9, 40, 0, 0, time.UTC)}},
+		{datetime.TimeRange{Start: date(2024, 1, 31), End: date(2024, 6, 1)}, datetime.MonthUnit, []time.Time{
+			date(2024, 1, 31), date(2024, 2, 29), date(2024, 3, 31), date(2024, 4, 30), date(2024, 5, 31)}},
+		{datetime.TimeRange{Start: date(2024, 2, 29), End: date(2029, 1, 1)}, datetime.Year, []time.Time{
+			date(2024, 2, 29), date(2025, 2, 28), date(2026, 2, 28), date(2027, 2, 28), date(2028, 2, 29)}},
+		{datetime.TimeRange{Start: date(2023, 11, 30), End: date(2024, 9, 1)}, datetime.Quarter, []time.Time{
+			date(2023, 11, 30), date(2024, 2, 29), date(2024, 5, 30), date(2024, 8, 30)}},
+		{datetime.TimeRange{Start: date(2024, 1, 1), End: date(2025, 1, 1)}, datetime.Quarter, []time.Time{
+			date(2024, 1, 1), date(2024, 4, 1), date(2024, 7, 1), date(2024, 10, 1)}},
+		{datetime.TimeRange{Start: date(2024, 1, 1), End: date(2024, 1, 20)}, datetime.Week, []time.Time{
+			date(2024, 1, 1), date(2024, 1, 8), date(2024, 1, 15)}},
+		{hours(9, 10), 0, nil},
+	}
+	for _, test := range tests {
+		var result []time.Time
+		it := test.r.Iterate(test.step)
+		for tm, ok := it.Next(); ok; tm, ok = it.Next() {
+			result = append(result, tm)
+		}
+		if formatOccurrences(result) != formatOccurrences(test.expected) {
+			t.Errorf("Iterate(%v, %d) = %s; expected %s", test.r, test.step, formatOccurrences(result), formatOccurrences(test.expected))
+		}
+		if _, ok := it.Next(); ok {
+			t.Errorf("Next() after the end = true")
+		}
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	daily := datetime.TimeRange{Start: time.Date(2024, 3, 9, 9, 0, 0, 0, ny), End: time.Date(2024, 3, 12, 0, 0, 0, 0, ny)}.Iterate(datetime.Day)
+	for tm, ok := daily.Next(); ok; tm, ok = daily.Next() {
+		if tm.Hour() != 9 {
+			t.Errorf("Iterate(Day) across DST = %v; expected 09:00", tm)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package datetime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+)
+
+// mustZone loads a zone or fails the test
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := datetime.LoadZone(name)
+	if err != nil {
+		t.Fatalf("LoadZone(%q): %v", name, err)
+	}
+	return loc
+}
+
+func TestLoadZone(t *testing.T) {
+	loc := mustZone(t, "Asia/Shanghai")
+	if again := mustZone(t, "Asia/Shanghai"); again != loc {
+		t.Error("LoadZone does not cache zones")
+	}
+	if loc := mustZone(t, ""); loc != time.UTC {
+		t.Errorf(`LoadZone("") = %v`, loc)
+	}
+	for _, name := range []string{"Mars/Olympus_Mons", "../etc/passwd"} {
+		if _, err := datetime.LoadZone(name); !errors.Is(err, datetime.ErrUnknownZone) {
+			t.Errorf("LoadZone(%q) error = %v", name, err)
+		}
+	}
+}
+
+func TestConvertZone(t *testing.T) {
+	at := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		zone string
+		want string
+	}{
+		{"Asia/Shanghai", "2024-07-01 20:00 CST"},
+		{"America/New_York", "2024-07-01 08:00 EDT"},
+		{"Europe/London", "2024-07-01 13:00 BST"},
+		{"Asia/Kolkata", "2024-07-01 17:30 IST"},
+	} {
+		got, err := datetime.FormatInZone(at, c.zone, "2006-01-02 15:04 MST")
+		if err != nil || got != c.want {
+			t.Errorf("FormatInZone(%s) = %q, %v, want %q", c.zone, got, err, c.want)
+		}
+	}
+	if _, err := datetime.ConvertZone(at, "Nowhere/City"); !errors.Is(err, datetime.ErrUnknownZone) {
+		t.Errorf("ConvertZone error = %v", err)
+	}
+
+	dt, err := datetime.NewDateTime(at).InZone("Asia/Tokyo")
+	if err != nil || dt.Hour() != 21 || !dt.Time.Equal(at) {
+		t.Errorf("InZone = %v, %v", dt, err)
+	}
+
+	// 09:00 in New York is 15:00 in Berlin in summer
+	got, err := datetime.ConvertLocalTime(time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC), "America/New_York", "Europe/Berlin", datetime.DisambiguateCompatible)
+	if err != nil || got.Format("2006-01-02 15:04 MST") != "2024-07-01 15:00 CEST" {
+		t.Errorf("ConvertLocalTime = %v, %v", got, err)
+	}
+}
+
+func TestInspectLocalTime(t *testing.T) {
+	newYork := mustZone(t, "America/New_York")
+	const layout = "2006-01-02 15:04 MST"
+	for _, c := range []struct {
+		wall           time.Time
+		kind           datetime.LocalTimeKind
+		earlier, later string
+	}{
+		{time.Date(2024, 3, 10, 1, 30, 0, 0, time.UTC), datetime.LocalTimeUnique, "2024-03-10 01:30 EST", "2024-03-10 01:30 EST"},
+		{time.Date(2024, 3, 10, 2, 30, 0, 0, time.UTC), datetime.LocalTimeSkipped, "2024-03-10 01:30 EST", "2024-03-10 03:30 EDT"},
+		{time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC), datetime.LocalTimeUnique, "2024-03-10 03:00 EDT", "2024-03-10 03:00 EDT"},
+		{time.Date(2024, 11, 3, 1, 30, 0, 0, time.UTC), datetime.LocalTimeAmbiguous, "2024-11-03 01:30 EDT", "2024-11-03 01:30 EST"},
+		{time.Date(2024, 11, 3, 2, 0, 0, 0, time.UTC), datetime.LocalTimeUnique, "2024-11-03 02:00 EST", "2024-11-03 02:00 EST"},
+	} {
+		got := datetime.InspectLocalTime(c.wall, newYork)
+		if got.Kind != c.kind || got.Earlier.Format(layout) != c.earlier || got.Later.Format(layout) != c.later {
+			t.Errorf("InspectLocalTime(%v) = %s %s / %s, want %s %s / %s", c.wall, got.Kind,
+				got.Earlier.Format(layout), got.Later.Format(layout), c.kind, c.earlier, c.later)
+		}
+	}
+
+	// a zone without DST
+	if got := datetime.InspectLocalTime(time.Date(2024, 3, 10, 2, 30, 0, 0, time.UTC), mustZone(t, "Asia/Shanghai")); got.Kind != datetime.LocalTimeUnique {
+		t.Errorf("Asia/Shanghai kind = %s", got.Kind)
+	}
+	// Lord Howe Island moves its clocks by 30 minutes
+	lordHowe := mustZone(t, "Australia/Lord_Howe")
+	if got := datetime.InspectLocalTime(time.Date(2024, 10, 6, 2, 15, 0, 0, time.UTC), lordHowe); got.Kind != datetime.LocalTimeSkipped || got.Later.Sub(got.Earlier) != 30*time.Minute {
+		t.Errorf("Lord Howe gap = %s %v %v", got.Kind, got.Earlier, got.Later)
+	}
+}
+
+func TestResolveLocalTime(t *testing.T) {
+	newYork := mustZone(t, "America/New_York")
+	skipped := time.Date(2024, 3, 10, 2, 30, 0, 0, time.UTC)
+	ambiguous := time.Date(2024, 11, 3, 1, 30, 0, 0, time.UTC)
+	const layout = "15:04 MST"
+	for _, c := range []struct {
+		wall time.Time
+		d    datetime.Disambiguation
+		want string
+		err  error
+	}{
+		{skipped, datetime.DisambiguateCompatible, "03:30 EDT", nil},
+		{skipped, datetime.DisambiguateEarlier, "01:30 EST", nil},
+		{skipped, datetime.DisambiguateLater, "03:30 EDT", nil},
+		{skipped, datetime.DisambiguateReject, "", datetime.ErrSkippedTime},
+		{ambiguous, datetime.DisambiguateCompatible, "01:30 EDT", nil},
+		{ambiguous, datetime.DisambiguateEarlier, "01:30 EDT", nil},
+		{ambiguous, datetime.DisambiguateLater, "01:30 EST", nil},
+		{ambiguous, datetime.DisambiguateReject, "", datetime.ErrAmbiguousTime},
+	} {
+		got, err := datetime.ResolveLocalTime(c.wall, newYork, c.d)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("ResolveLocalTime(%v, %d) error = %v, want %v", c.wall, c.d, err, c.err)
+			}
+			continue
+		}
+		if err != nil || got.Format(layout) != c.want {
+			t.Errorf("ResolveLocalTime(%v, %d) = %s, %v, want %s", c.wall, c.d, got.Format(layout), err, c.want)
+		}
+	}
+
+	// unique times agree with time.Date for every half hour of a year
+	for wall := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); wall.Year() == 2024; wall = wall.Add(30 * time.Minute) {
+		local := datetime.InspectLocalTime(wall, newYork)
+		if local.Kind != datetime.LocalTimeUnique {
+			continue
+		}
+		want := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, newYork)
+		if got, err := datetime.ResolveLocalTime(wall, newYork, datetime.DisambiguateReject); err != nil || !got.Equal(want) {
+			t.Fatalf("ResolveLocalTime(%v) = %v, %v, want %v", wall, got, err, want)
+		}
+	}
+}
+
+func TestZoneTransitions(t *testing.T) {
+	newYork := mustZone(t, "America/New_York")
+	transitions := datetime.ZoneTransitions(newYork,
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(transitions) != 2 {
+		t.Fatalf("ZoneTransitions = %v", transitions)
+	}
+	spring, fall := transitions[0], transitions[1]
+	if spring.At.Format("2006-01-02 15:04 MST") != "2024-03-10 03:00 EDT" || spring.Before != -5*time.Hour || spring.After != -4*time.Hour {
+		t.Errorf("spring transition = %+v", spring)
+	}
+	if fall.At.Format("2006-01-02 15:04 MST") != "2024-11-03 01:00 EST" || fall.Before != -4*time.Hour || fall.After != -5*time.Hour {
+		t.Errorf("fall transition = %+v", fall)
+	}
+	if got := datetime.ZoneTransitions(mustZone(t, "Asia/Shanghai"),
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); len(got) != 0 {
+		t.Errorf("Asia/Shanghai transitions = %v", got)
+	}
+}
+
+func TestListZones(t *testing.T) {
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	zones := datetime.ListZones(at)
+	if len(zones) < 300 {
+		t.Fatalf("ListZones returned %d zones", len(zones))
+	}
+	var shanghai *datetime.ZoneInfo
+	for i := range zones {
+		if i > 0 && zones[i].Offset < zones[i-1].Offset {
+			t.Fatalf("zones are not ordered by offset at %s", zones[i].Name)
+		}
+		if zones[i].Name == "Asia/Shanghai" {
+			shanghai = &zones[i]
+		}
+	}
+	if shanghai == nil || shanghai.Country != "CN" || shanghai.Offset != 8*time.Hour || shanghai.String() != "Asia/Shanghai (CST, UTC+08:00)" {
+		t.Errorf("Asia/Shanghai = %+v", shanghai)
+	}
+
+	if got := datetime.ZonesOfCountry("CN"); len(got) != 2 || got[0] != "Asia/Shanghai" || got[1] != "Asia/Urumqi" {
+		t.Errorf("ZonesOfCountry(CN) = %v", got)
+	}
+}
+
+func TestWorldClock(t *testing.T) {
+	at := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	clocks, err := datetime.WorldClock(at, "America/St_Johns", "UTC", "Asia/Kathmandu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"America/St_Johns (NDT, UTC-02:30) 09:30",
+		"UTC (UTC, UTC+00:00) 12:00",
+		"Asia/Kathmandu (+0545, UTC+05:45) 17:45",
+	}
+	for i, c := range clocks {
+		if got := c.String() + " " + c.Time.Format("15:04"); got != want[i] {
+			t.Errorf("WorldClock[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+	if !clocks[0].IsDST || clocks[2].IsDST || clocks[0].Country != "CA" {
+		t.Errorf("WorldClock = %+v", clocks)
+	}
+	if _, err := datetime.WorldClock(at, "UTC", "Bad/Zone"); !errors.Is(err, datetime.ErrUnknownZone) {
+		t.Errorf("WorldClock error = %v", err)
+	}
+}
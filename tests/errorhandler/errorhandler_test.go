@@ -38,6 +38,9 @@ func TestErrorHandlerPackage(t *testing.T) {
 	if customErr.Code != 1001 || customErr.Message != "Resource not found" || customErr.Level != errorhandler.Error {
 		t.Errorf("custom error fields do not match expected values")
 	}
+	if !errors.Is(customErr, originalErr) {
+		t.Errorf("expected custom error to unwrap to original error")
+	}
 
 	// 测试日志记录（控制台）
 	errorhandler.LogError(customErr)
@@ -0,0 +1,159 @@
+package hashutil_test
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"GoFast/pkg/util/hashutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestVectors(t *testing.T) {
+	vectors := map[hashutil.Algorithm]string{
+		hashutil.MD5:        "900150983cd24fb0d6963f7d28e17f72",
+		hashutil.SHA1:       "a9993e364706816aba3e25717850c26c9cd0d89d",
+		hashutil.SHA224:     "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7",
+		hashutil.SHA256:     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		hashutil.SHA384:     "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7",
+		hashutil.SHA512:     "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		hashutil.SHA3_256:   "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+		hashutil.SHA3_512:   "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0",
+		hashutil.BLAKE2b512: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+	}
+	for alg, want := range vectors {
+		assert.Equal(t, want, hashutil.DigestString(alg, "abc").Hex(), alg.String())
+		assert.Equal(t, want, hashutil.Digest(alg, []byte("abc")).Hex(), alg.String())
+		sum, err := hashutil.DigestReader(alg, strings.NewReader("abc"))
+		assert.NoError(t, err)
+		assert.Equal(t, want, sum.String(), alg.String())
+		assert.Len(t, sum, alg.Size())
+	}
+
+	assert.Equal(t, "900150983cd24fb0d6963f7d28e17f72", hashutil.MD5Hex("abc"))
+	assert.Equal(t, "a9993e364706816aba3e25717850c26c9cd0d89d", hashutil.SHA1Hex("abc"))
+	assert.Equal(t, vectors[hashutil.SHA256], hashutil.SHA256Hex("abc"))
+	assert.Equal(t, vectors[hashutil.SHA512], hashutil.SHA512Hex("abc"))
+	assert.Equal(t, vectors[hashutil.SHA3_256], hashutil.SHA3_256Hex("abc"))
+	assert.Equal(t, "ungWv48Bz+pBQUDeXa4iI7ADYaOWF3qctBD/YfIAFa0=", hashutil.DigestString(hashutil.SHA256, "abc").Base64())
+	assert.Equal(t, "ungWv48Bz-pBQUDeXa4iI7ADYaOWF3qctBD_YfIAFa0", hashutil.DigestString(hashutil.SHA256, "abc").Base64URL())
+	assert.Len(t, hashutil.DigestString(hashutil.BLAKE2b256, "abc"), 32)
+}
+
+// TestHMAC checks RFC 4231 test case 2
+func TestHMAC(t *testing.T) {
+	const want = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	assert.Equal(t, want, hashutil.HmacSHA256Hex("Jefe", "what do ya want for nothing?"))
+	assert.Equal(t, want, hashutil.HMAC(hashutil.SHA256, []byte("Jefe"), []byte("what do ya want for nothing?")).Hex())
+	sum, err := hashutil.HMACReader(hashutil.SHA256, []byte("Jefe"), strings.NewReader("what do ya want for nothing?"))
+	assert.NoError(t, err)
+	assert.True(t, sum.Equal(hashutil.HMACString(hashutil.SHA256, "Jefe", "what do ya want for nothing?")))
+	assert.NotEqual(t, want, hashutil.HmacSHA256Hex("jefe", "what do ya want for nothing?"))
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	for name, want := range map[string]hashutil.Algorithm{
+		"sha256": hashutil.SHA256, "SHA-256": hashutil.SHA256, "sha3_256": hashutil.SHA3_256,
+		"SHA3-512": hashutil.SHA3_512, "md5": hashutil.MD5, "blake2b": hashutil.BLAKE2b512,
+		"blake2b-256": hashutil.BLAKE2b256,
+	} {
+		alg, err := hashutil.ParseAlgorithm(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, alg, name)
+	}
+	_, err := hashutil.ParseAlgorithm("crc32")
+	assert.ErrorIs(t, err, hashutil.ErrUnsupportedAlgorithm)
+	assert.False(t, hashutil.Algorithm(99).Available())
+	assert.Panics(t, func() { hashutil.Algorithm(99).New() })
+}
+
+func TestFileDigestAndChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "artifact.bin")
+	content := strings.Repeat("GoFast artifact\n", 100000)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	want := hashutil.DigestString(hashutil.SHA256, content)
+	got, err := hashutil.DigestFile(hashutil.SHA256, path)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	mac, err := hashutil.HMACFile(hashutil.SHA512, []byte("k"), path)
+	assert.NoError(t, err)
+	assert.Equal(t, hashutil.HMACString(hashutil.SHA512, "k", content), mac)
+
+	assert.NoError(t, hashutil.VerifyFileChecksum(path, hashutil.SHA256, want.Hex()))
+	assert.NoError(t, hashutil.VerifyFileChecksum(path, hashutil.SHA256, strings.ToUpper(want.Hex())))
+	assert.NoError(t, hashutil.VerifyFileChecksum(path, hashutil.SHA256, want.Base64()))
+	assert.NoError(t, hashutil.VerifyFile(path, "sha256:"+want.Hex()))
+	assert.NoError(t, hashutil.VerifyFile(path, "sha256-"+want.Base64()))
+	assert.NoError(t, hashutil.VerifyChecksum(hashutil.SHA256, strings.NewReader(content), want.Hex()))
+
+	other := hashutil.DigestString(hashutil.SHA256, "tampered")
+	assert.ErrorIs(t, hashutil.VerifyFileChecksum(path, hashutil.SHA256, other.Hex()), hashutil.ErrChecksumMismatch)
+	assert.ErrorIs(t, hashutil.VerifyFile(path, "sha256="+other.Hex()), hashutil.ErrChecksumMismatch)
+	assert.ErrorIs(t, hashutil.VerifyFileChecksum(path, hashutil.SHA256, "abcd"), hashutil.ErrInvalidChecksum)
+	assert.ErrorIs(t, hashutil.VerifyFile(path, want.Hex()), hashutil.ErrInvalidChecksum)
+	_, err = hashutil.DigestFile(hashutil.SHA256, filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	alg, sum, err := hashutil.ParseChecksum("SHA-512:" + hashutil.SHA512Hex("x"))
+	assert.NoError(t, err)
+	assert.Equal(t, hashutil.SHA512, alg)
+	assert.Equal(t, hashutil.SHA512Hex("x"), sum.Hex())
+}
+
+func TestParseChecksumHyphenatedNames(t *testing.T) {
+	for _, alg := range []hashutil.Algorithm{hashutil.SHA256, hashutil.SHA3_256, hashutil.SHA3_512, hashutil.BLAKE2b256, hashutil.BLAKE2b512} {
+		want := hashutil.DigestString(alg, "integrity")
+		for _, checksum := range []string{
+			alg.String() + "-" + want.Base64(),
+			alg.String() + ":" + want.Hex(),
+			alg.String() + "=" + want.Hex(),
+			alg.String() + "-" + base64.RawURLEncoding.EncodeToString(want),
+		} {
+			got, sum, err := hashutil.ParseChecksum(checksum)
+			if assert.NoError(t, err, checksum) {
+				assert.Equal(t, alg, got, checksum)
+				assert.True(t, sum.Equal(want), checksum)
+			}
+		}
+	}
+
+	// "blake2b" alone still names BLAKE2b-512
+	want := hashutil.DigestString(hashutil.BLAKE2b512, "integrity")
+	alg, _, err := hashutil.ParseChecksum("blake2b-" + want.Base64())
+	assert.NoError(t, err)
+	assert.Equal(t, hashutil.BLAKE2b512, alg)
+}
+
+func TestParseChecksumList(t *testing.T) {
+	list := "# release checksums\n" +
+		hashutil.SHA256Hex("a") + "  app-linux.tar.gz\n" +
+		hashutil.SHA256Hex("b") + " *app-windows.zip\n\n" +
+		"SHA256 (app darwin.tar.gz) = " + strings.ToUpper(hashutil.SHA256Hex("c")) + "\n"
+	sums, err := hashutil.ParseChecksumList(strings.NewReader(list))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"app-linux.tar.gz":  hashutil.SHA256Hex("a"),
+		"app-windows.zip":   hashutil.SHA256Hex("b"),
+		"app darwin.tar.gz": hashutil.SHA256Hex("c"),
+	}, sums)
+
+	_, err = hashutil.ParseChecksumList(strings.NewReader("not-hex  file\n"))
+	assert.ErrorIs(t, err, hashutil.ErrInvalidChecksum)
+	_, err = hashutil.ParseChecksumList(strings.NewReader("abcd\n"))
+	assert.ErrorIs(t, err, hashutil.ErrInvalidChecksum)
+}
+
+func BenchmarkDigest(b *testing.B) {
+	data := make([]byte, 1<<20)
+	for _, alg := range []hashutil.Algorithm{hashutil.MD5, hashutil.SHA256, hashutil.SHA3_256, hashutil.BLAKE2b256} {
+		b.Run(alg.String(), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				hashutil.Digest(alg, data)
+			}
+		})
+	}
+}
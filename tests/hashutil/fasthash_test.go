@@ -0,0 +1,128 @@
+package hashutil_test
+
+import (
+	"fmt"
+	"testing"
+
+	"GoFast/pkg/util/hashutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fastHashVectors 由各算法的参考实现生成，输入为 i%251 序列，长度覆盖所有分支。
+// fastHashVectors were generated with the reference implementations for the input
+// byte(i%251), at lengths that cover every code path. Murmur3 and seeded XXH3 use seed 42.
+var fastHashVectors = []struct {
+	n        int
+	murmur32 uint32
+	murmurH1 uint64
+	murmurH2 uint64
+	xxh64    uint64
+	xxh3     uint64
+	xxh3Seed uint64
+	city64   uint64
+}{
+	{0, 0x087fcd5c, 0xf02aa77dfa1b8523, 0xd1016610da11cbb9, 0xef46db3751d8e999, 0x2d06800538d394c2, 0xb029411ff43d84d2, 0x9ae16a3b2f90404f},
+	{1, 0xdd4449c2, 0x323efed30b226dc8, 0x16bdbdf28659c459, 0xe934a84adb052768, 0xc44bdff4074eecdb, 0x5cf10f10bf2dd245, 0xbe6056edf5e94b54},
+	{3, 0x55339868, 0x55e97282198acf70, 0x8ac79afc40cae619, 0xe5c7bb4533bc65dd, 0x5f4299fc161c9cbb, 0x75881294bdbaf34c, 0x94a13d22e9eba49a},
+	{4, 0xc07061b1, 0xb1e997578432f247, 0x71c2bda0028295bc, 0xffced8604453cc1e, 0x60dab036a58211f2, 0xd8571bd6d6d17e42, 0x82bffd898958e540},
+	{8, 0x0843e47c, 0x5045fc347ce9218e, 0x9f05810be6648b7b, 0x884a173614b81b8d, 0x3a1c2d7c85af88f8, 0x533b2c25fa397f0b, 0xad5a13e1e8e93b98},
+	{9, 0xc647771d, 0x443643fae0918397, 0xed5ac840fdf71df6, 0x67d85784a7c78c5b, 0xe9612598145bb9dc, 0xec60d7913c5410f9, 0x81371e150e4ad84f},
+	{16, 0xda27e4eb, 0x52b5fa4f1786de29, 0x3c4d5bc560421e40, 0x44b6ef2fb84169f7, 0x8355e3a6f61770db, 0x74891a34d3fff0a9, 0x0efd25a0a34156d4},
+	{17, 0x8ddb6fb5, 0x8f08f89d332aa1fb, 0x592bb498cd19df6c, 0x5603e60c527599b6, 0x9ef341a99de37328, 0x2668e3977d451c23, 0xbbb6a6f8f20d1f1c},
+	{32, 0x068eef47, 0x35ac4ee2a375fe11, 0xf5c12c4cbf068b69, 0xcbf59c5116ff32b4, 0x3523581fe96e4c05, 0xeb1c9b8fb88f9bb6, 0x1a9d8199972cdf49},
+	{33, 0xceba6008, 0xd693141f9e1df25e, 0xaf456193ea9735c8, 0x0c535d1acafb8ead, 0xe68c56ba88991e58, 0x42fa7404a76a4f26, 0x46e1378cbc22daba},
+	{64, 0x929fb814, 0xf1ade7ef84a12d05, 0x5ab6b814e60eab4d, 0xf7c67301db6713f0, 0x6187eb9089b0ed55, 0x19611ac4647c1dfd, 0xe99ab80f5ec7dca5},
+	{65, 0x22253f99, 0xf0a9a9a12ac7bdeb, 0xdb12abc7c2c3e061, 0xc31eb63b2ae4465b, 0x6928c76ce90422d0, 0x3f5cdef7b5f97e9f, 0xac589c990483dd2e},
+	{96, 0xf4a75e2e, 0xcc567fc7f378ee27, 0xc09a044ebefe55eb, 0x450baa11f6739216, 0x278a3e12ea046dfb, 0x2aab2a10042630b1, 0xe3f6cd656b9c26be},
+	{97, 0x66418e0e, 0xeec669ebb1c3a72b, 0xb13cf4803dbdc37a, 0xc93ec3db0dd47e34, 0xe7220282dc4e14f4, 0x73a68701de555959, 0xb1541a33562869ea},
+	{128, 0x3dde6d07, 0xca7c3adcc1dec60b, 0xc689a23f9102411f, 0x7a7fe14647b9ab92, 0x85c6174c7ff4c46b, 0xa7f863935f4a4028, 0x10b153630af1f395},
+	{129, 0xa25584d4, 0x9d2901a7999dacdc, 0x16fb7da19c867ef8, 0x0ba25dfd6e891fcf, 0xec7642b431ba3e5a, 0x82b80bdd4ac29db5, 0x46be8f236f918770},
+	{240, 0x941d98cb, 0xeaf3903d28848186, 0xd51dc1979613acf1, 0x012947f0da6a27b1, 0x375a384d957fe865, 0x4c023d24e6a84d31, 0x619f36b7b1eb4599},
+	{241, 0xebe9606e, 0x1b3a01e4403a0f7c, 0xbc7f7b5ce2c3aba6, 0x8d643f23bf2808e1, 0x02e8cd95421c6d02, 0x26e3d358d4e0a1d6, 0xff8eecd0580401d2},
+	{1024, 0xafadfe72, 0xdbcdba76e08a02c0, 0x0e0d0fbda55aaa30, 0x138e26c65048ce29, 0xe5d78bafa45b2aa5, 0xb0e3ba3ff9ba14fd, 0x63887f74055574ed},
+	{1025, 0x077b7b5c, 0xf078213eeee26223, 0x419c6b1ca851045d, 0xcfd73aedd2d6a39d, 0xe95c42288f28186e, 0x34e5b2d01b3d0213, 0xb835e8809df9b9b9},
+	{4096, 0x1f1afa86, 0x2a191604c943bf10, 0xa7573995b77d9f94, 0x122a8c8d994ad3ec, 0x7135ffa504f1bc71, 0x993219a67ae6d3fb, 0x3de2a8213d6af16d},
+}
+
+func sequence(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestFastHashVectors(t *testing.T) {
+	for _, v := range fastHashVectors {
+		data := sequence(v.n)
+		assert.Equal(t, v.murmur32, hashutil.Murmur3Hash32(data, 42), "murmur3-32 len %d", v.n)
+		h1, h2 := hashutil.Murmur3Hash128(data, 42)
+		assert.Equal(t, v.murmurH1, h1, "murmur3-128 len %d", v.n)
+		assert.Equal(t, v.murmurH2, h2, "murmur3-128 len %d", v.n)
+		assert.Equal(t, v.xxh64, hashutil.XXHash64(data, 0), "xxh64 len %d", v.n)
+		assert.Equal(t, v.xxh3, hashutil.XXH3Hash64(data), "xxh3 len %d", v.n)
+		assert.Equal(t, v.xxh3Seed, hashutil.XXH3Hash64WithSeed(data, 42), "xxh3 seeded len %d", v.n)
+		assert.Equal(t, v.city64, hashutil.CityHash64(data), "cityhash64 len %d", v.n)
+	}
+}
+
+func TestFastHashPublishedValues(t *testing.T) {
+	fox := []byte("The quick brown fox jumps over the lazy dog")
+	assert.Equal(t, uint32(0x2e4ff723), hashutil.Murmur3Hash32(fox, 0))
+	h1, h2 := hashutil.Murmur3Hash128(fox, 0)
+	assert.Equal(t, uint64(0xe34bbc7bbc071b6c), h1)
+	assert.Equal(t, uint64(0x7a433ca9c49a9347), h2)
+	assert.Equal(t, uint64(0x0b242d361fda71bc), hashutil.XXHash64(fox, 0))
+	assert.Equal(t, uint64(0xef46db3751d8e999), hashutil.XXHash64(nil, 0))
+	assert.Equal(t, uint64(0x2d06800538d394c2), hashutil.XXH3Hash64(nil))
+	assert.Equal(t, uint32(0), hashutil.Murmur3Hash32(nil, 0))
+
+	// 种子会改变结果。 Seeds change the result.
+	assert.NotEqual(t, hashutil.XXHash64(fox, 0), hashutil.XXHash64(fox, 1))
+	assert.NotEqual(t, hashutil.CityHash64(fox), hashutil.CityHash64WithSeed(fox, 1))
+	assert.Equal(t, hashutil.CityHash64WithSeed(fox, 7), hashutil.CityHash64WithSeeds(fox, 0x9ae16a3b2f90404f, 7))
+}
+
+func TestFastHashNoAllocation(t *testing.T) {
+	data := sequence(5000)
+	allocs := testing.AllocsPerRun(100, func() {
+		hashutil.Murmur3Hash32(data, 1)
+		hashutil.Murmur3Hash128(data, 1)
+		hashutil.XXHash64(data, 1)
+		hashutil.XXH3Hash64(data)
+		hashutil.XXH3Hash64WithSeed(data, 1)
+		hashutil.CityHash64WithSeed(data, 1)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+// BenchmarkFastHash 对比新算法与现有的字符串哈希函数。
+// BenchmarkFastHash compares the new hashes with the existing string hashes.
+func BenchmarkFastHash(b *testing.B) {
+	for _, n := range []int{16, 64, 1024} {
+		data := sequence(n)
+		str := string(data)
+		cases := []struct {
+			name string
+			fn   func()
+		}{
+			{"Murmur3Hash32", func() { hashutil.Murmur3Hash32(data, 0) }},
+			{"Murmur3Hash128", func() { hashutil.Murmur3Hash128(data, 0) }},
+			{"XXHash64", func() { hashutil.XXHash64(data, 0) }},
+			{"XXH3Hash64", func() { hashutil.XXH3Hash64(data) }},
+			{"CityHash64", func() { hashutil.CityHash64(data) }},
+			{"FnvHash", func() { hashutil.FnvHash(str) }},
+			{"BkdrHash", func() { hashutil.BkdrHash(str) }},
+			{"Bernstein", func() { hashutil.Bernstein(str) }},
+			{"MixHash", func() { hashutil.MixHash(str) }},
+		}
+		for _, c := range cases {
+			b.Run(fmt.Sprintf("%s/%d", c.name, n), func(b *testing.B) {
+				b.SetBytes(int64(n))
+				for i := 0; i < b.N; i++ {
+					c.fn()
+				}
+			})
+		}
+	}
+}
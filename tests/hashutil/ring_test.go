@@ -0,0 +1,219 @@
+package hashutil_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"GoFast/pkg/util/hashutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// router 是 Ring 和 Rendezvous 的公共接口。
+// router is the API shared by Ring and Rendezvous.
+type router interface {
+	Add(nodes ...string)
+	Remove(nodes ...string)
+	Get(key string) (string, bool)
+	GetN(key string, n int) []string
+	Nodes() []string
+	Len() int
+}
+
+func routers() map[string]func() router {
+	return map[string]func() router{
+		"ring":       func() router { return hashutil.NewRing(0, nil) },
+		"ring-city":  func() router { return hashutil.NewRing(100, hashutil.CityHash64) },
+		"rendezvous": func() router { return hashutil.NewRendezvous(nil) },
+	}
+}
+
+func keys(n int) []string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("user:%d", i)
+	}
+	return list
+}
+
+func TestRouterBasics(t *testing.T) {
+	for name, create := range routers() {
+		t.Run(name, func(t *testing.T) {
+			r := create()
+			_, ok := r.Get("k")
+			assert.False(t, ok)
+			assert.Nil(t, r.GetN("k", 2))
+
+			r.Add("node-c", "node-a", "node-b", "node-a")
+			assert.Equal(t, 3, r.Len())
+			assert.Equal(t, []string{"node-a", "node-b", "node-c"}, r.Nodes())
+
+			node, ok := r.Get("k")
+			assert.True(t, ok)
+			replicas := r.GetN("k", 2)
+			assert.Len(t, replicas, 2)
+			assert.Equal(t, node, replicas[0])
+			assert.NotEqual(t, replicas[0], replicas[1])
+			assert.ElementsMatch(t, r.Nodes(), r.GetN("k", 10))
+
+			// 结果与插入顺序无关。 Results do not depend on insertion order.
+			other := create()
+			other.Add("node-b", "node-c", "node-a")
+			for _, k := range keys(200) {
+				a, _ := r.Get(k)
+				b, _ := other.Get(k)
+				assert.Equal(t, a, b)
+			}
+		})
+	}
+}
+
+func TestRouterBalanceAndMinimalMovement(t *testing.T) {
+	for name, create := range routers() {
+		t.Run(name, func(t *testing.T) {
+			r := create()
+			nodes := []string{"n0", "n1", "n2", "n3", "n4"}
+			r.Add(nodes...)
+
+			all := keys(20000)
+			before := make(map[string]string, len(all))
+			counts := map[string]int{}
+			for _, k := range all {
+				node, _ := r.Get(k)
+				before[k] = node
+				counts[node]++
+			}
+			expected := float64(len(all)) / float64(len(nodes))
+			for _, node := range nodes {
+				assert.InDelta(t, expected, float64(counts[node]), expected*0.25, "%s has %d keys", node, counts[node])
+			}
+
+			// 移除节点后，只有原本属于该节点的键会移动。
+			// After a removal only the removed node's keys move.
+			r.Remove("n2")
+			moved := 0
+			for _, k := range all {
+				node, _ := r.Get(k)
+				if before[k] == "n2" {
+					assert.NotEqual(t, "n2", node)
+					moved++
+				} else {
+					assert.Equal(t, before[k], node, k)
+				}
+			}
+			assert.Equal(t, counts["n2"], moved)
+
+			// 新增节点只会从已有节点拿走键。 Adding a node only takes keys over.
+			r.Add("n2")
+			for _, k := range all {
+				node, _ := r.Get(k)
+				assert.Equal(t, before[k], node, k)
+			}
+		})
+	}
+}
+
+func TestWeights(t *testing.T) {
+	ring := hashutil.NewRing(0, nil)
+	ring.AddWeighted("small", 1)
+	ring.AddWeighted("large", 3)
+	hrw := hashutil.NewRendezvous(nil)
+	hrw.AddWeighted("small", 1)
+	hrw.AddWeighted("large", 3)
+
+	for _, r := range []router{ring, hrw} {
+		counts := map[string]int{}
+		for _, k := range keys(20000) {
+			node, _ := r.Get(k)
+			counts[node]++
+		}
+		assert.InDelta(t, 0.75, float64(counts["large"])/20000, 0.05)
+	}
+
+	ring.AddWeighted("large", 0)
+	hrw.AddWeighted("large", -1)
+	assert.Equal(t, []string{"small"}, ring.Nodes())
+	assert.Equal(t, []string{"small"}, hrw.Nodes())
+}
+
+func TestRouterConcurrentMembership(t *testing.T) {
+	for name, create := range routers() {
+		t.Run(name, func(t *testing.T) {
+			r := create()
+			r.Add("stable")
+			var wg sync.WaitGroup
+			stop := make(chan struct{})
+			for g := 0; g < 4; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; ; i++ {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+						node, ok := r.Get(fmt.Sprint(i))
+						assert.True(t, ok)
+						assert.NotEmpty(t, node)
+						assert.NotEmpty(t, r.GetN(fmt.Sprint(i), 2))
+					}
+				}()
+			}
+			for i := 0; i < 50; i++ {
+				r.Add(fmt.Sprintf("n%d", i%5))
+				r.Remove(fmt.Sprintf("n%d", (i+2)%5))
+			}
+			close(stop)
+			wg.Wait()
+		})
+	}
+}
+
+func TestJumpHash(t *testing.T) {
+	// 参考实现的取值。 Values of the reference implementation.
+	assert.Equal(t, 0, hashutil.JumpHash(1, 1))
+	assert.Equal(t, 43, hashutil.JumpHash(42, 57))
+	assert.Equal(t, 0, hashutil.JumpHash(0xDEAD10CC, 1))
+	assert.Equal(t, 361, hashutil.JumpHash(0xDEAD10CC, 666))
+	assert.Equal(t, 520, hashutil.JumpHash(256, 1024))
+	assert.Equal(t, -1, hashutil.JumpHash(1, 0))
+
+	// 从 10 个桶增加到 11 个时，约 1/11 的键移动，且都移到新桶。
+	// Growing from 10 to 11 buckets moves about 1/11 of the keys, all to the new bucket.
+	moved := 0
+	all := keys(20000)
+	for _, k := range all {
+		a, b := hashutil.JumpHashString(k, 10), hashutil.JumpHashString(k, 11)
+		assert.True(t, a >= 0 && a < 10)
+		if a != b {
+			assert.Equal(t, 10, b)
+			moved++
+		}
+	}
+	assert.InDelta(t, float64(len(all))/11, float64(moved), float64(len(all))*0.02)
+}
+
+func BenchmarkRouterGet(b *testing.B) {
+	ring := hashutil.NewRing(0, nil)
+	hrw := hashutil.NewRendezvous(nil)
+	for i := 0; i < 16; i++ {
+		ring.Add(fmt.Sprintf("node-%d", i))
+		hrw.Add(fmt.Sprintf("node-%d", i))
+	}
+	b.Run("Ring", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ring.Get("user:12345")
+		}
+	})
+	b.Run("Rendezvous", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			hrw.Get("user:12345")
+		}
+	})
+	b.Run("JumpHash", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			hashutil.JumpHashString("user:12345", 16)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package idcardutil_test
+
+import (
+	"testing"
+	"time"
+
+	"GoFast/pkg/util/idcardutil"
+)
+
+func TestIsValidCardBirth(t *testing.T) {
+	tests := []struct {
+		idCard   string
+		expected bool
+	}{
+		{"11010519491231002X", true},
+		{"130102198002290042", true},  // 闰年 2 月 29 日
+		{"130102198102290040", false}, // 非闰年 2 月 29 日
+		{"440305199013071234", false}, // 月份无效
+	}
+
+	for _, test := range tests {
+		if result := idcardutil.IsValidCard(test.idCard); result != test.expected {
+			t.Errorf("IsValidCard(%v) = %v; expected %v", test.idCard, result, test.expected)
+		}
+	}
+}
+
+func TestConvert15To18(t *testing.T) {
+	tests := []struct {
+		idCard   string
+		expected string
+		hasError bool
+	}{
+		{"150102880730303", "150102198807303035", false},
+		{"130503670401001", "130503196704010016", false},
+		{"150102881330303", "", true}, // 月份无效
+		{"15010288073030", "", true},  // 长度不足
+		{"15010288073030X", "", true}, // 非数字
+	}
+
+	for _, test := range tests {
+		result, err := idcardutil.Convert15To18(test.idCard)
+		if (err != nil) != test.hasError || result != test.expected {
+			t.Errorf("Convert15To18(%v) = %v, %v; expected %v, %v", test.idCard, result, err, test.expected, test.hasError)
+		}
+		if err == nil && !idcardutil.IsValidCard(result) {
+			t.Errorf("Convert15To18(%v) = %v is not a valid 18-digit card", test.idCard, result)
+		}
+	}
+}
+
+func TestHKMacauTaiwanCards(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) bool
+		idCard   string
+		expected bool
+	}{
+		{"hk", idcardutil.IsValidHKCard, "A123456(3)", true},
+		{"hk", idcardutil.IsValidHKCard, "A1234563", true},
+		{"hk", idcardutil.IsValidHKCard, "AB987654(3)", true},
+		{"hk", idcardutil.IsValidHKCard, "G123456(A)", true},
+		{"hk", idcardutil.IsValidHKCard, "A123456(4)", false},
+		{"hk", idcardutil.IsValidHKCard, "A12345(3)", false},
+		{"macau", idcardutil.IsValidMacauCard, "1234567(8)", true},
+		{"macau", idcardutil.IsValidMacauCard, "5215299(8)", true},
+		{"macau", idcardutil.IsValidMacauCard, "2234567(8)", false},
+		{"taiwan", idcardutil.IsValidTaiwanCard, "A123456789", true},
+		{"taiwan", idcardutil.IsValidTaiwanCard, "B234567894", true},
+		{"taiwan", idcardutil.IsValidTaiwanCard, "A123456788", false},
+		{"taiwan", idcardutil.IsValidTaiwanCard, "A323456789", false},
+		{"hk-macau-permit", idcardutil.IsValidHKMacauTravelPermit, "H12345678", true},
+		{"hk-macau-permit", idcardutil.IsValidHKMacauTravelPermit, "M1234567801", true},
+		{"hk-macau-permit", idcardutil.IsValidHKMacauTravelPermit, "C12345678", false},
+		{"taiwan-permit", idcardutil.IsValidTaiwanTravelPermit, "12345678", true},
+		{"taiwan-permit", idcardutil.IsValidTaiwanTravelPermit, "1234567890", true},
+		{"taiwan-permit", idcardutil.IsValidTaiwanTravelPermit, "123456789", false},
+		{"foreigner", idcardutil.IsValidForeignerPermanentResidenceCard, "911840198506150011", true},
+		{"foreigner", idcardutil.IsValidForeignerPermanentResidenceCard, "911840198506150012", false},
+		{"foreigner", idcardutil.IsValidForeignerPermanentResidenceCard, "11010519491231002X", false},
+	}
+
+	for _, test := range tests {
+		if result := test.validate(test.idCard); result != test.expected {
+			t.Errorf("%s(%v) = %v; expected %v", test.name, test.idCard, result, test.expected)
+		}
+	}
+}
+
+func TestParseIdCard(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		idCard   string
+		expected idcardutil.IdCardInfo
+	}{
+		{"11010519491231002x", idcardutil.IdCardInfo{
+			Number: "11010519491231002X", Type: idcardutil.CardTypeMainland, Birth: date(1949, 12, 31), Gender: "女",
+			RegionCode: "110105", Province: "北京市", City: "北京市", District: "朝阳区",
+		}},
+		{" 210102199003072113 ", idcardutil.IdCardInfo{
+			Number: "210102199003072113", Type: idcardutil.CardTypeMainland, Birth: date(1990, 3, 7), Gender: "男",
+			RegionCode: "210102", Province: "辽宁省", City: "沈阳市", District: "和平区",
+		}},
+		{"130503670401001", idcardutil.IdCardInfo{
+			Number: "130503670401001", Type: idcardutil.CardTypeMainland15, Birth: date(1967, 4, 1), Gender: "男",
+			RegionCode: "130503", Province: "河北省", City: "邢台市", District: "信都区",
+		}},
+		{"810000198808080021", idcardutil.IdCardInfo{
+			Number: "810000198808080021", Type: idcardutil.CardTypeResidencePermit, Birth: date(1988, 8, 8), Gender: "女",
+			RegionCode: "810000", Province: "香港特别行政区",
+		}},
+		{"911840198506150011", idcardutil.IdCardInfo{
+			Number: "911840198506150011", Type: idcardutil.CardTypeForeignerPermanentResidence, Birth: date(1985, 6, 15), Gender: "男",
+			RegionCode: "110000", Province: "北京市", City: "北京市", Nationality: "840",
+		}},
+		{"a1234563", idcardutil.IdCardInfo{
+			Number: "A123456(3)", Type: idcardutil.CardTypeHongKong, RegionCode: "810000", Province: "香港特别行政区",
+		}},
+		{"1234567(8)", idcardutil.IdCardInfo{
+			Number: "1234567(8)", Type: idcardutil.CardTypeMacau, RegionCode: "820000", Province: "澳门特别行政区",
+		}},
+		{"B234567894", idcardutil.IdCardInfo{
+			Number: "B234567894", Type: idcardutil.CardTypeTaiwan, Gender: "女", RegionCode: "830000", Province: "台湾省",
+		}},
+		{"M12345678", idcardutil.IdCardInfo{
+			Number: "M12345678", Type: idcardutil.CardTypeHKMacauTravelPermit, RegionCode: "820000", Province: "澳门特别行政区",
+		}},
+		{"12345678", idcardutil.IdCardInfo{
+			Number: "12345678", Type: idcardutil.CardTypeTaiwanTravelPermit, RegionCode: "830000", Province: "台湾省",
+		}},
+	}
+
+	for _, test := range tests {
+		info, err := idcardutil.ParseIdCard(test.idCard)
+		if err != nil {
+			t.Errorf("ParseIdCard(%v) error = %v", test.idCard, err)
+			continue
+		}
+		if *info != test.expected {
+			t.Errorf("ParseIdCard(%v) = %+v; expected %+v", test.idCard, *info, test.expected)
+		}
+	}
+
+	for _, idCard := range []string{"", "110105194912310021", "A123456(4)", "X123"} {
+		if _, err := idcardutil.ParseIdCard(idCard); err != idcardutil.ErrInvalidIdCard {
+			t.Errorf("ParseIdCard(%v) error = %v; expected %v", idCard, err, idcardutil.ErrInvalidIdCard)
+		}
+	}
+}
+
+func TestCardTypeString(t *testing.T) {
+	if name := idcardutil.CardTypeForeignerPermanentResidence.String(); name != "外国人永久居留身份证" {
+		t.Errorf("CardType.String() = %v", name)
+	}
+	if name := idcardutil.CardTypeUnknown.String(); name != "未知" {
+		t.Errorf("CardType.String() = %v", name)
+	}
+}
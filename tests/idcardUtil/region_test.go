@@ -0,0 +1,171 @@
+package idcardutil_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoFast/pkg/util/idcardutil"
+)
+
+func regionNames(regions []idcardutil.Region) []string {
+	names := make([]string, len(regions))
+	for i, r := range regions {
+		names[i] = r.Code + " " + r.Name
+	}
+	return names
+}
+
+func TestGetRegion(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected idcardutil.Region
+		found    bool
+	}{
+		{"130000", idcardutil.Region{Code: "130000", Name: "河北省", Level: idcardutil.RegionLevelProvince}, true},
+		{"130100", idcardutil.Region{Code: "130100", Name: "石家庄市", Level: idcardutil.RegionLevelCity, ParentCode: "130000"}, true},
+		{"130102", idcardutil.Region{Code: "130102", Name: "长安区", Level: idcardutil.RegionLevelDistrict, ParentCode: "130100"}, true},
+		// 直辖市的区县直接隶属于省级
+		{"110105", idcardutil.Region{Code: "110105", Name: "朝阳区", Level: idcardutil.RegionLevelDistrict, ParentCode: "110000"}, true},
+		{"999999", idcardutil.Region{}, false},
+	}
+
+	for _, test := range tests {
+		region, found := idcardutil.GetRegion(test.code)
+		if found != test.found || region != test.expected {
+			t.Errorf("GetRegion(%v) = %+v, %v; expected %+v, %v", test.code, region, found, test.expected, test.found)
+		}
+	}
+}
+
+func TestGetRegionPath(t *testing.T) {
+	tests := []struct {
+		code     string
+		sep      string
+		expected string
+	}{
+		{"130102", "/", "河北省/石家庄市/长安区"},
+		{"130100", "/", "河北省/石家庄市"},
+		{"110105", " ", "北京市 朝阳区"},
+		{"210102", "", "辽宁省沈阳市和平区"},
+		{"999999", "/", ""},
+	}
+
+	for _, test := range tests {
+		if result := idcardutil.GetRegionFullName(test.code, test.sep); result != test.expected {
+			t.Errorf("GetRegionFullName(%v, %q) = %v; expected %v", test.code, test.sep, result, test.expected)
+		}
+	}
+	if path := idcardutil.GetRegionPath("999999"); path != nil {
+		t.Errorf("GetRegionPath(999999) = %v; expected nil", path)
+	}
+}
+
+func TestGetChildren(t *testing.T) {
+	children := idcardutil.GetChildren("130100")
+	if len(children) == 0 || children[0].Code != "130102" || children[0].ParentCode != "130100" {
+		t.Errorf("GetChildren(130100) = %v", regionNames(children))
+	}
+	for i := 1; i < len(children); i++ {
+		if children[i-1].Code >= children[i].Code {
+			t.Errorf("GetChildren(130100) is not sorted: %v", regionNames(children))
+		}
+	}
+	if beijing := idcardutil.GetChildren("110000"); len(beijing) != 16 {
+		t.Errorf("GetChildren(110000) returned %d districts; expected 16", len(beijing))
+	}
+	if children := idcardutil.GetChildren("130102"); len(children) != 0 {
+		t.Errorf("GetChildren(130102) = %v; expected none", regionNames(children))
+	}
+
+	provinces := idcardutil.GetProvinces()
+	if len(provinces) == 0 || provinces[0].Code != "110000" {
+		t.Errorf("GetProvinces() = %v", regionNames(provinces))
+	}
+}
+
+func TestFindRegionsByName(t *testing.T) {
+	expected := []string{"120101 和平区", "210102 和平区"}
+	if result := regionNames(idcardutil.FindRegionsByName("和平区")); !reflect.DeepEqual(result, expected) {
+		t.Errorf("FindRegionsByName(和平区) = %v; expected %v", result, expected)
+	}
+	if result := idcardutil.FindRegionsByName("和平"); len(result) != 0 {
+		t.Errorf("FindRegionsByName(和平) = %v; expected none", regionNames(result))
+	}
+}
+
+func TestSearchRegions(t *testing.T) {
+	tests := []struct {
+		keyword string
+		first   []string
+	}{
+		{"石家庄市", []string{"130100 石家庄市"}},
+		{"石家庄", []string{"130100 石家庄市"}},
+		{"朝阳", []string{"211300 朝阳市", "110105 朝阳区", "211321 朝阳县", "220104 朝阳区"}},
+		{"内蒙古", []string{"150000 内蒙古自治区"}},
+		{"呼和", []string{"150100 呼和浩特市"}},
+	}
+
+	for _, test := range tests {
+		result := regionNames(idcardutil.SearchRegions(test.keyword))
+		if len(result) < len(test.first) || !reflect.DeepEqual(result[:len(test.first)], test.first) {
+			t.Errorf("SearchRegions(%v) = %v; expected to start with %v", test.keyword, result, test.first)
+		}
+	}
+	if result := idcardutil.SearchRegions("  "); result != nil {
+		t.Errorf("SearchRegions(blank) = %v; expected nil", regionNames(result))
+	}
+}
+
+func TestLoadRegions(t *testing.T) {
+	t.Cleanup(idcardutil.ResetRegions)
+
+	data := `[{"code": "440000", "label": "广东省", "list": [
+		{"code": "440300", "label": "深圳市", "list": [
+			{"code": "440305", "label": "南山区"},
+			{"code": "440306", "label": "宝安区"}
+		]}
+	]}]`
+	path := filepath.Join(t.TempDir(), "regions.json")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := idcardutil.LoadRegionsFromFile(path); err != nil {
+		t.Fatalf("LoadRegionsFromFile() error = %v", err)
+	}
+
+	if name := idcardutil.GetRegionFullName("440305", "/"); name != "广东省/深圳市/南山区" {
+		t.Errorf("GetRegionFullName(440305) = %v", name)
+	}
+	if _, found := idcardutil.GetRegion("130000"); found {
+		t.Errorf("GetRegion(130000) found after loading a new dataset")
+	}
+	if region, err := idcardutil.GetRegionByIdCard("440305199003071236"); err != nil || region != "广东省 深圳市 南山区" {
+		t.Errorf("GetRegionByIdCard() = %v, %v", region, err)
+	}
+
+	invalid := []string{
+		``,
+		`[]`,
+		`{"code": "440000"}`,
+		`[{"code": "4400", "label": "广东省"}]`,
+		`[{"code": "440000", "label": ""}]`,
+		`[{"code": "440000", "label": "广东省", "list": [{"code": "44030X", "label": "深圳市"}]}]`,
+	}
+	for _, data := range invalid {
+		if err := idcardutil.LoadRegions(strings.NewReader(data)); !errors.Is(err, idcardutil.ErrInvalidRegionData) {
+			t.Errorf("LoadRegions(%q) error = %v; expected %v", data, err, idcardutil.ErrInvalidRegionData)
+		}
+	}
+	if err := idcardutil.LoadRegionsFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("LoadRegionsFromFile(missing) error = nil")
+	}
+
+	idcardutil.ResetRegions()
+	if _, found := idcardutil.GetRegion("130000"); !found {
+		t.Errorf("GetRegion(130000) not found after ResetRegions")
+	}
+}
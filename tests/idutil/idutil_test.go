@@ -0,0 +1,34 @@
+package idutil_test
+
+import (
+	"testing"
+	"time"
+
+	"GoFast/pkg/datetime"
+	"GoFast/pkg/util/idutil"
+)
+
+func TestSnowflakeWithClock(t *testing.T) {
+	clock := datetime.NewFakeClock(time.UnixMilli(1700000000000))
+	s := idutil.NewSnowflakeWithClock(1, clock)
+	first, second := s.NextId(), s.NextId()
+	if second != first+1 {
+		t.Errorf("NextId() in the same millisecond = %d, %d; expected consecutive ids", first, second)
+	}
+	clock.Advance(time.Second)
+	third := s.NextId()
+	if elapsed := (third >> 22) - (first >> 22); elapsed != 1000 {
+		t.Errorf("timestamp difference = %dms; expected 1000ms", elapsed)
+	}
+	if node := (third >> 12) & 0x3ff; node != 1 {
+		t.Errorf("node id = %d; expected 1", node)
+	}
+}
+
+func TestObjectIdUsesClock(t *testing.T) {
+	datetime.SetClock(datetime.NewFakeClock(time.Unix(0x65000000, 0)))
+	defer datetime.SetClock(nil)
+	if id := idutil.ObjectId(); id[:8] != "65000000" {
+		t.Errorf("ObjectId() = %s; expected the timestamp 65000000", id)
+	}
+}
@@ -0,0 +1,406 @@
+package sketchutil_test
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"GoFast/pkg/util/sketchutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// 编译期检查所有结构都实现了二进制序列化接口。
+// Compile-time check that every structure supports binary serialization.
+var (
+	_ encoding.BinaryMarshaler   = (*sketchutil.BloomFilter)(nil)
+	_ encoding.BinaryUnmarshaler = (*sketchutil.BloomFilter)(nil)
+	_ encoding.BinaryMarshaler   = (*sketchutil.CountingBloomFilter)(nil)
+	_ encoding.BinaryUnmarshaler = (*sketchutil.CountingBloomFilter)(nil)
+	_ encoding.BinaryMarshaler   = (*sketchutil.ScalableBloomFilter)(nil)
+	_ encoding.BinaryUnmarshaler = (*sketchutil.ScalableBloomFilter)(nil)
+	_ encoding.BinaryMarshaler   = (*sketchutil.CountMinSketch)(nil)
+	_ encoding.BinaryUnmarshaler = (*sketchutil.CountMinSketch)(nil)
+	_ encoding.BinaryMarshaler   = (*sketchutil.HyperLogLog)(nil)
+	_ encoding.BinaryUnmarshaler = (*sketchutil.HyperLogLog)(nil)
+)
+
+func key(prefix string, i int) []byte {
+	return []byte(fmt.Sprintf("%s-%d", prefix, i))
+}
+
+func TestOptimalBloomSize(t *testing.T) {
+	m, k := sketchutil.OptimalBloomSize(1000, 0.01)
+	assert.Equal(t, uint64(9586), m)
+	assert.Equal(t, uint32(7), k)
+
+	_, err := sketchutil.NewBloomFilter(1000, 0)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+	_, err = sketchutil.NewBloomFilter(1000, 1)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+	_, err = sketchutil.NewBloomFilterWithSize(0, 3)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+}
+
+func TestBloomFilter(t *testing.T) {
+	const n = 10000
+	f, err := sketchutil.NewBloomFilter(n, 0.01)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		f.Add(key("in", i))
+	}
+	for i := 0; i < n; i++ {
+		assert.True(t, f.Contains(key("in", i)), "false negative for %d", i)
+	}
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if f.Contains(key("out", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/n, 0.02)
+	assert.InDelta(t, 0.01, f.FalsePositiveRate(), 0.005)
+	assert.Equal(t, uint64(n), f.Count())
+	assert.InEpsilon(t, n, float64(f.EstimatedCount()), 0.03)
+
+	f.AddString("hello")
+	assert.True(t, f.ContainsString("hello"))
+	f.Reset()
+	assert.False(t, f.ContainsString("hello"))
+	assert.Zero(t, f.Count())
+}
+
+func TestBloomFilterTestAndAdd(t *testing.T) {
+	f, _ := sketchutil.NewBloomFilter(100, 0.001)
+	assert.False(t, f.TestAndAdd([]byte("a")))
+	assert.True(t, f.TestAndAdd([]byte("a")))
+	assert.True(t, f.Contains([]byte("a")))
+}
+
+func TestBloomFilterMerge(t *testing.T) {
+	a, _ := sketchutil.NewBloomFilter(1000, 0.01)
+	b, _ := sketchutil.NewBloomFilter(1000, 0.01)
+	for i := 0; i < 500; i++ {
+		a.Add(key("a", i))
+		b.Add(key("b", i))
+	}
+	assert.NoError(t, a.Merge(b))
+	for i := 0; i < 500; i++ {
+		assert.True(t, a.Contains(key("a", i)))
+		assert.True(t, a.Contains(key("b", i)))
+	}
+	assert.Equal(t, uint64(1000), a.Count())
+
+	other, _ := sketchutil.NewBloomFilter(2000, 0.01)
+	assert.ErrorIs(t, a.Merge(other), sketchutil.ErrIncompatible)
+}
+
+func TestBloomFilterMarshal(t *testing.T) {
+	f, _ := sketchutil.NewBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.Add(key("x", i))
+	}
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+
+	var g sketchutil.BloomFilter
+	assert.NoError(t, g.UnmarshalBinary(data))
+	assert.Equal(t, f.Cap(), g.Cap())
+	assert.Equal(t, f.K(), g.K())
+	assert.Equal(t, f.Count(), g.Count())
+	for i := 0; i < 1000; i++ {
+		assert.True(t, g.Contains(key("x", i)))
+	}
+
+	assert.ErrorIs(t, g.UnmarshalBinary(data[:len(data)-1]), sketchutil.ErrInvalidData)
+	assert.ErrorIs(t, g.UnmarshalBinary(append(data, 0)), sketchutil.ErrInvalidData)
+	assert.ErrorIs(t, g.UnmarshalBinary(nil), sketchutil.ErrInvalidData)
+	hll, _ := sketchutil.NewHyperLogLog(10)
+	wrong, _ := hll.MarshalBinary()
+	assert.ErrorIs(t, g.UnmarshalBinary(wrong), sketchutil.ErrInvalidData)
+}
+
+func TestCountingBloomFilter(t *testing.T) {
+	f, err := sketchutil.NewCountingBloomFilter(1000, 0.01)
+	assert.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		f.Add(key("x", i))
+	}
+	for i := 0; i < 500; i++ {
+		assert.True(t, f.Remove(key("x", i)))
+	}
+	for i := 500; i < 1000; i++ {
+		assert.True(t, f.Contains(key("x", i)), "false negative after removals for %d", i)
+	}
+	removed := 0
+	for i := 0; i < 500; i++ {
+		if !f.Contains(key("x", i)) {
+			removed++
+		}
+	}
+	assert.Greater(t, removed, 490)
+	assert.Equal(t, uint64(500), f.Count())
+	assert.False(t, f.RemoveString("never added"))
+
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+	var g sketchutil.CountingBloomFilter
+	assert.NoError(t, g.UnmarshalBinary(data))
+	assert.Equal(t, f.Count(), g.Count())
+	for i := 500; i < 1000; i++ {
+		assert.True(t, g.Contains(key("x", i)))
+	}
+
+	plain := f.BloomFilter()
+	assert.Equal(t, f.Cap(), plain.Cap())
+	for i := 500; i < 1000; i++ {
+		assert.True(t, plain.Contains(key("x", i)))
+	}
+}
+
+func TestCountingBloomFilterMerge(t *testing.T) {
+	a, _ := sketchutil.NewCountingBloomFilter(100, 0.01)
+	b, _ := sketchutil.NewCountingBloomFilter(100, 0.01)
+	a.AddString("shared")
+	b.AddString("shared")
+	b.AddString("only-b")
+	assert.NoError(t, a.Merge(b))
+	assert.True(t, a.ContainsString("only-b"))
+	assert.True(t, a.RemoveString("shared"))
+	assert.True(t, a.ContainsString("shared"), "one copy of shared remains")
+
+	c, _ := sketchutil.NewCountingBloomFilter(1000, 0.01)
+	assert.ErrorIs(t, a.Merge(c), sketchutil.ErrIncompatible)
+}
+
+func TestScalableBloomFilter(t *testing.T) {
+	const n = 20000
+	f, err := sketchutil.NewScalableBloomFilter(100, 0.01)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		f.Add(key("in", i))
+	}
+	assert.Greater(t, f.Filters(), 5)
+	for i := 0; i < n; i++ {
+		assert.True(t, f.Contains(key("in", i)), "false negative for %d", i)
+	}
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if f.Contains(key("out", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, float64(falsePositives)/n, 0.01)
+	assert.Less(t, f.FalsePositiveRate(), 0.01)
+	assert.InEpsilon(t, n, float64(f.Count()), 0.01)
+
+	// 重复添加不占用容量。
+	// Duplicates do not use up capacity.
+	count := f.Count()
+	assert.True(t, f.Add(key("in", 0)))
+	assert.Equal(t, count, f.Count())
+
+	f.Reset()
+	assert.Equal(t, 1, f.Filters())
+	assert.Equal(t, uint64(0), f.Count())
+	assert.False(t, f.Add(key("in", 0)))
+	assert.True(t, f.Contains(key("in", 0)))
+
+	// 第一个过滤器过大时在创建时报错。
+	// A first filter that is too large is reported when the filter is created.
+	_, err = sketchutil.NewScalableBloomFilter(1<<50, 0.01)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+}
+
+func TestScalableBloomFilterMergeMarshal(t *testing.T) {
+	a, _ := sketchutil.NewScalableBloomFilter(64, 0.01)
+	b, _ := sketchutil.NewScalableBloomFilter(64, 0.01)
+	for i := 0; i < 100; i++ {
+		a.Add(key("a", i))
+	}
+	for i := 0; i < 1000; i++ {
+		b.Add(key("b", i))
+	}
+	rate, count := b.FalsePositiveRate(), a.Count()+b.Count()
+	assert.NoError(t, a.Merge(b))
+	assert.Equal(t, b.Filters(), a.Filters())
+	assert.Equal(t, count, a.Count())
+	// 同层按位或后填充率升高，估算的误判率随之反映。
+	// OR-ing same-level filters raises their fill, which the estimate reflects.
+	assert.Greater(t, a.FalsePositiveRate(), rate)
+	for i := 0; i < 100; i++ {
+		assert.True(t, a.Contains(key("a", i)))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(t, a.Contains(key("b", i)))
+	}
+
+	data, err := a.MarshalBinary()
+	assert.NoError(t, err)
+	var c sketchutil.ScalableBloomFilter
+	assert.NoError(t, c.UnmarshalBinary(data))
+	assert.Equal(t, a.Count(), c.Count())
+	assert.Equal(t, a.SizeBits(), c.SizeBits())
+	for i := 0; i < 1000; i++ {
+		assert.True(t, c.Contains(key("b", i)))
+	}
+	assert.ErrorIs(t, c.UnmarshalBinary(data[:len(data)-3]), sketchutil.ErrInvalidData)
+
+	// 层的尺寸与容量和误判率不符时拒绝。
+	// Levels whose size does not match their capacity and rate are rejected.
+	single, _ := sketchutil.NewScalableBloomFilter(64, 0.01)
+	single.Add(key("a", 0))
+	valid, _ := single.MarshalBinary()
+	assert.NoError(t, c.UnmarshalBinary(valid))
+	for _, forge := range []func(buf []byte){
+		func(buf []byte) { binary.BigEndian.PutUint64(buf[2:], 128) },
+		func(buf []byte) { binary.BigEndian.PutUint64(buf[2:], 32) },
+		func(buf []byte) { binary.BigEndian.PutUint64(buf[10:], math.Float64bits(0.02)) },
+	} {
+		forged := append([]byte(nil), valid...)
+		forge(forged)
+		assert.ErrorIs(t, c.UnmarshalBinary(forged), sketchutil.ErrInvalidData)
+	}
+
+	d, _ := sketchutil.NewScalableBloomFilter(64, 0.001)
+	assert.ErrorIs(t, a.Merge(d), sketchutil.ErrIncompatible)
+}
+
+func TestCountMinSketch(t *testing.T) {
+	s, err := sketchutil.NewCountMinSketch(0.001, 0.01)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2719), s.Width())
+	assert.Equal(t, uint32(5), s.Depth())
+
+	// Zipf 风格的分布：元素 i 出现 1000/(i+1) 次。
+	// A Zipf-like distribution: item i occurs 1000/(i+1) times.
+	truth := map[string]uint64{}
+	for i := 0; i < 2000; i++ {
+		k := fmt.Sprintf("item-%d", i)
+		c := uint64(1000/(i+1)) + 1
+		s.AddString(k, c)
+		truth[k] = c
+	}
+	bound := uint64(0.001 * float64(s.TotalCount()))
+	exceeded := 0
+	for k, c := range truth {
+		est := s.EstimateString(k)
+		assert.GreaterOrEqual(t, est, c)
+		if est > c+bound {
+			exceeded++
+		}
+	}
+	assert.LessOrEqual(t, exceeded, len(truth)/100)
+	assert.LessOrEqual(t, s.EstimateString("missing"), bound)
+}
+
+func TestCountMinSketchMergeMarshal(t *testing.T) {
+	a, _ := sketchutil.NewCountMinSketchWithSize(1000, 4)
+	b, _ := sketchutil.NewCountMinSketchWithSize(1000, 4)
+	a.AddString("x", 3)
+	b.AddString("x", 4)
+	b.Add([]byte("y"), 1)
+	assert.NoError(t, a.Merge(b))
+	assert.Equal(t, uint64(7), a.EstimateString("x"))
+	assert.Equal(t, uint64(1), a.Estimate([]byte("y")))
+	assert.Equal(t, uint64(8), a.TotalCount())
+
+	data, err := a.MarshalBinary()
+	assert.NoError(t, err)
+	var c sketchutil.CountMinSketch
+	assert.NoError(t, c.UnmarshalBinary(data))
+	assert.Equal(t, uint64(7), c.EstimateString("x"))
+	assert.Equal(t, a.TotalCount(), c.TotalCount())
+	assert.ErrorIs(t, c.UnmarshalBinary(data[:10]), sketchutil.ErrInvalidData)
+
+	d, _ := sketchutil.NewCountMinSketchWithSize(1000, 5)
+	assert.ErrorIs(t, a.Merge(d), sketchutil.ErrIncompatible)
+	_, err = sketchutil.NewCountMinSketch(0, 0.1)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+}
+
+func TestHyperLogLog(t *testing.T) {
+	h, err := sketchutil.NewHyperLogLog(sketchutil.DefaultHyperLogLogPrecision)
+	assert.NoError(t, err)
+	assert.Zero(t, h.Count())
+
+	// 在小、中、大基数下误差都应在约 3 个标准差以内。
+	// The error stays within about 3 standard errors for small, medium and large cardinalities.
+	stdErr := 1.04 / math.Sqrt(1<<sketchutil.DefaultHyperLogLogPrecision)
+	added := 0
+	for _, n := range []int{10, 100, 1000, 10000, 40000, 100000, 1000000} {
+		for ; added < n; added++ {
+			h.Add(key("x", added))
+		}
+		assert.InEpsilon(t, n, float64(h.Count()), math.Max(3*stdErr, 0.01), "n=%d", n)
+	}
+
+	// 重复元素不影响计数。
+	// Duplicates do not change the count.
+	before := h.Count()
+	for i := 0; i < 1000; i++ {
+		h.Add(key("x", i))
+	}
+	assert.Equal(t, before, h.Count())
+
+	_, err = sketchutil.NewHyperLogLog(3)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+	_, err = sketchutil.NewHyperLogLog(19)
+	assert.ErrorIs(t, err, sketchutil.ErrInvalidParameter)
+}
+
+func TestHyperLogLogMergeMarshal(t *testing.T) {
+	a, _ := sketchutil.NewHyperLogLog(12)
+	b, _ := sketchutil.NewHyperLogLog(12)
+	for i := 0; i < 30000; i++ {
+		a.Add(key("x", i))
+	}
+	for i := 20000; i < 50000; i++ {
+		b.Add(key("x", i))
+	}
+	union := a.Clone()
+	assert.NoError(t, union.Merge(b))
+	assert.InEpsilon(t, 50000, float64(union.Count()), 0.05)
+
+	data, err := union.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Len(t, data, 3+1<<12)
+	var c sketchutil.HyperLogLog
+	assert.NoError(t, c.UnmarshalBinary(data))
+	assert.Equal(t, union.Count(), c.Count())
+	assert.Equal(t, uint8(12), c.Precision())
+
+	data[10] = 0xff
+	assert.ErrorIs(t, c.UnmarshalBinary(data), sketchutil.ErrInvalidData)
+
+	d, _ := sketchutil.NewHyperLogLog(10)
+	assert.ErrorIs(t, a.Merge(d), sketchutil.ErrIncompatible)
+}
+
+func BenchmarkSketches(b *testing.B) {
+	data := []byte("user:1234567890")
+	bloom, _ := sketchutil.NewBloomFilter(1000000, 0.01)
+	cms, _ := sketchutil.NewCountMinSketch(0.001, 0.01)
+	hll, _ := sketchutil.NewHyperLogLog(sketchutil.DefaultHyperLogLogPrecision)
+	b.Run("BloomAdd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bloom.Add(data)
+		}
+	})
+	b.Run("BloomContains", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bloom.Contains(data)
+		}
+	})
+	b.Run("CountMinAdd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cms.Add(data, 1)
+		}
+	})
+	b.Run("HyperLogLogAdd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			hll.Add(data)
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package validatorutil_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"GoFast/pkg/util/validatorutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	City    string `validate:"required"`
+	ZipCode string `validate:"omitempty,len=6,numeric"`
+}
+
+type Item struct {
+	Name     string  `validate:"required,max=10"`
+	Quantity int     `validate:"gte=1,lte=99"`
+	Price    float64 `validate:"gt=0"`
+}
+
+type User struct {
+	Name       string            `validate:"required,min=2,max=8"`
+	Email      string            `validate:"omitempty,email"`
+	Role       string            `validate:"oneof=admin user guest"`
+	Age        *int              `validate:"omitempty,gte=18"`
+	Password   string            `validate:"required,min=8"`
+	Confirm    string            `validate:"eqfield=Password"`
+	IdCard     string            `validate:"omitempty,idcard"`
+	IP         string            `validate:"omitempty,ip"`
+	Phone      string            `validate:"omitempty,mobile"`
+	Website    string            `validate:"omitempty,url"`
+	Code       string            `validate:"omitempty,regexp=^[A-Z]{2}\\d{2\\,4}$"`
+	Tags       []string          `validate:"max=3,dive,required,alpha"`
+	Address    Address           `validate:"required"`
+	Backup     *Address          ``
+	Items      []Item            `validate:"min=1"`
+	Labels     map[string]string `validate:"dive,max=5"`
+	Timeout    time.Duration     `validate:"omitempty,min=1s,max=1m"`
+	StartAt    time.Time         ``
+	EndAt      time.Time         `validate:"gtfield=StartAt"`
+	Nickname   string            `validate:"required_without=Name"`
+	Internal   string            `validate:"-"`
+	unexported string            `validate:"required"`
+}
+
+func validUser() User {
+	age := 30
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return User{
+		Name:     "alice",
+		Email:    "alice@example.com",
+		Role:     "admin",
+		Age:      &age,
+		Password: "s3cretpass",
+		Confirm:  "s3cretpass",
+		IdCard:   "11010519491231002X",
+		IP:       "192.168.1.1",
+		Phone:    "13800138000",
+		Website:  "https://example.com/path",
+		Code:     "AB123",
+		Tags:     []string{"go", "fast"},
+		Address:  Address{City: "Beijing", ZipCode: "100000"},
+		Items:    []Item{{Name: "pen", Quantity: 2, Price: 1.5}},
+		Labels:   map[string]string{"env": "prod"},
+		Timeout:  30 * time.Second,
+		StartAt:  start,
+		EndAt:    start.Add(time.Hour),
+	}
+}
+
+func failedPaths(err error) []string {
+	var errs validatorutil.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path + ":" + e.Rule
+	}
+	return paths
+}
+
+func TestValidateValid(t *testing.T) {
+	user := validUser()
+	assert.NoError(t, validatorutil.Validate(user))
+	assert.NoError(t, validatorutil.Validate(&user))
+}
+
+func TestValidateInvalid(t *testing.T) {
+	age := 16
+	user := validUser()
+	user.Name = "a"
+	user.Email = "not-an-email"
+	user.Role = "root"
+	user.Age = &age
+	user.Confirm = "different"
+	user.IdCard = "110105194912310021"
+	user.IP = "999.1.1.1"
+	user.Phone = "12345"
+	user.Website = "example.com"
+	user.Code = "ab1"
+	user.Tags = []string{"go", "", "c++"}
+	user.Address = Address{ZipCode: "12a"}
+	user.Backup = &Address{}
+	user.Items = []Item{{Name: "a very long name", Quantity: 0, Price: 0}}
+	user.Labels = map[string]string{"env": "production"}
+	user.Timeout = 2 * time.Minute
+	user.EndAt = user.StartAt
+
+	err := validatorutil.Validate(user)
+	assert.Error(t, err)
+	assert.ElementsMatch(t, []string{
+		"Name:min",
+		"Email:email",
+		"Role:oneof",
+		"Age:gte",
+		"Confirm:eqfield",
+		"IdCard:idcard",
+		"IP:ip",
+		"Phone:mobile",
+		"Website:url",
+		"Code:regexp",
+		"Tags[1]:required",
+		"Tags[2]:alpha",
+		"Address.City:required",
+		"Address.ZipCode:len",
+		"Address.ZipCode:numeric",
+		"Backup.City:required",
+		"Items[0].Name:max",
+		"Items[0].Quantity:gte",
+		"Items[0].Price:gt",
+		"Labels[env]:max",
+		"Timeout:max",
+		"EndAt:gtfield",
+	}, failedPaths(err))
+}
+
+func TestRequiredRules(t *testing.T) {
+	user := validUser()
+	user.Name = ""
+	user.Password = ""
+	user.Items = nil
+	err := validatorutil.Validate(user)
+	assert.ElementsMatch(t, []string{
+		"Name:required",
+		"Password:required",
+		"Confirm:eqfield",
+		"Items:min",
+		"Nickname:required_without",
+	}, failedPaths(err))
+
+	user.Nickname = "al"
+	user.Name = "alice"
+	user.Password, user.Confirm = "password", "password"
+	user.Items = []Item{{Name: "pen", Quantity: 1, Price: 1}}
+	assert.NoError(t, validatorutil.Validate(user))
+}
+
+func TestLocalize(t *testing.T) {
+	user := validUser()
+	user.Name = ""
+	user.Nickname = "al"
+	user.Role = "root"
+	err := validatorutil.Validate(user)
+
+	var errs validatorutil.ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []string{"Name is required", "Role must be one of [admin user guest]"}, errs.Localize("en"))
+	assert.Equal(t, []string{"Name为必填字段", "Role必须是[admin user guest]中的一个"}, errs.Localize("zh"))
+	assert.Equal(t, "Name is required; Role must be one of [admin user guest]", err.Error())
+	assert.Len(t, errs.Fields()["Role"], 1)
+	assert.Equal(t, validatorutil.CodeOneOf, errs.Fields()["Role"][0].Code)
+}
+
+func TestCustomRule(t *testing.T) {
+	v := validatorutil.New()
+	v.RegisterRule("even", 0, func(ctx validatorutil.FieldContext) bool {
+		return ctx.Value.Int()%2 == 0
+	})
+
+	type Payload struct {
+		Count int `check:"even"`
+	}
+	v.SetTagName("check")
+	assert.NoError(t, v.Struct(Payload{Count: 2}))
+
+	err := v.Struct(Payload{Count: 3})
+	assert.Equal(t, []string{"Count:even"}, failedPaths(err))
+	assert.Equal(t, "Count is invalid", err.Error())
+
+	// Unknown rules are reported as programming errors, not validation failures
+	type Broken struct {
+		Value string `check:"nope"`
+	}
+	err = v.Struct(Broken{})
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, validatorutil.ErrInvalidTag))
+	assert.True(t, strings.Contains(err.Error(), "unknown rule"))
+}
+
+func TestInvalidTag(t *testing.T) {
+	type Pair struct {
+		A []string `validate:"dive,eqfield=B"`
+		B string
+	}
+	type Missing struct {
+		A string `validate:"eqfield=C"`
+	}
+	type BadMin struct {
+		Name string `validate:"min=abc"`
+	}
+	type BadDuration struct {
+		Timeout *time.Duration `validate:"max=10"`
+	}
+	type BadRegexp struct {
+		Code string `validate:"regexp=[a-"`
+	}
+	for _, obj := range []interface{}{
+		Pair{A: []string{"x"}, B: "x"},
+		Missing{},
+		BadMin{Name: "alice"},
+		BadDuration{},
+		BadRegexp{},
+	} {
+		err := validatorutil.Validate(obj)
+		assert.True(t, errors.Is(err, validatorutil.ErrInvalidTag), "%T: %v", obj, err)
+		var errs validatorutil.ValidationErrors
+		assert.False(t, errors.As(err, &errs), "%T reported as a field error", obj)
+	}
+
+	assert.True(t, errors.Is(validatorutil.ValidateVar(5, "gt=five"), validatorutil.ErrInvalidTag))
+	assert.True(t, errors.Is(validatorutil.ValidateVar("x", "eqfield=Other"), validatorutil.ErrInvalidTag))
+	assert.NoError(t, validatorutil.ValidateVar("abc", "eq=abc"))
+}
+
+func TestSetTagNameConcurrent(t *testing.T) {
+	type Payload struct {
+		Name string `validate:"required" check:"max=1"`
+	}
+	v := validatorutil.New()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = v.Struct(Payload{Name: "ab"})
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v.SetTagName([]string{"validate", "check"}[(i+j)%2])
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// The cache never keeps specs parsed with a previous tag name
+	v.SetTagName("check")
+	assert.Equal(t, []string{"Name:max"}, failedPaths(v.Struct(Payload{Name: "ab"})))
+	v.SetTagName("validate")
+	assert.NoError(t, v.Struct(Payload{Name: "ab"}))
+}
+
+func TestValidateVar(t *testing.T) {
+	assert.NoError(t, validatorutil.ValidateVar("admin@example.com", "required,email"))
+	assert.Error(t, validatorutil.ValidateVar("", "required,email"))
+	assert.NoError(t, validatorutil.ValidateVar("2001:db8::1", "ipv6"))
+	assert.Error(t, validatorutil.ValidateVar("10.0.0.1", "ipv6"))
+	assert.NoError(t, validatorutil.ValidateVar("10.0.0.1", "ipv4"))
+	assert.NoError(t, validatorutil.ValidateVar("123e4567-e89b-12d3-a456-426614174000", "uuid"))
+	assert.NoError(t, validatorutil.ValidateVar([]int{1, 2, 3}, "len=3,dive,gt=0"))
+	assert.Error(t, validatorutil.ValidateVar(nil, "required"))
+	assert.Error(t, validatorutil.Validate("not a struct"))
+}